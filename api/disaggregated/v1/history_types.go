@@ -0,0 +1,85 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the default max records kept in the history of cluster.
+const DefaultHistoryMaxRecords int32 = 1000
+
+// History config recording the significant lifecycle events of cluster into the DorisDisaggregatedClusterHistory named `{cluster}-history`.
+// the kubernetes events expire in an hour by default, the history keeps them for the post-incident review.
+type History struct {
+	// MaxRecords is the max records kept in history, the oldest records are removed beyond it. default is 1000.
+	// +optional
+	MaxRecords int32 `json:"maxRecords,omitempty"`
+}
+
+// HistoryRecord describe a significant lifecycle event of cluster, ep: scaling, suspending, dropping backends and failures.
+type HistoryRecord struct {
+	// Time is the time that the event emitted.
+	Time metav1.Time `json:"time"`
+
+	// ComputeGroup is the uniqueId of compute group that the event is about, empty for the events of cluster or other components.
+	// +optional
+	ComputeGroup string `json:"computeGroup,omitempty"`
+
+	// Type is the type of event, `Normal` or `Warning`.
+	Type string `json:"type"`
+
+	// Reason is the reason of event, ep: `CGScaleStarted`.
+	Reason string `json:"reason"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=ddch
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.cluster`
+// +kubebuilder:printcolumn:name="Records",type=integer,JSONPath=`.recordCount`
+// DorisDisaggregatedClusterHistory is the durable history of the significant lifecycle events of a DorisDisaggregatedCluster, recorded by operator best-effort.
+type DorisDisaggregatedClusterHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Cluster is the name of DorisDisaggregatedCluster that the history belongs to.
+	Cluster string `json:"cluster"`
+
+	// RecordCount is the number of records.
+	// +optional
+	RecordCount int32 `json:"recordCount,omitempty"`
+
+	// Records are the records in the order of time, the oldest first.
+	// +optional
+	Records []HistoryRecord `json:"records,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// DorisDisaggregatedClusterHistoryList contains a list of DorisDisaggregatedClusterHistory
+type DorisDisaggregatedClusterHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DorisDisaggregatedClusterHistory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DorisDisaggregatedClusterHistory{}, &DorisDisaggregatedClusterHistoryList{})
+}
@@ -18,8 +18,11 @@
 package v1
 
 import (
+	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type DorisDisaggregatedClusterSpec struct {
@@ -36,6 +39,18 @@ type DorisDisaggregatedClusterSpec struct {
 	//ComputeGroups describe a list of ComputeGroup, ComputeGroup is a group of compute node to do same thing.
 	ComputeGroups []ComputeGroup `json:"computeGroups,omitempty"`
 
+	// ComputeGroupNameRegex override the regular expression that the uniqueId of compute groups must match, ep: the naming constraint of doris version or the environment prefix.
+	// the built-in `[a-zA-Z](_?[0-9a-zA-Z])*` is used when not set. the expression not compiled fails the validation of compute groups.
+	// +optional
+	ComputeGroupNameRegex string `json:"computeGroupNameRegex,omitempty"`
+
+	// Profiles describe the environment specific overrides of compute groups, ep: the replicas and resources in dev, staging and prod.
+	// the profile is selected by the annotation `doris.disaggregated.cluster/profile` of cluster, the `--profile` flag of operator is used when the annotation not set.
+	// the values of the selected profile override the base values in computeGroups before validating, the overrides are not written back to computeGroups.
+	// the cluster uses the base values when no profile selected or the profile selected by flag not defined in the cluster.
+	// +optional
+	Profiles []Profile `json:"profiles,omitempty"`
+
 	// the name of secret that type is `kubernetes.io/basic-auth` and contains keys username, password for management doris node in cluster as fe, be register.
 	// the password key is `password`. the username defaults to `root` and is omitempty.
 	AuthSecret string `json:"authSecret,omitempty"`
@@ -44,13 +59,79 @@ type DorisDisaggregatedClusterSpec struct {
 	//+Deprecated, from 1.4.1 please use secret config username and password.
 	AdminUser *AdminUser `json:"adminUser,omitempty"`
 
+	// OperationSecret specify the credential of a dedicated user for dropping or decommissioning nodes(backends and frontends) in fe.
+	// the user only needs `NODE_PRIV` for least privilege. when not set, the user from authSecret(or adminUser) is used.
+	// +optional
+	OperationSecret *OperationSecret `json:"operationSecret,omitempty"`
+
+	// TLSSecret specify the secret of certificates for the operator connecting the query port of fe with tls, when the query port of fe enforces tls.
+	// when not set, the certificates are resolved from the tls config of fe and the secret mounted in fe by `secrets`.
+	// +optional
+	TLSSecret *TLSSecret `json:"tlsSecret,omitempty"`
+
 	// decommission be or not. default value is false.
 	// if true, will decommission be node when scale down compute group.
 	// if false, will drop be node when scale down compute group.
 	EnableDecommission bool `json:"enableDecommission,omitempty"`
 
+	// ScaleDownOrder decides the order of removing backends from fe and shrinking the statefulset when scaling down compute group.
+	// `DrainFirst`(default): drop or decommission(when enableDecommission is true) the backends first, then shrink the statefulset, the removed pods have not in-flight writes.
+	// `ShrinkFirst`: shrink the statefulset first, then drop the backends of removed pods. decommission is not used as the pods are gone.
+	// +kubebuilder:validation:Enum=DrainFirst;ShrinkFirst
+	// +optional
+	ScaleDownOrder ScaleDownOrder `json:"scaleDownOrder,omitempty"`
+
+	// SkipBalanceCheckOnScaleDown skip checking the balancing or cloning tablets in fe before scale down compute group.
+	// Default value is 'false', the scale down will wait for fe finishing the balancing and cloning tablets.
+	// only set true in emergency, scale down in stressed state may cause cascading unavailability.
+	SkipBalanceCheckOnScaleDown bool `json:"skipBalanceCheckOnScaleDown,omitempty"`
+
+	// RequireFEMasterElected require the fe have elected master(verified by `show frontends`) before reconciling compute groups, not only any fe endpoint ready.
+	// Default value is 'false'. when true, the sql operations like dropping or decommissioning backends will not be executed when fe have not master.
+	RequireFEMasterElected bool `json:"requireFEMasterElected,omitempty"`
+
+	// QuiesceComputeGroupsOnFERestart pause the scale operations of compute groups when fe statefulset is rolling(restarting or upgrading) or not all ready.
+	// Default value is 'false'. when true, the new scale keeps the replicas and the compute group in `QuiescingForFE` phase, the scale down in progress is paused,
+	// the dropping or decommissioning backends through the in-flux fe are avoided. the scale operations resume when fe is healthy again.
+	QuiesceComputeGroupsOnFERestart bool `json:"quiesceComputeGroupsOnFERestart,omitempty"`
+
+	// RequireStorageVault require the storage vault configured in fe(verified by `show storage vault`) before bringing up compute groups, the backends are useless without storage vault.
+	// Default value is 'false'. when true, the compute groups not created are kept in `WaitingStorageVault` phase until the storage vault configured, the created compute groups are not affected.
+	RequireStorageVault bool `json:"requireStorageVault,omitempty"`
+
+	// DecommissionOnEviction decommission and drop the backend of compute group pod from fe before the pod evicted, ep: the node drained for maintenance.
+	// Default value is 'false'. when true, the eviction is rejected with `429 TooManyRequests` until the backend decommissioned, the drain retries the eviction.
+	// it works by the pods eviction webhook, requires the operator deployed by the manifests in config/operator with webhook enabled, the helm chart not deploys webhooks.
+	DecommissionOnEviction bool `json:"decommissionOnEviction,omitempty"`
+
+	// RetainRemovedComputeGroupPVCs keeps the pvcs of compute groups removed from spec, the status of compute group removed without deleting them, the pvcs should be deleted manually.
+	// Default value is 'false', the pvcs are deleted and the status of compute group kept in `Removing` phase until the pvcs confirmed gone.
+	// +optional
+	RetainRemovedComputeGroupPVCs bool `json:"retainRemovedComputeGroupPVCs,omitempty"`
+
 	// KerberosInfo contains a series of access key files, Provides access to kerberos.
 	KerberosInfo *KerberosInfo `json:"kerberosInfo,omitempty"`
+
+	// History records the significant lifecycle events(ep: scaling, suspending, dropping backends and failures) into the DorisDisaggregatedClusterHistory named `{cluster}-history`.
+	// the recording is best-effort, the failures not block reconciling. not recorded when not set.
+	// +optional
+	History *History `json:"history,omitempty"`
+
+	// VersionSkew is the supported window of doris versions running across compute groups, the cluster beyond it has the condition VersionSkewExceeded and warning event.
+	// the default window is used when not set.
+	// +optional
+	VersionSkew *VersionSkew `json:"versionSkew,omitempty"`
+}
+
+// VersionSkew describe the window that compute groups running different doris versions is supported, ep: in a staged upgrade.
+type VersionSkew struct {
+	// MaxMinorVersions is the max difference of minor versions running across compute groups, the different major versions always exceed the window. default is 1.
+	// +optional
+	MaxMinorVersions *int32 `json:"maxMinorVersions,omitempty"`
+
+	// MaxMixedSeconds is the seconds that compute groups running different versions is supported, the staged upgrade should finish in it. default is 86400, 0 disables it.
+	// +optional
+	MaxMixedSeconds *int32 `json:"maxMixedSeconds,omitempty"`
 }
 
 type KerberosInfo struct {
@@ -68,6 +149,30 @@ type KerberosInfo struct {
 	KeytabPath string `json:"keytabPath,omitempty"`
 }
 
+// Profile describe the overrides of compute groups in a named environment.
+type Profile struct {
+	// the name of profile, selected by the annotation of cluster or the flag of operator.
+	Name string `json:"name"`
+
+	// ComputeGroups describe the overrides of compute groups, matched by uniqueId. the compute groups not listed use the base values.
+	// +optional
+	ComputeGroups []ComputeGroupOverride `json:"computeGroups,omitempty"`
+}
+
+// ComputeGroupOverride describe the values that override the base values of a compute group, the unset fields keep the base values.
+type ComputeGroupOverride struct {
+	// the uniqueId of compute group in computeGroups.
+	UniqueId string `json:"uniqueId"`
+
+	// Replicas override the replicas of compute group.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources override the requests and limits of compute group entirely, not merged with the base.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
 // AdminUser describe administrator for manage components in specified cluster.
 type AdminUser struct {
 	//the user name for admin service's node.
@@ -77,6 +182,56 @@ type AdminUser struct {
 	Password string `json:"password,omitempty"`
 }
 
+type ScaleDownOrder string
+
+const (
+	DrainFirst  ScaleDownOrder = "DrainFirst"
+	ShrinkFirst ScaleDownOrder = "ShrinkFirst"
+)
+
+type ScaleDownPolicy string
+
+const (
+	Ordinal      ScaleDownPolicy = "Ordinal"
+	ColdestCache ScaleDownPolicy = "ColdestCache"
+)
+
+// OperationSecret describe the secret and keys of the user for node operations.
+type OperationSecret struct {
+	// the name of secret in the namespace of cluster.
+	SecretName string `json:"secretName"`
+
+	// the key of username in secret, default is `username`.
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// the key of password in secret, default is `password`.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// TLSSecret describe the secret and keys of certificates for connecting fe with tls.
+type TLSSecret struct {
+	// the name of secret in the namespace of cluster.
+	SecretName string `json:"secretName"`
+
+	// the key of ca certificate in secret, default is `ca.crt`.
+	// +optional
+	CAKey string `json:"caKey,omitempty"`
+
+	// the key of client certificate in secret, default is `tls.crt`. the client certificate is not required when the secret have not it.
+	// +optional
+	CertKey string `json:"certKey,omitempty"`
+
+	// the key of client private key in secret, default is `tls.key`.
+	// +optional
+	KeyKey string `json:"keyKey,omitempty"`
+
+	// InsecureSkipVerify not verify the certificate of fe, the ca is not required. only for test.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
 type MetaService struct {
 	CommonSpec `json:",inline"`
 	//specify the address of fdb that used by doris Compute-storage decoupled cluster.
@@ -121,6 +276,340 @@ type ComputeGroup struct {
 	// Default System Init means that the container must be started in privileged mode.
 	// Default System Init configuration is implemented through the initContainers of the pod, so changes to this configuration may be ignored by k8s when it is not the first deployment.
 	SkipDefaultSystemInit bool `json:"skipDefaultSystemInit,omitempty"`
+
+	// ScaleDownWithoutBackends decides how to scale down when fe have not returned any backend of the compute group but pods still exist.
+	// Default value is 'false', the scale down will wait for fe metadata ready and requeue, not treat the scale down as succeed.
+	// if true, operator will not confirm backends in fe and directly shrink the statefulset.
+	ScaleDownWithoutBackends bool `json:"scaleDownWithoutBackends,omitempty"`
+
+	// NodePoolReplicas specify the replicas of compute group as a percentage of the schedulable nodes in a node pool.
+	// when configured, operator resolves it to an absolute number and overwrites the `replicas` in every reconcile, the replicas follow the changes of node pool.
+	// the replicas of compute group take precedence in order: the resolved replicas of nodePoolReplicas, `replicas`, the `minReplicas` of nodePoolReplicas, 1.
+	NodePoolReplicas *NodePoolReplicas `json:"nodePoolReplicas,omitempty"`
+
+	// ExternalMetricReplicas scale the compute group by a metric of the kubernetes external metrics api, ep: the lag of kafka, the depth of a queue.
+	// when configured, operator resolves the replicas from the metric value and overwrites the `replicas` in every reconcile. not use it with nodePoolReplicas.
+	ExternalMetricReplicas *ExternalMetricReplicas `json:"externalMetricReplicas,omitempty"`
+
+	// ReplicaRange run the compute group with the preferred replicas, and temporarily reduce to the minimum under resource pressure(the pods unschedulable).
+	// the replicas scale back to preferred when the nodes have capacity again. when configured, operator overwrites the `replicas` in every reconcile, not use it with nodePoolReplicas or externalMetricReplicas.
+	// +optional
+	ReplicaRange *ReplicaRange `json:"replicaRange,omitempty"`
+
+	// EnableMemoryAutoTuning derive the be memory config from the container memory limit(use memory request when limit not set).
+	// Default value is 'false'.
+	// when enabled, operator injects envs `BE_MEM_LIMIT`, `BE_STORAGE_PAGE_CACHE_LIMIT`, `BE_CHUNK_RESERVED_BYTES_LIMIT` into be container, reference them in be.conf as `mem_limit = ${BE_MEM_LIMIT}`.
+	// the envs are recomputed when resources changed, pods will rolling restart as the pod template changed.
+	EnableMemoryAutoTuning bool `json:"enableMemoryAutoTuning,omitempty"`
+
+	// RackAwareness spread the pods of compute group across racks by the rack topology label of nodes.
+	RackAwareness *RackAwareness `json:"rackAwareness,omitempty"`
+
+	// SwapTo is the uniqueId of another compute group(green) that replaces this compute group(blue).
+	// when the green compute group is Ready, the service of this compute group is repointed to the green pods, and this compute group is drained by scaling in to zero.
+	// remove this compute group from spec after validating the green one, or clear the field to roll back.
+	SwapTo string `json:"swapTo,omitempty"`
+
+	// PodManagementPolicy controls how pods are created during initial scale up, when replacing pods on nodes, or when scaling down.
+	// Default value is 'Parallel', set 'OrderedReady' to start pods one by one.
+	// pods are named by ordinal in any policy, the scale down always removes the backends that have the largest ordinals.
+	// the field of statefulset is immutable, the changed value only takes effect when the statefulset recreated.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy appv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
+	// ScaleInSnapshotClassName is the VolumeSnapshotClass used to snapshot the pvcs of scaled in pods before deleting them.
+	// when configured, the pvcs of scaled in pods are kept until the VolumeSnapshots are ready to use, it provides a recovery point for the accidental scale in.
+	// the VolumeSnapshots are not deleted by operator, please clean them when not needed.
+	// +optional
+	ScaleInSnapshotClassName string `json:"scaleInSnapshotClassName,omitempty"`
+
+	// ScaleCooldown is the minimum interval between two consecutive scale operations of compute group, ep: `10m`.
+	// the replicas changed in cooldown is kept in spec, and applied after the cooldown elapses. Default is no cooldown.
+	// +optional
+	ScaleCooldown *metav1.Duration `json:"scaleCooldown,omitempty"`
+
+	// NodePoolScaleUpCooldown is the cooldown of scale up that driven by nodePoolReplicas, it is usually shorter than scaleCooldown for following the node pool quickly.
+	// if not set, use scaleCooldown.
+	// +optional
+	NodePoolScaleUpCooldown *metav1.Duration `json:"nodePoolScaleUpCooldown,omitempty"`
+
+	// BaseConfigMap is the name of a shared configmap that the compute groups use as the base config.
+	// when configured, operator merges the base and the configMaps mounted to `/etc/doris` in order into a single configmap that named `{statefulsetName}-merged-config`,
+	// the later configmap overrides the earlier: the keys in be.conf are overridden one by one, other files are replaced as a whole.
+	// the merged configmap is mounted to `/etc/doris` in place of the base and the overlay configmaps.
+	// +optional
+	BaseConfigMap string `json:"baseConfigMap,omitempty"`
+
+	// Tenants bind users and roles to the compute group for routing their queries to it.
+	// after the compute group is Ready, operator grants the usage of compute group to users and roles, and sets it as the `default_compute_group` property of users.
+	// the binding is revoked when the user or role removed from tenants. a user can only be bound to one compute group.
+	// +optional
+	Tenants *ComputeGroupTenants `json:"tenants,omitempty"`
+
+	// MemoryLimit caps the memory used by queries in the compute group, the percentage of backend memory, ep: `50%`.
+	// after the backends registered, operator sets it as the `memory_limit` of the `normal` workload group of the compute group in fe. removing it keeps the last applied limit in fe.
+	// +kubebuilder:validation:Pattern=`^([1-9][0-9]?|100)%$`
+	// +optional
+	MemoryLimit string `json:"memoryLimit,omitempty"`
+
+	// PreservedPodAnnotationPrefixes are the prefixes of pod template annotations that added by other controllers, ep: `sidecar.istio.io/`.
+	// the matched annotations are kept from the existing statefulset when operator updates it, not stripped and restart the pods. not work with server-side apply, that keeps the fields not owned by operator.
+	// +optional
+	PreservedPodAnnotationPrefixes []string `json:"preservedPodAnnotationPrefixes,omitempty"`
+
+	// AutoRollback roll back the statefulset of compute group to the last known good image when the pods crashloop after upgrading image.
+	// the image in spec is kept, the rollback is released when the image in spec changed. not set means not roll back.
+	// +optional
+	AutoRollback *AutoRollback `json:"autoRollback,omitempty"`
+
+	// GracefulStopPort is the port of backend that the preStop script and the decommission waiting reach to stop backend gracefully.
+	// default is the `webserver_port` resolved from be config, the port should be exposed on the compute container.
+	// +optional
+	GracefulStopPort int32 `json:"gracefulStopPort,omitempty"`
+
+	// AllowDegradedScaleDown allow scaling down when the compute group is not fully ready, only for emergency scale-in.
+	// Default value is 'false', the scale down is deferred until all pods of compute group ready, for avoiding draining backends from an unhealthy compute group.
+	// +optional
+	AllowDegradedScaleDown bool `json:"allowDegradedScaleDown,omitempty"`
+
+	// MaxScaleInPercentage is the max percentage of the backends of compute group removed in one step of scaling in, at least one backend removed in a step.
+	// the large scale in is spread over steps, the next step starts after the backends of the last step dropped and the statefulset shrunk, the compute group keeps Scaling until reaching the replicas.
+	// not set means removing all backends in one step.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxScaleInPercentage *int32 `json:"maxScaleInPercentage,omitempty"`
+
+	// DecommissionBeforeDrop decommission the backends of compute group before dropping them when scaling down, the same as `enableDecommission` of cluster but only for this compute group.
+	// the backends are dropped after the tablets migrated, the compute group is Decommissioning in progress.
+	// +optional
+	DecommissionBeforeDrop bool `json:"decommissionBeforeDrop,omitempty"`
+
+	// DecommissionTimeout is the max time of decommissioning backends in scaling down, ep: `1h`. the decommission not finished in time is reported by warning event and the compute group is ScaleDownFailed.
+	// the decommission is still checked in later reconciles, the backends are dropped when it finished. not set means no timeout.
+	// +optional
+	DecommissionTimeout *metav1.Duration `json:"decommissionTimeout,omitempty"`
+
+	// ScaleDownPolicy decides the backends removed by scaling down compute group, not used when scaleDownOrder is ShrinkFirst.
+	// `Ordinal`(default): remove the backends of pods with the highest ordinals.
+	// `ColdestCache`: remove the backends with the coldest file cache. the statefulset always removes the pods with the highest ordinals, when their backends are warmer than the kept ones,
+	// the queries of them are disabled for the kept backends warming the cache, the scale down waits until they are the coldest or cacheWarmupTimeout passed, then removes them as `Ordinal`.
+	// the cache statistics not available in fe fall back to `Ordinal`.
+	// +kubebuilder:validation:Enum=Ordinal;ColdestCache
+	// +optional
+	ScaleDownPolicy ScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+
+	// CacheWarmupTimeout is the max time of waiting the kept backends warm the cache in scaling down with the ColdestCache policy, ep: `30m`. default is 10m.
+	// +optional
+	CacheWarmupTimeout *metav1.Duration `json:"cacheWarmupTimeout,omitempty"`
+
+	// Suspend scale the statefulset of compute group to zero and keep the pvcs, the backends are dropped from fe as scaling down. the compute group is Suspended when all pods removed.
+	// the replicas before suspending is recorded in status `suspendReplicas`, set to false for resuming, the recorded replicas are restored and the backends added again when the pods ready.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// LogRotation config the rolling and retention of be logs, for avoiding the log volume full.
+	// +optional
+	LogRotation *LogRotation `json:"logRotation,omitempty"`
+
+	// CutoverOnRecreate recreate the statefulset when the immutable fields changed, ep: `podManagementPolicy`, the volume claim templates of persistentVolumes.
+	// Default value is 'false', the immutable fields keep the existing values.
+	// when true, operator brings up a temporary statefulset with the new spec first, deletes the old statefulset after the temporary pods ready and backends registered,
+	// then recreates the statefulset and removes the temporary one after the recreated ready. the service always selects the ready pods of the two statefulsets.
+	// +optional
+	CutoverOnRecreate bool `json:"cutoverOnRecreate,omitempty"`
+
+	// ConnectionDraining config the service stops sending new connections to the pods that will be removed, and the existing connections drain before the pods removed.
+	// the pods removed by scaling in or recreating the statefulset are taken out of the service endpoints first, the backends dropped or decommissioned after the timeout.
+	// +optional
+	ConnectionDraining *ConnectionDraining `json:"connectionDraining,omitempty"`
+
+	// ScaleWebhooks are the urls that operator POSTs the scale notifications of compute group to, before the scale started and after it finished.
+	// the payload describes the compute group, the old and new replicas and the outcome, ep: notify a data catalog or cost tracker.
+	// the failed notifications are retried in the later reconciles, the scale operation never waits the notifications.
+	// +optional
+	ScaleWebhooks []string `json:"scaleWebhooks,omitempty"`
+
+	// BackendTags are the tags set on every backend of compute group in fe by `ALTER SYSTEM MODIFY BACKEND`, the key is the tag name without `tag.` prefix, ep: `location`.
+	// the tags managed by fe(compute_group_name, compute_group_id, cloud_unique_id...) can not be set. the tags modified by sql are set back in reconciling.
+	// the key consists of lowercase letters, digits and '_', and not starts with a digit.
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^[a-z_][a-z0-9_]*$'))",message="the keys of backendTags must match ^[a-z_][a-z0-9_]*$"
+	// +optional
+	BackendTags map[string]string `json:"backendTags,omitempty"`
+
+	// PreserveManualBackendTags only set the backendTags that missing on backends, the values modified by sql are kept, for managing the tags manually.
+	// Default value is 'false', the values differ from backendTags are set back.
+	// +optional
+	PreserveManualBackendTags bool `json:"preserveManualBackendTags,omitempty"`
+
+	// InitSQL references the configmap that contains the sql statements run once when the compute group first ready, ep: grant the usage of compute group.
+	// the statements run in order by the operation user, the edited configmap(resourceVersion changed) runs them again.
+	// +optional
+	InitSQL *InitSQL `json:"initSQL,omitempty"`
+
+	// Cordoned stops routing new queries to the compute group for maintenance, the backends are set `disable_query` in fe.
+	// the backends are kept alive and the cache warm, the statefulset not scaled. set false to restore the queries.
+	// +optional
+	Cordoned bool `json:"cordoned,omitempty"`
+
+	// RequireBackendsAlive marks the compute group Ready only when the backends of all replicas are alive in fe, not only the pods ready.
+	// the backend registered but unreachable from fe(ep: blocked by network policy) keeps the compute group not Ready.
+	// the compute group is `BackendsRegistering` when the pods ready but the alive backends less than replicas, or the backends can not be queried from fe.
+	// +optional
+	RequireBackendsAlive bool `json:"requireBackendsAlive,omitempty"`
+
+	// ReadyDeadlineSeconds is the seconds waiting any pod of compute group ready, counted from the creation of statefulset or the pods.
+	// the compute group without ready pod beyond the deadline is in `Stuck` phase, the event tells the likely cause from the pods. default is 1800, 0 disables it.
+	// +optional
+	ReadyDeadlineSeconds *int32 `json:"readyDeadlineSeconds,omitempty"`
+
+	// ReadyThreshold is the available pods(a number or a percentage of replicas, ep: `80%`) that the compute group is counted serving, the percentage rounded up.
+	// the compute group that available pods reach it but not all is in `PartiallyReady` phase. not set requires all pods available, the compute group is `Ready` only then.
+	// +optional
+	ReadyThreshold *intstr.IntOrString `json:"readyThreshold,omitempty"`
+
+	// CapacityReservation holds the headroom of nodes for scaling out the compute group quickly, by the low priority placeholder pods sized to the be.
+	// the placeholder pods run in a deployment, they are preempted by the pods of compute group when it scales out, the preempted placeholders pending until nodes added.
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+}
+
+// CapacityReservation describe the placeholder pods that reserve capacity for compute group.
+type CapacityReservation struct {
+	// Replicas is the number of placeholder pods, every placeholder requests the resources of one be.
+	Replicas int32 `json:"replicas"`
+
+	// PriorityClassName is the priority class of placeholder pods, the priority should be lower than the pods of compute group so that they are preempted, ep: a class with value -10.
+	PriorityClassName string `json:"priorityClassName"`
+
+	// Image is the image of placeholder container, default is `registry.k8s.io/pause:3.9`.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// InitSQL describe the sql statements in configmap, the statements are separated by `;`.
+type InitSQL struct {
+	// ConfigMapName is the name of configmap in the namespace of cluster.
+	ConfigMapName string `json:"configMapName"`
+
+	// Key is the key of sql statements in configmap data, default is `init.sql`.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ConnectionDraining describe how long the existing connections of the removing pods drain.
+type ConnectionDraining struct {
+	// TimeoutSeconds is the seconds waiting the existing connections drained after the pods taken out of the service endpoints.
+	// the terminationGracePeriodSeconds of pods should not less than it when the pods serve long queries.
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// AutoRollback describe when the failed upgrade of compute group is rolled back.
+type AutoRollback struct {
+	// RestartThreshold is the number of restarts of the compute container in an upgraded pod that considered the upgrade failed, default is 3.
+	// +optional
+	RestartThreshold int32 `json:"restartThreshold,omitempty"`
+}
+
+// LogRotation describe how the be logs rolled and how long the log files kept.
+type LogRotation struct {
+	// RollSizeMB is the size of be log file rolled in MB, operator injects env `BE_SYS_LOG_ROLL_MODE` with value `SIZE-MB-{rollSizeMB}` into be container.
+	// reference it in be.conf as `sys_log_roll_mode = ${BE_SYS_LOG_ROLL_MODE}`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollSizeMB int32 `json:"rollSizeMB,omitempty"`
+
+	// RollNum is the number of rolled log files kept for every log level, operator injects env `BE_SYS_LOG_ROLL_NUM` into be container.
+	// reference it in be.conf as `sys_log_roll_num = ${BE_SYS_LOG_ROLL_NUM}`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollNum int32 `json:"rollNum,omitempty"`
+
+	// RetentionDays is the days that the files in log volume kept, a sidecar container deletes the files modified before retention days every hour.
+	// the files not rolled by be, ep: `be.out`, `be.gc.log`, are also recycled. only works when the log volume is a pvc, 0 means not recycle.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+}
+
+// ComputeGroupTenants describe the users and roles that use the compute group.
+type ComputeGroupTenants struct {
+	// Users are the names of doris users, the queries of users are routed to the compute group by default.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Roles are the names of doris roles that granted the usage of compute group.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+}
+
+// RackAwareness describe how to spread the pods of compute group across racks.
+type RackAwareness struct {
+	// TopologyKey is the label key of node that represents rack, ep: `topology.kubernetes.io/rack`.
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew describes the degree to which pods may be unevenly distributed between racks, default is 1.
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy the spread constraint, default is `ScheduleAnyway`.
+	// `DoNotSchedule` forces the pods strictly spread across racks.
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// NodePoolReplicas describe the replicas of compute group relative to the schedulable nodes in a node pool.
+type NodePoolReplicas struct {
+	// NodeSelector select the nodes of pool by labels. if not set, use the nodeSelector of compute group.
+	// if both are empty, all nodes in kubernetes are the pool.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Percentage of the schedulable nodes in pool, the resolved replicas is rounded up.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percentage int32 `json:"percentage"`
+
+	// MinReplicas is the lower limit of the resolved replicas, default is 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the resolved replicas, not limited when not set.
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// ExternalMetricReplicas describe how the replicas of compute group resolved from an external metric.
+type ExternalMetricReplicas struct {
+	// MetricName is the name of metric in external metrics api `external.metrics.k8s.io/v1beta1`, the metric is read in the namespace of cluster.
+	MetricName string `json:"metricName"`
+
+	// MetricSelector select the series of metric by labels, the values of all selected series are summed.
+	MetricSelector map[string]string `json:"metricSelector,omitempty"`
+
+	// TargetAverageValue is the metric value that one replica handles, the resolved replicas is the metric value divided by it and rounded up.
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+
+	// MinReplicas is the lower limit of the resolved replicas, default is 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the resolved replicas, not limited when not set.
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// StabilizationWindowSeconds is the seconds that the highest resolved replicas in it is used, avoid the replicas flapping by the metric fluctuation.
+	// the scale up applies immediately, the scale down applies after the higher replicas out of window. default is 300.
+	// +kubebuilder:validation:Minimum=0
+	StabilizationWindowSeconds *int32 `json:"stabilizationWindowSeconds,omitempty"`
+}
+
+// ReplicaRange describe the preferred replicas of compute group and the acceptable minimum for cost-based scheduling.
+type ReplicaRange struct {
+	// Preferred is the replicas that compute group runs with when the nodes have capacity.
+	// +kubebuilder:validation:Minimum=1
+	Preferred int32 `json:"preferred"`
+
+	// Min is the acceptable minimum replicas that compute group reduced to when pods unschedulable, should not be greater than preferred.
+	// +kubebuilder:validation:Minimum=0
+	Min int32 `json:"min"`
 }
 
 type CommonSpec struct {
@@ -184,7 +673,8 @@ type CommonSpec struct {
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
-	//serviceAccount for compute node access cloud service.
+	//serviceAccount for compute node access cloud service, ep: the workload identity(IRSA, GKE Workload Identity) accessing object storage.
+	//the serviceAccount should exist in the namespace of cluster, changing it rolls the pods.
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 
 	// HostAliases is an optional list of hosts and IPs that will be injected into the pod's hosts
@@ -193,6 +683,8 @@ type CommonSpec struct {
 	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
 
 	//Security context for pod.
+	//for compute group, when runAsUser is non-root the fsGroup defaults to runAsGroup(or runAsUser) and fsGroupChangePolicy defaults to OnRootMismatch for the pvcs writable.
+	//the operator warns by event when the pods would be rejected by the pod security standard enforced on namespace.
 	//+optional
 	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
 
@@ -312,6 +804,50 @@ type DorisDisaggregatedClusterStatus struct {
 
 	//is the most recent generation observed for DorisDisaggregatedCluster
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	//Conditions represent the latest observations of cluster, ep: the fe metadata unhealthy.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	//Profile is the name of profile applied to compute groups in the last reconcile, empty when the base values used.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	//Plan is the actions that reconcile intends to do when the cluster annotated with reconcile mode `plan`, the actions are not executed.
+	// +optional
+	Plan *ReconcilePlan `json:"plan,omitempty"`
+}
+
+// ReconcilePlan describe the actions computed by the last reconcile in plan mode.
+type ReconcilePlan struct {
+	//ObservedGeneration is the generation of cluster that the plan computed from.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	//PlanTime is the time that the plan computed.
+	PlanTime metav1.Time `json:"planTime,omitempty"`
+
+	//Actions is the intended actions in the order of reconciling.
+	// +optional
+	Actions []PlannedAction `json:"actions,omitempty"`
+}
+
+// PlannedAction describe an action that reconcile intends to do on kubernetes resources or in fe by sql.
+type PlannedAction struct {
+	//Verb is the action, one of `Create`, `Update`, `Patch`, `Delete`, `DeleteAllOf`, `UpdateStatus`, `PatchStatus` for resources, `Exec` for sql.
+	Verb string `json:"verb"`
+
+	//Kind is the kind of resource, `SQL` for the statements executed in fe.
+	Kind string `json:"kind"`
+
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	//Statement is the sql statement of `Exec`.
+	// +optional
+	Statement string `json:"statement,omitempty"`
 }
 
 type MetaServiceStatus struct {
@@ -347,6 +883,10 @@ type ClusterHealth struct {
 	CGAvailableCount int32 `json:"cgAvailableCount,omitempty"`
 	//the full available numbers of compute group, represents all pod in compute group are ready.
 	CGFullAvailableCount int32 `json:"cgFullAvailableCount,omitempty"`
+	//the expected number of backends, the sum of replicas of compute groups.
+	ExpectedBackends int32 `json:"expectedBackends,omitempty"`
+	//the number of alive backends registered in fe, the sum of alive backends of compute groups.
+	AliveBackends int32 `json:"aliveBackends,omitempty"`
 }
 
 type Phase string
@@ -363,6 +903,26 @@ const (
 	ResumeFailed    Phase = "ResumeFailed"
 	SuspendFailed   Phase = "SuspendFailed"
 	Suspended       Phase = "Suspended"
+	//Removing represents the compute group removed from spec and the resources are cleaning.
+	Removing Phase = "Removing"
+	//ScaleDownBlocked represents the scale down stopped after sql failed continuously, reset after cooldown or by annotation.
+	ScaleDownBlocked Phase = "ScaleDownBlocked"
+	//QuiescingForFE represents the scale operation of compute group paused until fe restarting finished.
+	QuiescingForFE Phase = "QuiescingForFE"
+	//CuttingOver represents the statefulset of compute group is recreating, the temporary statefulset serves in the gap.
+	CuttingOver Phase = "CuttingOver"
+	//WaitingStorageVault represents the compute group not created until the storage vault configured in fe.
+	WaitingStorageVault Phase = "WaitingStorageVault"
+	//AwaitingStorage represents the pods of compute group pending for the pvcs not bound, the storage provisioning delays the compute group ready.
+	AwaitingStorage Phase = "AwaitingStorage"
+	//Stuck represents the compute group have no ready pod beyond the ready deadline, the pods need manual intervention.
+	Stuck Phase = "Stuck"
+	//HeldDegraded represents most pods of compute group crash looping, the statefulset updates are held to avoid the restart storm until the spec changed or released by annotation.
+	HeldDegraded Phase = "Degraded"
+	//PartiallyReady represents the available pods of compute group reach the readyThreshold but not all, the compute group serves with less backends.
+	PartiallyReady Phase = "PartiallyReady"
+	//BackendsRegistering represents all pods of compute group ready but the backends not all registered and alive in fe, only when requireBackendsAlive configured.
+	BackendsRegistering Phase = "BackendsRegistering"
 )
 
 type AvailableStatus string
@@ -398,13 +958,325 @@ type ComputeGroupStatus struct {
 	SuspendReplicas int32 `json:"suspendReplicas,omitempty"`
 
 	// replicas is the number of Pods created by the StatefulSet controller.
+	// when nodePoolReplicas is configured, it is the replicas resolved from node pool.
 	Replicas int32 `json:"replicas,omitempty"`
 
 	// Total number of available pods (ready for at least minReadySeconds) targeted by this statefulset.
 	// +optional
 	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// AliveBackends is the number of alive backends of the compute group registered in fe.
+	// +optional
+	AliveBackends int32 `json:"aliveBackends,omitempty"`
+
+	// Conditions represent the latest observations of compute group, ep: the ready pods are consistent with the alive backends in fe or not.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// UsageSamples is a small rolling window of the aggregated resource usage of compute group, the newest sample is the last.
+	// it is a quick capacity planning signal, please use the monitoring system for accurate metrics.
+	// +optional
+	UsageSamples []UsageSample `json:"usageSamples,omitempty"`
+
+	// ExternalMetric is the value of external metric that drives the replicas of compute group, and the replicas resolved from it.
+	// +optional
+	ExternalMetric *ExternalMetricStatus `json:"externalMetric,omitempty"`
+
+	// ReplicaRange is the effective replicas resolved from the replicaRange of compute group, and the reason when it is reduced from preferred.
+	// +optional
+	ReplicaRange *ReplicaRangeStatus `json:"replicaRange,omitempty"`
+
+	// SwappedTo is the uniqueId of compute group that the service of this compute group repointed to.
+	// +optional
+	SwappedTo string `json:"swappedTo,omitempty"`
+
+	// ScaleDownSqlFailures is the number of consecutive sql failures when dropping or decommissioning backends in scaling down.
+	// +optional
+	ScaleDownSqlFailures int32 `json:"scaleDownSqlFailures,omitempty"`
+
+	// LastScaleDownSqlFailureTime is the time of the last sql failure in scaling down.
+	// +optional
+	LastScaleDownSqlFailureTime *metav1.Time `json:"lastScaleDownSqlFailureTime,omitempty"`
+
+	// DecommissionStartTime is the time of starting decommission backends in scaling down, cleared when the decommission finished.
+	// +optional
+	DecommissionStartTime *metav1.Time `json:"decommissionStartTime,omitempty"`
+
+	// CacheWarmupStartTime is the time of starting waiting the kept backends warm the cache in scaling down with the ColdestCache policy.
+	// +optional
+	CacheWarmupStartTime *metav1.Time `json:"cacheWarmupStartTime,omitempty"`
+
+	// CacheWarmupBackends are the backends(host:heartbeatPort) that queries disabled for warming the cache of kept backends, the queries are enabled again when the scale down canceled.
+	// +optional
+	CacheWarmupBackends []string `json:"cacheWarmupBackends,omitempty"`
+
+	// DroppedBackends is the number of backends dropped by the scale down in progress, reported and reset when the scale down succeeded.
+	// +optional
+	DroppedBackends int32 `json:"droppedBackends,omitempty"`
+
+	// RemainingPVCs are the pvcs of the removing compute group that not confirmed deleted, the status of compute group kept until they are gone.
+	// +optional
+	RemainingPVCs []string `json:"remainingPVCs,omitempty"`
+
+	// RunningVersions is the doris versions parsed from the images of compute group pods running, more than one in rolling upgrade.
+	// +optional
+	RunningVersions []string `json:"runningVersions,omitempty"`
+
+	// PendingScaleInSnapshots is the number of pvcs of scaled in pods that waiting the VolumeSnapshots ready before deleting.
+	// +optional
+	PendingScaleInSnapshots int32 `json:"pendingScaleInSnapshots,omitempty"`
+
+	// LastScaleTime is the time of the last scale operation applied on the statefulset of compute group.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// ScaleDeferredUntil is the time that the scale operation deferred by cooldown will be applied.
+	// +optional
+	ScaleDeferredUntil *metav1.Time `json:"scaleDeferredUntil,omitempty"`
+
+	// MergedConfigMapName is the configmap that contains the merged config of baseConfigMap and configMaps.
+	// +optional
+	MergedConfigMapName string `json:"mergedConfigMapName,omitempty"`
+
+	// LabelSchemeVersion is the version of labels on the pods and pvcs of compute group, the resources labeled by old operator are relabeled once when it is older than the operator's.
+	// +optional
+	LabelSchemeVersion int32 `json:"labelSchemeVersion,omitempty"`
+
+	// BoundUsers are the users that granted the usage of compute group and use it as default compute group.
+	// +optional
+	BoundUsers []string `json:"boundUsers,omitempty"`
+
+	// BoundRoles are the roles that granted the usage of compute group.
+	// +optional
+	BoundRoles []string `json:"boundRoles,omitempty"`
+
+	// AppliedMemoryLimit is the memoryLimit that applied to the workload group of compute group in fe.
+	// +optional
+	AppliedMemoryLimit string `json:"appliedMemoryLimit,omitempty"`
+
+	// ReregisteringBackends are the pods that added as backends by the reregister-backends annotation, waiting confirmed registered in fe.
+	// +optional
+	ReregisteringBackends []string `json:"reregisteringBackends,omitempty"`
+
+	// LastKnownGoodImage is the image of compute group that all pods were ready with, the failed upgrade is rolled back to it.
+	// +optional
+	LastKnownGoodImage string `json:"lastKnownGoodImage,omitempty"`
+
+	// RolledBackImage is the image that upgraded failed and rolled back, the statefulset uses lastKnownGoodImage until the image in spec changed.
+	// +optional
+	RolledBackImage string `json:"rolledBackImage,omitempty"`
+
+	// CutoverStatefulsetName is the temporary statefulset that serves when recreating the statefulset of compute group.
+	// +optional
+	CutoverStatefulsetName string `json:"cutoverStatefulsetName,omitempty"`
+
+	// HeldGeneration is the generation of cluster when the statefulset updates held for most pods crash looping, the newer generation releases the hold.
+	// +optional
+	HeldGeneration int64 `json:"heldGeneration,omitempty"`
+
+	// DrainingPods is the pods taken out of the service endpoints for draining connections before removed.
+	// +optional
+	DrainingPods []string `json:"drainingPods,omitempty"`
+
+	// DrainingStartTime is the time that the draining pods taken out of the service endpoints.
+	// +optional
+	DrainingStartTime *metav1.Time `json:"drainingStartTime,omitempty"`
+
+	// GeneratedSummary is the summary of the generated statefulset and service, only recorded when cluster annotated `doris.disaggregated.cluster/debug-generated: "true"`.
+	// +optional
+	GeneratedSummary string `json:"generatedSummary,omitempty"`
+
+	// InitSQL is the execution of initSQL statements.
+	// +optional
+	InitSQL *InitSQLStatus `json:"initSQL,omitempty"`
+
+	// ScaleOperation is the last scale operation of compute group that notified to scaleWebhooks.
+	// +optional
+	ScaleOperation *ScaleOperation `json:"scaleOperation,omitempty"`
+
+	// PendingScaleNotifications are the scale notifications failed to deliver to scaleWebhooks, retried in the later reconciles.
+	// +optional
+	PendingScaleNotifications []ScaleNotification `json:"pendingScaleNotifications,omitempty"`
 }
 
+// InitSQLStatus record the statements of initSQL executed, the execution restarts when the resourceVersion of configmap changed.
+type InitSQLStatus struct {
+	// ResourceVersion is the resourceVersion of configmap that the statements read from.
+	ResourceVersion string `json:"resourceVersion"`
+
+	// TotalStatements is the number of statements in configmap.
+	TotalStatements int32 `json:"totalStatements"`
+
+	// ExecutedStatements is the number of statements executed successfully in order, the execution retries from the next one.
+	ExecutedStatements int32 `json:"executedStatements"`
+
+	// LastError is the error of the statement failed, ep: `statement 2 failed: ...`.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ScaleOperation describe a scale operation of compute group, the outcome is empty when the scale in progress.
+type ScaleOperation struct {
+	OldReplicas int32       `json:"oldReplicas"`
+	NewReplicas int32       `json:"newReplicas"`
+	StartTime   metav1.Time `json:"startTime"`
+	// +optional
+	Outcome ScaleOutcome `json:"outcome,omitempty"`
+}
+
+// ScaleNotification is a notification of scale operation posted to a scale webhook.
+type ScaleNotification struct {
+	URL         string     `json:"url"`
+	Stage       ScaleStage `json:"stage"`
+	OldReplicas int32      `json:"oldReplicas"`
+	NewReplicas int32      `json:"newReplicas"`
+	// +optional
+	Outcome ScaleOutcome `json:"outcome,omitempty"`
+	// Time is the time that the notification generated, not changed by retrying.
+	Time metav1.Time `json:"time"`
+	// Attempts is the number of failed deliveries.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+type ScaleStage string
+
+const (
+	BeforeScale ScaleStage = "Before"
+	AfterScale  ScaleStage = "After"
+)
+
+type ScaleOutcome string
+
+const (
+	ScaleSucceeded ScaleOutcome = "Succeeded"
+	// ScaleFailed represents the scale down blocked by the continuous sql failures.
+	ScaleFailed ScaleOutcome = "Failed"
+	// ScaleSuperseded represents the replicas changed again before the scale finished.
+	ScaleSuperseded ScaleOutcome = "Superseded"
+)
+
+// ReplicaRangeStatus record the effective replicas of compute group that configured replicaRange.
+type ReplicaRangeStatus struct {
+	// EffectiveReplicas is the replicas that compute group runs with, between the min and preferred of replicaRange.
+	EffectiveReplicas int32 `json:"effectiveReplicas"`
+
+	// Reason is why the effective replicas reduced from preferred, empty when running with preferred.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ExternalMetricStatus describe the last value of external metric and the replicas resolved in stabilization window.
+type ExternalMetricStatus struct {
+	// MetricName is the name of metric in external metrics api.
+	MetricName string `json:"metricName,omitempty"`
+
+	// Value is the summed value of metric at LastReadTime.
+	Value string `json:"value,omitempty"`
+
+	// LastReadTime is the time that metric read successfully.
+	LastReadTime *metav1.Time `json:"lastReadTime,omitempty"`
+
+	// DesiredReplicas is the replicas applied after stabilization and clamped by min and max.
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// Recommendations is the replicas resolved from metric in the stabilization window, the oldest is the first.
+	// +optional
+	Recommendations []ReplicasRecommendation `json:"recommendations,omitempty"`
+}
+
+// ReplicasRecommendation is the replicas resolved from metric at a time.
+type ReplicasRecommendation struct {
+	Time     metav1.Time `json:"time"`
+	Replicas int32       `json:"replicas"`
+}
+
+// UsageSample is the aggregated resource usage of all pods in compute group at a time.
+type UsageSample struct {
+	// the time of sampling.
+	Time metav1.Time `json:"time"`
+	// the sum of cpu usage of compute group pods, empty when the metrics api is not available.
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+	// the sum of memory usage of compute group pods, empty when the metrics api is not available.
+	// +optional
+	Memory string `json:"memory,omitempty"`
+	// the average used percent of cache disks that reported by backends in fe.
+	// +optional
+	CacheUsedPercent int32 `json:"cacheUsedPercent,omitempty"`
+}
+
+const (
+	// BackendsConsistent is the condition type that represents the ready pods of compute group are consistent with the alive backends registered in fe.
+	BackendsConsistent string = "BackendsConsistent"
+
+	// condition reasons for BackendsConsistent.
+	BackendsMatched           string = "BackendsMatched"
+	PodsReadyBackendsNotAlive string = "PodsReadyBackendsNotAlive"
+	BackendsAlivePodsNotReady string = "BackendsAlivePodsNotReady"
+
+	// PVCBindFailed is the condition type that represents the compute group degraded by the pvcs pending beyond timeout, the storage(storageClass or persistent volumes) is the root cause of pods not ready.
+	PVCBindFailed string = "PVCBindFailed"
+
+	// condition reasons for PVCBindFailed.
+	PVCsBound          string = "PVCsBound"
+	PVCsPendingTimeout string = "PVCsPendingTimeout"
+
+	// FEMetadataUnhealthy is the condition type of cluster that represents fe reports inconsistent metadata, ep: multiple masters, duplicate backend ids.
+	// operator stops dropping and decommissioning nodes when it is true, for not making changes on top of a corrupted fe state.
+	FEMetadataUnhealthy string = "FEMetadataUnhealthy"
+
+	// condition reasons for FEMetadataUnhealthy.
+	FEMetadataConsistent   string = "FEMetadataConsistent"
+	FEMetadataInconsistent string = "FEMetadataInconsistent"
+
+	// VersionSkewExceeded is the condition type of cluster that represents the compute groups running doris versions beyond the window of versionSkew.
+	// the status is Unknown when compute groups running different versions in the window, ep: in a staged upgrade.
+	VersionSkewExceeded string = "VersionSkewExceeded"
+
+	// condition reasons for VersionSkewExceeded.
+	VersionsConsistent       string = "VersionsConsistent"
+	VersionsMixed            string = "VersionsMixed"
+	MinorVersionSkewExceeded string = "MinorVersionSkewExceeded"
+	MixedVersionsTooLong     string = "MixedVersionsTooLong"
+
+	// NoBackendsRegistered is the condition type that represents the compute group have ready pods but no alive backends in fe after a grace period.
+	// the compute group looks healthy by pods, but can not serve queries. the status is Unknown in the grace period.
+	NoBackendsRegistered string = "NoBackendsRegistered"
+
+	// condition reasons for NoBackendsRegistered.
+	BackendsRegistered         string = "BackendsRegistered"
+	WaitingBackendsRegistered  string = "WaitingBackendsRegistered"
+	BackendsNotRegistered      string = "BackendsNotRegistered"
+	BackendsRegisteredNotAlive string = "BackendsRegisteredNotAlive"
+
+	// BackendsUnreachable is the condition type that represents some backends of compute group registered in fe but not alive beyond a grace period.
+	// fe can not reach the backends by heartbeat, the likely cause is network policy or firewall. the status is Unknown in the grace period.
+	BackendsUnreachable string = "BackendsUnreachable"
+
+	// condition reasons for BackendsUnreachable.
+	RegisteredBackendsAlive    string = "RegisteredBackendsAlive"
+	WaitingBackendsAlive       string = "WaitingBackendsAlive"
+	RegisteredBackendsNotAlive string = "RegisteredBackendsNotAlive"
+
+	// Degraded is the condition type that represents the compute group have pods crash looping, the pods are counted as failed not creating.
+	Degraded string = "Degraded"
+
+	// condition reasons for Degraded.
+	PodsNotCrashLooping string = "PodsNotCrashLooping"
+	PodsCrashLooping    string = "PodsCrashLooping"
+
+	// Cordoned is the condition type that represents the backends of compute group are disabled query in fe by the cordoned of spec.
+	// the condition removed after the queries of backends restored by uncordoning.
+	Cordoned string = "Cordoned"
+
+	// condition reasons for Cordoned.
+	ComputeGroupCordoned    string = "ComputeGroupCordoned"
+	ComputeGroupUncordoning string = "ComputeGroupUncordoning"
+)
+
 type FEStatus struct {
 	//Phase represent the stage of reconciling.
 	Phase Phase `json:"phase,omitempty"`
@@ -413,6 +1285,17 @@ type FEStatus struct {
 	//ClusterId display  the clusterId of fe in fe.conf,
 	//It is the hash value of the concatenated string of namespace and ddcName
 	ClusterId string `json:"clusterId,omitempty"`
+	//Master is the fe master node that operator discovered by `show frontends` when reconciling, the admin sql(drop or decommission nodes) executed on it.
+	//empty means fe not available or the master not discovered.
+	Master *FEMasterNode `json:"master,omitempty"`
+}
+
+// FEMasterNode describe the fe master node in fe cluster.
+type FEMasterNode struct {
+	//Host is the ip or fqdn of master registered in fe cluster.
+	Host string `json:"host,omitempty"`
+	//Role is the role of master in fe cluster.
+	Role string `json:"role,omitempty"`
 }
 
 // +genclient
@@ -20,6 +20,7 @@ package v1
 import (
     "crypto/sha256"
     "math/big"
+    "strconv"
     "strings"
 )
 
@@ -76,10 +77,33 @@ func (ddc *DorisDisaggregatedCluster) GetFEInternalServiceName() string {
 	return ddc.Name + "-" + "fe-internal"
 }
 
+// GetFEPodAddresses return the addresses of fe pods resolved by the internal service, the same as the fe pods registered in fe.
+func (ddc *DorisDisaggregatedCluster) GetFEPodAddresses() []string {
+	replicas := DefaultFeReplicaNumber
+	if ddc.Spec.FeSpec.Replicas != nil {
+		replicas = *ddc.Spec.FeSpec.Replicas
+	}
+	var addrs []string
+	for i := int32(0); i < replicas; i++ {
+		addrs = append(addrs, ddc.GetFEStatefulsetName()+"-"+strconv.Itoa(int(i))+"."+ddc.GetFEInternalServiceName()+"."+ddc.Namespace)
+	}
+	return addrs
+}
+
 func (ddc *DorisDisaggregatedCluster) GetMSServiceName() string {
 	return ddc.Name + "-" + "ms"
 }
 
+// the configmap that records the compute groups of cluster for client applications discovering.
+func (ddc *DorisDisaggregatedCluster) GetCGDiscoveryConfigMapName() string {
+	return ddc.Name + "-" + "compute-groups"
+}
+
+// the configmap that merged the base configmap and configmaps of compute group.
+func (ddc *DorisDisaggregatedCluster) GetCGMergedConfigMapName(cg *ComputeGroup) string {
+	return ddc.GetCGStatefulsetName(cg) + "-merged-config"
+}
+
 //the first deployed used computegroup name, when user rename the compute group name by sql command `ALTER SYSTEM RENAME COMPUTE GROUP <old_name> <new_name>`, this function will not right.
 func (ddc *DorisDisaggregatedCluster) GetCGName(cg *ComputeGroup) string {
 	// use uniqueId as compute group name, the uniqueId restrict not empty, and the computegroup's name should use "_" not "-"
@@ -17,6 +17,8 @@
 
 package v1
 
+import "k8s.io/apimachinery/pkg/api/meta"
+
 const (
 	DorisDisaggregatedClusterName string = "app.doris.disaggregated.cluster"
 
@@ -27,6 +29,18 @@ const (
 
 	DorisDisaggregatedPodType string = "app.doris.disaggregated.type"
 
+	//the doris version of pods parsed from image tag, only for monitoring not used in selector.
+	DorisDisaggregatedVersion string = "app.doris.disaggregated.version"
+
+	//the pods of temporary statefulset that serves when recreating the statefulset of compute group.
+	DorisDisaggregatedCutover string = "app.doris.disaggregated.cutover"
+
+	//the pods of compute group selected by service when the connection draining configured, the draining pods have value `false`.
+	DorisDisaggregatedServing string = "app.doris.disaggregated.serving"
+
+	//the placeholder pods that reserve capacity for compute group, the value is the statefulset name of compute group. the placeholders are not the pods of compute group.
+	DorisDisaggregatedCapacityReservation string = "app.doris.disaggregated.reservation"
+
 	DisaggregatedSpecHashValueAnnotation string = "doris.disaggregated.cluster/hash"
 
 	ServiceRoleForCluster string = "app.doris.service/role"
@@ -36,6 +50,36 @@ const (
 
 	//use uniqueId as indifier of which statefulset updated. value is the ddc updateVersion
 	UpdateStatefulsetName = "doris.disaggregated.cluster/%s"
+
+	//annotate on DorisDisaggregatedCluster to reset the blocked scale down of compute group, %s is the uniqueId. operator removes it after reset.
+	ResetScaleDownBlocked = "doris.disaggregated.cluster/reset-scaledown-blocked-%s"
+
+	//annotate on DorisDisaggregatedCluster to drop the listed backends of compute group, %s is the uniqueId. the value is comma separated pod ordinals or backend host:heartbeatPort.
+	//operator removes it after dropped.
+	DropBackends = "doris.disaggregated.cluster/drop-backends-%s"
+
+	//annotate on DorisDisaggregatedCluster to release the held statefulset updates of compute group that most pods crash looping, %s is the uniqueId. operator removes it after released.
+	ReleaseDegradedHold = "doris.disaggregated.cluster/release-degraded-hold-%s"
+
+	//annotate on DorisDisaggregatedCluster to register the running pods of compute group that have no backend in fe as backends, %s is the uniqueId. used after fe metadata restored from backup.
+	//operator removes it after all pods registered.
+	ReregisterBackends = "doris.disaggregated.cluster/reregister-backends-%s"
+
+	//annotate on DorisDisaggregatedCluster with value `true` to record the summary of the generated statefulset and service of compute groups in status and events,
+	//used to inspect what operator generated without the access to statefulsets.
+	DebugGenerated = "doris.disaggregated.cluster/debug-generated"
+
+	//annotate on DorisDisaggregatedCluster to select the reconcile mode, the value `plan` computes the intended actions into status.plan without executing them.
+	//used to validate the reconcile of an upgraded operator against the existing clusters before enabling live reconciling.
+	ReconcileMode     = "doris.disaggregated.cluster/reconcile-mode"
+	ReconcileModePlan = "plan"
+
+	//annotate on DorisDisaggregatedCluster to select the profile that overrides the compute groups, takes precedence over the `--profile` flag of operator.
+	ProfileAnnotation = "doris.disaggregated.cluster/profile"
+
+	//annotate on DorisDisaggregatedCluster with value `true` to skip validating the cpu and memory requests of compute groups against the allocatable of nodes,
+	//used when the nodes are provisioned by cluster autoscaler for the pending pods.
+	SkipResourceValidation = "doris.disaggregated.cluster/skip-resource-validation"
 )
 
 type DisaggregatedComponentType string
@@ -65,3 +109,13 @@ func (ddc *DorisDisaggregatedCluster) GetElectionNumber() int32 {
 	}
 	return DefaultDisFeElectionNumber
 }
+
+// IsFEMetadataUnhealthy return true when the condition FEMetadataUnhealthy is true, the dropping and decommissioning nodes should be stopped.
+func (ddc *DorisDisaggregatedCluster) IsFEMetadataUnhealthy() bool {
+	return meta.IsStatusConditionTrue(ddc.Status.Conditions, FEMetadataUnhealthy)
+}
+
+// IsPlanMode return true when the cluster annotated with reconcile mode `plan`, the reconcile only records the intended actions.
+func (ddc *DorisDisaggregatedCluster) IsPlanMode() bool {
+	return ddc.Annotations[ReconcileMode] == ReconcileModePlan
+}
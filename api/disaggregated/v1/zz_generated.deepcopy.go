@@ -23,7 +23,9 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -41,6 +43,36 @@ func (in *AdminUser) DeepCopy() *AdminUser {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRollback) DeepCopyInto(out *AutoRollback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRollback.
+func (in *AutoRollback) DeepCopy() *AutoRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservation) DeepCopyInto(out *CapacityReservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservation.
+func (in *CapacityReservation) DeepCopy() *CapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
 	*out = *in
@@ -175,6 +207,108 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 func (in *ComputeGroup) DeepCopyInto(out *ComputeGroup) {
 	*out = *in
 	in.CommonSpec.DeepCopyInto(&out.CommonSpec)
+	if in.NodePoolReplicas != nil {
+		in, out := &in.NodePoolReplicas, &out.NodePoolReplicas
+		*out = new(NodePoolReplicas)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalMetricReplicas != nil {
+		in, out := &in.ExternalMetricReplicas, &out.ExternalMetricReplicas
+		*out = new(ExternalMetricReplicas)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaRange != nil {
+		in, out := &in.ReplicaRange, &out.ReplicaRange
+		*out = new(ReplicaRange)
+		**out = **in
+	}
+	if in.RackAwareness != nil {
+		in, out := &in.RackAwareness, &out.RackAwareness
+		*out = new(RackAwareness)
+		**out = **in
+	}
+	if in.ScaleCooldown != nil {
+		in, out := &in.ScaleCooldown, &out.ScaleCooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NodePoolScaleUpCooldown != nil {
+		in, out := &in.NodePoolScaleUpCooldown, &out.NodePoolScaleUpCooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = new(ComputeGroupTenants)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreservedPodAnnotationPrefixes != nil {
+		in, out := &in.PreservedPodAnnotationPrefixes, &out.PreservedPodAnnotationPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(AutoRollback)
+		**out = **in
+	}
+	if in.MaxScaleInPercentage != nil {
+		in, out := &in.MaxScaleInPercentage, &out.MaxScaleInPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DecommissionTimeout != nil {
+		in, out := &in.DecommissionTimeout, &out.DecommissionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CacheWarmupTimeout != nil {
+		in, out := &in.CacheWarmupTimeout, &out.CacheWarmupTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LogRotation != nil {
+		in, out := &in.LogRotation, &out.LogRotation
+		*out = new(LogRotation)
+		**out = **in
+	}
+	if in.ConnectionDraining != nil {
+		in, out := &in.ConnectionDraining, &out.ConnectionDraining
+		*out = new(ConnectionDraining)
+		**out = **in
+	}
+	if in.ScaleWebhooks != nil {
+		in, out := &in.ScaleWebhooks, &out.ScaleWebhooks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendTags != nil {
+		in, out := &in.BackendTags, &out.BackendTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InitSQL != nil {
+		in, out := &in.InitSQL, &out.InitSQL
+		*out = new(InitSQL)
+		**out = **in
+	}
+	if in.ReadyDeadlineSeconds != nil {
+		in, out := &in.ReadyDeadlineSeconds, &out.ReadyDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadyThreshold != nil {
+		in, out := &in.ReadyThreshold, &out.ReadyThreshold
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(CapacityReservation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeGroup.
@@ -187,9 +321,134 @@ func (in *ComputeGroup) DeepCopy() *ComputeGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeGroupOverride) DeepCopyInto(out *ComputeGroupOverride) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeGroupOverride.
+func (in *ComputeGroupOverride) DeepCopy() *ComputeGroupOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeGroupOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComputeGroupStatus) DeepCopyInto(out *ComputeGroupStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UsageSamples != nil {
+		in, out := &in.UsageSamples, &out.UsageSamples
+		*out = make([]UsageSample, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalMetric != nil {
+		in, out := &in.ExternalMetric, &out.ExternalMetric
+		*out = new(ExternalMetricStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaRange != nil {
+		in, out := &in.ReplicaRange, &out.ReplicaRange
+		*out = new(ReplicaRangeStatus)
+		**out = **in
+	}
+	if in.LastScaleDownSqlFailureTime != nil {
+		in, out := &in.LastScaleDownSqlFailureTime, &out.LastScaleDownSqlFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DecommissionStartTime != nil {
+		in, out := &in.DecommissionStartTime, &out.DecommissionStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CacheWarmupStartTime != nil {
+		in, out := &in.CacheWarmupStartTime, &out.CacheWarmupStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CacheWarmupBackends != nil {
+		in, out := &in.CacheWarmupBackends, &out.CacheWarmupBackends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemainingPVCs != nil {
+		in, out := &in.RemainingPVCs, &out.RemainingPVCs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunningVersions != nil {
+		in, out := &in.RunningVersions, &out.RunningVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ScaleDeferredUntil != nil {
+		in, out := &in.ScaleDeferredUntil, &out.ScaleDeferredUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.BoundUsers != nil {
+		in, out := &in.BoundUsers, &out.BoundUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BoundRoles != nil {
+		in, out := &in.BoundRoles, &out.BoundRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReregisteringBackends != nil {
+		in, out := &in.ReregisteringBackends, &out.ReregisteringBackends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DrainingPods != nil {
+		in, out := &in.DrainingPods, &out.DrainingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DrainingStartTime != nil {
+		in, out := &in.DrainingStartTime, &out.DrainingStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InitSQL != nil {
+		in, out := &in.InitSQL, &out.InitSQL
+		*out = new(InitSQLStatus)
+		**out = **in
+	}
+	if in.ScaleOperation != nil {
+		in, out := &in.ScaleOperation, &out.ScaleOperation
+		*out = new(ScaleOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingScaleNotifications != nil {
+		in, out := &in.PendingScaleNotifications, &out.PendingScaleNotifications
+		*out = make([]ScaleNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeGroupStatus.
@@ -202,6 +461,31 @@ func (in *ComputeGroupStatus) DeepCopy() *ComputeGroupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeGroupTenants) DeepCopyInto(out *ComputeGroupTenants) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeGroupTenants.
+func (in *ComputeGroupTenants) DeepCopy() *ComputeGroupTenants {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeGroupTenants)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMap) DeepCopyInto(out *ConfigMap) {
 	*out = *in
@@ -217,6 +501,21 @@ func (in *ConfigMap) DeepCopy() *ConfigMap {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDraining) DeepCopyInto(out *ConnectionDraining) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDraining.
+func (in *ConnectionDraining) DeepCopy() *ConnectionDraining {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDraining)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DorisDisaggregatedCluster) DeepCopyInto(out *DorisDisaggregatedCluster) {
 	*out = *in
@@ -244,6 +543,70 @@ func (in *DorisDisaggregatedCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DorisDisaggregatedClusterHistory) DeepCopyInto(out *DorisDisaggregatedClusterHistory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make([]HistoryRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DorisDisaggregatedClusterHistory.
+func (in *DorisDisaggregatedClusterHistory) DeepCopy() *DorisDisaggregatedClusterHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(DorisDisaggregatedClusterHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DorisDisaggregatedClusterHistory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DorisDisaggregatedClusterHistoryList) DeepCopyInto(out *DorisDisaggregatedClusterHistoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DorisDisaggregatedClusterHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DorisDisaggregatedClusterHistoryList.
+func (in *DorisDisaggregatedClusterHistoryList) DeepCopy() *DorisDisaggregatedClusterHistoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(DorisDisaggregatedClusterHistoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DorisDisaggregatedClusterHistoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DorisDisaggregatedClusterList) DeepCopyInto(out *DorisDisaggregatedClusterList) {
 	*out = *in
@@ -288,16 +651,43 @@ func (in *DorisDisaggregatedClusterSpec) DeepCopyInto(out *DorisDisaggregatedClu
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]Profile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.AdminUser != nil {
 		in, out := &in.AdminUser, &out.AdminUser
 		*out = new(AdminUser)
 		**out = **in
 	}
+	if in.OperationSecret != nil {
+		in, out := &in.OperationSecret, &out.OperationSecret
+		*out = new(OperationSecret)
+		**out = **in
+	}
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(TLSSecret)
+		**out = **in
+	}
 	if in.KerberosInfo != nil {
 		in, out := &in.KerberosInfo, &out.KerberosInfo
 		*out = new(KerberosInfo)
 		**out = **in
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(History)
+		**out = **in
+	}
+	if in.VersionSkew != nil {
+		in, out := &in.VersionSkew, &out.VersionSkew
+		*out = new(VersionSkew)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DorisDisaggregatedClusterSpec.
@@ -314,12 +704,26 @@ func (in *DorisDisaggregatedClusterSpec) DeepCopy() *DorisDisaggregatedClusterSp
 func (in *DorisDisaggregatedClusterStatus) DeepCopyInto(out *DorisDisaggregatedClusterStatus) {
 	*out = *in
 	out.MetaServiceStatus = in.MetaServiceStatus
-	out.FEStatus = in.FEStatus
+	in.FEStatus.DeepCopyInto(&out.FEStatus)
 	out.ClusterHealth = in.ClusterHealth
 	if in.ComputeGroupStatuses != nil {
 		in, out := &in.ComputeGroupStatuses, &out.ComputeGroupStatuses
 		*out = make([]ComputeGroupStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ReconcilePlan)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -361,26 +765,110 @@ func (in *ExportService) DeepCopy() *ExportService {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FDB) DeepCopyInto(out *FDB) {
+func (in *ExternalMetricReplicas) DeepCopyInto(out *ExternalMetricReplicas) {
 	*out = *in
-	out.ConfigMapNamespaceName = in.ConfigMapNamespaceName
+	if in.MetricSelector != nil {
+		in, out := &in.MetricSelector, &out.MetricSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.TargetAverageValue = in.TargetAverageValue.DeepCopy()
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StabilizationWindowSeconds != nil {
+		in, out := &in.StabilizationWindowSeconds, &out.StabilizationWindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FDB.
-func (in *FDB) DeepCopy() *FDB {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalMetricReplicas.
+func (in *ExternalMetricReplicas) DeepCopy() *ExternalMetricReplicas {
 	if in == nil {
 		return nil
 	}
-	out := new(FDB)
+	out := new(ExternalMetricReplicas)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FEStatus) DeepCopyInto(out *FEStatus) {
+func (in *ExternalMetricStatus) DeepCopyInto(out *ExternalMetricStatus) {
 	*out = *in
-}
-
+	if in.LastReadTime != nil {
+		in, out := &in.LastReadTime, &out.LastReadTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = make([]ReplicasRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalMetricStatus.
+func (in *ExternalMetricStatus) DeepCopy() *ExternalMetricStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalMetricStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FDB) DeepCopyInto(out *FDB) {
+	*out = *in
+	out.ConfigMapNamespaceName = in.ConfigMapNamespaceName
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FDB.
+func (in *FDB) DeepCopy() *FDB {
+	if in == nil {
+		return nil
+	}
+	out := new(FDB)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FEMasterNode) DeepCopyInto(out *FEMasterNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FEMasterNode.
+func (in *FEMasterNode) DeepCopy() *FEMasterNode {
+	if in == nil {
+		return nil
+	}
+	out := new(FEMasterNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FEStatus) DeepCopyInto(out *FEStatus) {
+	*out = *in
+	if in.Master != nil {
+		in, out := &in.Master, &out.Master
+		*out = new(FEMasterNode)
+		**out = **in
+	}
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FEStatus.
 func (in *FEStatus) DeepCopy() *FEStatus {
 	if in == nil {
@@ -412,6 +900,67 @@ func (in *FeSpec) DeepCopy() *FeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *History) DeepCopyInto(out *History) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new History.
+func (in *History) DeepCopy() *History {
+	if in == nil {
+		return nil
+	}
+	out := new(History)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryRecord) DeepCopyInto(out *HistoryRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryRecord.
+func (in *HistoryRecord) DeepCopy() *HistoryRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitSQL) DeepCopyInto(out *InitSQL) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitSQL.
+func (in *InitSQL) DeepCopy() *InitSQL {
+	if in == nil {
+		return nil
+	}
+	out := new(InitSQL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitSQLStatus) DeepCopyInto(out *InitSQLStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitSQLStatus.
+func (in *InitSQLStatus) DeepCopy() *InitSQLStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InitSQLStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KerberosInfo) DeepCopyInto(out *KerberosInfo) {
 	*out = *in
@@ -427,6 +976,21 @@ func (in *KerberosInfo) DeepCopy() *KerberosInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogRotation) DeepCopyInto(out *LogRotation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogRotation.
+func (in *LogRotation) DeepCopy() *LogRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(LogRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetaService) DeepCopyInto(out *MetaService) {
 	*out = *in
@@ -474,6 +1038,53 @@ func (in *NamespaceName) DeepCopy() *NamespaceName {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolReplicas) DeepCopyInto(out *NodePoolReplicas) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolReplicas.
+func (in *NodePoolReplicas) DeepCopy() *NodePoolReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationSecret) DeepCopyInto(out *OperationSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationSecret.
+func (in *OperationSecret) DeepCopy() *OperationSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentVolume) DeepCopyInto(out *PersistentVolume) {
 	*out = *in
@@ -502,6 +1113,21 @@ func (in *PersistentVolume) DeepCopy() *PersistentVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedAction) DeepCopyInto(out *PlannedAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedAction.
+func (in *PlannedAction) DeepCopy() *PlannedAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PortMap) DeepCopyInto(out *PortMap) {
 	*out = *in
@@ -517,6 +1143,142 @@ func (in *PortMap) DeepCopy() *PortMap {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Profile) DeepCopyInto(out *Profile) {
+	*out = *in
+	if in.ComputeGroups != nil {
+		in, out := &in.ComputeGroups, &out.ComputeGroups
+		*out = make([]ComputeGroupOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Profile.
+func (in *Profile) DeepCopy() *Profile {
+	if in == nil {
+		return nil
+	}
+	out := new(Profile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RackAwareness) DeepCopyInto(out *RackAwareness) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RackAwareness.
+func (in *RackAwareness) DeepCopy() *RackAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(RackAwareness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcilePlan) DeepCopyInto(out *ReconcilePlan) {
+	*out = *in
+	in.PlanTime.DeepCopyInto(&out.PlanTime)
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]PlannedAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcilePlan.
+func (in *ReconcilePlan) DeepCopy() *ReconcilePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcilePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaRange) DeepCopyInto(out *ReplicaRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaRange.
+func (in *ReplicaRange) DeepCopy() *ReplicaRange {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaRangeStatus) DeepCopyInto(out *ReplicaRangeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaRangeStatus.
+func (in *ReplicaRangeStatus) DeepCopy() *ReplicaRangeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaRangeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicasRecommendation) DeepCopyInto(out *ReplicasRecommendation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasRecommendation.
+func (in *ReplicasRecommendation) DeepCopy() *ReplicasRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicasRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleNotification) DeepCopyInto(out *ScaleNotification) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleNotification.
+func (in *ScaleNotification) DeepCopy() *ScaleNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleOperation) DeepCopyInto(out *ScaleOperation) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleOperation.
+func (in *ScaleOperation) DeepCopy() *ScaleOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Secret) DeepCopyInto(out *Secret) {
 	*out = *in
@@ -556,3 +1318,59 @@ func (in *SystemInitialization) DeepCopy() *SystemInitialization {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSecret) DeepCopyInto(out *TLSSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSecret.
+func (in *TLSSecret) DeepCopy() *TLSSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageSample) DeepCopyInto(out *UsageSample) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageSample.
+func (in *UsageSample) DeepCopy() *UsageSample {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionSkew) DeepCopyInto(out *VersionSkew) {
+	*out = *in
+	if in.MaxMinorVersions != nil {
+		in, out := &in.MaxMinorVersions, &out.MaxMinorVersions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxMixedSeconds != nil {
+		in, out := &in.MaxMixedSeconds, &out.MaxMixedSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionSkew.
+func (in *VersionSkew) DeepCopy() *VersionSkew {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionSkew)
+	in.DeepCopyInto(out)
+	return out
+}
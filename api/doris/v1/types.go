@@ -104,6 +104,11 @@ type FeSpec struct {
 	//the number of fe in election. electionNumber <= replicas, left as observers. default value=3
 	ElectionNumber *int32 `json:"electionNumber,omitempty"`
 
+	//the max number of observers dropped in one reconcile when scale in observers, the next batch is dropped after all frontends alive in `show frontends`.
+	//default 0, drop all the scale in observers at once.
+	// +optional
+	ObserverScaleInBatchSize int32 `json:"observerScaleInBatchSize,omitempty"`
+
 	//the foundation spec for creating be software services.
 	BaseSpec `json:",inline"`
 }
@@ -406,6 +411,18 @@ type DorisClusterStatus struct {
 
 	//describe broker cluster status, record running, creating and failed pods.
 	BrokerStatus *ComponentStatus `json:"brokerStatus,omitempty"`
+
+	//FEMaster is the fe master node that operator discovered by `show frontends` when reconciling, the admin sql(drop or add observers) executed on it.
+	//empty means fe not available or the master not discovered.
+	FEMaster *FEMasterNode `json:"feMaster,omitempty"`
+}
+
+// FEMasterNode describe the fe master node in fe cluster.
+type FEMasterNode struct {
+	//Host is the ip or fqdn of master registered in fe cluster.
+	Host string `json:"host,omitempty"`
+	//Role is the role of master in fe cluster.
+	Role string `json:"role,omitempty"`
 }
 
 type CnStatus struct {
@@ -438,9 +455,53 @@ type ComponentStatus struct {
 	//RunningInstances in running status pod names.
 	RunningMembers []string `json:"runningInstances,omitempty"`
 
+	//UnregisteredObservers the fe observer pods that not confirmed registered as observer in fe cluster, only used by fe.
+	UnregisteredObservers []string `json:"unregisteredObservers,omitempty"`
+
+	//UnpromotedFollowers the fe pods(index less than electionNumber) that not confirmed registered as alive follower in fe cluster after the electionNumber increased, only used by fe.
+	UnpromotedFollowers []string `json:"unpromotedFollowers,omitempty"`
+
+	//FollowerPromotion the progress of the observer pod in promoting to follower, only used by fe.
+	FollowerPromotion *FollowerPromotion `json:"followerPromotion,omitempty"`
+
 	ComponentCondition ComponentCondition `json:"componentCondition"`
 }
 
+// FollowerPromotion describe an observer pod restarting with empty meta to register as follower.
+// every step is executed after the step before recorded in status, the meta pvc is deleted only after the pod confirmed deleted.
+type FollowerPromotion struct {
+	//PodName the name of pod in promoting.
+	PodName string `json:"podName"`
+
+	//PodUID the uid of pod when the promotion started, the pod is confirmed deleted when the pod of name not exist or have another uid.
+	PodUID string `json:"podUID,omitempty"`
+
+	//MetaPVCUID the uid of meta pvc that deleted for clearing the meta, empty when the meta not persisted.
+	MetaPVCUID string `json:"metaPVCUID,omitempty"`
+
+	//Phase the step of promotion.
+	Phase FollowerPromotionPhase `json:"phase"`
+
+	//LastTransitionTime the time of the phase changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	//Message the reason of the promotion blocked or failed.
+	Message string `json:"message,omitempty"`
+}
+
+type FollowerPromotionPhase string
+
+const (
+	//PromotionRestartingPod the observer of pod is dropped and the pod is deleted.
+	PromotionRestartingPod FollowerPromotionPhase = "RestartingPod"
+	//PromotionClearingMeta the pod confirmed deleted, the meta pvc is deleted.
+	PromotionClearingMeta FollowerPromotionPhase = "ClearingMeta"
+	//PromotionRegistering the pod recreated with empty meta, waiting the pod registered as alive follower by the start script.
+	PromotionRegistering FollowerPromotionPhase = "Registering"
+	//PromotionFailed the pod not registered as alive follower in time, the follower not alive is dropped for keeping the quorum of fe.
+	PromotionFailed FollowerPromotionPhase = "Failed"
+)
+
 type ComponentCondition struct {
 	SubResourceName string `json:"subResourceName,omitempty"`
 	// Phase of statefulset condition.
@@ -286,6 +286,21 @@ func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UnregisteredObservers != nil {
+		in, out := &in.UnregisteredObservers, &out.UnregisteredObservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnpromotedFollowers != nil {
+		in, out := &in.UnpromotedFollowers, &out.UnpromotedFollowers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FollowerPromotion != nil {
+		in, out := &in.FollowerPromotion, &out.FollowerPromotion
+		*out = new(FollowerPromotion)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ComponentCondition.DeepCopyInto(&out.ComponentCondition)
 }
 
@@ -484,6 +499,11 @@ func (in *DorisClusterStatus) DeepCopyInto(out *DorisClusterStatus) {
 		*out = new(ComponentStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FEMaster != nil {
+		in, out := &in.FEMaster, &out.FEMaster
+		*out = new(FEMasterNode)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DorisClusterStatus.
@@ -575,6 +595,21 @@ func (in *ExternalMetricSource) DeepCopy() *ExternalMetricSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FEMasterNode) DeepCopyInto(out *FEMasterNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FEMasterNode.
+func (in *FEMasterNode) DeepCopy() *FEMasterNode {
+	if in == nil {
+		return nil
+	}
+	out := new(FEMasterNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeAddress) DeepCopyInto(out *FeAddress) {
 	*out = *in
@@ -612,6 +647,22 @@ func (in *FeSpec) DeepCopy() *FeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FollowerPromotion) DeepCopyInto(out *FollowerPromotion) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FollowerPromotion.
+func (in *FollowerPromotion) DeepCopy() *FollowerPromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(FollowerPromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HPAPolicy) DeepCopyInto(out *HPAPolicy) {
 	*out = *in
@@ -56,12 +56,18 @@ func ParseEnvs() *EnvVariables {
 }
 
 // build start parameters for controller
-func NewControllerOptions(envs *EnvVariables) *controller.Options {
+func NewControllerOptions(envs *EnvVariables, f *Flag) *controller.Options {
 	return &controller.Options{
-		EnableWebHook:  envs.EnableWebhook,
-		Name:           envs.OperatorName,
-		SecretName:     Default_Secret_Name,
-		Namespace:      envs.OperatorNamespace,
-		WebhookService: envs.ServiceName,
+		EnableWebHook:           envs.EnableWebhook,
+		Name:                    envs.OperatorName,
+		SecretName:              Default_Secret_Name,
+		Namespace:               envs.OperatorNamespace,
+		WebhookService:          envs.ServiceName,
+		ServerSideApply:         f.ServerSideApply,
+		SkipEquivalentApply:     f.SkipEquivalentApply,
+		MaxConcurrentReconciles: f.MaxConcurrentReconciles,
+		Profile:                 f.Profile,
+		ComputeGroupWorkers:     f.ComputeGroupWorkers,
+		WatchNamespaces:         f.GetWatchNamespaces(),
 	}
 }
@@ -19,6 +19,9 @@ package conf
 
 import (
 	"flag"
+	"strings"
+	"time"
+
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -28,10 +31,22 @@ type Flag struct {
 	MetricsAddr          string
 	ProbeAddr            string
 	Namespace            string
+	WatchNamespaces      string
 	EnableLeaderElection bool
 	PrintVar             bool
 	EnableWebhook        bool
-	Opts                 zap.Options
+	ServerSideApply      bool
+	SkipEquivalentApply  bool
+	//the number of reconcile workers of each controller.
+	MaxConcurrentReconciles int
+	//the profile of disaggregated clusters that overrides the compute groups when the cluster not annotated.
+	Profile string
+	//the number of compute groups of a disaggregated cluster synced in parallel.
+	ComputeGroupWorkers int
+	//the timeouts of connecting fe and reading the result of sql.
+	SQLConnectTimeout time.Duration
+	SQLReadTimeout    time.Duration
+	Opts              zap.Options
 }
 
 func ParseFlags() *Flag {
@@ -39,6 +54,9 @@ func ParseFlags() *Flag {
 	flag.StringVar(&f.MetricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&f.ProbeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&f.Namespace, "namespace", v12.NamespaceAll, "The namespace to watch for changes.")
+	flag.StringVar(&f.WatchNamespaces, "watch-namespaces", "",
+		"The comma separated namespaces to watch for changes, the cache and reconcile of operator are scoped to them with the namespace flag. "+
+			"empty means all namespaces, the resources referenced out of them can not be read.")
 	flag.BoolVar(&f.EnableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -46,6 +64,24 @@ func ParseFlags() *Flag {
 
 	// check switch unnamedwatches on or off, if 'true' passed from console or config in env, will start unnamedwatches operator.
 	flag.BoolVar(&f.EnableWebhook, "enable-unnamedwatches", true, "start the unnamedwatches.")
+	flag.BoolVar(&f.ServerSideApply, "server-side-apply", false,
+		"Reconcile the statefulset and service of compute groups by server-side apply, "+
+			"the operator only owns the fields it sets and keeps the fields set by other controllers.")
+	flag.BoolVar(&f.SkipEquivalentApply, "skip-equivalent-statefulset-apply", false,
+		"Skip updating the statefulset of compute groups when the hash changed but the compared fields already hold in the existing statefulset, "+
+			"the zero value fields are considered defaulted by apiserver, so clearing a scalar field is not applied until others changed.")
+	flag.IntVar(&f.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of reconcile workers of each controller, the different clusters are reconciled in parallel, "+
+			"one cluster is never reconciled by multiple workers at the same time.")
+	flag.StringVar(&f.Profile, "profile", "",
+		"The profile of disaggregated clusters that overrides the base values of compute groups, ep: prod. "+
+			"the profile annotation of cluster takes precedence, the clusters not defined the profile use the base values.")
+	flag.IntVar(&f.ComputeGroupWorkers, "compute-group-workers", 4,
+		"The number of compute groups of a disaggregated cluster synced in parallel in one reconcile, 1 means syncing one by one.")
+	flag.DurationVar(&f.SQLConnectTimeout, "sql-connect-timeout", 5*time.Second,
+		"The timeout of connecting fe by mysql protocol, the reconcile not blocked by a hung fe.")
+	flag.DurationVar(&f.SQLReadTimeout, "sql-read-timeout", 30*time.Second,
+		"The timeout of reading the result of a sql from fe.")
 	f.Opts = zap.Options{
 		Development: true,
 	}
@@ -53,3 +89,18 @@ func ParseFlags() *Flag {
 	flag.Parse()
 	return &f
 }
+
+// GetWatchNamespaces return the namespaces that the operator scoped to, merged from the namespace and watch-namespaces flags. empty means all namespaces.
+func (f *Flag) GetWatchNamespaces() []string {
+	var nss []string
+	seen := map[string]bool{}
+	for _, ns := range append([]string{f.Namespace}, strings.Split(f.WatchNamespaces, ",")...) {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		nss = append(nss, ns)
+	}
+	return nss
+}
@@ -40,6 +40,7 @@ import (
 	dorisv1 "github.com/apache/doris-operator/api/doris/v1"
 	"github.com/apache/doris-operator/cmd/operator/conf"
 	"github.com/apache/doris-operator/pkg/common/utils/certificate"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
 	"github.com/apache/doris-operator/pkg/controller"
 	"github.com/apache/doris-operator/pkg/controller/unnamedwatches"
 	"io"
@@ -112,14 +113,15 @@ func main() {
 	envs := conf.ParseEnvs()
 	//print version infos.
 	printVersionInfos(f.PrintVar)
+	mysql.DefaultConnectTimeout, mysql.DefaultReadTimeout = f.SQLConnectTimeout, f.SQLReadTimeout
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&f.Opts)))
 	webhookServer := webhook.NewServer(webhook.Options{
 		Port: 9443,
 	})
 	defaultNamespaces := map[string]cache.Config{}
-	if f.Namespace != "" {
-		defaultNamespaces[f.Namespace] = cache.Config{}
+	for _, ns := range f.GetWatchNamespaces() {
+		defaultNamespaces[ns] = cache.Config{}
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -156,7 +158,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	options := conf.NewControllerOptions(envs)
+	options := conf.NewControllerOptions(envs, f)
 	//initial all controllers
 	for _, c := range controller.Controllers {
 		c.Init(mgr, options)
@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 )
 
 // judge two services equal or not in some fields. develoer can custom the function.
@@ -44,6 +45,9 @@ type StatefulSetEqual func(st1 *appv1.StatefulSet, st2 *appv1.StatefulSet) bool
 
 type PreApplyStatefulset func(nst *appv1.StatefulSet, est *appv1.StatefulSet)
 
+// the field manager of server-side apply, the fields set by operator are owned by it.
+const FieldManager = "doris-operator"
+
 func ApplyService(ctx context.Context, k8sclient client.Client, svc *corev1.Service, equal ServiceEqual) error {
 	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
 	var esvc corev1.Service
@@ -71,6 +75,23 @@ func ApplyService(ctx context.Context, k8sclient client.Client, svc *corev1.Serv
 	return PatchClientObject(ctx, k8sclient, svc)
 }
 
+// ServerSideApplyService is same as ApplyService, but create or update the service by server-side apply, the fields set by other controllers are kept.
+func ServerSideApplyService(ctx context.Context, k8sclient client.Client, svc *corev1.Service, equal ServiceEqual) error {
+	var esvc corev1.Service
+	svc.Spec.ClusterIPs = nil
+	err := k8sclient.Get(ctx, types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, &esvc)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && equal(svc, &esvc) {
+		klog.Info("ServerSideApplyService service Name, Ports, Selector, ServiceType, Labels have not change ", "namespace ", svc.Namespace, " name ", svc.Name)
+		return nil
+	}
+
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Service"}
+	return ServerSideApply(ctx, k8sclient, svc)
+}
+
 func ListServicesInNamespace(ctx context.Context, k8sclient client.Client, namespace string, selector map[string]string) ([]corev1.Service, error) {
 	var svcList corev1.ServiceList
 	if err := k8sclient.List(ctx, &svcList, client.InNamespace(namespace), client.MatchingLabels(selector)); err != nil {
@@ -88,8 +109,35 @@ func ListStatefulsetInNamespace(ctx context.Context, k8sclient client.Client, na
 	return stsList.Items, nil
 }
 
+func ListDeploymentsInNamespace(ctx context.Context, k8sclient client.Client, namespace string, selector map[string]string) ([]appv1.Deployment, error) {
+	var deployList appv1.DeploymentList
+	if err := k8sclient.List(ctx, &deployList, client.InNamespace(namespace), client.MatchingLabels(selector)); err != nil {
+		return nil, err
+	}
+	return deployList.Items, nil
+}
+
 // ApplyStatefulSet when the object is not exist, create object. if exist and statefulset have been updated, patch the statefulset.
+// the conflict of patching is ignored, the statefulset will be applied in next reconcile.
 func ApplyStatefulSet(ctx context.Context, k8sclient client.Client, st *appv1.StatefulSet, equal StatefulSetEqual, pasfs ...PreApplyStatefulset) error {
+	err := ApplyStatefulSetWithConflict(ctx, k8sclient, st, equal, pasfs...)
+	if apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// ApplyStatefulSetWithConflict is same as ApplyStatefulSet, but return the conflict error(resourceVersion mismatch) of patching, the caller can requeue quickly to apply again.
+func ApplyStatefulSetWithConflict(ctx context.Context, k8sclient client.Client, st *appv1.StatefulSet, equal StatefulSetEqual, pasfs ...PreApplyStatefulset) error {
+	return applyStatefulSet(ctx, k8sclient, st, equal, false, pasfs...)
+}
+
+// ServerSideApplyStatefulSet is same as ApplyStatefulSetWithConflict, but create or update the statefulset by server-side apply, the fields set by other controllers are kept.
+func ServerSideApplyStatefulSet(ctx context.Context, k8sclient client.Client, st *appv1.StatefulSet, equal StatefulSetEqual, pasfs ...PreApplyStatefulset) error {
+	return applyStatefulSet(ctx, k8sclient, st, equal, true, pasfs...)
+}
+
+func applyStatefulSet(ctx context.Context, k8sclient client.Client, st *appv1.StatefulSet, equal StatefulSetEqual, serverSide bool, pasfs ...PreApplyStatefulset) error {
 	var est appv1.StatefulSet
 	create := false
 	err := k8sclient.Get(ctx, types.NamespacedName{Namespace: st.Namespace, Name: st.Name}, &est)
@@ -107,22 +155,22 @@ func ApplyStatefulSet(ctx context.Context, k8sclient client.Client, st *appv1.St
 		pasf(st, nil)
 	}
 
-	if create {
-		return CreateClientObject(ctx, k8sclient, st)
-	}
-
-	//if have restart annotation we should exclude it impacts on hash.
-	if ev {
+	if !create && ev {
+		//if have restart annotation we should exclude it impacts on hash.
 		klog.Infof("ApplyStatefulSet Sync exist statefulset name=%s, namespace=%s, equals to new statefulset.", est.Name, est.Namespace)
 		return nil
 	}
 
-	st.ResourceVersion = est.ResourceVersion
-	err = PatchClientObject(ctx, k8sclient, st)
-	if err == nil || apierrors.IsConflict(err) {
-		return nil
+	if serverSide {
+		st.TypeMeta = metav1.TypeMeta{APIVersion: appv1.SchemeGroupVersion.String(), Kind: "StatefulSet"}
+		return ServerSideApply(ctx, k8sclient, st)
 	}
-	return err
+	if create {
+		return CreateClientObject(ctx, k8sclient, st)
+	}
+
+	st.ResourceVersion = est.ResourceVersion
+	return PatchClientObject(ctx, k8sclient, st)
 }
 
 func ApplyDorisCluster(ctx context.Context, k8sclient client.Client, dcr *dorisv1.DorisCluster) error {
@@ -177,6 +225,15 @@ func PatchClientObject(ctx context.Context, k8sclient client.Client, object clie
 	return nil
 }
 
+// ServerSideApply create or update object by server-side apply, the object should have apiVersion and kind. the conflicts on the fields set by operator are forced to be owned by operator.
+func ServerSideApply(ctx context.Context, k8sclient client.Client, object client.Object) error {
+	klog.Infof("server-side apply resource namespace=%s,name=%s,kind=%s.", object.GetNamespace(), object.GetName(), object.GetObjectKind().GroupVersionKind().Kind)
+	//apply without optimistic lock, and the managedFields must be empty in apply request.
+	object.SetResourceVersion("")
+	object.SetManagedFields(nil)
+	return k8sclient.Patch(ctx, object, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
 // PatchOrCreate patch object if not exist create object.
 func PatchOrCreate(ctx context.Context, k8sclient client.Client, object client.Object) error {
 	klog.V(4).Infof("patch or create resource namespace=%s,name=%s,kind=%s.", object.GetNamespace(), object.GetName(), object.GetObjectKind())
@@ -312,6 +369,26 @@ func GetService(ctx context.Context, k8sclient client.Client, namespace, name st
 	return &svc, nil
 }
 
+// ErrServicePortNotExposed means the port that connecting through the service is not in the ports of service.
+var ErrServicePortNotExposed = errors.New("service port not exposed")
+
+// CheckServicePortExposed return ErrServicePortNotExposed when the service exists but not exposes the port, the connection by the port refused.
+// the service not found or get failed return nil, the connection error tells it.
+func CheckServicePortExposed(ctx context.Context, k8sclient client.Client, namespace, name string, port int32) error {
+	svc, err := GetService(ctx, k8sclient, namespace, name)
+	if err != nil {
+		return nil
+	}
+	var ports []string
+	for _, sp := range svc.Spec.Ports {
+		if sp.Port == port {
+			return nil
+		}
+		ports = append(ports, fmt.Sprintf("%d", sp.Port))
+	}
+	return fmt.Errorf("%w, the service %s/%s exposes ports [%s] not the port %d", ErrServicePortNotExposed, namespace, name, strings.Join(ports, ","), port)
+}
+
 func GetPods(ctx context.Context, k8sclient client.Client, namespace string, labels map[string]string) (corev1.PodList, error) {
 	pods := corev1.PodList{}
 
@@ -328,6 +405,31 @@ func GetPods(ctx context.Context, k8sclient client.Client, namespace string, lab
 	return pods, nil
 }
 
+// GetNodes list the nodes match the labels, nodes are cluster scoped.
+func GetNodes(ctx context.Context, k8sclient client.Client, labels map[string]string) (corev1.NodeList, error) {
+	nodes := corev1.NodeList{}
+	if err := k8sclient.List(ctx, &nodes, client.MatchingLabels(labels)); err != nil {
+		return nodes, err
+	}
+
+	return nodes, nil
+}
+
+// NodeIsSchedulable check the node is ready and not cordoned.
+func NodeIsSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 // GetConfig get conf from configmap by componentType , if not use configmap get an empty map.
 func GetConfig(ctx context.Context, k8sclient client.Client, configMapInfo *dorisv1.ConfigMapInfo, namespace string, componentType dorisv1.ComponentType) (map[string]interface{}, error) {
 	cms := resource.GetMountConfigMapInfo(*configMapInfo)
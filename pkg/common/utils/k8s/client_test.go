@@ -18,6 +18,8 @@ package k8s
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
@@ -30,6 +32,7 @@ import (
 	"k8s.io/utils/pointer"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func Test_ApplyService(t *testing.T) {
@@ -140,6 +143,86 @@ func Test_ApplyStatefulSet(t *testing.T) {
 	}
 }
 
+func Test_ServerSideApplyStatefulSet(t *testing.T) {
+	est := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "test"},
+		Spec:       appv1.StatefulSetSpec{Replicas: pointer.Int32(1)},
+	}
+	var applied []string
+	fakeClient := fake.NewClientBuilder().WithObjects(est).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType || obj.GetObjectKind().GroupVersionKind().Kind != "StatefulSet" || obj.GetResourceVersion() != "" {
+				t.Errorf("server-side apply statefulset %s not apply patch, type %s kind %s", obj.GetName(), patch.Type(), obj.GetObjectKind().GroupVersionKind().Kind)
+			}
+			applied = append(applied, obj.GetName())
+			return nil
+		},
+	}).Build()
+
+	equal := func(st1 *appv1.StatefulSet, st2 *appv1.StatefulSet) bool {
+		return st2.Spec.Replicas != nil && *st1.Spec.Replicas == *st2.Spec.Replicas
+	}
+	tsts := []*appv1.StatefulSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "test"}, Spec: appv1.StatefulSetSpec{Replicas: pointer.Int32(1)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "test"}, Spec: appv1.StatefulSetSpec{Replicas: pointer.Int32(2)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "testnoexist", Namespace: "test"}, Spec: appv1.StatefulSetSpec{Replicas: pointer.Int32(1)}},
+	}
+	for _, st := range tsts {
+		if err := ServerSideApplyStatefulSet(context.Background(), fakeClient, st, equal); err != nil {
+			t.Errorf("server-side apply statefulset %s failed, err %s", st.Name, err.Error())
+		}
+	}
+	if len(applied) != 2 || applied[0] != "test1" || applied[1] != "testnoexist" {
+		t.Errorf("server-side apply expect the changed and not exist statefulsets applied, got %v", applied)
+	}
+}
+
+func Test_ApplyStatefulSet_SkipEquivalent(t *testing.T) {
+	//the existing statefulset have defaulted fields and a stale hash, ep: the hash changed by upgrading operator.
+	est := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "test", Annotations: map[string]string{"spec-hash": "stale"}},
+		Spec: appv1.StatefulSetSpec{
+			Replicas: pointer.Int32(1),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyAlways,
+				Containers:    []corev1.Container{{Name: "compute", Image: "test", ImagePullPolicy: corev1.PullIfNotPresent}},
+			}},
+		},
+	}
+	var patched []string
+	fakeClient := fake.NewClientBuilder().WithObjects(est).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patched = append(patched, obj.GetName())
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+
+	equal := func(st1 *appv1.StatefulSet, st2 *appv1.StatefulSet) bool {
+		return resource.StatefulsetDeepEqualWithKey(st1, st2, "spec-hash", false) || resource.StatefulsetEquivalent(st1, st2, false)
+	}
+	newSt := func(image string) *appv1.StatefulSet {
+		return &appv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "test"},
+			Spec: appv1.StatefulSetSpec{
+				Replicas: pointer.Int32(1),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "compute", Image: image}}}},
+			},
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := ApplyStatefulSet(context.Background(), fakeClient, newSt("test"), equal); err != nil {
+			t.Errorf("apply statefulset failed, err %s", err.Error())
+		}
+	}
+	if len(patched) != 0 {
+		t.Errorf("apply statefulset expected no patch when nothing material changed, got %v", patched)
+	}
+	if err := ApplyStatefulSet(context.Background(), fakeClient, newSt("test-new"), equal); err != nil || len(patched) != 1 {
+		t.Errorf("apply statefulset expected patch when image changed, patched %v err %v", patched, err)
+	}
+}
+
 func Test_ApplyFoundationDBCluster(t *testing.T) {
 	fdbs := []client.Object{
 		&v1beta2.FoundationDBCluster{
@@ -240,3 +323,22 @@ func Test_DeletePVC(t *testing.T) {
 		}
 	}
 }
+
+func Test_CheckServicePortExposed(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-fe"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8030}, {Name: "query", Port: 9030}}},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(svc).Build()
+	ctx := context.Background()
+
+	if err := CheckServicePortExposed(ctx, fakeClient, "default", "test-fe", 9030); err != nil {
+		t.Errorf("CheckServicePortExposed expected exposed port pass, err=%s", err.Error())
+	}
+	if err := CheckServicePortExposed(ctx, fakeClient, "default", "test-fe", 9031); !errors.Is(err, ErrServicePortNotExposed) || !strings.Contains(err.Error(), "[8030,9030]") {
+		t.Errorf("CheckServicePortExposed expected ErrServicePortNotExposed with the exposed ports, got %v", err)
+	}
+	if err := CheckServicePortExposed(ctx, fakeClient, "default", "noexist", 9030); err != nil {
+		t.Errorf("CheckServicePortExposed expected not found service pass, err=%s", err.Error())
+	}
+}
@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// ExternalMetricsClient get the values of metric from the kubernetes external metrics api, the api served by adapters, ep: keda, prometheus-adapter.
+type ExternalMetricsClient interface {
+	GetExternalMetricValues(ctx context.Context, namespace, metricName string, selector map[string]string) ([]apiresource.Quantity, error)
+}
+
+// the metric name is the resource of external metrics api, the controller-runtime client can not map it by kind.
+type externalMetricsClient struct {
+	rest rest.Interface
+}
+
+func NewExternalMetricsClient(cfg *rest.Config) (ExternalMetricsClient, error) {
+	c := rest.CopyConfig(cfg)
+	c.GroupVersion = &schema.GroupVersion{Group: "external.metrics.k8s.io", Version: "v1beta1"}
+	c.APIPath = "/apis"
+	c.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	if c.UserAgent == "" {
+		c.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	rc, err := rest.RESTClientFor(c)
+	if err != nil {
+		return nil, err
+	}
+	return &externalMetricsClient{rest: rc}, nil
+}
+
+// GetExternalMetricValues return the values of all series that the metric selected by selector.
+func (c *externalMetricsClient) GetExternalMetricValues(ctx context.Context, namespace, metricName string, selector map[string]string) ([]apiresource.Quantity, error) {
+	req := c.rest.Get().Namespace(namespace).Resource(metricName)
+	if len(selector) != 0 {
+		req = req.Param("labelSelector", labels.SelectorFromSet(selector).String())
+	}
+	body, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseExternalMetricValues(body)
+}
+
+// parseExternalMetricValues parse the values from the ExternalMetricValueList.
+func parseExternalMetricValues(body []byte) ([]apiresource.Quantity, error) {
+	var list struct {
+		Items []struct {
+			Value apiresource.Quantity `json:"value"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, err
+	}
+	var values []apiresource.Quantity
+	for _, item := range list.Items {
+		values = append(values, item.Value)
+	}
+	return values, nil
+}
@@ -0,0 +1,30 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package k8s
+
+import "testing"
+
+func Test_parseExternalMetricValues(t *testing.T) {
+	body := `{"kind":"ExternalMetricValueList","apiVersion":"external.metrics.k8s.io/v1beta1","metadata":{},
+"items":[{"metricName":"kafka_lag","metricLabels":{"topic":"a"},"timestamp":"2024-01-01T00:00:00Z","value":"1500m"},
+{"metricName":"kafka_lag","metricLabels":{"topic":"b"},"timestamp":"2024-01-01T00:00:00Z","value":"3"}]}`
+	values, err := parseExternalMetricValues([]byte(body))
+	if err != nil || len(values) != 2 || values[0].MilliValue() != 1500 || values[1].Value() != 3 {
+		t.Errorf("parseExternalMetricValues expected 1500m and 3, got %v err %v", values, err)
+	}
+}
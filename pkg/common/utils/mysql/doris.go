@@ -83,6 +83,11 @@ type Backend struct {
 	Memory                  string  `json:"memory" db:"Memory"`
 }
 
+type StorageVault struct {
+	Name      string `json:"name"`
+	IsDefault bool   `json:"is_default"`
+}
+
 // BuildSeqNumberToFrontendMap
 // input ipMap key is podIP,value is fe.podName(from 'kubectl get pods -owide')
 // return frontendMap key is fe pod index ,value is frontend
@@ -18,12 +18,18 @@
 package mysql
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	_ "github.com/go-sql-driver/mysql"
@@ -36,18 +42,42 @@ const (
 	COMPUTE_GROUP_ID = "compute_group_id"
 )
 
+// ErrFEMasterNotElected represents the fe cluster is up, but have not elected the master.
+var ErrFEMasterNotElected = errors.New("fe master not elected")
+
+// ErrFEMasterUnreachable represents the fe is reachable by the configured host and port, but the master can not be connected or the connected fe is not the master.
+// the admin sql executed on a fe not master may fail silently.
+var ErrFEMasterUnreachable = errors.New("fe master unreachable")
+
+// the timeouts of connecting fe when DBConfig not set, overridden by the flags of operator.
+var (
+	DefaultConnectTimeout = 5 * time.Second
+	DefaultReadTimeout    = 30 * time.Second
+)
+
 type DBConfig struct {
 	User     string
 	Password string
 	Host     string
 	Port     string
 	Database string
+	// ConnectTimeout is the timeout of dialing fe, use DefaultConnectTimeout when zero.
+	ConnectTimeout time.Duration
+	// ReadTimeout is the timeout of reading the result of a sql from fe, use DefaultReadTimeout when zero.
+	ReadTimeout time.Duration
+	// FallbackHosts are the hosts of fe tried in rotation after Host failed by NewDorisMasterSqlDBWithRetry, ep: the fe pods.
+	FallbackHosts []string
 }
 
+// TLSConfig describe the keys of certificates in secret for connecting fe with tls.
 type TLSConfig struct {
 	CAFileName         string
 	ClientCertFileName string
 	ClientKeyFileName  string
+	// InsecureSkipVerify not verify the certificate of fe, only for the self-signed certificate without ca in test.
+	InsecureSkipVerify bool
+	// ClientCertOptional not require the client certificate, the fe only enforcing the server side tls not requires it.
+	ClientCertOptional bool
 }
 
 func NewDBConfig() DBConfig {
@@ -58,52 +88,97 @@ func NewDBConfig() DBConfig {
 
 type DB struct {
 	*sqlx.DB
+	//PlanRecorder records the statements of Exec instead of executing them when not nil, used by the plan reconcile mode.
+	PlanRecorder func(query string)
+	//User is the user that the client connected to fe as, recorded in the audit of destructive sql.
+	User string
+	//ctx bounds the sql executed by the client, the sql returns when the reconcile cancelled.
+	ctx context.Context
+}
+
+// buildDSN return the dsn of go-sql-driver with the timeouts, tlsKey is the name of registered tls config, empty means not use tls.
+func buildDSN(cfg DBConfig, tlsKey string) string {
+	connectTimeout, readTimeout := cfg.ConnectTimeout, cfg.ReadTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	if readTimeout <= 0 {
+		readTimeout = DefaultReadTimeout
+	}
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?timeout=%s&readTimeout=%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database, connectTimeout.String(), readTimeout.String())
+	if tlsKey != "" {
+		dsn = dsn + "&tls=" + tlsKey
+	}
+	return dsn
 }
 
 func NewDorisSqlDB(cfg DBConfig, tlsConfig *TLSConfig, secret *corev1.Secret) (*DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
-	rootCertPool := x509.NewCertPool()
+	return NewDorisSqlDBContext(context.Background(), cfg, tlsConfig, secret)
+}
 
+// NewDorisSqlDBContext is same as NewDorisSqlDB, the connecting and the sql executed by the client return when ctx done.
+func NewDorisSqlDBContext(ctx context.Context, cfg DBConfig, tlsConfig *TLSConfig, secret *corev1.Secret) (*DB, error) {
+	var tlsKey string
 	if tlsConfig != nil && secret != nil {
-		ca := secret.Data[tlsConfig.CAFileName]
-		clientCert := secret.Data[tlsConfig.ClientCertFileName]
-		clientKey := secret.Data[tlsConfig.ClientKeyFileName]
-		if ok := rootCertPool.AppendCertsFromPEM(ca); !ok {
-			klog.Errorf("NewDorisSqlDB append cert from pem failed")
-			return nil, errors.New("NewDorisSqlDB append cert from pem failed")
-		}
-		clientCerts := make([]tls.Certificate, 0, 1)
-		cCert, err := tls.X509KeyPair(clientCert, clientKey)
+		tc, err := newTLSConfig(tlsConfig, secret)
 		if err != nil {
-			return nil, errors.New("NewDorisSqlDB load x509 key pair failed," + err.Error())
+			klog.Errorf("NewDorisSqlDB build tls config from secret %s failed, err: %s", secret.Name, err.Error())
+			return nil, err
 		}
-
-		clientCerts = append(clientCerts, cCert)
 		registerKey := secret.Namespace + "-" + secret.Name
-		if err = mysql.RegisterTLSConfig(registerKey, &tls.Config{
-			RootCAs:      rootCertPool,
-			Certificates: clientCerts,
-		}); err != nil {
+		if err = mysql.RegisterTLSConfig(registerKey, tc); err != nil {
 			return nil, errors.New("NewDorisSqlDB register tls config failed," + err.Error())
 		}
-		dsn = dsn + "?tls=" + registerKey
+		tlsKey = registerKey
 	}
 
-	db, err := sqlx.Open("mysql", dsn)
+	db, err := sqlx.Open("mysql", buildDSN(cfg, tlsKey))
 	if err != nil {
 		klog.Errorf("NewDorisSqlDB sqlx.Open failed open doris sql client connection, err: %s \n", err.Error())
 		return nil, err
 	}
 
-	if err = db.Ping(); err != nil {
+	if err = db.PingContext(ctx); err != nil {
 		klog.Errorf("NewDorisSqlDB sqlx.Open.Ping failed ping doris sql client connection, err: %s \n", err.Error())
+		db.Close()
 		return nil, err
 	}
-	return &DB{db}, nil
+	return &DB{DB: db, User: cfg.User, ctx: ctx}, nil
+}
+
+// newTLSConfig build the tls config of go-sql-driver from the certificates in secret. the ca is not required when skipping verify,
+// the client certificate is not required when ClientCertOptional and the secret have not it.
+func newTLSConfig(tlsConfig *TLSConfig, secret *corev1.Secret) (*tls.Config, error) {
+	tc := &tls.Config{InsecureSkipVerify: tlsConfig.InsecureSkipVerify}
+	ca := secret.Data[tlsConfig.CAFileName]
+	if len(ca) != 0 || !tlsConfig.InsecureSkipVerify {
+		rootCertPool := x509.NewCertPool()
+		if ok := rootCertPool.AppendCertsFromPEM(ca); !ok {
+			return nil, errors.New("NewDorisSqlDB append cert from pem failed")
+		}
+		tc.RootCAs = rootCertPool
+	}
+
+	clientCert := secret.Data[tlsConfig.ClientCertFileName]
+	clientKey := secret.Data[tlsConfig.ClientKeyFileName]
+	if tlsConfig.ClientCertOptional && len(clientCert) == 0 && len(clientKey) == 0 {
+		return tc, nil
+	}
+	cCert, err := tls.X509KeyPair(clientCert, clientKey)
+	if err != nil {
+		return nil, errors.New("NewDorisSqlDB load x509 key pair failed," + err.Error())
+	}
+	tc.Certificates = []tls.Certificate{cCert}
+	return tc, nil
 }
 
 func NewDorisMasterSqlDB(dbConf DBConfig, tlsConfig *TLSConfig, secret *corev1.Secret) (*DB, error) {
-	loadBalanceDBClient, err := NewDorisSqlDB(dbConf, tlsConfig, secret)
+	return NewDorisMasterSqlDBContext(context.Background(), dbConf, tlsConfig, secret)
+}
+
+// NewDorisMasterSqlDBContext is same as NewDorisMasterSqlDB, the connecting and the sql executed by the client return when ctx done.
+func NewDorisMasterSqlDBContext(ctx context.Context, dbConf DBConfig, tlsConfig *TLSConfig, secret *corev1.Secret) (*DB, error) {
+	loadBalanceDBClient, err := NewDorisSqlDBContext(ctx, dbConf, tlsConfig, secret)
 	if err != nil {
 		klog.Errorf("NewDorisMasterSqlDB failed, get fe node connection err:%s", err.Error())
 		return nil, err
@@ -111,8 +186,14 @@ func NewDorisMasterSqlDB(dbConf DBConfig, tlsConfig *TLSConfig, secret *corev1.S
 	master, _, err := loadBalanceDBClient.GetFollowers()
 	if err != nil {
 		klog.Errorf("NewDorisMasterSqlDB GetFollowers master failed, err:%s", err.Error())
+		loadBalanceDBClient.Close()
 		return nil, err
 	}
+	if master == nil {
+		klog.Errorf("NewDorisMasterSqlDB the fe master not elected.")
+		loadBalanceDBClient.Close()
+		return nil, ErrFEMasterNotElected
+	}
 	var masterDBClient *DB
 	if master.CurrentConnected == "Yes" {
 		masterDBClient = loadBalanceDBClient
@@ -120,31 +201,120 @@ func NewDorisMasterSqlDB(dbConf DBConfig, tlsConfig *TLSConfig, secret *corev1.S
 		// loadBalanceDBClient should be closed
 		defer loadBalanceDBClient.Close()
 		// Get the connection to the master
-		masterDBClient, err = NewDorisSqlDB(DBConfig{
-			User:     dbConf.User,
-			Password: dbConf.Password,
-			Host:     master.Host,
-			Port:     dbConf.Port,
-			Database: "mysql",
+		masterDBClient, err = NewDorisSqlDBContext(ctx, DBConfig{
+			User:           dbConf.User,
+			Password:       dbConf.Password,
+			Host:           master.Host,
+			Port:           masterQueryPort(master, dbConf.Port),
+			Database:       "mysql",
+			ConnectTimeout: dbConf.ConnectTimeout,
+			ReadTimeout:    dbConf.ReadTimeout,
 		}, tlsConfig, secret)
 		if err != nil {
 			klog.Errorf("NewDorisMasterSqlDB failed, get fe master connection  err:%s", err.Error())
+			return nil, fmt.Errorf("%w, connect the master %s failed, err=%s", ErrFEMasterUnreachable, master.Host, err.Error())
+		}
+		if err = masterDBClient.checkConnectedMaster(); err != nil {
+			klog.Errorf("NewDorisMasterSqlDB check the connected fe is master failed, err:%s", err.Error())
+			masterDBClient.Close()
 			return nil, err
 		}
 	}
 	return masterDBClient, nil
 }
 
+const (
+	// DefaultMasterConnectBackoff is the backoff before the first retry of connecting fe master.
+	DefaultMasterConnectBackoff = 500 * time.Millisecond
+	// the backoff between the retries of connecting fe master doubles after each failure, and not exceed it.
+	maxMasterConnectBackoff = 5 * time.Second
+)
+
+// connectMasterSqlDB connect the fe master once, replaced in tests for faking the failures.
+var connectMasterSqlDB = NewDorisMasterSqlDBContext
+
+// NewDorisMasterSqlDBWithRetry connect the fe master and confirm it, the host of config and the FallbackHosts are tried in rotation, one host in an attempt.
+// the backoff between attempts doubles after each failure, return the error of the last attempt when all failed or the ctx done.
+func NewDorisMasterSqlDBWithRetry(ctx context.Context, dbConf DBConfig, attempts int, backoff time.Duration, tlsConfig *TLSConfig, secret *corev1.Secret) (*DB, error) {
+	hosts := append([]string{dbConf.Host}, dbConf.FallbackHosts...)
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, err
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxMasterConnectBackoff)
+		}
+		conf := dbConf
+		conf.Host = hosts[i%len(hosts)]
+		db, cerr := connectMasterSqlDB(ctx, conf, tlsConfig, secret)
+		if cerr == nil {
+			return db, nil
+		}
+		err = cerr
+		klog.Infof("NewDorisMasterSqlDBWithRetry attempt %d of %d connect fe master by %s failed, err:%s", i+1, attempts, conf.Host, err.Error())
+	}
+	return nil, err
+}
+
+// checkConnectedMaster confirm the fe connected reports itself master, the address of master reported by fe may be routed to another fe.
+func (db *DB) checkConnectedMaster() error {
+	frontends, err := db.ShowFrontends()
+	if err != nil {
+		return err
+	}
+	for _, fe := range frontends {
+		if fe.CurrentConnected != "Yes" {
+			continue
+		}
+		if !fe.IsMaster {
+			return fmt.Errorf("%w, the connected fe %s is not the master", ErrFEMasterUnreachable, fe.Host)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w, the connected fe not found in frontends", ErrFEMasterUnreachable)
+}
+
+// masterQueryPort return the query port that master reported in fe, the query_port of config changed takes effect after master restarted, connecting master by the config port before that targets a wrong port.
+func masterQueryPort(master *Frontend, configPort string) string {
+	if master.QueryPort == 0 {
+		return configPort
+	}
+	port := strconv.Itoa(master.QueryPort)
+	if port != configPort {
+		klog.Infof("NewDorisMasterSqlDB the query port %s of fe master %s differs from config %s, connect master by the port it runs with.", port, master.Host, configPort)
+	}
+	return port
+}
+
 func (db *DB) Close() error {
 	return db.DB.Close()
 }
 
+// context return the context bounds the sql, the client not created with context is not bounded.
+func (db *DB) context() context.Context {
+	if db.ctx == nil {
+		return context.Background()
+	}
+	return db.ctx
+}
+
 func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return db.DB.Exec(query, args...)
+	if db.PlanRecorder != nil {
+		db.PlanRecorder(query)
+		return driver.RowsAffected(0), nil
+	}
+	return db.DB.ExecContext(db.context(), query, args...)
 }
 
 func (db *DB) Select(dest interface{}, query string, args ...interface{}) error {
-	return db.DB.Select(dest, query, args...)
+	return db.DB.SelectContext(db.context(), dest, query, args...)
 }
 
 func (db *DB) ShowFrontends() ([]*Frontend, error) {
@@ -179,29 +349,187 @@ func (db *DB) DropBE(nodes []*Backend) error {
 		klog.Infoln("mysql DropBE BE node is empty")
 		return nil
 	}
+	_, err := db.Exec(DropBESQL(nodes))
+	return err
+}
+
+// DropBESQL return the statement that DropBE executes.
+func DropBESQL(nodes []*Backend) string {
 	nodesString := fmt.Sprintf(`"%s:%d"`, nodes[0].Host, nodes[0].HeartbeatPort)
 	for _, node := range nodes[1:] {
 		nodesString = nodesString + fmt.Sprintf(`,"%s:%d"`, node.Host, node.HeartbeatPort)
 	}
+	return fmt.Sprintf("ALTER SYSTEM DROPP BACKEND %s;", nodesString)
+}
+
+// DropBEByIds drop the backends by backend id, the duplicate backends with the same host:heartbeatPort can only be distinguished by id.
+func (db *DB) DropBEByIds(nodes []*Backend) error {
+	if len(nodes) == 0 {
+		klog.Infoln("mysql DropBEByIds BE node is empty")
+		return nil
+	}
+	_, err := db.Exec(DropBEByIdsSQL(nodes))
+	return err
+}
+
+// DropBEByIdsSQL return the statement that DropBEByIds executes.
+func DropBEByIdsSQL(nodes []*Backend) string {
+	var ids []string
+	for _, node := range nodes {
+		ids = append(ids, fmt.Sprintf(`"%s"`, node.BackendID))
+	}
+	return fmt.Sprintf("ALTER SYSTEM DROPP BACKEND %s;", strings.Join(ids, ","))
+}
+
+// AddBE register the nodes as backends of the compute group in fe, the compute group created when not exists.
+func (db *DB) AddBE(nodes []*Backend, cgName string) error {
+	if len(nodes) == 0 {
+		klog.Infoln("mysql AddBE BE node is empty")
+		return nil
+	}
+	var hosts []string
+	for _, node := range nodes {
+		hosts = append(hosts, fmt.Sprintf(`"%s:%d"`, node.Host, node.HeartbeatPort))
+	}
+
+	alter := fmt.Sprintf(`ALTER SYSTEM ADD BACKEND %s PROPERTIES ("tag.compute_group_name" = %s);`, strings.Join(hosts, ","), quoteString(cgName))
+	_, err := db.Exec(alter)
+	return err
+}
+
+// ModifyBackendTags set the tags of backend in fe, the keys are the tag names without `tag.` prefix that validated by the caller, the values are quoted.
+func (db *DB) ModifyBackendTags(node *Backend, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var props []string
+	for _, k := range keys {
+		props = append(props, fmt.Sprintf(`"tag.%s" = %s`, k, quoteString(tags[k])))
+	}
 
-	alter := fmt.Sprintf("ALTER SYSTEM DROPP BACKEND %s;", nodesString)
+	alter := fmt.Sprintf(`ALTER SYSTEM MODIFY BACKEND "%s:%d" SET (%s);`, node.Host, node.HeartbeatPort, strings.Join(props, ", "))
 	_, err := db.Exec(alter)
 	return err
 }
 
+// SetBackendQueryDisabled set the disable_query property of backend in fe, the backend disabled not receives new queries and keeps alive.
+func (db *DB) SetBackendQueryDisabled(node *Backend, disabled bool) error {
+	alter := fmt.Sprintf(`ALTER SYSTEM MODIFY BACKEND "%s:%d" SET ("disable_query" = "%t");`, node.Host, node.HeartbeatPort, disabled)
+	_, err := db.Exec(alter)
+	return err
+}
+
+// QueryDisabled return the isQueryDisabled in the status of backend, the status is a json object in `show backends`.
+func (b *Backend) QueryDisabled() (bool, error) {
+	var status struct {
+		IsQueryDisabled bool `json:"isQueryDisabled"`
+	}
+	if b.Status == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(b.Status), &status); err != nil {
+		return false, err
+	}
+	return status.IsQueryDisabled, nil
+}
+
 func (db *DB) DropObserver(nodes []*Frontend) error {
 	if len(nodes) == 0 {
 		klog.Infoln("DropObserver observer node is empty")
 		return nil
 	}
+	_, err := db.Exec(DropObserverSQL(nodes))
+	return err
+}
+
+// DropObserverSQL return the statements that DropObserver executes.
+func DropObserverSQL(nodes []*Frontend) string {
 	var alter string
 	for _, node := range nodes {
 		alter = alter + fmt.Sprintf(`ALTER SYSTEM DROP OBSERVER "%s:%d";`, node.Host, node.EditLogPort)
 	}
+	return alter
+}
+
+// DropFollower drop the nodes registered as follower in fe cluster.
+func (db *DB) DropFollower(nodes []*Frontend) error {
+	if len(nodes) == 0 {
+		klog.Infoln("DropFollower follower node is empty")
+		return nil
+	}
+	_, err := db.Exec(DropFollowerSQL(nodes))
+	return err
+}
+
+// DropFollowerSQL return the statements that DropFollower executes.
+func DropFollowerSQL(nodes []*Frontend) string {
+	var alter string
+	for _, node := range nodes {
+		alter = alter + fmt.Sprintf(`ALTER SYSTEM DROP FOLLOWER "%s:%d";`, node.Host, node.EditLogPort)
+	}
+	return alter
+}
+
+// AddObserver register the nodes as observer in fe cluster.
+func (db *DB) AddObserver(nodes []*Frontend) error {
+	if len(nodes) == 0 {
+		klog.Infoln("AddObserver observer node is empty")
+		return nil
+	}
+	var alter string
+	for _, node := range nodes {
+		alter = alter + fmt.Sprintf(`ALTER SYSTEM ADD OBSERVER "%s:%d";`, node.Host, node.EditLogPort)
+	}
 	_, err := db.Exec(alter)
 	return err
 }
 
+// GrantComputeGroupUsage grant the usage of compute group to the user, or the role when isRole is true.
+func (db *DB) GrantComputeGroupUsage(cgName, name string, isRole bool) error {
+	_, err := db.Exec(fmt.Sprintf(`GRANT USAGE_PRIV ON COMPUTE GROUP %s TO %s%s;`, quoteString(cgName), roleKeyword(isRole), quoteString(name)))
+	return err
+}
+
+// RevokeComputeGroupUsage revoke the usage of compute group from the user, or the role when isRole is true.
+func (db *DB) RevokeComputeGroupUsage(cgName, name string, isRole bool) error {
+	_, err := db.Exec(fmt.Sprintf(`REVOKE USAGE_PRIV ON COMPUTE GROUP %s FROM %s%s;`, quoteString(cgName), roleKeyword(isRole), quoteString(name)))
+	return err
+}
+
+func roleKeyword(isRole bool) string {
+	if isRole {
+		return "ROLE "
+	}
+	return ""
+}
+
+// SetDefaultComputeGroup set the default compute group of the user, the empty cgName unset it.
+func (db *DB) SetDefaultComputeGroup(user, cgName string) error {
+	_, err := db.Exec(fmt.Sprintf(`SET PROPERTY FOR %s 'default_compute_group' = %s;`, quoteString(user), quoteString(cgName)))
+	return err
+}
+
+// quoteString quote the value as a single-quoted string literal of sql, the backslashes and single quotes in value are escaped.
+func quoteString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// quoteIdentifier quote the name as a backtick-quoted identifier of sql, the backticks in name are doubled.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// SetWorkloadGroupMemoryLimit set the memory_limit of the workload group that belongs to the compute group.
+func (db *DB) SetWorkloadGroupMemoryLimit(wgName, cgName, memoryLimit string) error {
+	_, err := db.Exec(fmt.Sprintf(`ALTER WORKLOAD GROUP %s FOR %s PROPERTIES ('memory_limit'=%s);`, quoteIdentifier(wgName), quoteIdentifier(cgName), quoteString(memoryLimit)))
+	return err
+}
+
 func (db *DB) GetObservers() ([]*Frontend, error) {
 	frontends, err := db.ShowFrontends()
 	if err != nil {
@@ -245,6 +573,105 @@ func (db *DB) GetBackendsByComputeGroupId(cgid string) ([]*Backend, error) {
 	return res, nil
 }
 
+// GetBalancingTabletsNum return the number of tablets that fe scheduled for balancing or cloning, include pending and running.
+func (db *DB) GetBalancingTabletsNum() (int, error) {
+	num := 0
+	for _, proc := range []string{"/cluster_balance/pending_tablets", "/cluster_balance/running_tablets"} {
+		rows, err := db.DB.QueryxContext(db.context(), fmt.Sprintf("show proc '%s'", proc))
+		if err != nil {
+			klog.Errorf("GetBalancingTabletsNum show proc %s failed, err: %s\n", proc, err.Error())
+			return 0, err
+		}
+		for rows.Next() {
+			num++
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return num, nil
+}
+
+// GetBackendCacheHitRatios return the file cache hit ratio of backends by backend id from `information_schema.file_cache_statistics`, the ratios of cache paths are averaged.
+// the table is not supported by the fe before 3.0, the error returned.
+func (db *DB) GetBackendCacheHitRatios() (map[string]float64, error) {
+	var stats []struct {
+		BeId  string `db:"BE_ID"`
+		Value string `db:"METRIC_VALUE"`
+	}
+	if err := db.Select(&stats, "SELECT BE_ID, METRIC_VALUE FROM information_schema.file_cache_statistics WHERE METRIC_NAME = 'hits_ratio'"); err != nil {
+		klog.Errorf("GetBackendCacheHitRatios query file cache statistics failed, err: %s\n", err.Error())
+		return nil, err
+	}
+
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, stat := range stats {
+		v, err := strconv.ParseFloat(stat.Value, 64)
+		if err != nil {
+			continue
+		}
+		sums[stat.BeId] += v
+		counts[stat.BeId]++
+	}
+	ratios := map[string]float64{}
+	for id, sum := range sums {
+		ratios[id] = sum / float64(counts[id])
+	}
+	return ratios, nil
+}
+
+// HasNodePrivilege check the current user have the global privilege for node operations(NODE_PRIV or ADMIN_PRIV) by `show grants`.
+func (db *DB) HasNodePrivilege() (bool, error) {
+	rows, err := db.DB.QueryxContext(db.context(), "show grants")
+	if err != nil {
+		klog.Errorf("HasNodePrivilege show grants failed, err: %s\n", err.Error())
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := map[string]interface{}{}
+		if err := rows.MapScan(m); err != nil {
+			return false, err
+		}
+		privs := strings.ToLower(fmt.Sprintf("%s", m["GlobalPrivs"]))
+		if strings.Contains(privs, "node_priv") || strings.Contains(privs, "admin_priv") {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// ShowStorageVaults return the storage vaults configured in fe by `show storage vault`, the name column is `StorageVaultName` in 3.0.x and `Name` in later versions.
+func (db *DB) ShowStorageVaults() ([]*StorageVault, error) {
+	rows, err := db.DB.QueryxContext(db.context(), "show storage vault")
+	if err != nil {
+		klog.Errorf("ShowStorageVaults show storage vault failed, err: %s\n", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vaults []*StorageVault
+	for rows.Next() {
+		m := map[string]interface{}{}
+		if err := rows.MapScan(m); err != nil {
+			return nil, err
+		}
+		name := m["StorageVaultName"]
+		if name == nil {
+			name = m["Name"]
+		}
+		vaults = append(vaults, &StorageVault{
+			Name:      fmt.Sprintf("%s", name),
+			IsDefault: strings.EqualFold(fmt.Sprintf("%s", m["IsDefault"]), "true"),
+		})
+	}
+	return vaults, rows.Err()
+}
+
 // GetFollowers return fe master,all followers(including master) and err
 func (db *DB) GetFollowers() (*Frontend, []*Frontend, error) {
 	frontends, err := db.ShowFrontends()
@@ -264,3 +691,62 @@ func (db *DB) GetFollowers() (*Frontend, []*Frontend, error) {
 	}
 	return master, res, nil
 }
+
+// GetFEMetadataUnhealthySignals check the frontends and backends reported by fe, return the signals that fe metadata is unhealthy.
+// `show backends` failed after `show frontends` succeeded is a signal, as the fe is reachable.
+func (db *DB) GetFEMetadataUnhealthySignals() ([]string, error) {
+	frontends, err := db.ShowFrontends()
+	if err != nil {
+		klog.Errorf("GetFEMetadataUnhealthySignals show frontends failed, err: %s\n", err.Error())
+		return nil, err
+	}
+	backends, err := db.ShowBackends()
+	if err != nil {
+		return []string{fmt.Sprintf("show backends failed, err=%s", err.Error())}, nil
+	}
+	return FEMetadataUnhealthySignals(frontends, backends), nil
+}
+
+// FEMetadataUnhealthySignals return the known signals of fe metadata unhealthy: multiple masters, different cluster ids of frontends,
+// duplicate backend ids, and the tags of backends not have compute group id in disaggregated cluster.
+func FEMetadataUnhealthySignals(frontends []*Frontend, backends []*Backend) []string {
+	var signals []string
+	var masters []string
+	clusterIds := map[string]bool{}
+	for _, fe := range frontends {
+		if fe.IsMaster {
+			masters = append(masters, fe.Host)
+		}
+		if fe.ClusterId != "" {
+			clusterIds[fe.ClusterId] = true
+		}
+	}
+	if len(masters) > 1 {
+		signals = append(signals, fmt.Sprintf("multiple fe masters %s", strings.Join(masters, ",")))
+	}
+	if len(clusterIds) > 1 {
+		var ids []string
+		for id := range clusterIds {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		signals = append(signals, fmt.Sprintf("frontends have different cluster ids %s", strings.Join(ids, ",")))
+	}
+
+	backendIds := map[string]int{}
+	var duplicates []string
+	for _, be := range backends {
+		backendIds[be.BackendID]++
+		if backendIds[be.BackendID] == 2 {
+			duplicates = append(duplicates, be.BackendID)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(be.Tag), &m); err != nil || m[COMPUTE_GROUP_ID] == nil {
+			signals = append(signals, fmt.Sprintf("backend %s(%s) tag %q have not compute group id", be.BackendID, be.Host, be.Tag))
+		}
+	}
+	if len(duplicates) != 0 {
+		signals = append(signals, fmt.Sprintf("duplicate backend ids %s", strings.Join(duplicates, ",")))
+	}
+	return signals
+}
@@ -18,13 +18,19 @@
 package mysql
 
 import (
+	"context"
 	_ "crypto/tls"
 	"database/sql/driver"
+	"errors"
+	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func Test_ShowFrontends(t *testing.T) {
@@ -175,6 +181,49 @@ func Test_DropObserver(t *testing.T) {
 	}
 }
 
+func Test_AddObserver(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(`ALTER SYSTEM ADD OBSERVER "doriscluster-sample-fe-3.doriscluster-sample-fe-internal.default.svc.cluster.local:9010";`).WillReturnResult(sqlmock.NewResult(1, 1))
+	dorisdb := sqlx.NewDb(mysql_db, "mysql")
+	db := &DB{
+		DB: dorisdb,
+	}
+	defer db.Close()
+
+	if err := db.AddObserver(nil); err != nil {
+		t.Errorf("add empty observers failed, err=%s", err.Error())
+	}
+	if err := db.AddObserver([]*Frontend{{Host: "doriscluster-sample-fe-3.doriscluster-sample-fe-internal.default.svc.cluster.local", EditLogPort: 9010}}); err != nil {
+		t.Errorf("add observers failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("add observers sql not expected, err=%s", err.Error())
+	}
+}
+
+func Test_DropFollower(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(`ALTER SYSTEM DROP FOLLOWER "doriscluster-sample-fe-1.doriscluster-sample-fe-internal.default.svc.cluster.local:9010";`).WillReturnResult(sqlmock.NewResult(1, 1))
+	dorisdb := sqlx.NewDb(mysql_db, "mysql")
+	db := &DB{
+		DB: dorisdb,
+	}
+	defer db.Close()
+
+	if err := db.DropFollower([]*Frontend{{Host: "doriscluster-sample-fe-1.doriscluster-sample-fe-internal.default.svc.cluster.local", EditLogPort: 9010}}); err != nil {
+		t.Errorf("drop followers failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("drop followers sql not expected, err=%s", err.Error())
+	}
+}
+
 func Test_GetObservers(t *testing.T) {
 	mysql_db, mock, err := sqlmock.New()
 	if err != nil {
@@ -227,6 +276,32 @@ func Test_GetFollowers(t *testing.T) {
 	}
 }
 
+func Test_GetFollowers_NoMaster(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+
+	columns := []string{"Name", "Host", "EditLogPort", "HttpPort", "QueryPort", "RpcPort", "ArrowFlightSqlPort", "Role", "IsMaster",
+		"ClusterId", "Join", "Alive", "ReplayedJournalId", "LastStartTime", "LastHeartbeat", "IsHelper", "ErrMsg", "Version", "CurrentConnected"}
+	values := []driver.Value{"fe_36d7bccc_d358_4dfd_ad4c_6e988f94f12d", "doriscluster-sample-fe-0.doriscluster-sample-fe-internal.default.svc.cluster.local", 9010, 8030, 9030, 9020, -1, "FOLLOWER", false, "1807668748", true, true, "15443", "2024-08-21 10:04:29",
+		"2024-08-22 07:29:55", true, "", "doris-2.1.5-rc02-d5a02e095d", "Yes"}
+	mock.ExpectQuery("show frontends").WillReturnRows(sqlmock.NewRows(columns).AddRows(values))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	//the fe is up but not elected master, the master should be nil for callers waiting election.
+	master, fts, err := db.GetFollowers()
+	if err != nil {
+		t.Errorf("get followers failed, err=%s", err.Error())
+	}
+	if master != nil || len(fts) != 1 {
+		t.Errorf("get followers expected no master and 1 follower, got master %v, followers %d", master, len(fts))
+	}
+}
+
 func Test_DropBE(t *testing.T) {
 	tests := [][]*Backend{
 		{
@@ -261,3 +336,362 @@ func Test_DropBE(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetBalancingTabletsNum(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	columns := []string{"TabletId", "Type", "Status", "State"}
+	mock.ExpectQuery("show proc '/cluster_balance/pending_tablets'").WillReturnRows(sqlmock.NewRows(columns).AddRow("10001", "BALANCE", "HEALTHY", "PENDING"))
+	mock.ExpectQuery("show proc '/cluster_balance/running_tablets'").WillReturnRows(sqlmock.NewRows(columns).AddRow("10002", "REPAIR", "REPLICA_MISSING", "RUNNING").AddRow("10003", "BALANCE", "HEALTHY", "RUNNING"))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	num, err := db.GetBalancingTabletsNum()
+	if err != nil {
+		t.Errorf("GetBalancingTabletsNum failed, %s", err.Error())
+	}
+	if num != 3 {
+		t.Errorf("GetBalancingTabletsNum expected 3, got %d", num)
+	}
+}
+
+func Test_GetBackendCacheHitRatios(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT BE_ID, METRIC_VALUE FROM information_schema.file_cache_statistics WHERE METRIC_NAME = 'hits_ratio'")).
+		WillReturnRows(sqlmock.NewRows([]string{"BE_ID", "METRIC_VALUE"}).AddRow("10001", "0.8").AddRow("10001", "0.6").AddRow("10002", "0.1"))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	ratios, err := db.GetBackendCacheHitRatios()
+	if err != nil {
+		t.Errorf("GetBackendCacheHitRatios failed, %s", err.Error())
+	}
+	if len(ratios) != 2 || ratios["10001"] < 0.69 || ratios["10001"] > 0.71 || ratios["10002"] != 0.1 {
+		t.Errorf("GetBackendCacheHitRatios expected 10001=0.7 10002=0.1, got %v", ratios)
+	}
+}
+
+func Test_HasNodePrivilege(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	columns := []string{"UserIdentity", "Comment", "Password", "Roles", "GlobalPrivs", "CatalogPrivs", "DatabasePrivs", "TablePrivs"}
+	mock.ExpectQuery("show grants").WillReturnRows(sqlmock.NewRows(columns).AddRow("'operator'@'%'", "", "Yes", "", "Node_priv  (false)", nil, nil, nil))
+	mock.ExpectQuery("show grants").WillReturnRows(sqlmock.NewRows(columns).AddRow("'reader'@'%'", "", "Yes", "", "Select_priv  (false)", nil, nil, nil))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	if ok, err := db.HasNodePrivilege(); err != nil || !ok {
+		t.Errorf("HasNodePrivilege expected true for Node_priv, got %t, err=%v", ok, err)
+	}
+	if ok, err := db.HasNodePrivilege(); err != nil || ok {
+		t.Errorf("HasNodePrivilege expected false for Select_priv, got %t, err=%v", ok, err)
+	}
+}
+
+func Test_ShowStorageVaults(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectQuery("show storage vault").WillReturnRows(sqlmock.NewRows([]string{"StorageVaultName", "StorageVaultId", "Propeties", "IsDefault"}).
+		AddRow("s3_vault", "1", "type: S3", "true").AddRow("hdfs_vault", "2", "type: HDFS", "false"))
+	mock.ExpectQuery("show storage vault").WillReturnRows(sqlmock.NewRows([]string{"Name", "Id", "Properties", "IsDefault"}))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	vaults, err := db.ShowStorageVaults()
+	if err != nil || len(vaults) != 2 || vaults[0].Name != "s3_vault" || !vaults[0].IsDefault || vaults[1].IsDefault {
+		t.Errorf("ShowStorageVaults expected s3_vault as default and hdfs_vault, got %+v, err=%v", vaults, err)
+	}
+	if vaults, err = db.ShowStorageVaults(); err != nil || len(vaults) != 0 {
+		t.Errorf("ShowStorageVaults expected empty, got %+v, err=%v", vaults, err)
+	}
+}
+
+func Test_SetWorkloadGroupMemoryLimit(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta("ALTER WORKLOAD GROUP `normal` FOR `cg1` PROPERTIES ('memory_limit'='50%');")).WillReturnResult(sqlmock.NewResult(0, 0))
+	//the names with backtick and quote not break out of the identifiers.
+	mock.ExpectExec(regexp.QuoteMeta("ALTER WORKLOAD GROUP `normal` FOR `cg``1'; DROP DATABASE d;` PROPERTIES ('memory_limit'='50\\'%');")).WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	if err := db.SetWorkloadGroupMemoryLimit("normal", "cg1", "50%"); err != nil {
+		t.Errorf("SetWorkloadGroupMemoryLimit failed, err=%s", err.Error())
+	}
+	if err := db.SetWorkloadGroupMemoryLimit("normal", "cg`1'; DROP DATABASE d;", "50'%"); err != nil {
+		t.Errorf("SetWorkloadGroupMemoryLimit with quoted names failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("SetWorkloadGroupMemoryLimit sql not expected, err=%s", err.Error())
+	}
+}
+
+func Test_AddBE(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM ADD BACKEND "test-cg1-0.test-cg1.default.svc.cluster.local:9050","test-cg1-1.test-cg1.default.svc.cluster.local:9050" PROPERTIES ("tag.compute_group_name" = 'cg1');`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	nodes := []*Backend{{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050}, {Host: "test-cg1-1.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050}}
+	if err := db.AddBE(nodes, "cg1"); err != nil {
+		t.Errorf("AddBE failed, err=%s", err.Error())
+	}
+	//the name matched by a custom regex not breaks the statement.
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM ADD BACKEND "test-cg1-0.test-cg1.default.svc.cluster.local:9050" PROPERTIES ("tag.compute_group_name" = 'cg\'1');`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := db.AddBE(nodes[:1], "cg'1"); err != nil {
+		t.Errorf("AddBE failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("AddBE sql not expected, err=%s", err.Error())
+	}
+}
+
+func Test_ModifyBackendTags(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM MODIFY BACKEND "test-cg1-0.test-cg1.default.svc.cluster.local:9050" SET ("tag.location" = 'zone-a', "tag.rack" = 'r1";');`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	node := &Backend{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050}
+	if err := db.ModifyBackendTags(node, map[string]string{"rack": `r1";`, "location": "zone-a"}); err != nil {
+		t.Errorf("ModifyBackendTags failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("ModifyBackendTags sql not expected, err=%s", err.Error())
+	}
+}
+
+func Test_SetBackendQueryDisabled(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM MODIFY BACKEND "test-cg1-0.test-cg1.default.svc.cluster.local:9050" SET ("disable_query" = "true");`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &DB{
+		DB: sqlx.NewDb(mysql_db, "mysql"),
+	}
+	defer db.Close()
+
+	node := &Backend{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050}
+	if err := db.SetBackendQueryDisabled(node, true); err != nil {
+		t.Errorf("SetBackendQueryDisabled failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("SetBackendQueryDisabled sql not expected, err=%s", err.Error())
+	}
+}
+
+func Test_BackendQueryDisabled(t *testing.T) {
+	be := &Backend{Status: `{"lastSuccessReportTabletsTime":"2024-10-16 10:00:00","lastStreamLoadTime":-1,"isQueryDisabled":true,"isLoadDisabled":false}`}
+	if disabled, err := be.QueryDisabled(); err != nil || !disabled {
+		t.Errorf("QueryDisabled expect true, got %t, err=%v", disabled, err)
+	}
+	if disabled, err := (&Backend{}).QueryDisabled(); err != nil || disabled {
+		t.Errorf("QueryDisabled empty status expect false, got %t, err=%v", disabled, err)
+	}
+	if _, err := (&Backend{Status: "{"}).QueryDisabled(); err == nil {
+		t.Errorf("QueryDisabled expect error for invalid status.")
+	}
+}
+
+func Test_FEMetadataUnhealthySignals(t *testing.T) {
+	tag := `{"compute_group_id" : "cg1id"}`
+	frontends := []*Frontend{{Host: "fe-0", IsMaster: true, ClusterId: "1807668748"}, {Host: "fe-1", ClusterId: "1807668748"}}
+	backends := []*Backend{{BackendID: "10001", Host: "be-0", Tag: tag}, {BackendID: "10002", Host: "be-1", Tag: tag}}
+	if signals := FEMetadataUnhealthySignals(frontends, backends); len(signals) != 0 {
+		t.Errorf("FEMetadataUnhealthySignals of healthy metadata expected empty, got %v", signals)
+	}
+
+	frontends[1].IsMaster = true
+	frontends[1].ClusterId = "1"
+	backends[1].BackendID = "10001"
+	backends = append(backends, &Backend{BackendID: "10003", Host: "be-2", Tag: "{}"})
+	if signals := FEMetadataUnhealthySignals(frontends, backends); len(signals) != 4 {
+		t.Errorf("FEMetadataUnhealthySignals expected multiple masters, different cluster ids, duplicate backend ids and invalid tag, got %v", signals)
+	}
+}
+
+func Test_masterQueryPort(t *testing.T) {
+	if port := masterQueryPort(&Frontend{Host: "fe-0", QueryPort: 9030}, "9031"); port != "9030" {
+		t.Errorf("masterQueryPort expect the port master runs with 9030, got %s", port)
+	}
+	if port := masterQueryPort(&Frontend{Host: "fe-0"}, "9031"); port != "9031" {
+		t.Errorf("masterQueryPort expect config port 9031 when master not report, got %s", port)
+	}
+}
+
+func Test_checkConnectedMaster(t *testing.T) {
+	columns := []string{"Host", "Role", "IsMaster", "CurrentConnected"}
+	tests := []struct {
+		name    string
+		rows    [][]driver.Value
+		wantErr bool
+	}{
+		{"connected master", [][]driver.Value{{"fe-0", "FOLLOWER", true, "Yes"}, {"fe-1", "FOLLOWER", false, "No"}}, false},
+		{"connected follower", [][]driver.Value{{"fe-0", "FOLLOWER", true, "No"}, {"fe-1", "FOLLOWER", false, "Yes"}}, true},
+		{"connected not found", [][]driver.Value{{"fe-0", "FOLLOWER", true, "No"}}, true},
+	}
+	for _, tt := range tests {
+		mdb, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock new failed %s", err.Error())
+		}
+		mock.ExpectQuery("show frontends").WillReturnRows(sqlmock.NewRows(columns).AddRows(tt.rows...))
+		db := &DB{DB: sqlx.NewDb(mdb, "mysql")}
+		err = db.checkConnectedMaster()
+		if tt.wantErr != (err != nil) || (err != nil && !errors.Is(err, ErrFEMasterUnreachable)) {
+			t.Errorf("%s checkConnectedMaster expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		db.Close()
+	}
+}
+
+func Test_buildDSN(t *testing.T) {
+	cfg := DBConfig{User: "root", Password: "pwd", Host: "test-fe.default", Port: "9030", Database: "mysql"}
+	if dsn := buildDSN(cfg, ""); dsn != "root:pwd@tcp(test-fe.default:9030)/mysql?timeout=5s&readTimeout=30s" {
+		t.Errorf("buildDSN with default timeouts not expected, got %s", dsn)
+	}
+
+	cfg.ConnectTimeout = 2 * time.Second
+	cfg.ReadTimeout = time.Minute
+	dsn := buildDSN(cfg, "default-tls")
+	if dsn != "root:pwd@tcp(test-fe.default:9030)/mysql?timeout=2s&readTimeout=1m0s&tls=default-tls" {
+		t.Errorf("buildDSN with timeouts and tls not expected, got %s", dsn)
+	}
+	//the dsn parsed by driver with the timeouts.
+	mc, err := mysql.ParseDSN(buildDSN(cfg, ""))
+	if err != nil {
+		t.Fatalf("buildDSN parse dsn failed, err=%s", err.Error())
+	}
+	if mc.Timeout != 2*time.Second || mc.ReadTimeout != time.Minute {
+		t.Errorf("buildDSN parsed config not expected, got timeout %s readTimeout %s", mc.Timeout, mc.ReadTimeout)
+	}
+}
+
+func Test_DBContextCancelled(t *testing.T) {
+	mysql_db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	db := &DB{DB: sqlx.NewDb(mysql_db, "mysql"), ctx: ctx}
+	defer db.Close()
+	cancel()
+
+	if _, err := db.ShowFrontends(); !errors.Is(err, context.Canceled) {
+		t.Errorf("show frontends expected returned by cancelled context, got err=%v", err)
+	}
+	if _, err := db.Exec("ALTER SYSTEM DROP OBSERVER \"test-fe-3:9010\""); !errors.Is(err, context.Canceled) {
+		t.Errorf("exec expected returned by cancelled context, got err=%v", err)
+	}
+}
+
+func Test_NewDorisMasterSqlDBWithRetry(t *testing.T) {
+	defer func(connect func(context.Context, DBConfig, *TLSConfig, *corev1.Secret) (*DB, error)) {
+		connectMasterSqlDB = connect
+	}(connectMasterSqlDB)
+
+	//the fake dialer fails the first 2 attempts.
+	var hosts []string
+	connectMasterSqlDB = func(ctx context.Context, conf DBConfig, tlsConfig *TLSConfig, secret *corev1.Secret) (*DB, error) {
+		hosts = append(hosts, conf.Host)
+		if len(hosts) <= 2 {
+			return nil, ErrFEMasterUnreachable
+		}
+		return &DB{User: conf.User}, nil
+	}
+	conf := DBConfig{User: "root", Host: "test-fe.default", FallbackHosts: []string{"test-fe-0.test-fe-internal.default", "test-fe-1.test-fe-internal.default"}}
+	db, err := NewDorisMasterSqlDBWithRetry(context.Background(), conf, 3, time.Millisecond, nil, nil)
+	if err != nil || db == nil {
+		t.Fatalf("NewDorisMasterSqlDBWithRetry expected connected at the third attempt, err=%v", err)
+	}
+	if len(hosts) != 3 || hosts[0] != "test-fe.default" || hosts[1] != conf.FallbackHosts[0] || hosts[2] != conf.FallbackHosts[1] {
+		t.Errorf("NewDorisMasterSqlDBWithRetry expected rotate the hosts, got %v", hosts)
+	}
+
+	//all attempts failed return the last error.
+	hosts = nil
+	if _, err := NewDorisMasterSqlDBWithRetry(context.Background(), conf, 2, time.Millisecond, nil, nil); !errors.Is(err, ErrFEMasterUnreachable) || len(hosts) != 2 {
+		t.Errorf("NewDorisMasterSqlDBWithRetry expected failed after 2 attempts, attempts %d err=%v", len(hosts), err)
+	}
+
+	//the cancelled ctx stops retrying.
+	hosts = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewDorisMasterSqlDBWithRetry(ctx, conf, 3, time.Minute, nil, nil); err == nil || len(hosts) != 1 {
+		t.Errorf("NewDorisMasterSqlDBWithRetry expected stop retrying by cancelled ctx, attempts %d err=%v", len(hosts), err)
+	}
+}
+
+func Test_newTLSConfig(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{"ca.crt": []byte("not a pem")}}
+	if _, err := newTLSConfig(&TLSConfig{CAFileName: "ca.crt"}, secret); err == nil {
+		t.Errorf("newTLSConfig expected error when the ca not valid")
+	}
+	if _, err := newTLSConfig(&TLSConfig{CAFileName: "ca.crt", InsecureSkipVerify: true}, secret); err == nil {
+		t.Errorf("newTLSConfig expected error when the ca set but not valid with skip verify")
+	}
+
+	tc, err := newTLSConfig(&TLSConfig{CAFileName: "ca.crt", ClientCertFileName: "tls.crt", ClientKeyFileName: "tls.key", InsecureSkipVerify: true, ClientCertOptional: true}, &corev1.Secret{})
+	if err != nil {
+		t.Fatalf("newTLSConfig expected no error when skip verify and client certificate optional, err=%s", err.Error())
+	}
+	if !tc.InsecureSkipVerify || tc.RootCAs != nil || len(tc.Certificates) != 0 {
+		t.Errorf("newTLSConfig expected skip verify without ca and client certificate, got %+v", tc)
+	}
+	if _, err := newTLSConfig(&TLSConfig{ClientCertFileName: "tls.crt", ClientKeyFileName: "tls.key", InsecureSkipVerify: true}, &corev1.Secret{}); err == nil {
+		t.Errorf("newTLSConfig expected error when the client certificate required but not exist")
+	}
+}
+
+func Test_GrantComputeGroupUsage(t *testing.T) {
+	mysql_db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO ROLE 'r\'1\\';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &DB{DB: sqlx.NewDb(mysql_db, "mysql")}
+	defer db.Close()
+	if err := db.GrantComputeGroupUsage("cg1", `r'1\`, true); err != nil {
+		t.Errorf("grant compute group usage failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("grant compute group usage sql not expected, err=%s", err.Error())
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"strings"
 
 	dorisv1 "github.com/apache/doris-operator/api/doris/v1"
 	"github.com/spf13/viper"
@@ -210,3 +211,53 @@ func GetDorisCoreConfigMapNames(dcr *dorisv1.DorisCluster) map[dorisv1.Component
 
 	return dorisCoreConfigMaps
 }
+
+// MergeConfigMapsData merge the data of configmaps in order, the later configmap overrides the earlier.
+// the start config file(resolveKey, ep: be.conf) is merged by properties, other files are replaced as a whole.
+func MergeConfigMapsData(resolveKey string, cms []*corev1.ConfigMap) map[string]string {
+	data := map[string]string{}
+	var startConfs []string
+	for _, cm := range cms {
+		if cm == nil {
+			continue
+		}
+		for k, v := range cm.Data {
+			if k == resolveKey {
+				startConfs = append(startConfs, v)
+				continue
+			}
+			data[k] = v
+		}
+	}
+	if len(startConfs) != 0 {
+		data[resolveKey] = MergeProperties(startConfs...)
+	}
+	return data
+}
+
+// MergeProperties merge the properties contents in order, the value of later content overrides the earlier.
+// the keys keep the order of first appeared, comments and empty lines are dropped.
+func MergeProperties(contents ...string) string {
+	var keys []string
+	values := map[string]string{}
+	for _, content := range contents {
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+				continue
+			}
+			key, value, _ := strings.Cut(line, "=")
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			values[key] = value
+		}
+	}
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key + " = " + values[key] + "\n")
+	}
+	return b.String()
+}
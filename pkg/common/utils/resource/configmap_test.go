@@ -20,6 +20,7 @@ package resource
 import (
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	dorisv1 "github.com/apache/doris-operator/api/doris/v1"
@@ -190,3 +191,32 @@ func Test_getCoreCmName(t *testing.T) {
 		})
 	}
 }
+
+func Test_MergeConfigMapsData(t *testing.T) {
+	base := &corev1.ConfigMap{Data: map[string]string{
+		"be.conf":   "# base config\nbe_port = 9060\nmem_limit = 80%\nfile_cache_path = [{\"path\":\"/opt/cache\"}]\n",
+		"log4j.xml": "base",
+	}}
+	overlay1 := &corev1.ConfigMap{Data: map[string]string{
+		"be.conf": "mem_limit=70%\nenable_file_cache = true",
+	}}
+	overlay2 := &corev1.ConfigMap{Data: map[string]string{
+		"be.conf":   "mem_limit = 60%\n",
+		"log4j.xml": "overlay",
+	}}
+
+	data := MergeConfigMapsData("be.conf", []*corev1.ConfigMap{base, nil, overlay1, overlay2})
+	expectConf := "be_port = 9060\nmem_limit = 60%\nfile_cache_path = [{\"path\":\"/opt/cache\"}]\nenable_file_cache = true\n"
+	if data["be.conf"] != expectConf {
+		t.Errorf("MergeConfigMapsData be.conf expected the later overrides the earlier, got %q", data["be.conf"])
+	}
+	if data["log4j.xml"] != "overlay" {
+		t.Errorf("MergeConfigMapsData expected the later file replaces the earlier, got %q", data["log4j.xml"])
+	}
+
+	//the reversed order reverses the precedence.
+	data = MergeConfigMapsData("be.conf", []*corev1.ConfigMap{overlay2, overlay1, base})
+	if !strings.Contains(data["be.conf"], "mem_limit = 80%") || data["log4j.xml"] != "base" {
+		t.Errorf("MergeConfigMapsData expected the base overrides when it is the last, got %v", data)
+	}
+}
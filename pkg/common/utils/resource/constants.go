@@ -35,4 +35,16 @@ const (
 	STATEFULSET_NAME = "STATEFULSET_NAME"
 
 	COMPUTE_GROUP_NAME = "COMPUTE_GROUP_NAME"
+
+	// the port of backend for graceful stop, used by be_disaggregated_prestop script.
+	GRACEFUL_STOP_PORT = "GRACEFUL_STOP_PORT"
+
+	// be memory config derived from container memory, the unit is byte.
+	BE_MEM_LIMIT                  = "BE_MEM_LIMIT"
+	BE_STORAGE_PAGE_CACHE_LIMIT   = "BE_STORAGE_PAGE_CACHE_LIMIT"
+	BE_CHUNK_RESERVED_BYTES_LIMIT = "BE_CHUNK_RESERVED_BYTES_LIMIT"
+
+	// be log rolling config, reference them in be.conf as `sys_log_roll_mode` and `sys_log_roll_num`.
+	BE_SYS_LOG_ROLL_MODE = "BE_SYS_LOG_ROLL_MODE"
+	BE_SYS_LOG_ROLL_NUM  = "BE_SYS_LOG_ROLL_NUM"
 )
@@ -88,6 +88,9 @@ const (
 	DISAGGREGATED_FE_MAIN_CONTAINER_NAME = "fe"
 	DISAGGREGATED_BE_MAIN_CONTAINER_NAME = "compute"
 	DISAGGREGATED_MS_MAIN_CONTAINER_NAME= "metaservice"
+
+	// the sidecar container recycles the expired files in be log volume.
+	DISAGGREGATED_BE_LOG_RETENTION_CONTAINER_NAME = "log-retention"
 )
 
 type ProbeType string
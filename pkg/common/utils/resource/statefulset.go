@@ -18,11 +18,14 @@
 package resource
 
 import (
+	"reflect"
+
 	v1 "github.com/apache/doris-operator/api/doris/v1"
 	"github.com/apache/doris-operator/pkg/common/utils/hash"
 	"github.com/apache/doris-operator/pkg/common/utils/metadata"
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 )
@@ -182,6 +185,8 @@ func clear_config_env_path_numbers_alwaysEquals(new *appv1.StatefulSet, old *app
 	}
 }
 
+// StatefulsetDeepEqualWithKey compare the hash of StatefulsetComparedFields in new with the hash stored in the annotation of old, the hash is assigned to the annotation of new.
+// excludeReplicas true not compare the replicas. the fields not in StatefulsetComparedFields are not compared, the changes of them are not applied until others changed.
 func StatefulsetDeepEqualWithKey(new, old *appv1.StatefulSet, annoKey string, excludeReplicas bool) bool {
 	/*	if omit {
 		newHso := statefulSetHashObject(new, excludeReplicas)
@@ -219,7 +224,11 @@ func StatefulsetDeepEqualWithKey(new, old *appv1.StatefulSet, annoKey string, ex
 		new.Namespace == old.Namespace
 }
 
-// hashStatefulsetObject contains the info for hash comparison.
+// StatefulsetComparedFields the fields of statefulset compared by StatefulsetDeepEqualWithKey and StatefulsetEquivalent.
+// the others, ep: annotations, updateStrategy, podManagementPolicy, minReadySeconds, persistentVolumeClaimRetentionPolicy, are not compared.
+var StatefulsetComparedFields = []string{"metadata.name", "metadata.namespace", "metadata.labels", "spec.selector", "spec.template", "spec.serviceName", "spec.volumeClaimTemplates", "spec.replicas"}
+
+// hashStatefulsetObject contains the info for hash comparison, the fields are StatefulsetComparedFields.
 type hashStatefulsetObject struct {
 	name                 string
 	namespace            string
@@ -244,20 +253,100 @@ func statefulSetHashObject(st *appv1.StatefulSet, excludeReplica bool) hashState
 	if st.Spec.Selector != nil {
 		selector = *st.Spec.Selector
 	}
+	//the empty labels and annotations are same as not set, avoid rolling pods when metadata only serialized differently.
+	podTemplate := st.Spec.Template
+	podTemplate.Labels = emptyMapToNil(podTemplate.Labels)
+	podTemplate.Annotations = emptyMapToNil(podTemplate.Annotations)
 
 	return hashStatefulsetObject{
 		name:                 st.Name,
 		namespace:            st.Namespace,
-		labels:               st.Labels,
+		labels:               emptyMapToNil(st.Labels),
 		selector:             selector,
-		podTemplate:          st.Spec.Template,
+		podTemplate:          podTemplate,
 		serviceName:          st.Spec.ServiceName,
 		volumeClaimTemplates: st.Spec.VolumeClaimTemplates,
 		replicas:             replicas,
 	}
 }
 
+func emptyMapToNil(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
 // MergeStatefulSets merge exist statefulset and new statefulset.
 func MergeStatefulSets(new *appv1.StatefulSet, old appv1.StatefulSet) {
 	MergeMetadata(&new.ObjectMeta, old.ObjectMeta)
 }
+
+// StatefulsetEquivalent return true when the StatefulsetComparedFields of new already hold in old, the old is the statefulset read from apiserver.
+// it is used when the hash not equal, ep: the hash annotation removed or the hash changed by upgrading operator, the update would write nothing material.
+// the zero value fields and nil pointers in new are considered defaulted by apiserver, so clearing a scalar field is not detected. the slices and maps should have the same length and keys.
+func StatefulsetEquivalent(new, old *appv1.StatefulSet, excludeReplicas bool) bool {
+	if new.Name != old.Name || new.Namespace != old.Namespace || new.Spec.ServiceName != old.Spec.ServiceName ||
+		!reflect.DeepEqual(emptyMapToNil(new.Labels), emptyMapToNil(old.Labels)) || !reflect.DeepEqual(new.Spec.Selector, old.Spec.Selector) {
+		return false
+	}
+	if !excludeReplicas && !reflect.DeepEqual(new.Spec.Replicas, old.Spec.Replicas) {
+		return false
+	}
+	return derived(reflect.ValueOf(new.Spec.Template), reflect.ValueOf(old.Spec.Template)) &&
+		derived(reflect.ValueOf(new.Spec.VolumeClaimTemplates), reflect.ValueOf(old.Spec.VolumeClaimTemplates))
+}
+
+var quantityType = reflect.TypeOf(apiresource.Quantity{})
+
+// derived return true when the set values of desired are equal to actual, the zero values of desired are skipped.
+func derived(desired, actual reflect.Value) bool {
+	if desired.Type() == quantityType {
+		dq, aq := desired.Interface().(apiresource.Quantity), actual.Interface().(apiresource.Quantity)
+		return dq.IsZero() || dq.Cmp(aq) == 0
+	}
+
+	switch desired.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if desired.IsNil() {
+			return true
+		}
+		if actual.IsNil() {
+			return false
+		}
+		return derived(desired.Elem(), actual.Elem())
+	case reflect.Struct:
+		for i := 0; i < desired.NumField(); i++ {
+			if !desired.Type().Field(i).IsExported() {
+				continue
+			}
+			if !derived(desired.Field(i), actual.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if desired.Len() != actual.Len() {
+			return false
+		}
+		for i := 0; i < desired.Len(); i++ {
+			if !derived(desired.Index(i), actual.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if desired.Len() != actual.Len() {
+			return false
+		}
+		for _, k := range desired.MapKeys() {
+			av := actual.MapIndex(k)
+			if !av.IsValid() || !derived(desired.MapIndex(k), av) {
+				return false
+			}
+		}
+		return true
+	default:
+		return desired.IsZero() || desired.Interface() == actual.Interface()
+	}
+}
@@ -18,8 +18,10 @@ package resource
 
 import (
 	v1 "github.com/apache/doris-operator/api/doris/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/metadata"
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
@@ -88,3 +90,84 @@ func Test_StatefulsetSetDeepEqual(t *testing.T) {
 		}
 	}
 }
+
+func Test_StatefulsetDeepEqualWithKey_MetadataOnly(t *testing.T) {
+	nst := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cg1", Namespace: "default"},
+		Spec: appv1.StatefulSetSpec{
+			Replicas: GetInt32Pointer(1),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Name: "compute", Labels: map[string]string{"app": "doris"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "compute", Image: "apache/doris:be-3.0.3"}}},
+			},
+		},
+	}
+	annoKey := "doris.disaggregated.cluster/spec-hash"
+
+	//the old statefulset stores the hash of new statefulset when applied.
+	applied := func(st *appv1.StatefulSet) *appv1.StatefulSet {
+		ost := st.DeepCopy()
+		StatefulsetDeepEqualWithKey(ost, &appv1.StatefulSet{}, annoKey, false)
+		return ost
+	}
+
+	annoChanged := nst.DeepCopy()
+	annoChanged.Spec.Template.Annotations = map[string]string{"prometheus.io/scrape": "true"}
+	labelChanged := nst.DeepCopy()
+	labelChanged.Spec.Template.Labels["team"] = "olap"
+	labelRemoved := nst.DeepCopy()
+	labelRemoved.Spec.Template.Labels = nil
+	emptyAnno := nst.DeepCopy()
+	emptyAnno.Spec.Template.Annotations = map[string]string{}
+
+	nsts := []*appv1.StatefulSet{annoChanged, labelChanged, labelRemoved, emptyAnno}
+	ress := []bool{false, false, false, true}
+	for i := range nsts {
+		if res := StatefulsetDeepEqualWithKey(nsts[i], applied(nst), annoKey, false); res != ress[i] {
+			t.Errorf("StatefulsetDeepEqualWithKey metadata only diff failed in index %d, expected %t", i, ress[i])
+		}
+	}
+}
+
+func Test_StatefulsetEquivalent(t *testing.T) {
+	nst := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cg1", Namespace: "default", Labels: map[string]string{"app": "doris"}},
+		Spec: appv1.StatefulSetSpec{
+			Replicas: GetInt32Pointer(2),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "doris"}},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name:      "compute",
+					Image:     "apache/doris:be-3.0.3",
+					Env:       []corev1.EnvVar{{Name: "k", Value: "v"}},
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: apiresource.MustParse("1Gi")}},
+				}}},
+			},
+		},
+	}
+	//the existing statefulset have the fields defaulted by apiserver.
+	est := nst.DeepCopy()
+	est.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	est.Spec.Template.Spec.TerminationGracePeriodSeconds = metadata.GetInt64ptr(30)
+	est.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+	est.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory] = apiresource.MustParse("1073741824")
+	est.Annotations = map[string]string{"doris.disaggregated.cluster/spec-hash": "stale"}
+
+	imageChanged := nst.DeepCopy()
+	imageChanged.Spec.Template.Spec.Containers[0].Image = "apache/doris:be-3.0.4"
+	envRemoved := nst.DeepCopy()
+	envRemoved.Spec.Template.Spec.Containers[0].Env = nil
+	replicasChanged := nst.DeepCopy()
+	replicasChanged.Spec.Replicas = GetInt32Pointer(3)
+
+	nsts := []*appv1.StatefulSet{nst, imageChanged, envRemoved, replicasChanged}
+	ress := []bool{true, false, false, false}
+	for i := range nsts {
+		if res := StatefulsetEquivalent(nsts[i], est, false); res != ress[i] {
+			t.Errorf("StatefulsetEquivalent failed in index %d, expected %t", i, ress[i])
+		}
+	}
+	if !StatefulsetEquivalent(replicasChanged, est, true) {
+		t.Errorf("StatefulsetEquivalent expected equivalent when replicas excluded")
+	}
+}
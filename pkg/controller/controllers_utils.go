@@ -88,6 +88,13 @@ func inconsistentComponentStatus(eStatus *v1.ComponentStatus, nStatus *v1.Compon
 		return true
 	}
 
+	//the progress of fe recorded in status, the next step depends on it.
+	if !equalSplice(eStatus.UnregisteredObservers, nStatus.UnregisteredObservers) ||
+		!equalSplice(eStatus.UnpromotedFollowers, nStatus.UnpromotedFollowers) ||
+		!reflect.DeepEqual(eStatus.FollowerPromotion, nStatus.FollowerPromotion) {
+		return true
+	}
+
 	return false
 }
 
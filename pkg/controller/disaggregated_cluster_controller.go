@@ -22,6 +22,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
+	"sync"
 	"time"
 
 	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
@@ -33,6 +36,7 @@ import (
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -41,10 +45,12 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	controller_builder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var (
@@ -61,6 +67,13 @@ type DisaggregatedClusterReconciler struct {
 	Recorder record.EventRecorder
 	Scheme   *runtime.Scheme
 	Scs      map[string]sc.DisaggregatedSubController
+	//PlanScs are the sub controllers run in plan mode, the writes and sql of them are recorded into Plan without executing.
+	PlanScs map[string]sc.DisaggregatedSubController
+	Plan    *sc.Plan
+	//the plan is shared by the plan sub controllers, serialize the plan reconciles when multiple workers configured.
+	planLock sync.Mutex
+	//the number of reconcile workers, the different clusters are reconciled in parallel.
+	MaxConcurrentReconciles int
 	//record configmap response instance. key: configMap namespacedName, value: DorisDisaggregatedCluster namespacedName
 	//wcms map[string]string
 }
@@ -68,18 +81,55 @@ type DisaggregatedClusterReconciler struct {
 func (dc *DisaggregatedClusterReconciler) Init(mgr ctrl.Manager, options *Options) {
 	//wcms := make(map[string]string)
 	scs := make(map[string]sc.DisaggregatedSubController)
+	//record the significant events of clusters into the history resources, the plan controllers not recorded.
+	history := sc.NewHistoryWriter(mgr.GetClient())
 	msc := metaservice.New(mgr)
+	msc.K8srecorder = history.Recorder(msc.K8srecorder)
+	msc.WatchNamespaces = options.WatchNamespaces
 	scs[msc.GetControllerName()] = msc
 
 	dfec := dfe.New(mgr)
+	dfec.K8srecorder = history.Recorder(dfec.K8srecorder)
+	dfec.WatchNamespaces = options.WatchNamespaces
 	scs[dfec.GetControllerName()] = dfec
 	dccsc := dcgs.New(mgr)
+	dccsc.K8srecorder = history.Recorder(dccsc.K8srecorder)
+	dccsc.ServerSideApply = options.ServerSideApply
+	dccsc.SkipEquivalentApply = options.SkipEquivalentApply
+	dccsc.Profile = options.Profile
+	dccsc.WorkerCount = options.ComputeGroupWorkers
+	dccsc.WatchNamespaces = options.WatchNamespaces
 	scs[dccsc.GetControllerName()] = dccsc
 
+	plan := &sc.Plan{}
+	planClient := sc.NewPlanClient(mgr.GetClient(), plan)
+	planRecorder := sc.NewPlanRecorder()
+	pscs := make(map[string]sc.DisaggregatedSubController)
+	pmsc := metaservice.New(mgr)
+	pmsc.K8sclient, pmsc.K8srecorder, pmsc.Plan = planClient, planRecorder, plan
+	pmsc.WatchNamespaces = options.WatchNamespaces
+	pscs[pmsc.GetControllerName()] = pmsc
+	pdfec := dfe.New(mgr)
+	pdfec.K8sclient, pdfec.K8srecorder, pdfec.Plan = planClient, planRecorder, plan
+	pdfec.WatchNamespaces = options.WatchNamespaces
+	pscs[pdfec.GetControllerName()] = pdfec
+	pdccsc := dcgs.New(mgr)
+	pdccsc.ServerSideApply = options.ServerSideApply
+	pdccsc.SkipEquivalentApply = options.SkipEquivalentApply
+	pdccsc.Profile = options.Profile
+	//the planned actions are recorded in the order of compute groups.
+	pdccsc.WorkerCount = 1
+	pdccsc.K8sclient, pdccsc.K8srecorder, pdccsc.Plan = planClient, planRecorder, plan
+	pdccsc.WatchNamespaces = options.WatchNamespaces
+	pscs[pdccsc.GetControllerName()] = pdccsc
+
 	if err := (&DisaggregatedClusterReconciler{
-		Client:   mgr.GetClient(),
-		Recorder: mgr.GetEventRecorderFor(disaggregatedClusterController),
-		Scs:      scs,
+		Client:                  mgr.GetClient(),
+		Recorder:                history.Recorder(mgr.GetEventRecorderFor(disaggregatedClusterController)),
+		Scs:                     scs,
+		PlanScs:                 pscs,
+		Plan:                    plan,
+		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
 		//wcms:     wcms,
 	}).SetupWithManager(mgr); err != nil {
 		klog.Error(err, "unable to create controller ", "disaggregatedClusterReconciler")
@@ -91,6 +141,8 @@ func (dc *DisaggregatedClusterReconciler) Init(mgr ctrl.Manager, options *Option
 			klog.Error(err, " unable to create unnamedwatches ", " controller ", " DorisDisaggregatedCluster ")
 			os.Exit(1)
 		}
+		//decommission the backends of compute group pods before evicted, enabled by decommissionOnEviction of cluster.
+		mgr.GetWebhookServer().Register(dcgs.EvictionWebhookPath, &webhook.Admission{Handler: dcgs.NewEvictionHandler(mgr, options.WatchNamespaces)})
 	}
 }
 
@@ -98,6 +150,8 @@ func (dc *DisaggregatedClusterReconciler) SetupWithManager(mgr ctrl.Manager) err
 	builder := dc.resourceBuilder(ctrl.NewControllerManagedBy(mgr))
 	builder = dc.watchPodBuilder(builder)
 	//builder = dc.watchConfigMapBuilder(builder)
+	//the workqueue never hands the same cluster to multiple workers, the status of cluster is mutated on the copy of one reconcile.
+	builder = builder.WithOptions(crcontroller.Options{MaxConcurrentReconciles: dc.MaxConcurrentReconciles})
 	return builder.Complete(dc)
 }
 
@@ -200,6 +254,11 @@ func (dc *DisaggregatedClusterReconciler) Reconcile(ctx context.Context, req rec
 		klog.Warningf("disaggreatedClusterReconciler not find resource DorisDisaggregatedCluster namespaceName %s", req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
+	//the plan mode only records the intended actions into status, the resources and fe are not changed.
+	if ddc.IsPlanMode() {
+		return dc.reconcilePlan(ctx, &ddc)
+	}
+	ddc.Status.Plan = nil
 	hv := hash.HashObject(ddc.Spec)
 
 	var res ctrl.Result
@@ -227,7 +286,7 @@ func (dc *DisaggregatedClusterReconciler) Reconcile(ctx context.Context, req rec
 		//reorganize status.
 		var stsRes ctrl.Result
 		var stsErr error
-		if stsRes, stsErr = dc.reorganizeStatus(&ddc); stsErr != nil {
+		if stsRes, stsErr = dc.reorganizeStatus(ctx, &ddc); stsErr != nil {
 			return stsRes, stsErr
 		}
 
@@ -251,6 +310,47 @@ func (dc *DisaggregatedClusterReconciler) Reconcile(ctx context.Context, req rec
 	return res, nil
 }
 
+// reconcilePlan run the full reconcile by the plan sub controllers on a copy of cluster, the writes of resources and sql are recorded into status.plan.
+// the spec and status changed by reconciling are discarded, only the plan updated.
+func (dc *DisaggregatedClusterReconciler) reconcilePlan(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) (ctrl.Result, error) {
+	dc.planLock.Lock()
+	defer dc.planLock.Unlock()
+	pddc := ddc.DeepCopy()
+	dc.Plan.Reset()
+	//run the sub controllers in fixed order, the same spec gets the same plan.
+	var names []string
+	for name := range dc.PlanScs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := dc.PlanScs[name].Sync(ctx, pddc); err != nil {
+			klog.Infof("disaggreatedClusterReconciler plan sub reconciler %s sync namespace %s name %s err=%s.", name, ddc.Namespace, ddc.Name, err.Error())
+		}
+	}
+	for _, name := range names {
+		dc.PlanScs[name].ClearResources(ctx, pddc)
+	}
+	for _, name := range names {
+		if err := dc.PlanScs[name].UpdateComponentStatus(ctx, pddc); err != nil {
+			klog.Infof("disaggreatedClusterReconciler plan sub reconciler %s update status namespace %s name %s err=%s.", name, ddc.Namespace, ddc.Name, err.Error())
+		}
+	}
+
+	actions := dc.Plan.Actions()
+	//the status updating triggers reconcile, not update when the plan not changed.
+	if ddc.Status.Plan != nil && ddc.Status.Plan.ObservedGeneration == ddc.Generation && reflect.DeepEqual(ddc.Status.Plan.Actions, actions) {
+		return ctrl.Result{}, nil
+	}
+	ddc.Status.Plan = &dv1.ReconcilePlan{ObservedGeneration: ddc.Generation, PlanTime: metav1.Now(), Actions: actions}
+	klog.Infof("disaggreatedClusterReconciler namespace %s name %s planned %d actions.", ddc.Namespace, ddc.Name, len(actions))
+	if err := dc.Status().Update(ctx, ddc); err != nil {
+		klog.Errorf("disaggreatedClusterReconciler update plan of DorisDisaggregatedCluster namespace %s name %s failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
 func (dc *DisaggregatedClusterReconciler) clearUnusedResources(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) (ctrl.Result, error) {
 	for _, subC := range dc.Scs {
 		subC.ClearResources(ctx, ddc)
@@ -259,10 +359,10 @@ func (dc *DisaggregatedClusterReconciler) clearUnusedResources(ctx context.Conte
 	return ctrl.Result{}, nil
 }
 
-func (dc *DisaggregatedClusterReconciler) reorganizeStatus(ddc *dv1.DorisDisaggregatedCluster) (ctrl.Result, error) {
+func (dc *DisaggregatedClusterReconciler) reorganizeStatus(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) (ctrl.Result, error) {
 	for _, sc := range dc.Scs {
 		//update component status.
-		if err := sc.UpdateComponentStatus(ddc); err != nil {
+		if err := sc.UpdateComponentStatus(ctx, ddc); err != nil {
 			klog.Errorf("DorisClusterReconciler reconcile update component %s status failed.err=%s\n", sc.GetControllerName(), err.Error())
 			// if failed, the cluster status is not green, in follow step will return requeue after 5 second. so, return error is not need.
 			//return requeueIfError(err)
@@ -273,7 +373,9 @@ func (dc *DisaggregatedClusterReconciler) reorganizeStatus(ddc *dv1.DorisDisaggr
 	ddc.Status.ClusterHealth.Health = dv1.Green
 	if ddc.Status.FEStatus.AvailableStatus != dv1.Available || ddc.Status.ClusterHealth.CGAvailableCount <= (ddc.Status.ClusterHealth.CGCount/2) {
 		ddc.Status.ClusterHealth.Health = dv1.Red
-	} else if ddc.Status.FEStatus.Phase != dv1.Ready || ddc.Status.ClusterHealth.CGAvailableCount < ddc.Status.ClusterHealth.CGCount {
+	} else if ddc.Status.FEStatus.Phase != dv1.Ready || ddc.Status.ClusterHealth.CGAvailableCount < ddc.Status.ClusterHealth.CGCount ||
+		//pods ready but not all backends registered in fe, the cluster is not fully healthy.
+		ddc.Status.ClusterHealth.AliveBackends < ddc.Status.ClusterHealth.ExpectedBackends {
 		ddc.Status.ClusterHealth.Health = dv1.Yellow
 	}
 
@@ -330,17 +432,64 @@ func (dc *DisaggregatedClusterReconciler) updateObjectORStatus(ctx context.Conte
 	}
 
 	//if decommissioning, be is migrating data should wait it over, so return reconciling after 10 seconds.
+	//if removing, the resources of removed compute group are cleaning, should continue until status removed.
+	//if quiescing, the paused scale operation resumes after fe restarting finished.
+	//if cutting over, the statefulsets of compute group are waited ready step by step.
+	//if waiting storage vault, the compute group is created after storage vault configured.
+	//if backends registering, the pods not changed when the backends alive in fe, should check again.
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.Phase == dv1.Decommissioning || cgs.Phase == dv1.Removing || cgs.Phase == dv1.QuiescingForFE || cgs.Phase == dv1.CuttingOver || cgs.Phase == dv1.WaitingStorageVault || cgs.Phase == dv1.BackendsRegistering {
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+	//if the pvcs of scaled in pods waiting snapshots ready, should delete them after snapshots ready.
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.PendingScaleInSnapshots > 0 {
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+	//if the pods draining connections, remove them after the draining timeout.
 	for _, cgs := range ddc.Status.ComputeGroupStatuses {
-		if cgs.Phase == dv1.Decommissioning {
+		if cgs.DrainingStartTime != nil {
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 	}
+	//if scale down blocked, check the reset of blocking periodically.
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.Phase == dv1.ScaleDownBlocked {
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
 
 	// If the cluster status is abnormal(Health is not Green), reconciling is required.
 	if ddc.Status.ClusterHealth.Health != dv1.Green {
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	//if scale deferred by cooldown, apply the scale after the earliest cooldown elapses.
+	var deferred time.Duration
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.ScaleDeferredUntil == nil {
+			continue
+		}
+		after := time.Until(cgs.ScaleDeferredUntil.Time)
+		if after < time.Second {
+			after = time.Second
+		}
+		if deferred == 0 || after < deferred {
+			deferred = after
+		}
+	}
+	if deferred != 0 {
+		return ctrl.Result{RequeueAfter: deferred}, nil
+	}
+	//if replicas resolved from external metric, read the metric periodically.
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.ExternalMetric != nil {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
 	return res, nil
 
 }
@@ -56,12 +56,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"strings"
+	"sync"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
 var (
@@ -80,6 +82,10 @@ type DorisClusterReconciler struct {
 	Scs      map[string]sub_controller.SubController
 	//record configmap response instance. key: configMap namespacedName, value: DorisCluster namespacedName
 	WatchConfigMaps map[string]string
+	//the WatchConfigMaps written in reconcile and read in the watch of configmaps concurrently.
+	wcmLock sync.RWMutex
+	//the number of reconcile workers, the different clusters are reconciled in parallel.
+	MaxConcurrentReconciles int
 }
 
 var (
@@ -101,8 +107,13 @@ var (
 //+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="core",resources=endpoints,verbs=get;watch;list
-//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
-//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;update;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups=external.metrics.k8s.io,resources=*,verbs=get;list
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;create
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;update;watch;delete
 //+kubebuilder:rbac:groups=admissionregistration,resources=validatingwebhookconfigurations,verbs=get;list;update;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -140,7 +151,7 @@ func (r *DorisClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		for componentType := range coreConfigMaps {
 			cmnn := types.NamespacedName{Namespace: dcr.Namespace, Name: coreConfigMaps[componentType]}
 			dcrnn := types.NamespacedName{Namespace: dcr.Namespace, Name: dcr.Name}
-			r.WatchConfigMaps[cmnn.String()] = dcrnn.String()
+			r.addWatchConfigMap(cmnn.String(), dcrnn.String())
 		}
 	}
 
@@ -220,6 +231,18 @@ func (r *DorisClusterReconciler) reconcile(dcr *dorisv1.DorisCluster) bool {
 		if dcr.Status.FEStatus.ComponentCondition.Phase != dorisv1.Available {
 			return true
 		}
+		//the observers not confirmed registered, should check again.
+		if len(dcr.Status.FEStatus.UnregisteredObservers) != 0 {
+			return true
+		}
+		//the followers promoted from observers not confirmed, should check again.
+		if len(dcr.Status.FEStatus.UnpromotedFollowers) != 0 {
+			return true
+		}
+		//the promotion in progress, continue the next step.
+		if p := dcr.Status.FEStatus.FollowerPromotion; p != nil && p.Phase != dorisv1.PromotionFailed {
+			return true
+		}
 	}
 
 	if dcr.Spec.BeSpec != nil {
@@ -307,7 +330,7 @@ func (r *DorisClusterReconciler) watchConfigMapBuilder(builder *ctrl.Builder) *c
 	mapFn := handler.EnqueueRequestsFromMapFunc(
 		func(ctx context.Context, a client.Object) []reconcile.Request {
 			cmnn := types.NamespacedName{Namespace: a.GetNamespace(), Name: a.GetName()}
-			if dcrNamespacedNameStr, ok := r.WatchConfigMaps[cmnn.String()]; ok {
+			if dcrNamespacedNameStr, ok := r.getWatchConfigMap(cmnn.String()); ok {
 				// nna[0] is namespace, nna[1] is dcrName,
 				nna := strings.Split(dcrNamespacedNameStr, "/")
 				// not run only for code standard
@@ -327,7 +350,7 @@ func (r *DorisClusterReconciler) watchConfigMapBuilder(builder *ctrl.Builder) *c
 		CreateFunc: func(u event.CreateEvent) bool {
 
 			cmnn := types.NamespacedName{Namespace: u.Object.GetNamespace(), Name: u.Object.GetName()}
-			_, ok := r.WatchConfigMaps[cmnn.String()]
+			_, ok := r.getWatchConfigMap(cmnn.String())
 			return ok
 		},
 
@@ -335,7 +358,7 @@ func (r *DorisClusterReconciler) watchConfigMapBuilder(builder *ctrl.Builder) *c
 
 			cmnn := types.NamespacedName{Namespace: u.ObjectNew.GetNamespace(), Name: u.ObjectNew.GetName()}
 
-			if _, ok := r.WatchConfigMaps[cmnn.String()]; !ok {
+			if _, ok := r.getWatchConfigMap(cmnn.String()); !ok {
 				return false
 			}
 
@@ -352,9 +375,25 @@ func (r *DorisClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := r.resourceBuilder(ctrl.NewControllerManagedBy(mgr))
 	builder = r.watchPodBuilder(builder)
 	builder = r.watchConfigMapBuilder(builder)
+	builder = builder.WithOptions(crcontroller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles})
 	return builder.Complete(r)
 }
 
+// addWatchConfigMap record the DorisCluster that the configmap response to.
+func (r *DorisClusterReconciler) addWatchConfigMap(cmNamespacedName, dcrNamespacedName string) {
+	r.wcmLock.Lock()
+	defer r.wcmLock.Unlock()
+	r.WatchConfigMaps[cmNamespacedName] = dcrNamespacedName
+}
+
+// getWatchConfigMap return the DorisCluster that the configmap response to.
+func (r *DorisClusterReconciler) getWatchConfigMap(cmNamespacedName string) (string, bool) {
+	r.wcmLock.RLock()
+	defer r.wcmLock.RUnlock()
+	dcrNamespacedName, ok := r.WatchConfigMaps[cmNamespacedName]
+	return dcrNamespacedName, ok
+}
+
 // Init initial the DorisClusterReconciler for reconcile.
 func (r *DorisClusterReconciler) Init(mgr ctrl.Manager, options *Options) {
 	subcs := make(map[string]sub_controller.SubController)
@@ -368,10 +407,11 @@ func (r *DorisClusterReconciler) Init(mgr ctrl.Manager, options *Options) {
 	subcs[brokerControllerName] = brk
 
 	if err := (&DorisClusterReconciler{
-		Client:          mgr.GetClient(),
-		Recorder:        mgr.GetEventRecorderFor(name),
-		Scs:             subcs,
-		WatchConfigMaps: make(map[string]string),
+		Client:                  mgr.GetClient(),
+		Recorder:                mgr.GetEventRecorderFor(name),
+		Scs:                     subcs,
+		WatchConfigMaps:         make(map[string]string),
+		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
 	}).SetupWithManager(mgr); err != nil {
 		klog.Error(err, " unable to create controller ", "controller ", "DorisCluster ")
 		os.Exit(1)
@@ -28,4 +28,16 @@ type Options struct {
 	Namespace string
 	//the service for operator
 	WebhookService string
+	//reconcile the statefulset and service of compute groups by server-side apply.
+	ServerSideApply bool
+	//skip updating the statefulset of compute groups when the compared fields already hold in the existing statefulset.
+	SkipEquivalentApply bool
+	//the number of reconcile workers of each controller, the default is 1.
+	MaxConcurrentReconciles int
+	//the profile that overrides the compute groups of disaggregated clusters not annotated with profile.
+	Profile string
+	//the number of compute groups of a disaggregated cluster synced in parallel.
+	ComputeGroupWorkers int
+	//the namespaces that the cache and reconcile scoped to, empty means all namespaces.
+	WatchNamespaces []string
 }
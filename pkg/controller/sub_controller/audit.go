@@ -0,0 +1,123 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sub_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the prefix of the audit log line, the json record follows it.
+const SQLAuditLogPrefix = "doris-operator-sql-audit"
+
+// the operations audited.
+const (
+	AuditDropBackend     = "DropBackend"
+	AuditDropObserver    = "DropObserver"
+	AuditPromoteObserver = "PromoteObserver"
+)
+
+// the outcomes of audited sql.
+const (
+	AuditSucceeded = "Succeeded"
+	AuditFailed    = "Failed"
+)
+
+// SQLAuditRecord is the audit record of a destructive sql that operator executed in fe. the fields and json names are stable for ingesting into SIEM, only add new fields.
+type SQLAuditRecord struct {
+	// the time executed in RFC3339 format of UTC.
+	Time string `json:"time"`
+	// Operator is the identity of the operator pod, `namespace/name`.
+	Operator string `json:"operator"`
+	// User is the user that operator connected to fe as.
+	User         string   `json:"user"`
+	Namespace    string   `json:"namespace"`
+	Cluster      string   `json:"cluster"`
+	ComputeGroup string   `json:"computeGroup,omitempty"`
+	Operation    string   `json:"operation"`
+	Nodes        []string `json:"nodes"`
+	SQL          string   `json:"sql"`
+	Outcome      string   `json:"outcome"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// AuditSQL complete the record by the cluster and the result of execution, write it as a json line in the log with SQLAuditLogPrefix and as an event with SQLAudit reason.
+// the sql not executed in plan mode is not audited.
+func AuditSQL(recorder record.EventRecorder, obj client.Object, db *mysql.DB, rec SQLAuditRecord, err error) {
+	if db.PlanRecorder != nil {
+		return
+	}
+	rec.Time = time.Now().UTC().Format(time.RFC3339)
+	rec.Operator = operatorIdentity()
+	rec.User = db.User
+	rec.Namespace = obj.GetNamespace()
+	rec.Cluster = obj.GetName()
+	rec.Outcome = AuditSucceeded
+	eventType := EventNormal
+	if err != nil {
+		rec.Outcome = AuditFailed
+		rec.Error = err.Error()
+		eventType = EventWarning
+	}
+
+	data, merr := json.Marshal(rec)
+	if merr != nil {
+		klog.Errorf("AuditSQL marshal audit record of namespace %s name %s failed, err=%s", rec.Namespace, rec.Cluster, merr.Error())
+		return
+	}
+	klog.Infof("%s %s", SQLAuditLogPrefix, string(data))
+	recorder.Event(obj, string(eventType), string(SQLAudit), string(data))
+}
+
+// BackendNodes return the `host:heartbeat_port` of backends for the audit record.
+func BackendNodes(backends []*mysql.Backend) []string {
+	var nodes []string
+	for _, b := range backends {
+		nodes = append(nodes, fmt.Sprintf("%s:%d", b.Host, b.HeartbeatPort))
+	}
+	return nodes
+}
+
+// FrontendNodes return the `host:edit_log_port` of frontends for the audit record.
+func FrontendNodes(frontends []*mysql.Frontend) []string {
+	var nodes []string
+	for _, f := range frontends {
+		nodes = append(nodes, fmt.Sprintf("%s:%d", f.Host, f.EditLogPort))
+	}
+	return nodes
+}
+
+// operatorIdentity return the namespace and name of operator pod, the envs are injected by the deployment of operator.
+func operatorIdentity() string {
+	namespace := os.Getenv("OPERATOR_NAMESPACE")
+	if namespace == "" {
+		namespace = "doris"
+	}
+	name := os.Getenv("OPERATOR_NAME")
+	if name == "" {
+		name = "doris-operator"
+	}
+	return namespace + "/" + name
+}
@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sub_controller
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestAuditSQL(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ddc := &v1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	backends := []*mysql.Backend{{Host: "test-cg1-1.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050}}
+	db := &mysql.DB{User: "admin"}
+
+	AuditSQL(recorder, ddc, db, SQLAuditRecord{ComputeGroup: "cg1", Operation: AuditDropBackend, Nodes: BackendNodes(backends), SQL: mysql.DropBESQL(backends)}, errors.New("connection refused"))
+	event := <-recorder.Events
+	if !strings.HasPrefix(event, string(EventWarning)+" "+string(SQLAudit)+" ") {
+		t.Fatalf("AuditSQL expected warning audit event, got %s", event)
+	}
+	var rec SQLAuditRecord
+	if err := json.Unmarshal([]byte(strings.SplitN(event, " ", 3)[2]), &rec); err != nil {
+		t.Fatalf("AuditSQL event message is not json record, err=%s", err.Error())
+	}
+	if rec.Namespace != "default" || rec.Cluster != "test" || rec.ComputeGroup != "cg1" || rec.User != "admin" || rec.Operator == "" || rec.Time == "" ||
+		rec.Outcome != AuditFailed || rec.Error != "connection refused" || len(rec.Nodes) != 1 || rec.Nodes[0] != "test-cg1-1.test-cg1.default.svc.cluster.local:9050" ||
+		rec.SQL != `ALTER SYSTEM DROPP BACKEND "test-cg1-1.test-cg1.default.svc.cluster.local:9050";` {
+		t.Errorf("AuditSQL record not expected, got %+v", rec)
+	}
+
+	//the sql not executed in plan mode is not audited.
+	db.PlanRecorder = func(query string) {}
+	AuditSQL(recorder, ddc, db, SQLAuditRecord{Operation: AuditDropBackend}, nil)
+	if len(recorder.Events) != 0 {
+		t.Errorf("AuditSQL expected no audit in plan mode, got %s", <-recorder.Events)
+	}
+}
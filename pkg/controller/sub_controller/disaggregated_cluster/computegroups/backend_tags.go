@@ -0,0 +1,146 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// the tags of backend maintained by fe in disaggregated cluster, modifying them breaks the compute group.
+var reservedBackendTags = map[string]bool{
+	"compute_group_name":             true,
+	"compute_group_id":               true,
+	"compute_group_status":           true,
+	"cloud_unique_id":                true,
+	"public_endpoint":                true,
+	"private_endpoint":               true,
+	"cloud_cluster_name":             true,
+	"cloud_cluster_id":               true,
+	"cloud_cluster_status":           true,
+	"cloud_cluster_public_endpoint":  true,
+	"cloud_cluster_private_endpoint": true,
+}
+
+// the key of backend tag embedded in the sql of modifying backend, same as the validation of backendTags in crd.
+var backendTagKeyRegexp = regexp.MustCompile("^[a-z_][a-z0-9_]*$")
+
+// reconcileBackendTags set the backendTags of compute group on the backends in fe, the tags differ from spec(modified by sql or not set) are set back with event.
+// when preserveManualBackendTags is true, only the missing tags are set.
+func (dcgs *DisaggregatedComputeGroupsController) reconcileBackendTags(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	if len(cg.BackendTags) == 0 || ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		return nil, nil
+	}
+	if reserved := reservedTagsIn(cg.BackendTags); len(reserved) != 0 {
+		msg := fmt.Sprintf("compute group %s backendTags %s are maintained by fe and can not be set, the backendTags not applied.", cg.UniqueId, strings.Join(reserved, ","))
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGBackendTagsInvalid, Message: msg}, errors.New(msg)
+	}
+	//the crd validates the keys, check again for the clusters created before the validation.
+	if invalid := invalidTagKeysIn(cg.BackendTags); len(invalid) != 0 {
+		msg := fmt.Sprintf("compute group %s backendTags keys %q not match %s, the backendTags not applied.", cg.UniqueId, invalid, backendTagKeyRegexp.String())
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGBackendTagsInvalid, Message: msg}, errors.New(msg)
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.ShowBackends()
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+
+	stsName := ddc.GetCGStatefulsetName(cg)
+	var corrected []string
+	for _, be := range backends {
+		if backendStatefulsetName(be.Host) != stsName {
+			continue
+		}
+		diff, err := backendTagsDiff(be, cg.BackendTags, cg.PreserveManualBackendTags)
+		if err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController reconcileBackendTags namespace %s name %s parse tag of backend %s failed, tag: %s, err=%s", ddc.Namespace, ddc.Name, be.Host, be.Tag, err.Error())
+			continue
+		}
+		if len(diff) == 0 {
+			continue
+		}
+		if err := sqlClient.ModifyBackendTags(be, diff); err != nil {
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+		}
+		corrected = append(corrected, strings.Split(be.Host, ".")[0])
+	}
+
+	if len(corrected) != 0 {
+		msg := fmt.Sprintf("compute group %s backends of pods %s have tags differ from backendTags, set back.", cg.UniqueId, strings.Join(corrected, ","))
+		klog.Infof("disaggregatedComputeGroupsController reconcileBackendTags namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGBackendTagsCorrected), msg)
+	}
+	return nil, nil
+}
+
+// backendTagsDiff return the desired tags that the backend not have or have different values, the different values are kept when preserveManual.
+func backendTagsDiff(be *mysql.Backend, desired map[string]string, preserveManual bool) (map[string]string, error) {
+	live := map[string]interface{}{}
+	if be.Tag != "" {
+		if err := json.Unmarshal([]byte(be.Tag), &live); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := map[string]string{}
+	for k, v := range desired {
+		lv, ok := live[k]
+		if !ok || (!preserveManual && fmt.Sprintf("%v", lv) != v) {
+			diff[k] = v
+		}
+	}
+	return diff, nil
+}
+
+func reservedTagsIn(tags map[string]string) []string {
+	var reserved []string
+	for k := range tags {
+		if reservedBackendTags[k] {
+			reserved = append(reserved, k)
+		}
+	}
+	sort.Strings(reserved)
+	return reserved
+}
+
+func invalidTagKeysIn(tags map[string]string) []string {
+	var invalid []string
+	for k := range tags {
+		if !backendTagKeyRegexp.MatchString(k) {
+			invalid = append(invalid, k)
+		}
+	}
+	sort.Strings(invalid)
+	return invalid
+}
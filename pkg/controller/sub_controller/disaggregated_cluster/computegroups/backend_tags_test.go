@@ -0,0 +1,70 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_backendTagsDiff(t *testing.T) {
+	be := &mysql.Backend{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", Tag: `{"compute_group_name" : "cg1", "location" : "zone-b"}`}
+	desired := map[string]string{"location": "zone-a", "rack": "r1"}
+
+	diff, err := backendTagsDiff(be, desired, false)
+	if err != nil || len(diff) != 2 || diff["location"] != "zone-a" || diff["rack"] != "r1" {
+		t.Errorf("backendTagsDiff expect location and rack, got %v, err=%v", diff, err)
+	}
+	diff, err = backendTagsDiff(be, desired, true)
+	if err != nil || len(diff) != 1 || diff["rack"] != "r1" {
+		t.Errorf("backendTagsDiff expect only the missing rack when preserve manual, got %v, err=%v", diff, err)
+	}
+	if _, err = backendTagsDiff(&mysql.Backend{Tag: "{"}, desired, false); err == nil {
+		t.Errorf("backendTagsDiff expect error for invalid tag.")
+	}
+}
+
+func Test_reconcileBackendTags_reserved(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: record.NewFakeRecorder(10)}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ddc.Status.FEStatus.AvailableStatus = dv1.Available
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", BackendTags: map[string]string{"compute_group_name": "cg2", "location": "zone-a"}}
+
+	event, err := dcgs.reconcileBackendTags(context.Background(), ddc, cg)
+	if err == nil || event == nil || event.Reason != sc.CGBackendTagsInvalid {
+		t.Errorf("reconcileBackendTags expect CGBackendTagsInvalid for reserved tags, got event %v err %v", event, err)
+	}
+}
+
+func Test_reconcileBackendTags_invalidKey(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: record.NewFakeRecorder(10)}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ddc.Status.FEStatus.AvailableStatus = dv1.Available
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", BackendTags: map[string]string{`rack" = "r1`: "r2", "location": "zone-a"}}
+
+	event, err := dcgs.reconcileBackendTags(context.Background(), ddc, cg)
+	if err == nil || event == nil || event.Reason != sc.CGBackendTagsInvalid {
+		t.Errorf("reconcileBackendTags expect CGBackendTagsInvalid for invalid keys, got event %v err %v", event, err)
+	}
+}
@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// checkBackendsReachable set the BackendsUnreachable condition of compute group by the backends registered in fe but not alive, emit warning event when it turns True.
+// when requireBackendsAlive configured, the Ready compute group that have less alive backends than replicas is set to BackendsRegistering.
+func (dcgs *DisaggregatedComputeGroupsController) checkBackendsReachable(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, backends []*mysql.Backend) {
+	prev := meta.FindStatusCondition(cgs.Conditions, dv1.BackendsUnreachable)
+	condition := newBackendsUnreachableCondition(prev, deadBackendPods(backends, cgs.StatefulsetName, cgs.Replicas), ddc.Generation, time.Now())
+	if condition.Status == metav1.ConditionTrue && (prev == nil || prev.Status != metav1.ConditionTrue) {
+		msg := fmt.Sprintf("compute group %s %s", cgs.UniqueId, condition.Message)
+		klog.Errorf("disaggregatedComputeGroupsController checkBackendsReachable namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGBackendsUnreachable), msg)
+	}
+	meta.SetStatusCondition(&cgs.Conditions, condition)
+
+	cg := findCG(ddc, cgs.UniqueId)
+	if cg != nil && cg.RequireBackendsAlive && cgs.Phase == dv1.Ready && cgs.AliveBackends < cgs.Replicas {
+		klog.Infof("disaggregatedComputeGroupsController checkBackendsReachable namespace %s name %s compute group %s have %d alive backends less than replicas %d, not mark ready.", ddc.Namespace, ddc.Name, cgs.UniqueId, cgs.AliveBackends, cgs.Replicas)
+		cgs.Phase = dv1.BackendsRegistering
+	}
+}
+
+// holdCGsBackendsUnknown set the Ready compute groups that require backends alive to BackendsRegistering, when the backends can not be queried from fe.
+func holdCGsBackendsUnknown(ddc *dv1.DorisDisaggregatedCluster) {
+	for i := range ddc.Status.ComputeGroupStatuses {
+		cgs := &ddc.Status.ComputeGroupStatuses[i]
+		if cg := findCG(ddc, cgs.UniqueId); cg != nil && cg.RequireBackendsAlive && cgs.Phase == dv1.Ready {
+			cgs.Phase = dv1.BackendsRegistering
+		}
+	}
+}
+
+// newBackendsUnreachableCondition return Unknown when the backends not alive in the grace period, the grace period starts from the last transition to Unknown.
+// the backends restarting are not alive for heartbeat seconds, the grace period avoids treating them as unreachable.
+func newBackendsUnreachableCondition(prev *metav1.Condition, dead []string, generation int64, now time.Time) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               dv1.BackendsUnreachable,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             dv1.RegisteredBackendsAlive,
+		Message:            "the backends registered in fe are alive.",
+	}
+	if len(dead) == 0 {
+		return condition
+	}
+
+	if prev == nil || prev.Status == metav1.ConditionFalse || (prev.Status == metav1.ConditionUnknown && now.Sub(prev.LastTransitionTime.Time) < noBackendsGracePeriod) {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = dv1.WaitingBackendsAlive
+		condition.Message = fmt.Sprintf("the backends of pods %s registered in fe, waiting them alive.", strings.Join(dead, ","))
+		return condition
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = dv1.RegisteredBackendsNotAlive
+	condition.Message = fmt.Sprintf("the backends of pods %s registered in fe but not alive after %s, fe can not reach them by heartbeat. the likely cause is a network policy or firewall, "+
+		"please allow fe to connect be heartbeat_service_port(default 9050), be_port(default 9060) and brpc_port(default 8060), and check the logs of be.", strings.Join(dead, ","), noBackendsGracePeriod)
+	return condition
+}
+
+// deadBackendPods return the pod names of backends registered by the statefulset but not alive, the backends of pods beyond replicas are removing and not counted.
+func deadBackendPods(backends []*mysql.Backend, stsName string, replicas int32) []string {
+	var pods []string
+	for _, be := range backends {
+		if be.Alive || backendStatefulsetName(be.Host) != stsName {
+			continue
+		}
+		if ordinal, err := backendOrdinal(be.Host); err != nil || int32(ordinal) >= replicas {
+			continue
+		}
+		pods = append(pods, strings.Split(be.Host, ".")[0])
+	}
+	sort.Strings(pods)
+	return pods
+}
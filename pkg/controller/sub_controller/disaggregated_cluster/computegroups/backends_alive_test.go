@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_checkBackendsReachable(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1", RequireBackendsAlive: true}}},
+	}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg1", Replicas: 2, AliveBackends: 1, Phase: dv1.Ready}
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", Alive: true},
+		{Host: "test-cg1-1.test-cg1.default.svc.cluster.local", Alive: false},
+		{Host: "test-cg1-2.test-cg1.default.svc.cluster.local", Alive: false},
+		{Host: "test-cg2-0.test-cg2.default.svc.cluster.local", Alive: false},
+	}
+
+	dcgs.checkBackendsReachable(ddc, cgs, backends)
+	c := meta.FindStatusCondition(cgs.Conditions, dv1.BackendsUnreachable)
+	if c == nil || c.Status != metav1.ConditionUnknown || len(recorder.Events) != 0 {
+		t.Fatalf("checkBackendsReachable expected Unknown in grace period without event, got %+v", c)
+	}
+	if cgs.Phase != dv1.BackendsRegistering {
+		t.Errorf("checkBackendsReachable expected phase BackendsRegistering when requireBackendsAlive and backends not alive, got %s", cgs.Phase)
+	}
+
+	c.LastTransitionTime = metav1.NewTime(time.Now().Add(-noBackendsGracePeriod))
+	dcgs.checkBackendsReachable(ddc, cgs, backends)
+	dcgs.checkBackendsReachable(ddc, cgs, backends)
+	c = meta.FindStatusCondition(cgs.Conditions, dv1.BackendsUnreachable)
+	if c.Status != metav1.ConditionTrue || c.Reason != dv1.RegisteredBackendsNotAlive || len(recorder.Events) != 1 {
+		t.Errorf("checkBackendsReachable expected True with reason %s and 1 event after grace period, got %+v events %d", dv1.RegisteredBackendsNotAlive, c, len(recorder.Events))
+	}
+
+	backends[1].Alive = true
+	cgs.AliveBackends = 2
+	cgs.Phase = dv1.Ready
+	dcgs.checkBackendsReachable(ddc, cgs, backends)
+	if c = meta.FindStatusCondition(cgs.Conditions, dv1.BackendsUnreachable); c.Status != metav1.ConditionFalse || cgs.Phase != dv1.Ready {
+		t.Errorf("checkBackendsReachable expected False and Ready when backends alive, got %+v phase %s", c, cgs.Phase)
+	}
+}
+
+func Test_deadBackendPods(t *testing.T) {
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-1.test-cg1.default.svc.cluster.local"},
+		{Host: "test-cg1-0.test-cg1.default.svc.cluster.local"},
+		{Host: "test-cg1-3.test-cg1.default.svc.cluster.local"},
+		{Host: "test-cg1-2.test-cg1.default.svc.cluster.local", Alive: true},
+	}
+	if pods := deadBackendPods(backends, "test-cg1", 3); len(pods) != 2 || pods[0] != "test-cg1-0" || pods[1] != "test-cg1-1" {
+		t.Errorf("deadBackendPods expected [test-cg1-0 test-cg1-1], got %v", pods)
+	}
+}
+
+func Test_holdCGsBackendsUnknown(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		Spec: dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1", RequireBackendsAlive: true}, {UniqueId: "cg2"}, {UniqueId: "cg3", RequireBackendsAlive: true}}},
+		Status: dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{
+			{UniqueId: "cg1", Phase: dv1.Ready}, {UniqueId: "cg2", Phase: dv1.Ready}, {UniqueId: "cg3", Phase: dv1.Scaling}}},
+	}
+
+	holdCGsBackendsUnknown(ddc)
+	expects := []dv1.Phase{dv1.BackendsRegistering, dv1.Ready, dv1.Scaling}
+	for i, expect := range expects {
+		if phase := ddc.Status.ComputeGroupStatuses[i].Phase; phase != expect {
+			t.Errorf("holdCGsBackendsUnknown expected compute group %s phase %s, got %s", ddc.Status.ComputeGroupStatuses[i].UniqueId, expect, phase)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+)
+
+// validateSwapTo check the swapTo of compute groups reference another compute group in spec, return the error message when invalid.
+func (dcgs *DisaggregatedComputeGroupsController) validateSwapTo(cgs []dv1.ComputeGroup) string {
+	uniqueIds := map[string]bool{}
+	for _, cg := range cgs {
+		uniqueIds[cg.UniqueId] = true
+	}
+
+	for _, cg := range cgs {
+		if cg.SwapTo == "" {
+			continue
+		}
+		if cg.SwapTo == cg.UniqueId {
+			return "compute group " + cg.UniqueId + " can not swap to itself."
+		}
+		if !uniqueIds[cg.SwapTo] {
+			return "compute group " + cg.UniqueId + " swap to " + cg.SwapTo + " that not exist in compute groups."
+		}
+	}
+	return ""
+}
+
+// swapTrafficReady return true when the traffic of compute group(blue) should be served by the swapTo compute group(green).
+// the swap happens when green is Ready first time, and is kept when green restarting until swapTo cleared.
+func swapTrafficReady(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) bool {
+	if cg.SwapTo == "" {
+		return false
+	}
+
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.UniqueId == cg.UniqueId && cgs.SwappedTo == cg.SwapTo {
+			return true
+		}
+	}
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		//the phase is reset in syncing, use the available replicas to judge green ready.
+		if cgs.UniqueId == cg.SwapTo {
+			return cgs.Replicas > 0 && cgs.AvailableReplicas >= cgs.Replicas
+		}
+	}
+	return false
+}
+
+// setCGStatusSwappedTo record the compute group that the service of compute group repointed to, return true when changed.
+func setCGStatusSwappedTo(ddc *dv1.DorisDisaggregatedCluster, uniqueId string, swappedTo string) bool {
+	for i := range ddc.Status.ComputeGroupStatuses {
+		if ddc.Status.ComputeGroupStatuses[i].UniqueId == uniqueId {
+			changed := ddc.Status.ComputeGroupStatuses[i].SwappedTo != swappedTo
+			ddc.Status.ComputeGroupStatuses[i].SwappedTo = swappedTo
+			return changed
+		}
+	}
+	return false
+}
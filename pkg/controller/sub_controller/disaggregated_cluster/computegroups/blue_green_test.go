@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+)
+
+func Test_validateSwapTo(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	tests := []struct {
+		cgs   []dv1.ComputeGroup
+		valid bool
+	}{
+		{cgs: []dv1.ComputeGroup{{UniqueId: "blue", SwapTo: "green"}, {UniqueId: "green"}}, valid: true},
+		{cgs: []dv1.ComputeGroup{{UniqueId: "blue", SwapTo: "blue"}}, valid: false},
+		{cgs: []dv1.ComputeGroup{{UniqueId: "blue", SwapTo: "green"}}, valid: false},
+	}
+
+	for i, test := range tests {
+		if msg := dcgs.validateSwapTo(test.cgs); (msg == "") != test.valid {
+			t.Errorf("validateSwapTo case %d expected valid %t, got message %q", i, test.valid, msg)
+		}
+	}
+}
+
+func Test_swapTrafficReady(t *testing.T) {
+	blue := &dv1.ComputeGroup{UniqueId: "blue", SwapTo: "green"}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		Status: dv1.DorisDisaggregatedClusterStatus{
+			ComputeGroupStatuses: []dv1.ComputeGroupStatus{
+				{UniqueId: "blue", Replicas: 3, AvailableReplicas: 3},
+				{UniqueId: "green", Replicas: 3, AvailableReplicas: 2},
+			},
+		},
+	}
+	if swapTrafficReady(ddc, blue) {
+		t.Errorf("swapTrafficReady expected false when green not ready")
+	}
+
+	ddc.Status.ComputeGroupStatuses[1].AvailableReplicas = 3
+	if !swapTrafficReady(ddc, blue) {
+		t.Errorf("swapTrafficReady expected true when green ready")
+	}
+
+	//keep swapped when green restarting.
+	setCGStatusSwappedTo(ddc, "blue", "green")
+	ddc.Status.ComputeGroupStatuses[1].AvailableReplicas = 1
+	if !swapTrafficReady(ddc, blue) {
+		t.Errorf("swapTrafficReady expected keep swapped when green restarting")
+	}
+
+	if swapTrafficReady(ddc, &dv1.ComputeGroup{UniqueId: "blue"}) {
+		t.Errorf("swapTrafficReady expected false when swapTo cleared")
+	}
+}
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the number of consecutive sql failures that block the scale down of compute group.
+	scaleDownMaxSqlFailures = 5
+	// the blocked scale down is retried after cooldown.
+	scaleDownBlockedCooldown = 30 * time.Minute
+)
+
+// scaleDownBlocked return true when the scale down of compute group is blocked by continuous sql failures.
+// the block is reset when cooldown passed or the reset annotation `doris.disaggregated.cluster/reset-scaledown-blocked-{uniqueId}` added on cluster.
+func (dcgs *DisaggregatedComputeGroupsController) scaleDownBlocked(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus) bool {
+	if cgStatus.ScaleDownSqlFailures < scaleDownMaxSqlFailures {
+		return false
+	}
+
+	resetKey := strings.ToLower(fmt.Sprintf(dv1.ResetScaleDownBlocked, cgStatus.UniqueId))
+	_, resetByAnno := cluster.Annotations[resetKey]
+	cooled := cgStatus.LastScaleDownSqlFailureTime == nil || time.Since(cgStatus.LastScaleDownSqlFailureTime.Time) >= scaleDownBlockedCooldown
+	if !resetByAnno && !cooled {
+		cgStatus.Phase = dv1.ScaleDownBlocked
+		return true
+	}
+
+	if resetByAnno {
+		if err := dcgs.removeClusterAnnotation(ctx, cluster, resetKey); err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController scaleDownBlocked remove annotation %s namespace=%s name=%s failed, err=%s", resetKey, cluster.Namespace, cluster.Name, err.Error())
+		}
+	}
+	klog.Infof("disaggregatedComputeGroupsController scaleDownBlocked namespace=%s name=%s compute group %s scale down reset, by annotation %t.", cluster.Namespace, cluster.Name, cgStatus.UniqueId, resetByAnno)
+	cgStatus.ScaleDownSqlFailures = 0
+	cgStatus.LastScaleDownSqlFailureTime = nil
+	cgStatus.Phase = dv1.ScaleDownFailed
+	return false
+}
+
+// recordScaleDownSqlResult count the consecutive sql failures of scale down, return the event when the scale down becomes blocked.
+// the failed phase represents dropping or decommissioning backends failed, other errors(ep: waiting tablets balanced) are not counted.
+func recordScaleDownSqlResult(cgStatus *dv1.ComputeGroupStatus, err error) *sc.Event {
+	if err == nil {
+		cgStatus.ScaleDownSqlFailures = 0
+		cgStatus.LastScaleDownSqlFailureTime = nil
+		return nil
+	}
+	if cgStatus.Phase != dv1.ScaleDownFailed {
+		return nil
+	}
+
+	now := metav1.Now()
+	cgStatus.ScaleDownSqlFailures++
+	cgStatus.LastScaleDownSqlFailureTime = &now
+	if cgStatus.ScaleDownSqlFailures < scaleDownMaxSqlFailures {
+		return nil
+	}
+
+	cgStatus.Phase = dv1.ScaleDownBlocked
+	msg := fmt.Sprintf("compute group %s scale down blocked after %d consecutive sql failures, will retry after %s or annotate cluster with '%s', the last err=%s",
+		cgStatus.UniqueId, cgStatus.ScaleDownSqlFailures, scaleDownBlockedCooldown.String(), strings.ToLower(fmt.Sprintf(dv1.ResetScaleDownBlocked, cgStatus.UniqueId)), err.Error())
+	return &sc.Event{Type: sc.EventWarning, Reason: sc.CGScaleDownBlocked, Message: msg}
+}
+
+// removeClusterAnnotation remove the annotation on cluster in kubernetes by merge patch, not use the cluster object for keeping the status in reconciling.
+func (dcgs *DisaggregatedComputeGroupsController) removeClusterAnnotation(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, key string) error {
+	delete(cluster.Annotations, key)
+	patchObj := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: cluster.Name}}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s":null}}}`, key)
+	return dcgs.K8sclient.Patch(ctx, patchObj, client.RawPatch(types.MergePatchType, []byte(patch)))
+}
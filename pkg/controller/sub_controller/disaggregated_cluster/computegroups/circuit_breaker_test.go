@@ -0,0 +1,96 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_recordScaleDownSqlResult(t *testing.T) {
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1"}
+	sqlErr := errors.New("drop backend failed")
+	for i := 1; i < scaleDownMaxSqlFailures; i++ {
+		cgs.Phase = dv1.ScaleDownFailed
+		if event := recordScaleDownSqlResult(cgs, sqlErr); event != nil {
+			t.Errorf("recordScaleDownSqlResult expected not blocked after %d failures", i)
+		}
+	}
+
+	//the waiting errors not counted.
+	cgs.Phase = dv1.Scaling
+	recordScaleDownSqlResult(cgs, errors.New("tablets balancing"))
+	if cgs.ScaleDownSqlFailures != scaleDownMaxSqlFailures-1 {
+		t.Errorf("recordScaleDownSqlResult expected not count the error not in failed phase, got %d", cgs.ScaleDownSqlFailures)
+	}
+
+	cgs.Phase = dv1.ScaleDownFailed
+	event := recordScaleDownSqlResult(cgs, sqlErr)
+	if event == nil || event.Reason != sc.CGScaleDownBlocked || cgs.Phase != dv1.ScaleDownBlocked {
+		t.Errorf("recordScaleDownSqlResult expected blocked after %d failures, event %v, phase %s", scaleDownMaxSqlFailures, event, cgs.Phase)
+	}
+
+	recordScaleDownSqlResult(cgs, nil)
+	if cgs.ScaleDownSqlFailures != 0 || cgs.LastScaleDownSqlFailureTime != nil {
+		t.Errorf("recordScaleDownSqlResult expected reset when succeed, got %d failures", cgs.ScaleDownSqlFailures)
+	}
+}
+
+func Test_scaleDownBlocked(t *testing.T) {
+	resetAnnos := map[string]string{"doris.disaggregated.cluster/reset-scaledown-blocked-cg1": "true"}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	scheme := runtime.NewScheme()
+	dv1.AddToScheme(scheme)
+	k8sclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Annotations: resetAnnos},
+	}).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+
+	now := metav1.Now()
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.ScaleDownBlocked, ScaleDownSqlFailures: scaleDownMaxSqlFailures, LastScaleDownSqlFailureTime: &now}
+	if !dcgs.scaleDownBlocked(context.Background(), ddc, cgs) {
+		t.Errorf("scaleDownBlocked expected blocked in cooldown")
+	}
+
+	//reset by annotation, and the annotation removed.
+	ddc.Annotations = map[string]string{"doris.disaggregated.cluster/reset-scaledown-blocked-cg1": "true"}
+	if dcgs.scaleDownBlocked(context.Background(), ddc, cgs) || cgs.ScaleDownSqlFailures != 0 || cgs.Phase != dv1.ScaleDownFailed {
+		t.Errorf("scaleDownBlocked expected reset by annotation, failures %d, phase %s", cgs.ScaleDownSqlFailures, cgs.Phase)
+	}
+	var eddc dv1.DorisDisaggregatedCluster
+	_ = k8sclient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test"}, &eddc)
+	if _, ok := eddc.Annotations["doris.disaggregated.cluster/reset-scaledown-blocked-cg1"]; ok {
+		t.Errorf("scaleDownBlocked expected the reset annotation removed")
+	}
+
+	//reset after cooldown.
+	before := metav1.NewTime(time.Now().Add(-scaleDownBlockedCooldown))
+	cgs = &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.ScaleDownBlocked, ScaleDownSqlFailures: scaleDownMaxSqlFailures, LastScaleDownSqlFailureTime: &before}
+	if dcgs.scaleDownBlocked(context.Background(), ddc, cgs) {
+		t.Errorf("scaleDownBlocked expected reset after cooldown")
+	}
+}
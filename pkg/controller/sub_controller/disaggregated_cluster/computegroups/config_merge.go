@@ -0,0 +1,134 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// applyMergedConfigMap merge the baseConfigMap and the configMaps mounted to config path in order, create or update the merged configmap.
+// when baseConfigMap not configured, delete the merged configmap that created before.
+func (dcgs *DisaggregatedComputeGroupsController) applyMergedConfigMap(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	name := ddc.GetCGMergedConfigMapName(cg)
+	if cg.BaseConfigMap == "" {
+		for _, cgs := range ddc.Status.ComputeGroupStatuses {
+			if cgs.UniqueId == cg.UniqueId && cgs.MergedConfigMapName != "" {
+				return nil, dcgs.deleteMergedConfigMap(ctx, ddc.Namespace, cgs.MergedConfigMapName)
+			}
+		}
+		return nil, nil
+	}
+
+	var cms []*corev1.ConfigMap
+	for _, cmName := range mergedConfigMapSources(cg) {
+		cm, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, ddc.Namespace, cmName)
+		if err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController applyMergedConfigMap namespace=%s get configmap %s failed, err=%s", ddc.Namespace, cmName, err.Error())
+			msg := fmt.Sprintf("compute group %s get configmap %s for merging config failed, err=%s", cg.UniqueId, cmName, err.Error())
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGConfigMergeFailed, Message: msg}, err
+		}
+		cms = append(cms, cm)
+	}
+	data := resource.MergeConfigMapsData(resource.BE_RESOLVEKEY, cms)
+
+	ecm, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, ddc.Namespace, name)
+	if apierrors.IsNotFound(err) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ddc.Namespace,
+				Name:            name,
+				Labels:          dcgs.newCG2LayerSchedulerLabels(ddc.Name, cg.UniqueId),
+				OwnerReferences: []metav1.OwnerReference{resource.GetOwnerReference(ddc)},
+			},
+			Data: data,
+		}
+		return nil, k8s.CreateClientObject(ctx, dcgs.K8sclient, cm)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if reflect.DeepEqual(ecm.Data, data) {
+		return nil, nil
+	}
+	ecm.Data = data
+	return nil, k8s.UpdateClientObject(ctx, dcgs.K8sclient, ecm)
+}
+
+// deleteMergedConfigMap delete the merged configmap, not found is ignored.
+func (dcgs *DisaggregatedComputeGroupsController) deleteMergedConfigMap(ctx context.Context, namespace, name string) error {
+	cm, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return k8s.DeleteClientObject(ctx, dcgs.K8sclient, cm)
+}
+
+// mergedConfigMapSources return the names of configmaps that merged in order, the base is the first.
+func mergedConfigMapSources(cg *dv1.ComputeGroup) []string {
+	names := []string{cg.BaseConfigMap}
+	for _, cm := range cg.ConfigMaps {
+		if (cm.MountPath == "" || cm.MountPath == resource.ConfigEnvPath) && cm.Name != cg.BaseConfigMap {
+			names = append(names, cm.Name)
+		}
+	}
+	return names
+}
+
+// mountedConfigMaps return the configmaps mounted in compute group pods.
+// when baseConfigMap configured, the configmaps mounted to config path are replaced by the merged configmap.
+func mountedConfigMaps(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) []dv1.ConfigMap {
+	if cg.BaseConfigMap == "" {
+		return cg.ConfigMaps
+	}
+
+	cms := []dv1.ConfigMap{{Name: ddc.GetCGMergedConfigMapName(cg), MountPath: resource.ConfigEnvPath}}
+	for _, cm := range cg.ConfigMaps {
+		if cm.MountPath == "" || cm.MountPath == resource.ConfigEnvPath {
+			continue
+		}
+		cms = append(cms, cm)
+	}
+	return cms
+}
+
+// setCGStatusMergedConfigMap record the merged configmap of compute group in status.
+func setCGStatusMergedConfigMap(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) {
+	var name string
+	if cg.BaseConfigMap != "" {
+		name = ddc.GetCGMergedConfigMapName(cg)
+	}
+	for i := range ddc.Status.ComputeGroupStatuses {
+		if ddc.Status.ComputeGroupStatuses[i].UniqueId == cg.UniqueId {
+			ddc.Status.ComputeGroupStatuses[i].MergedConfigMapName = name
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_applyMergedConfigMap(t *testing.T) {
+	base := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "base"},
+		Data:       map[string]string{"be.conf": "be_port = 9060\nmem_limit = 80%\n"},
+	}
+	overlay := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "overlay"},
+		Data:       map[string]string{"be.conf": "mem_limit = 60%\n"},
+	}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{
+		UniqueId:      "cg1",
+		BaseConfigMap: "base",
+		CommonSpec: dv1.CommonSpec{ConfigMaps: []dv1.ConfigMap{
+			{Name: "overlay"},
+			{Name: "hosts", MountPath: "/etc/hosts-conf"},
+		}},
+	}
+	ddc.Status.ComputeGroupStatuses = []dv1.ComputeGroupStatus{{UniqueId: "cg1"}}
+	k8sclient := fake.NewClientBuilder().WithObjects(base, overlay).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+	ctx := context.Background()
+
+	if _, err := dcgs.applyMergedConfigMap(ctx, ddc, cg); err != nil {
+		t.Fatalf("applyMergedConfigMap failed, err=%s", err.Error())
+	}
+	cm, err := k8s.GetConfigMap(ctx, k8sclient, "default", "test-cg1-merged-config")
+	if err != nil {
+		t.Fatalf("applyMergedConfigMap expected merged configmap created, err=%s", err.Error())
+	}
+	if cm.Data["be.conf"] != "be_port = 9060\nmem_limit = 60%\n" {
+		t.Errorf("merged be.conf expected overlay override base, got %q", cm.Data["be.conf"])
+	}
+
+	cms := mountedConfigMaps(ddc, cg)
+	if len(cms) != 2 || cms[0].Name != "test-cg1-merged-config" || cms[1].Name != "hosts" {
+		t.Errorf("mountedConfigMaps expected merged configmap replace the config path configmaps, got %+v", cms)
+	}
+
+	//clear the base, the merged configmap deleted.
+	setCGStatusMergedConfigMap(ddc, cg)
+	cg.BaseConfigMap = ""
+	if _, err := dcgs.applyMergedConfigMap(ctx, ddc, cg); err != nil {
+		t.Fatalf("applyMergedConfigMap clear failed, err=%s", err.Error())
+	}
+	if _, err := k8s.GetConfigMap(ctx, k8sclient, "default", "test-cg1-merged-config"); !apierrors.IsNotFound(err) {
+		t.Errorf("applyMergedConfigMap expected merged configmap deleted, err=%v", err)
+	}
+}
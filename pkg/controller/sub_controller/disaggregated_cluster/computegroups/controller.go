@@ -37,6 +37,8 @@ import (
     appv1 "k8s.io/api/apps/v1"
     corev1 "k8s.io/api/core/v1"
     apierrors "k8s.io/apimachinery/pkg/api/errors"
+    "k8s.io/apimachinery/pkg/api/meta"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
     "k8s.io/apimachinery/pkg/types"
     "k8s.io/klog/v2"
     ctrl "sigs.k8s.io/controller-runtime"
@@ -54,47 +56,78 @@ type DisaggregatedComputeGroupsController struct {
 }
 
 func New(mgr ctrl.Manager) *DisaggregatedComputeGroupsController {
+	emc, err := k8s.NewExternalMetricsClient(mgr.GetConfig())
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController new external metrics client failed, the externalMetricReplicas not work, err=%s", err.Error())
+	}
 	return &DisaggregatedComputeGroupsController{
 		sc.DisaggregatedSubDefaultController{
-			K8sclient:      mgr.GetClient(),
-			K8srecorder:    mgr.GetEventRecorderFor(disaggregatedComputeGroupsController),
-			ControllerName: disaggregatedComputeGroupsController,
+			K8sclient:       mgr.GetClient(),
+			K8srecorder:     mgr.GetEventRecorderFor(disaggregatedComputeGroupsController),
+			ControllerName:  disaggregatedComputeGroupsController,
+			ExternalMetrics: emc,
+			WorkerCount:     DefaultWorkerCount,
 		},
 	}
 }
 
 func (dcgs *DisaggregatedComputeGroupsController) Sync(ctx context.Context, obj client.Object) error {
 	ddc := obj.(*dv1.DorisDisaggregatedCluster)
+	//the cluster is terminating, not create or apply resources that racing with cleaning.
+	if !ddc.DeletionTimestamp.IsZero() {
+		klog.Infof("disaggregatedComputeGroupsController sync namespace=%s name=%s is deleting, skip syncing.", ddc.Namespace, ddc.Name)
+		return nil
+	}
 	if len(ddc.Spec.ComputeGroups) == 0 {
 		klog.Errorf("disaggregatedComputeGroupsController sync disaggregatedDorisCluster namespace=%s,name=%s have not compute group spec.", ddc.Namespace, ddc.Name)
 		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.ComputeGroupsEmpty), "compute group empty, the cluster will not work normal.")
 		return nil
 	}
 
-	if !dcgs.feAvailable(ddc) {
+	if available, notReady := dcgs.feAvailable(ddc); !available {
+		// distinguish the fe service missing from fe starting, the missing service will never be ready by waiting.
+		if !dcgs.feServiceExist(ctx, ddc) {
+			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.FEServiceNotFound), fmt.Sprintf("fe service %s not found in namespace %s, please check the fe service is deleted or renamed.", ddc.GetFEServiceName(), ddc.Namespace))
+			return nil
+		}
+		// the fe pods exist but all not ready, ep: in rolling update.
+		if notReady > 0 {
+			dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.FEEndpointsNotReady), fmt.Sprintf("fe service %s have 0 ready addresses and %d not ready addresses, waiting fe pods ready.", ddc.GetFEServiceName(), notReady))
+			return nil
+		}
 		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.WaitFEAvailable), "fe have not ready.")
 		return nil
 	}
 
+	if ddc.Spec.RequireFEMasterElected {
+		if err := dcgs.feMasterElected(ctx, ddc); err != nil {
+			klog.Infof("disaggregatedComputeGroupsController sync namespace=%s name=%s wait fe master elected, err=%s", ddc.Namespace, ddc.Name, err.Error())
+			dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.WaitFEMasterElected), "fe have not elected master, "+err.Error())
+			return nil
+		}
+	}
+
+	// the profile overrides the base values of compute groups, the resolved compute groups are validated as the normal spec.
+	restore, event, err := dcgs.applyProfile(ddc)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController namespace=%s name=%s applyProfile failed, %s", ddc.Namespace, ddc.Name, err.Error())
+		dcgs.K8srecorder.Event(ddc, string(event.Type), string(event.Reason), event.Message)
+		return err
+	}
+	defer restore()
+
 	// validating compute group information.
-	if event, res := dcgs.validateComputeGroup(ddc.Spec.ComputeGroups); !res {
+	if event, res := dcgs.validateComputeGroup(ctx, ddc); !res {
 		klog.Errorf("disaggregatedComputeGroupsController namespace=%s name=%s validateComputeGroup have not match specifications %s.", ddc.Namespace, ddc.Name, sc.EventString(event))
 		dcgs.K8srecorder.Eventf(ddc, string(event.Type), string(event.Reason), event.Message)
 		return errors.New("validating compute group failed")
 	}
 
-	var errs []error
-	cgs := ddc.Spec.ComputeGroups
-	for i, _ := range cgs {
+	// the compute groups not created wait the storage vault configured in fe.
+	waiting := dcgs.waitStorageVault(ctx, ddc)
 
-		if event, err := dcgs.computeGroupSync(ctx, ddc, &cgs[i]); err != nil {
-			if event != nil {
-				dcgs.K8srecorder.Event(ddc, string(event.Type), string(event.Reason), event.Message)
-			}
-			errs = append(errs, err)
-			klog.Errorf("disaggregatedComputeGroupsController computeGroups sync failed, compute group Uniqueid %s  sync failed, err=%s", cgs[i].UniqueId, err.Error())
-		}
-	}
+	// the compute groups synced in parallel, the failed one not block the others.
+	errs := dcgs.syncComputeGroups(ctx, ddc, waiting)
 
 	if len(errs) != 0 {
 		msg := fmt.Sprintf("disaggregatedComputeGroupsController sync namespace: %s ,ddc name: %s, compute group has the following error: ", ddc.Namespace, ddc.Name)
@@ -108,58 +141,231 @@ func (dcgs *DisaggregatedComputeGroupsController) Sync(ctx context.Context, obj
 }
 
 // validate compute group config information.
-func (dcgs *DisaggregatedComputeGroupsController) validateComputeGroup(cgs []dv1.ComputeGroup) (*sc.Event, bool) {
+func (dcgs *DisaggregatedComputeGroupsController) validateComputeGroup(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) (*sc.Event, bool) {
+	cgs := ddc.Spec.ComputeGroups
 	dupl := dcgs.validateDuplicated(cgs)
 	if dupl != "" {
 		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validate Duplicated have duplicate unique identifier %s.", dupl)
 		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGUniqueIdentifierDuplicate, Message: "unique identifier " + dupl + " duplicate in compute groups."}, false
 	}
 
-	if reg, res := dcgs.validateRegex(cgs); !res {
+	re, err := computeGroupNameRegexp(ddc.Spec.ComputeGroupNameRegex)
+	if err != nil {
+		msg := fmt.Sprintf("computeGroupNameRegex %s is not a valid regular expression, err=%s", ddc.Spec.ComputeGroupNameRegex, err.Error())
+		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup %s", msg)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGNameRegexInvalid, Message: msg}, false
+	}
+	if reg, res := dcgs.validateRegex(cgs, re); !res {
 		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validateRegex %s have not match regular expression", reg)
 		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGUniqueIdentifierNotMatchRegex, Message: reg}, false
 	}
 
+	if msg := dcgs.validateNameCollision(ddc); msg != "" {
+		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validateNameCollision failed, %s", msg)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGNameCollision, Message: msg}, false
+	}
+
+	if msg := dcgs.validateSwapTo(cgs); msg != "" {
+		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validateSwapTo failed, %s", msg)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSwapToInvalid, Message: msg}, false
+	}
+
+	if msg := dcgs.validateTenants(cgs); msg != "" {
+		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validateTenants failed, %s", msg)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGTenantsInvalid, Message: msg}, false
+	}
+
+	if msg := dcgs.validateReplicasBounds(cgs); msg != "" {
+		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validateReplicasBounds failed, %s", msg)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGReplicasBoundsInvalid, Message: msg}, false
+	}
+
+	if msg := dcgs.validateResourceRequests(ctx, ddc); msg != "" {
+		klog.Errorf("disaggregatedComputeGroupsController validateComputeGroup validateResourceRequests failed, %s", msg)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGResourceUnschedulable, Message: msg}, false
+	}
+
 	return nil, true
 }
 
-func (dcgs *DisaggregatedComputeGroupsController) feAvailable(ddc *dv1.DorisDisaggregatedCluster) bool {
+// feAvailable return true when the fe service have any ready address, and the number of not ready addresses for displaying why fe not available.
+func (dcgs *DisaggregatedComputeGroupsController) feAvailable(ddc *dv1.DorisDisaggregatedCluster) (bool, int) {
 	//if fe deploy in k8s, should wait fe available
 	//1. wait for fe ok.
 	endpoints := corev1.Endpoints{}
 	if err := dcgs.K8sclient.Get(context.Background(), types.NamespacedName{Namespace: ddc.Namespace, Name: ddc.GetFEServiceName()}, &endpoints); err != nil {
 		klog.Infof("disaggregatedComputeGroupsController Sync wait fe service name %s available occur failed %s\n", ddc.GetFEServiceName(), err.Error())
-		return false
+		return false, 0
 	}
 
+	ready, notReady := countEndpointsAddresses(&endpoints)
+	if ready == 0 && notReady > 0 {
+		klog.Infof("disaggregatedComputeGroupsController Sync fe service name %s have 0 ready addresses and %d not ready addresses.", ddc.GetFEServiceName(), notReady)
+	}
+	return ready > 0, notReady
+}
+
+// countEndpointsAddresses return the number of ready addresses and not ready addresses in all subsets.
+func countEndpointsAddresses(endpoints *corev1.Endpoints) (int, int) {
+	var ready, notReady int
 	for _, sub := range endpoints.Subsets {
-		if len(sub.Addresses) > 0 {
-			return true
-		}
+		ready += len(sub.Addresses)
+		notReady += len(sub.NotReadyAddresses)
+	}
+	return ready, notReady
+}
+
+// feMasterElected check the fe have elected master by `show frontends`, return nil when the master can be connected.
+func (dcgs *DisaggregatedComputeGroupsController) feMasterElected(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) error {
+	sqlClient, err := dcgs.getMasterSqlClient(ctx, ddc)
+	if err != nil {
+		return err
 	}
-	return false
+	return sqlClient.Close()
+}
+
+// feServiceExist check the fe service exists or not, return true when the result is not sure.
+func (dcgs *DisaggregatedComputeGroupsController) feServiceExist(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) bool {
+	_, err := k8s.GetService(ctx, dcgs.K8sclient, ddc.Namespace, ddc.GetFEServiceName())
+	if apierrors.IsNotFound(err) {
+		klog.Errorf("disaggregatedComputeGroupsController feServiceExist fe service namespace=%s name=%s not found.", ddc.Namespace, ddc.GetFEServiceName())
+		return false
+	}
+	return true
 }
 
 func (dcgs *DisaggregatedComputeGroupsController) computeGroupSync(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	dcgs.clampReplicasToBounds(ddc, cg)
+	if event, err := dcgs.resolveNodePoolReplicas(ctx, ddc, cg); err != nil {
+		return event, err
+	}
+	if event, err := dcgs.resolveExternalMetricReplicas(ctx, ddc, cg); err != nil {
+		return event, err
+	}
+	if event, err := dcgs.resolveReplicaRange(ctx, ddc, cg); err != nil {
+		return event, err
+	}
 	if cg.Replicas == nil {
-		cg.Replicas = resource.GetInt32Pointer(1)
+		cg.Replicas = resource.GetInt32Pointer(defaultReplicas(cg))
+	}
+	swapped := swapTrafficReady(ddc, cg)
+	if swapped {
+		//drain the blue compute group by scaling in to zero, use a copy to keep the spec for rolling back.
+		cg = cg.DeepCopy()
+		cg.Replicas = resource.GetInt32Pointer(0)
+	}
+	if image := rollbackImage(ddc, cg); image != "" {
+		//keep the image in spec, the statefulset uses the last known good image until the image in spec changed.
+		cg = cg.DeepCopy()
+		cg.Image = image
+	}
+	// the merged configmap is resolved for config values, apply it before resolving.
+	if event, err := dcgs.applyMergedConfigMap(ctx, ddc, cg); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController namespace %s name %s compute group %s apply merged configmap failed, err=%s", ddc.Namespace, ddc.Name, cg.UniqueId, err.Error())
+		return event, err
+	}
+	cvs := dcgs.GetConfigValuesFromConfigMaps(ddc.Namespace, resource.BE_RESOLVEKEY, mountedConfigMaps(ddc, cg))
+	// the log path and cache paths mount different volumes, the overlapped paths make volumes collide in statefulset.
+	if overlaps := dcgs.GetCachePathsOverlapLogPath(cvs); len(overlaps) != 0 {
+		msg := fmt.Sprintf("compute group %s cache paths %s overlap with the log path, please config sys_log_dir or file_cache_path to separate them.", cg.UniqueId, strings.Join(overlaps, ","))
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGLogCachePathConflict, Message: msg}, errors.New(msg)
+	}
+	// the persistent cache path without size provisions a pvc that be not use.
+	if cg.PersistentVolume != nil || len(cg.PersistentVolumes) != 0 {
+		if paths := dcgs.GetCachePathsWithoutSize(cvs); len(paths) != 0 {
+			msg := fmt.Sprintf("compute group %s cache paths %s have not positive total_size in file_cache_path, please config the size of cache paths.", cg.UniqueId, strings.Join(paths, ","))
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGCachePathSizeInvalid, Message: msg}, errors.New(msg)
+		}
+	}
+	// every storage tier path should be mounted by a distinct volume.
+	if conflicts := dcgs.GetStorageTierConflicts(cvs, &cg.CommonSpec); len(conflicts) != 0 {
+		msg := fmt.Sprintf("compute group %s storage tiers misconfigured: %s, please config every path in one of persistentVolumes and not nested in others.", cg.UniqueId, strings.Join(conflicts, "; "))
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGStorageTiersInvalid, Message: msg}, errors.New(msg)
 	}
-	cvs := dcgs.GetConfigValuesFromConfigMaps(ddc.Namespace, resource.BE_RESOLVEKEY, cg.CommonSpec.ConfigMaps)
 	st := dcgs.NewStatefulset(ddc, cg, cvs)
 	svc := dcgs.newService(ddc, cg, cvs)
+	if swapped {
+		svc.Spec.Selector = dcgs.newCGPodsSelector(ddc.Name, cg.SwapTo)
+	}
 	dcgs.initialCGStatus(ddc, cg)
+	setCGStatusMergedConfigMap(ddc, cg)
+	dcgs.recordGeneratedSummary(ddc, cg, st, svc)
 
 	dcgs.CheckSecretMountPath(ddc, cg.Secrets)
 	dcgs.CheckSecretExist(ctx, ddc, cg.Secrets)
+	dcgs.CheckServiceAccountExist(ctx, ddc, cg.ServiceAccount)
+	dcgs.checkRackTopologyKey(ctx, ddc, cg)
+	dcgs.checkPodSecurityStandard(ctx, ddc, cg, &st.Spec.Template.Spec)
+	dcgs.checkGracefulStopPort(ddc, cg, cvs, &st.Spec.Template.Spec)
+
+	//the service selects the serving pods when connection draining configured, label the pods before reconciling service.
+	if err := dcgs.labelServingPods(ctx, ddc, cg, findCGStatus(ddc, cg.UniqueId)); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController label serving pods of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+	}
 
 	event, err := dcgs.DefaultReconcileService(ctx, svc)
 	if err != nil {
 		klog.Errorf("disaggregatedComputeGroupsController reconcile service namespace %s name %s failed, err=%s", svc.Namespace, svc.Name, err.Error())
 		return event, err
 	}
-	event, err = dcgs.reconcileStatefulset(ctx, st, ddc, cg)
-	if err != nil {
-		klog.Errorf("disaggregatedComputeGroupsController reconcile statefulset namespace %s name %s failed, err=%s", st.Namespace, st.Name, err.Error())
+	var swappedTo string
+	if swapped {
+		swappedTo = cg.SwapTo
+	}
+	if setCGStatusSwappedTo(ddc, cg.UniqueId, swappedTo) {
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s compute group %s service repointed to compute group %q.", ddc.Namespace, ddc.Name, cg.UniqueId, swappedTo)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGTrafficSwapped), fmt.Sprintf("the service of compute group %s repointed to compute group %q.", cg.UniqueId, swappedTo))
+	}
+	//the statefulset updates are held when most pods crash looping, rolling the pods again only adds to the restart storm.
+	if !dcgs.degradedHeld(ctx, ddc, cg) {
+		event, err = dcgs.reconcileStatefulset(ctx, st, ddc, cg)
+		if err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController reconcile statefulset namespace %s name %s failed, err=%s", st.Namespace, st.Name, err.Error())
+			return event, err
+		}
+	}
+	//the placeholder pods hold the capacity for scaling out, the failure not blocks the compute group.
+	if event, err := dcgs.reconcileCapacityReservation(ctx, ddc, cg, st); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController reconcile capacity reservation of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		dcgs.K8srecorder.Event(ddc, string(event.Type), string(event.Reason), event.Message)
+	}
+	//the backends listed in annotation are dropped independent of the replicas.
+	if event, err := dcgs.dropAnnotatedBackends(ctx, ddc, cg); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController drop annotated backends of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		return event, err
+	}
+	//the pods without backends in fe registered by annotation, used after fe metadata restored.
+	if event, err := dcgs.reregisterAnnotatedBackends(ctx, ddc, cg, cvs); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController reregister annotated backends of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		return event, err
+	}
+	//the backends dropped in suspending are added again after the resumed pods ready.
+	if event, err := dcgs.addResumedBackends(ctx, ddc, cg, cvs); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController add resumed backends of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		return event, err
+	}
+	//the pods and pvcs labeled by old operator are invisible to cleaning and status, relabel them once after upgrading.
+	if err = dcgs.relabelCGResources(ctx, ddc, cg); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController relabel compute group %s resources namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+	}
+	//the labels modified externally make the service not select the pods, the compute group looks healthy but receives no queries.
+	if err := dcgs.reconcileCGLabelDrift(ctx, ddc, cg, svc); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController reconcile label drift of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+	}
+	//the statements of initSQL run once when the compute group ready, the failed statement retried in next reconcile.
+	if event, err := dcgs.runInitSQL(ctx, ddc, cg); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController run init sql of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		dcgs.K8srecorder.Event(ddc, string(event.Type), string(event.Reason), event.Message)
+	}
+	//the cordoned compute group not receives new queries, the backends and statefulset kept.
+	if event, err := dcgs.reconcileCordon(ctx, ddc, cg); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController reconcile cordon of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		dcgs.K8srecorder.Event(ddc, string(event.Type), string(event.Reason), event.Message)
+	}
+	//the tags of backends modified by sql diverge from spec, set them back.
+	if event, err := dcgs.reconcileBackendTags(ctx, ddc, cg); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController reconcile backend tags of compute group %s namespace %s name %s failed, err=%s", cg.UniqueId, ddc.Namespace, ddc.Name, err.Error())
+		return event, err
 	}
 
 	return event, err
@@ -174,6 +380,15 @@ func (dcgs *DisaggregatedComputeGroupsController) reconcileStatefulset(ctx conte
 
     var est appv1.StatefulSet
     if err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: st.Namespace, Name: st.Name}, &est); apierrors.IsNotFound(err) {
+		// the service reconciled before statefulset, confirm it ready before pods starting.
+		if err = dcgs.checkServiceReady(ctx, st); err != nil {
+			klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s wait service ready, err=%s", st.Namespace, st.Name, err.Error())
+			return &sc.Event{Type: sc.EventNormal, Reason: sc.CGWaitServiceReady, Message: err.Error()}, err
+		}
+		//the compute group suspended before creating, create the statefulset without pods.
+		if cg.Suspend {
+			st.Spec.Replicas = resource.GetInt32Pointer(0)
+		}
 		// add downlaodAPI volume Mounts
 		dcgs.DisaggregatedSubDefaultController.AddDownwardAPI(st)
 		//if err = k8s.CreateClientObject(ctx, dcgs.K8sclient, st); err != nil {
@@ -182,7 +397,11 @@ func (dcgs *DisaggregatedComputeGroupsController) reconcileStatefulset(ctx conte
 		//}
 
         //use apply replace create, if use create the default image not replace with be image and annotation for equal not assign.
-        if err = k8s.ApplyStatefulSet(ctx, dcgs.K8sclient, st, func(st, est *appv1.StatefulSet) bool {
+        apply := k8s.ApplyStatefulSet
+        if dcgs.ServerSideApply {
+            apply = k8s.ServerSideApplyStatefulSet
+        }
+        if err = apply(ctx, dcgs.K8sclient, st, func(st, est *appv1.StatefulSet) bool {
             //creating use the function to assign equal annotation.
             return resource.StatefulsetDeepEqualWithKey(st ,est, dv1.DisaggregatedSpecHashValueAnnotation, false)
         }, ndf); err != nil {
@@ -196,9 +415,66 @@ func (dcgs *DisaggregatedComputeGroupsController) reconcileStatefulset(ctx conte
 		return nil, err
 	}
 
-	err := dcgs.preApplyStatefulSet(ctx, st, &est, cluster, cg)
+	//the immutable fields changed recreate the statefulset when cutover enabled, the temporary statefulset serves in the gap.
+	if cutover, event, err := dcgs.cutoverStatefulset(ctx, cluster, cg, st, &est); cutover {
+		return event, err
+	}
+
+	//podManagementPolicy is immutable, keep the existing value for updating statefulset successfully.
+	if st.Spec.PodManagementPolicy != est.Spec.PodManagementPolicy {
+		klog.Warningf("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s podManagementPolicy can not change from %s to %s, recreate statefulset to take effect.", st.Namespace, st.Name, est.Spec.PodManagementPolicy, st.Spec.PodManagementPolicy)
+		st.Spec.PodManagementPolicy = est.Spec.PodManagementPolicy
+	}
+
+	var cgStatus *dv1.ComputeGroupStatus
+	for i := range cluster.Status.ComputeGroupStatuses {
+		if cluster.Status.ComputeGroupStatuses[i].UniqueId == cg.UniqueId {
+			cgStatus = &cluster.Status.ComputeGroupStatuses[i]
+			break
+		}
+	}
+	//the scale operation paused when fe restarting, the scale down in progress skip this reconcile.
+	if dcgs.quiesceForFE(ctx, cluster, cg, cgStatus, st, &est) {
+		klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s scale down in phase %s paused for fe restarting.", st.Namespace, st.Name, cgStatus.Phase)
+		return nil, nil
+	}
+	//the scale down paused when fe metadata unhealthy, the scale down in progress skip this reconcile.
+	if holdScaleDownForFEMetadata(cluster, cgStatus, st, &est) {
+		klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s scale down in phase %s paused for fe metadata unhealthy.", st.Namespace, st.Name, cgStatus.Phase)
+		return nil, nil
+	}
+	//the scale operation in cooldown keep the existing replicas, not start scaling.
+	if dcgs.deferScaleInCooldown(cluster, cg, cgStatus, st, &est) {
+		klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s scale deferred by cooldown until %s.", st.Namespace, st.Name, cgStatus.ScaleDeferredUntil.String())
+	}
+	//the scale down from a degraded compute group keep the existing replicas, not drain backends until all pods ready.
+	if dcgs.deferScaleDownDegraded(cluster, cg, cgStatus, st, &est) {
+		klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s scale down deferred, %d of %d pods available.", st.Namespace, st.Name, cgStatus.AvailableReplicas, *est.Spec.Replicas)
+	}
+
+	//the large scale in spreads over steps, the remaining backends removed in later reconciles.
+	if dcgs.limitScaleInStep(cluster, cg, st, &est) {
+		klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s scale in limited to %d replicas in this step.", st.Namespace, st.Name, *st.Spec.Replicas)
+	}
+	//the suspending scales to zero and the resuming restores the recorded replicas, not deferred or limited as scaling.
+	resuming := !cg.Suspend && cgStatus != nil && cgStatus.SuspendReplicas > 0
+	if reconcileSuspend(cg, cgStatus, st, &est) {
+		klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s compute group suspend=%t, replicas replaced to %d.", st.Namespace, st.Name, cg.Suspend, *st.Spec.Replicas)
+	}
+
+	//the notifications not block the scale, the failed ones retried in later reconciles.
+	dcgs.retryScaleNotifications(cluster, cg, cgStatus)
+	dcgs.notifyScaleStart(cluster, cg, cgStatus, st, &est)
+
+	event, err := dcgs.preApplyStatefulSet(ctx, st, &est, cluster, cg)
 	if err != nil {
 		klog.Errorf("disaggregatedComputeGroupsController reconcileStatefulset preApplyStatefulSet namespace=%s name=%s failed, err=%s", st.Namespace, st.Name, err.Error())
+		if sevent := suspendFailedEvent(cg, cgStatus, resuming, err); sevent != nil {
+			dcgs.K8srecorder.Event(cluster, string(sevent.Type), string(sevent.Reason), sevent.Message)
+		}
+		if event != nil {
+			return event, err
+		}
 		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
 	}
 	if skipApplyStatefulset(cluster, cg) {
@@ -206,10 +482,18 @@ func (dcgs *DisaggregatedComputeGroupsController) reconcileStatefulset(ctx conte
 	}
 
 
-	if err := k8s.ApplyStatefulSet(ctx, dcgs.K8sclient, st, func(st, est *appv1.StatefulSet) bool {
+	apply := k8s.ApplyStatefulSetWithConflict
+	if dcgs.ServerSideApply {
+		apply = k8s.ServerSideApplyStatefulSet
+	}
+	if err := apply(ctx, dcgs.K8sclient, st, func(st, est *appv1.StatefulSet) bool {
 		//store annotations "doris.disaggregated.cluster/generation={generation}" on statefulset
 		//store annotations "doris.disaggregated.cluster/update-{uniqueid}=true/false" on DorisDisaggregatedCluster
 		equal := resource.StatefulsetDeepEqualWithKey(st, est, dv1.DisaggregatedSpecHashValueAnnotation, false)
+		if !equal && dcgs.SkipEquivalentApply && resource.StatefulsetEquivalent(st, est, false) {
+			klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset namespace=%s name=%s hash changed but the compared fields not changed, skip updating.", st.Namespace, st.Name)
+			return true
+		}
 		if !equal {
 			if len(st.Annotations) == 0 {
 				st.Annotations = map[string]string{}
@@ -223,15 +507,30 @@ func (dcgs *DisaggregatedComputeGroupsController) reconcileStatefulset(ctx conte
 			msUniqueIdKey := strings.ToLower(fmt.Sprintf(dv1.UpdateStatefulsetName, cluster.GetCGStatefulsetName(cg)))
 			ddc_annos.Add(msUniqueIdKey, "true")
 			dcgs.DisaggregatedSubDefaultController.AddDownwardAPI(st)
+			//keep after the hash computed, the annotations added by other controllers not change the hash of spec.
+			if !dcgs.ServerSideApply {
+				preservePodAnnotations(st, est, cg.PreservedPodAnnotationPrefixes)
+			}
 		}
 		return equal
 
 	}, ndf); err != nil {
+		//the conflict is transient when statefulset edited concurrently, requeue to apply again without warning event.
+		if apierrors.IsConflict(err) {
+			klog.Infof("disaggregatedComputeGroupsController reconcileStatefulset apply statefulset namespace=%s name=%s conflict, requeue to apply again.", st.Namespace, st.Name)
+			setCGStatusPhase(cluster, cg.UniqueId, dv1.Reconciling)
+			return nil, err
+		}
 		klog.Errorf("disaggregatedComputeGroupsController reconcileStatefulset apply statefulset namespace=%s name=%s failed, err=%s", st.Namespace, st.Name, err.Error())
+		if sevent := suspendFailedEvent(cg, cgStatus, resuming, err); sevent != nil {
+			return sevent, err
+		}
 		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGApplyResourceFailed, Message: err.Error()}, err
 	}
+	recordScaleTime(cgStatus, st, &est)
+	dcgs.recordScaleEvent(cluster, cg, st, &est)
 
-	return nil, nil
+	return dcgs.postApplyStatefulSet(ctx, st, &est, cluster, cg)
 }
 
 // initial compute group status before sync resources. status changing with sync steps, and generate the last status by classify pods.
@@ -255,7 +554,7 @@ func (dcgs *DisaggregatedComputeGroupsController) initialCGStatus(ddc *dv1.Doris
 			}
 			defaultStatus.SuspendReplicas = cgss[i].SuspendReplicas
 			cgss[i] = defaultStatus*/
-			if cgss[i].Phase == dv1.Ready {
+			if cgServing(&cgss[i]) || cgss[i].Phase == dv1.WaitingStorageVault || cgss[i].Phase == dv1.BackendsRegistering {
 				cgss[i].Phase = defaultStatus.Phase
 			}
 			cgss[i].Replicas = *cg.Replicas
@@ -282,17 +581,30 @@ func (dcgs *DisaggregatedComputeGroupsController) validateDuplicated(cgs []dv1.C
 	return dupl
 }
 
+// validateNameCollision check the different uniqueIds not generate the same statefulset or service name, the "_" in uniqueId replaced by "-" in kubernetes name.
+// return the message of colliding uniqueIds, empty when not collide.
+func (dcgs *DisaggregatedComputeGroupsController) validateNameCollision(ddc *dv1.DorisDisaggregatedCluster) string {
+	names := map[string]string{}
+	msg := ""
+	for i := range ddc.Spec.ComputeGroups {
+		cg := &ddc.Spec.ComputeGroups[i]
+		for _, name := range []string{ddc.GetCGStatefulsetName(cg), ddc.GetCGServiceName(cg)} {
+			if uniqueId, ok := names[name]; ok && uniqueId != cg.UniqueId {
+				msg = msg + fmt.Sprintf("compute groups %s and %s generate the same statefulset or service name %s;", uniqueId, cg.UniqueId, name)
+				break
+			}
+			names[name] = cg.UniqueId
+		}
+	}
+	return msg
+}
+
 // checking the cg name compliant with regular expression or not.
-func (dcgs *DisaggregatedComputeGroupsController) validateRegex(cgs []dv1.ComputeGroup) (string, bool) {
+func (dcgs *DisaggregatedComputeGroupsController) validateRegex(cgs []dv1.ComputeGroup, re *regexp.Regexp) (string, bool) {
 	var regStr = ""
 	for _, cg := range cgs {
-		res, err := regexp.Match(compute_group_name_regex, []byte(cg.UniqueId))
-		if !res {
-			regStr = regStr + cg.UniqueId + " not match " + compute_group_name_regex
-		}
-		//for debugging, output the error in log
-		if err != nil {
-			klog.Errorf("disaggregatedComputeGroupsController validateRegex compute group name %s failed, err=%s", cg.UniqueId, err.Error())
+		if !re.MatchString(cg.UniqueId) {
+			regStr = regStr + cg.UniqueId + " not match " + re.String()
 		}
 	}
 	if regStr != "" {
@@ -307,7 +619,8 @@ func (dcgs *DisaggregatedComputeGroupsController) ClearResources(ctx context.Con
 	ddc := obj.(*dv1.DorisDisaggregatedCluster)
 
 	var eCGs []dv1.ComputeGroupStatus
-	var delComputeGroupIds []string
+	//the uniqueIds of compute groups that removed from spec and have status, they are removed by removeComputeGroup.
+	removingUniqueIds := set.NewSetString()
 	for i, cgs := range ddc.Status.ComputeGroupStatuses {
 		exist := false
 		for _, cg := range ddc.Spec.ComputeGroups {
@@ -318,8 +631,19 @@ func (dcgs *DisaggregatedComputeGroupsController) ClearResources(ctx context.Con
 			}
 		}
 
-		if !exist {
-			delComputeGroupIds = append(delComputeGroupIds, cgs.ComputeGroupId)
+		if exist {
+			continue
+		}
+
+		removingUniqueIds.Add(cgs.UniqueId)
+		cleared, err := dcgs.removeComputeGroup(ctx, ddc, &ddc.Status.ComputeGroupStatuses[i])
+		if err != nil {
+			klog.Errorf("DisaggregatedComputeGroupsController ClearResources remove compute group failed, namespace=%s, ddc name=%s, uniqueId=%s, err=%s", ddc.Namespace, ddc.Name, cgs.UniqueId, err.Error())
+			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGRemoveFailed), fmt.Sprintf("remove compute group %s failed, err=%s", cgs.UniqueId, err.Error()))
+		}
+		//keep the status until all resources of compute group cleared, the Removing phase will requeue for continuing.
+		if !cleared {
+			eCGs = append(eCGs, ddc.Status.ComputeGroupStatuses[i])
 		}
 	}
 
@@ -336,40 +660,44 @@ func (dcgs *DisaggregatedComputeGroupsController) ClearResources(ctx context.Con
 		return false, err
 	}
 
-	//clear unused service and statefulset.
-	delSvcNames := dcgs.findUnusedSvcs(svcs, ddc)
-	delStsNames, delUniqueIds := dcgs.findUnusedStssAndUniqueIds(stss, ddc)
+	//clear unused service and statefulset that not recorded in status, the removing compute groups are excluded.
+	delSvcNames := dcgs.findUnusedSvcs(svcs, ddc, removingUniqueIds)
+	delStsNames, delUniqueIds := dcgs.findUnusedStssAndUniqueIds(stss, ddc, removingUniqueIds)
 
-	if err = dcgs.clearCGInDorisMeta(ctx, delComputeGroupIds, ddc); err != nil {
-		return false, err
-	}
 	if err = dcgs.clearSvcs(ctx, delSvcNames, ddc); err != nil {
 		return false, err
 	}
 	if err = dcgs.clearStatefulsets(ctx, delStsNames, ddc); err != nil {
 		return false, err
 	}
+	if err = dcgs.clearCapacityReservations(ctx, ddc); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController clearCapacityReservations failed, namespace=%s, ddc name=%s, err=%s", ddc.Namespace, ddc.Name, err.Error())
+	}
 
 	//clear unused pvc
 	for i := range eCGs {
-		err = dcgs.ClearStatefulsetUnusedPVCs(ctx, ddc, eCGs[i])
+		if removingUniqueIds.Find(eCGs[i].UniqueId) {
+			continue
+		}
+		err = dcgs.ClearStatefulsetUnusedPVCs(ctx, ddc, &eCGs[i])
 		if err != nil {
 			klog.Errorf("disaggregatedComputeGroupsController ClearStatefulsetUnusedPVCs clear ComputeGroup reduced replicas PVC failed, namespace=%s, ddc name=%s, uniqueId=%s err=%s", ddc.Namespace, ddc.Name, eCGs[i].UniqueId, err.Error())
 		}
 	}
 
 	for _, uniqueId := range delUniqueIds {
-		//new fake computeGroup status for clear all pvcs owner reference to deleted compute group.
-		fakeCgs := dv1.ComputeGroupStatus{
-			UniqueId: uniqueId,
+		if ddc.Spec.RetainRemovedComputeGroupPVCs {
+			continue
 		}
-		err = dcgs.ClearStatefulsetUnusedPVCs(ctx, ddc, fakeCgs)
-		if err != nil {
-			klog.Errorf("disaggregatedComputeGroupsController ClearStatefulsetUnusedPVCs clear deleted compute group failed, namespace=%s, ddc name=%s, uniqueId=%s err=%s", ddc.Namespace, ddc.Name, uniqueId, err.Error())
+		if _, err = dcgs.clearCGPVCs(ctx, ddc, uniqueId); err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController clearCGPVCs clear deleted compute group failed, namespace=%s, ddc name=%s, uniqueId=%s err=%s", ddc.Namespace, ddc.Name, uniqueId, err.Error())
 		}
 	}
 
 	ddc.Status.ComputeGroupStatuses = eCGs
+	if err = dcgs.clearDiscoveryConfigMap(ctx, ddc); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController clearDiscoveryConfigMap failed, namespace=%s, ddc name=%s, err=%s", ddc.Namespace, ddc.Name, err.Error())
+	}
 	return true, nil
 }
 
@@ -393,33 +721,7 @@ func (dcgs *DisaggregatedComputeGroupsController) clearSvcs(ctx context.Context,
 	return nil
 }
 
-func (dcgs *DisaggregatedComputeGroupsController) clearCGInDorisMeta(ctx context.Context, cgids []string, ddc *dv1.DorisDisaggregatedCluster) error {
-	if len(cgids) == 0 {
-		return nil
-	}
-
-	sqlClient, err := dcgs.getMasterSqlClient(ctx, ddc)
-	if err != nil {
-		klog.Errorf("DisaggregatedComputeGroupsController clearCGInDorisMeta dropCGBySQLClient getMasterSqlClient failed: %s", err.Error())
-		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGSqlExecFailed), "computeGroupSync dropCGBySQLClient failed: "+err.Error())
-		return err
-	}
-	defer sqlClient.Close()
-
-	for _, cgid := range cgids {
-		//clear cg, the keepAmount = 0
-		err = dcgs.scaledOutBENodesByDrop(sqlClient, cgid, 0)
-		if err != nil {
-			klog.Errorf("DisaggregatedComputeGroupsController clearCGInDorisMeta dropCGBySQLClient failed: %s", err.Error())
-			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGSqlExecFailed), "computeGroupSync dropCGBySQLClient failed: "+err.Error())
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (dcgs *DisaggregatedComputeGroupsController) findUnusedSvcs(svcs []corev1.Service, ddc *dv1.DorisDisaggregatedCluster) []string {
+func (dcgs *DisaggregatedComputeGroupsController) findUnusedSvcs(svcs []corev1.Service, ddc *dv1.DorisDisaggregatedCluster, removingUniqueIds *set.SetString) []string {
 	var unusedSvcNames []string
 	for i, _ := range svcs {
 		own := ownerReference2ddc(&svcs[i], ddc)
@@ -429,7 +731,7 @@ func (dcgs *DisaggregatedComputeGroupsController) findUnusedSvcs(svcs []corev1.S
 		}
 
 		svcUniqueId := getUniqueIdFromClientObject(&svcs[i])
-		exist := false
+		exist := removingUniqueIds.Find(svcUniqueId)
 		for j := 0; j < len(ddc.Spec.ComputeGroups); j++ {
 			if ddc.Spec.ComputeGroups[j].UniqueId == svcUniqueId {
 				exist = true
@@ -445,7 +747,7 @@ func (dcgs *DisaggregatedComputeGroupsController) findUnusedSvcs(svcs []corev1.S
 	return unusedSvcNames
 }
 
-func (dcgs *DisaggregatedComputeGroupsController) findUnusedStssAndUniqueIds(stss []appv1.StatefulSet, ddc *dv1.DorisDisaggregatedCluster) ([]string /*sts*/, []string /*uniqueIds*/) {
+func (dcgs *DisaggregatedComputeGroupsController) findUnusedStssAndUniqueIds(stss []appv1.StatefulSet, ddc *dv1.DorisDisaggregatedCluster, removingUniqueIds *set.SetString) ([]string /*sts*/, []string /*uniqueIds*/) {
 	var unusedStsNames []string
 	var unusedUniqueIds []string
 	for i, _ := range stss {
@@ -456,7 +758,7 @@ func (dcgs *DisaggregatedComputeGroupsController) findUnusedStssAndUniqueIds(sts
 		}
 
 		stsUniqueId := getUniqueIdFromClientObject(&stss[i])
-		exist := false
+		exist := removingUniqueIds.Find(stsUniqueId)
 		for j := 0; j < len(ddc.Spec.ComputeGroups); j++ {
 			if ddc.Spec.ComputeGroups[j].UniqueId == stsUniqueId {
 				exist = true
@@ -476,7 +778,8 @@ func (dcgs *DisaggregatedComputeGroupsController) findUnusedStssAndUniqueIds(sts
 // 1.delete unused pvc skip cluster is Suspend
 // 2.delete unused pvc for statefulset
 // 3.delete pvc if not used by any statefulset
-func (dcgs *DisaggregatedComputeGroupsController) ClearStatefulsetUnusedPVCs(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cgs dv1.ComputeGroupStatus) error {
+// 4.snapshot pvc before deleting when scaleInSnapshotClassName configured, the pvc is deleted after snapshot ready.
+func (dcgs *DisaggregatedComputeGroupsController) ClearStatefulsetUnusedPVCs(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus) error {
 	var cg *dv1.ComputeGroup
 	for i := range ddc.Spec.ComputeGroups {
 		/*	uniqueId := ddc.GetCGId(&ddc.Spec.ComputeGroups[i])
@@ -502,8 +805,12 @@ func (dcgs *DisaggregatedComputeGroupsController) ClearStatefulsetUnusedPVCs(ctx
 	if cg == nil {
 		return nil
 	}
+	//the pvcs of suspended compute group are kept for resuming, the statefulset replicas is zero in suspending.
+	if cg.Suspend || cgs.SuspendReplicas > 0 {
+		return nil
+	}
 
-	var clearPVC []string
+	var clearPVC []*corev1.PersistentVolumeClaim
 	//we should use statefulset replicas for avoiding the phase=scaleDown, when phase `scaleDown` cg' replicas is less than statefuslet.
 	stsName := ddc.GetCGStatefulsetName(cg)
 	sts, err := k8s.GetStatefulSet(ctx, dcgs.K8sclient, ddc.Namespace, stsName)
@@ -513,33 +820,41 @@ func (dcgs *DisaggregatedComputeGroupsController) ClearStatefulsetUnusedPVCs(ctx
 		return nil
 	}
 	replicas := *sts.Spec.Replicas
-	for _, pvc := range currentPVCs.Items {
-		pvcName := pvc.Name
-		sl := strings.Split(pvcName, stsName+"-")
-		if len(sl) != 2 {
+	for i := range currentPVCs.Items {
+		pvcName := currentPVCs.Items[i].Name
+		//every storage tier of pod have a pvc, all of them are reserved when the ordinal in replicas.
+		index, ok := statefulsetPVCOrdinal(pvcName, stsName)
+		if !ok {
 			klog.Errorf("DisaggregatedComputeGroupsController ClearStatefulsetUnusedPVCs namespace %s name %s not format pvc name format.", ddc.Namespace, pvcName)
 			continue
 		}
-		var index int64
-		var perr error
-		index, perr = strconv.ParseInt(sl[1], 10, 32)
-		if perr != nil {
-			klog.Errorf("DisaggregatedComputeGroupsController ClearStatefulsetUnusedPVCs namespace %s name %s index parse failed, err=%s", ddc.Namespace, pvcName, perr.Error())
-			continue
-		}
 		if int32(index) >= replicas {
-			clearPVC = append(clearPVC, pvcName)
+			clearPVC = append(clearPVC, &currentPVCs.Items[i])
 		}
 	}
 
 	var mergeError error
-	for _, pvcName := range clearPVC {
+	var pendingSnapshots int32
+	for _, pvc := range clearPVC {
+		pvcName := pvc.Name
+		if cg.ScaleInSnapshotClassName != "" {
+			ready, serr := dcgs.snapshotPVCBeforeDelete(ctx, ddc, cg, pvc)
+			if serr != nil {
+				klog.Errorf("ClearStatefulsetUnusedPVCs snapshot pvc failed: namespace %s, name %s, err: %s .", ddc.Namespace, pvcName, serr.Error())
+				mergeError = utils.MergeError(mergeError, serr)
+			}
+			if !ready {
+				pendingSnapshots++
+				continue
+			}
+		}
 		if err = k8s.DeletePVC(ctx, dcgs.K8sclient, ddc.Namespace, pvcName, pvcLabels); err != nil {
 			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), sc.PVCDeleteFailed, err.Error())
 			klog.Errorf("ClearStatefulsetUnusedPVCs deletePVCs failed: namespace %s, name %s delete pvc %s, err: %s .", ddc.Namespace, pvcName, pvcName, err.Error())
 			mergeError = utils.MergeError(mergeError, err)
 		}
 	}
+	cgs.PendingScaleInSnapshots = pendingSnapshots
 	return mergeError
 }
 
@@ -547,7 +862,7 @@ func (dcgs *DisaggregatedComputeGroupsController) GetControllerName() string {
 	return dcgs.ControllerName
 }
 
-func (dcgs *DisaggregatedComputeGroupsController) UpdateComponentStatus(obj client.Object) error {
+func (dcgs *DisaggregatedComputeGroupsController) UpdateComponentStatus(ctx context.Context, obj client.Object) error {
 	ddc := obj.(*dv1.DorisDisaggregatedCluster)
 	cgss := ddc.Status.ComputeGroupStatuses
 	if len(cgss) == 0 {
@@ -561,6 +876,10 @@ func (dcgs *DisaggregatedComputeGroupsController) UpdateComponentStatus(obj clie
 	for i, _ := range cgss {
 		go func(idx int) {
 			defer wg.Done()
+			//the removing compute group status is maintained by ClearResources, the waiting storage vault compute group have not resources.
+			if cgss[idx].Phase == dv1.Removing || cgss[idx].Phase == dv1.WaitingStorageVault {
+				return
+			}
 			errChan <- dcgs.updateCGStatus(ddc, &cgss[idx])
 		}(i)
 	}
@@ -582,9 +901,29 @@ func (dcgs *DisaggregatedComputeGroupsController) UpdateComponentStatus(obj clie
 		}
 	}
 
+	// compare the ready pods with the alive backends registered in fe, the failure is not affect the status of compute group.
+	if err := dcgs.updateCGBackendsStatus(ddc); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController updateComponentStatus namespace %s name %s update backends status failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		holdCGsBackendsUnknown(ddc)
+	}
+	// bind the users and roles to ready compute groups for routing their queries.
+	dcgs.reconcileCGTenants(ctx, ddc)
+	// cap the memory of compute groups that backends registered by the workload group in fe.
+	dcgs.reconcileCGMemoryLimit(ctx, ddc)
+
 	var fullAvailableCount int32
 	var availableCount int32
+	var cgCount int32
+	var expectedBackends int32
+	var aliveBackends int32
 	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		//the suspended compute group not serves by intention, not counted in health.
+		if cgs.Phase == dv1.Removing || cgs.Phase == dv1.Suspended {
+			continue
+		}
+		cgCount++
+		expectedBackends += cgs.Replicas
+		aliveBackends += cgs.AliveBackends
 		if cgs.Phase == dv1.Ready {
 			fullAvailableCount++
 		}
@@ -592,9 +931,18 @@ func (dcgs *DisaggregatedComputeGroupsController) UpdateComponentStatus(obj clie
 			availableCount++
 		}
 	}
-	ddc.Status.ClusterHealth.CGCount = int32(len(ddc.Status.ComputeGroupStatuses))
+	ddc.Status.ClusterHealth.CGCount = cgCount
 	ddc.Status.ClusterHealth.CGFullAvailableCount = fullAvailableCount
 	ddc.Status.ClusterHealth.CGAvailableCount = availableCount
+	ddc.Status.ClusterHealth.ExpectedBackends = expectedBackends
+	ddc.Status.ClusterHealth.AliveBackends = aliveBackends
+	// the compute groups running different versions beyond the window is not supported by doris, ep: a staged upgrade left unfinished.
+	dcgs.checkVersionSkew(ddc)
+
+	// export the compute groups for client applications discovering, the failure is not affect the status of compute group.
+	if err := dcgs.applyDiscoveryConfigMap(context.Background(), ddc); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController updateComponentStatus namespace %s name %s apply discovery configmap failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+	}
 	if errMs == "" {
 		return nil
 	}
@@ -616,14 +964,18 @@ func(dcgs *DisaggregatedComputeGroupsController) recordComputeGroupIds(ddc *dv1.
 	cfg.Host = host
 	cfg.Port = strconv.FormatInt(int64(queryPort), 10)
 
-	tlsConfig, secretName := dcgs.DisaggregatedSubDefaultController.FindSecretTLSConfig(confMap, ddc)
-	secret, _ := k8s.GetSecret(context.Background(), dcgs.K8sclient, ddc.Namespace, secretName)
+	tlsConfig, secret, err := dcgs.GetTLSConfigAndSecret(context.Background(), confMap, ddc)
+	if err != nil {
+		klog.Errorf("DisaggregatedComputeGroupsController recordComputeGroupIds %s", err.Error())
+		return err
+	}
 
 	db, err := mysql.NewDorisSqlDB(cfg, tlsConfig, secret)
 	if err != nil {
 		klog.Errorf("DisaggregatedComputeGroupsController recordComputeGroupIds new doris client failed,err=%s", err.Error())
 		return err
 	}
+	dcgs.PlanSqlClient(db)
     defer db.Close()
 
 	backends, err := db.ShowBackends()
@@ -660,6 +1012,76 @@ func(dcgs *DisaggregatedComputeGroupsController) recordComputeGroupIds(ddc *dv1.
 }
 
 
+// updateCGBackendsStatus count the alive backends of every compute group from fe, and set the BackendsConsistent condition by comparing with the ready pods.
+func (dcgs *DisaggregatedComputeGroupsController) updateCGBackendsStatus(ddc *dv1.DorisDisaggregatedCluster) error {
+	if ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		holdCGsBackendsUnknown(ddc)
+		return nil
+	}
+
+	sqlClient, err := dcgs.getMasterSqlClient(context.Background(), ddc)
+	if err != nil {
+		return err
+	}
+	defer sqlClient.Close()
+
+	backends, err := sqlClient.ShowBackends()
+	if err != nil {
+		return err
+	}
+
+	aliveBackends := countAliveBackendsByStatefulset(backends)
+	registeredBackends := countRegisteredBackendsByStatefulset(backends)
+	for i := range ddc.Status.ComputeGroupStatuses {
+		cgs := &ddc.Status.ComputeGroupStatuses[i]
+		cgs.AliveBackends = aliveBackends[cgs.StatefulsetName]
+		meta.SetStatusCondition(&cgs.Conditions, newBackendsConsistentCondition(cgs, ddc.Generation))
+		dcgs.checkNoBackendsRegistered(ddc, cgs, registeredBackends[cgs.StatefulsetName])
+		dcgs.checkBackendsReachable(ddc, cgs, backends)
+	}
+
+	dcgs.recordCGUsageSamples(context.Background(), ddc, backends)
+	dcgs.dropDuplicateBackends(context.Background(), ddc, backends)
+	return nil
+}
+
+// countAliveBackendsByStatefulset return the number of alive backends grouped by the statefulset name that the backend pod belongs to.
+func countAliveBackendsByStatefulset(backends []*mysql.Backend) map[string]int32 {
+	m := map[string]int32{}
+	re := regexp.MustCompile("(.*)-[0-9]+$")
+	for _, backend := range backends {
+		if !backend.Alive {
+			continue
+		}
+		podName := strings.Split(backend.Host, ".")[0]
+		matchs := re.FindStringSubmatch(podName)
+		if len(matchs) < 2 {
+			continue
+		}
+		m[matchs[1]]++
+	}
+	return m
+}
+
+func newBackendsConsistentCondition(cgs *dv1.ComputeGroupStatus, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               dv1.BackendsConsistent,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             dv1.BackendsMatched,
+		Message:            fmt.Sprintf("%d pods ready, %d backends alive in fe.", cgs.AvailableReplicas, cgs.AliveBackends),
+	}
+
+	if cgs.AvailableReplicas > cgs.AliveBackends {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = dv1.PodsReadyBackendsNotAlive
+	} else if cgs.AvailableReplicas < cgs.AliveBackends {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = dv1.BackendsAlivePodsNotReady
+	}
+	return condition
+}
+
 func (dcgs *DisaggregatedComputeGroupsController) updateCGStatus(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus) error {
 	stfName := cgs.StatefulsetName
 	sts, err := k8s.GetStatefulSet(context.Background(), dcgs.K8sclient, ddc.Namespace, stfName)
@@ -683,7 +1105,14 @@ func (dcgs *DisaggregatedComputeGroupsController) updateCGStatus(ddc *dv1.DorisD
 	if err := dcgs.K8sclient.List(context.Background(), &podList, client.InNamespace(ddc.Namespace), client.MatchingLabels(selector)); err != nil {
 		return err
 	}
-
+	//the pods of temporary statefulset in cutover are not controlled by the statefulset.
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if pod.Labels[dv1.DorisDisaggregatedCutover] == "" {
+			pods = append(pods, pod)
+		}
+	}
+	podList.Items = pods
 
 	updateRevision := sts.Status.UpdateRevision
 	//check all pods controlled by new statefulset.
@@ -692,10 +1121,15 @@ func (dcgs *DisaggregatedComputeGroupsController) updateCGStatus(ddc *dv1.DorisD
 	var availableReplicas int32
 	var creatingReplicas int32
 	var failedReplicas int32
+	var crashLooping []string
 	//get all pod status that controlled by st.
 	for _, pod := range podList.Items {
 		if ready := k8s.PodIsReady(&pod.Status); ready {
 			availableReplicas++
+		} else if reason := podCrashLoopReason(&pod); reason != "" {
+			//the crash looping pod is running phase, should not be counted as creating.
+			failedReplicas++
+			crashLooping = append(crashLooping, reason)
 		} else if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
 			creatingReplicas++
 		} else {
@@ -704,8 +1138,31 @@ func (dcgs *DisaggregatedComputeGroupsController) updateCGStatus(ddc *dv1.DorisD
 	}
 
 	cgs.AvailableReplicas = availableReplicas
-	if allUpdated && availableReplicas == cgs.Replicas {
-		cgs.Phase = dv1.Ready
+	cgs.RunningVersions = podsRunningVersions(podList.Items)
+	dcgs.checkCGCrashLoop(ddc, cgs, crashLooping)
+	dcgs.checkUpgradeRollback(ddc, cgs, sts, podList.Items, allUpdated && availableReplicas == cgs.Replicas && cgs.Replicas > 0)
+	//the pods pending by pvcs not bound are counted as creating, surface the storage problem by condition.
+	if err := dcgs.checkCGPVCBinding(context.Background(), ddc, cgs, sts, creatingReplicas); err != nil {
+		klog.Errorf("DisaggregatedComputeGroupsController updateCGStatus check pvc binding of statefulset %s failed, err=%s", stfName, err.Error())
+	}
+	if err := dcgs.checkCGAwaitingStorage(context.Background(), ddc, cgs, podList.Items); err != nil {
+		klog.Errorf("DisaggregatedComputeGroupsController updateCGStatus check pods awaiting storage of statefulset %s failed, err=%s", stfName, err.Error())
+	}
+	dcgs.checkCGStuck(ddc, cgs, sts, podList.Items)
+	dcgs.checkCGMassFailure(ddc, cgs, podList.Items)
+	if cgSuspended(findCG(ddc, cgs.UniqueId), sts, podList.Items) {
+		cgs.Phase = dv1.Suspended
+	} else if allUpdated && availableReplicas == cgs.Replicas {
+		if dcgs.initSQLConfigMapMissing(context.Background(), ddc, cgs.UniqueId) {
+			klog.Errorf("DisaggregatedComputeGroupsController updateCGStatus compute group %s initSQL configmap not exist, not mark ready.", cgs.UniqueId)
+		} else {
+			cgs.Phase = dv1.Ready
+		}
+	} else if cgs.Phase == dv1.Reconciling && reachReadyThreshold(ddc, cgs, availableReplicas) {
+		//the compute group serves with the available pods, the phases of operations in progress are kept.
+		cgs.Phase = dv1.PartiallyReady
 	}
+	dcgs.reportScaleDownSucceeded(ddc, cgs)
+	dcgs.notifyScaleFinish(ddc, cgs)
 	return nil
 }
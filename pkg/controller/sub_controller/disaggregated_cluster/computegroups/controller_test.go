@@ -0,0 +1,252 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_countAliveBackendsByStatefulset(t *testing.T) {
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", Alive: true},
+		{Host: "test-cg1-1.test-cg1.default.svc.cluster.local", Alive: false},
+		{Host: "test-cg2-0.test-cg2.default.svc.cluster.local", Alive: true},
+		{Host: "test-cg2-1.test-cg2.default.svc.cluster.local", Alive: true},
+	}
+
+	m := countAliveBackendsByStatefulset(backends)
+	if m["test-cg1"] != 1 || m["test-cg2"] != 2 {
+		t.Errorf("countAliveBackendsByStatefulset got %v, expected test-cg1=1, test-cg2=2", m)
+	}
+}
+
+func Test_newBackendsConsistentCondition(t *testing.T) {
+	tests := []struct {
+		cgs    dv1.ComputeGroupStatus
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{cgs: dv1.ComputeGroupStatus{AvailableReplicas: 3, AliveBackends: 3}, status: metav1.ConditionTrue, reason: dv1.BackendsMatched},
+		{cgs: dv1.ComputeGroupStatus{AvailableReplicas: 3, AliveBackends: 1}, status: metav1.ConditionFalse, reason: dv1.PodsReadyBackendsNotAlive},
+		{cgs: dv1.ComputeGroupStatus{AvailableReplicas: 1, AliveBackends: 3}, status: metav1.ConditionFalse, reason: dv1.BackendsAlivePodsNotReady},
+	}
+
+	for _, test := range tests {
+		c := newBackendsConsistentCondition(&test.cgs, 1)
+		if c.Status != test.status || c.Reason != test.reason {
+			t.Errorf("newBackendsConsistentCondition for %+v got status %s reason %s, expected status %s reason %s", test.cgs, c.Status, c.Reason, test.status, test.reason)
+		}
+	}
+}
+
+func Test_reconcileStatefulset_Conflict(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Status: dv1.DorisDisaggregatedClusterStatus{
+			ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1", Phase: dv1.Ready}},
+		},
+	}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}
+	est := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"},
+		Spec:       appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(1)},
+	}
+	st := est.DeepCopy()
+	st.Spec.Template.Labels = map[string]string{"changed": "true"}
+
+	//simulate the statefulset was modified by others after operator got it.
+	k8sclient := fake.NewClientBuilder().WithObjects(est).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, obj.GetName(), nil)
+		},
+	}).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+
+	event, err := dcgs.reconcileStatefulset(context.Background(), st, ddc, cg)
+	if !apierrors.IsConflict(err) {
+		t.Errorf("reconcileStatefulset expected conflict error for requeue, got %v", err)
+	}
+	if event != nil {
+		t.Errorf("reconcileStatefulset expected no event when conflict, got %v", event)
+	}
+	if ddc.Status.ComputeGroupStatuses[0].Phase != dv1.Reconciling {
+		t.Errorf("reconcileStatefulset expected phase Reconciling when conflict, got %s", ddc.Status.ComputeGroupStatuses[0].Phase)
+	}
+}
+
+func Test_feServiceExist(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build()}}
+	if dcgs.feServiceExist(context.Background(), ddc) {
+		t.Errorf("feServiceExist expected false when fe service not created")
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: ddc.GetFEServiceName()}}
+	dcgs.K8sclient = fake.NewClientBuilder().WithObjects(svc).Build()
+	if !dcgs.feServiceExist(context.Background(), ddc) {
+		t.Errorf("feServiceExist expected true when fe service exists")
+	}
+}
+
+func Test_feAvailable(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: ddc.GetFEServiceName()},
+		Subsets: []corev1.EndpointSubset{
+			{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}}},
+			{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}}},
+		},
+	}
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().WithObjects(endpoints).Build()}}
+	if available, notReady := dcgs.feAvailable(ddc); available || notReady != 3 {
+		t.Errorf("feAvailable expected not available with 3 not ready addresses, got available %t, not ready %d", available, notReady)
+	}
+
+	endpoints.Subsets[1].Addresses = []corev1.EndpointAddress{{IP: "10.0.0.4"}}
+	dcgs.K8sclient = fake.NewClientBuilder().WithObjects(endpoints).Build()
+	if available, _ := dcgs.feAvailable(ddc); !available {
+		t.Errorf("feAvailable expected available when any address ready")
+	}
+}
+
+func Test_reconcileStatefulset_WaitService(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}
+	st := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"},
+		Spec:       appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(1), ServiceName: "test-cg1"},
+	}
+	k8sclient := fake.NewClientBuilder().Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+
+	event, err := dcgs.reconcileStatefulset(context.Background(), st, ddc, cg)
+	if err == nil || event == nil || event.Reason != sc.CGWaitServiceReady {
+		t.Errorf("reconcileStatefulset expected waiting service when service not exist, event %v, err %v", event, err)
+	}
+	var est appv1.StatefulSet
+	if err := k8sclient.Get(context.Background(), client.ObjectKeyFromObject(st), &est); !apierrors.IsNotFound(err) {
+		t.Errorf("reconcileStatefulset expected statefulset not created before service ready, err %v", err)
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}}
+	if err := k8sclient.Create(context.Background(), svc); err != nil {
+		t.Fatalf("create service failed, err=%s", err.Error())
+	}
+	if err := dcgs.checkServiceReady(context.Background(), st); err == nil {
+		t.Errorf("checkServiceReady expected error when service have not selector")
+	}
+	svc.Spec.Selector = dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId)
+	if err := k8sclient.Update(context.Background(), svc); err != nil {
+		t.Fatalf("update service failed, err=%s", err.Error())
+	}
+	if err := dcgs.checkServiceReady(context.Background(), st); err != nil {
+		t.Errorf("checkServiceReady expected ready, err=%s", err.Error())
+	}
+}
+
+func Test_Sync_Deleting(t *testing.T) {
+	now := metav1.Now()
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", DeletionTimestamp: &now},
+		Spec: dv1.DorisDisaggregatedClusterSpec{
+			ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}},
+		},
+	}
+	k8sclient := fake.NewClientBuilder().Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+
+	if err := dcgs.Sync(context.Background(), ddc); err != nil {
+		t.Errorf("Sync deleting cluster expected nil, err=%s", err.Error())
+	}
+	if len(recorder.Events) != 0 || len(ddc.Status.ComputeGroupStatuses) != 0 {
+		t.Errorf("Sync deleting cluster expected skipped, events %d, compute group status %v", len(recorder.Events), ddc.Status.ComputeGroupStatuses)
+	}
+	var services corev1.ServiceList
+	if err := k8sclient.List(context.Background(), &services); err != nil || len(services.Items) != 0 {
+		t.Errorf("Sync deleting cluster expected no service created, services %d, err %v", len(services.Items), err)
+	}
+}
+
+func Test_validateNameCollision(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: dv1.DorisDisaggregatedClusterSpec{
+			ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg_1"}, {UniqueId: "cg_2"}, {UniqueId: "cg-1"}},
+		},
+	}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	if msg := dcgs.validateNameCollision(ddc); msg != "compute groups cg_1 and cg-1 generate the same statefulset or service name test-cg-1;" {
+		t.Errorf("validateNameCollision expected cg_1 collides with cg-1, got %q", msg)
+	}
+
+	ddc.Spec.ComputeGroups = ddc.Spec.ComputeGroups[:2]
+	if msg := dcgs.validateNameCollision(ddc); msg != "" {
+		t.Errorf("validateNameCollision expected no collision, got %q", msg)
+	}
+}
+
+func Test_validateRegex_CustomPattern(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	cgs := []dv1.ComputeGroup{{UniqueId: "prod_cg1"}, {UniqueId: "cg2"}}
+	re, err := computeGroupNameRegexp("^prod_[a-z0-9]+$")
+	if err != nil {
+		t.Fatalf("computeGroupNameRegexp expected the valid pattern compiled, err=%s", err.Error())
+	}
+	if msg, ok := dcgs.validateRegex(cgs, re); ok || msg != "cg2 not match ^prod_[a-z0-9]+$" {
+		t.Errorf("validateRegex expected cg2 not match the custom pattern, got %q", msg)
+	}
+	if cached, _ := computeGroupNameRegexp("^prod_[a-z0-9]+$"); cached != re {
+		t.Errorf("computeGroupNameRegexp expected the compiled pattern reused")
+	}
+
+	re, _ = computeGroupNameRegexp("")
+	if msg, ok := dcgs.validateRegex(cgs, re); !ok {
+		t.Errorf("validateRegex expected the built-in pattern used when not set, got %q", msg)
+	}
+}
+
+func Test_validateComputeGroup_InvalidNameRegex(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: dv1.DorisDisaggregatedClusterSpec{
+			ComputeGroupNameRegex: "prod_[a-z",
+			ComputeGroups:         []dv1.ComputeGroup{{UniqueId: "prod_cg1"}},
+		},
+	}
+	event, ok := dcgs.validateComputeGroup(context.Background(), ddc)
+	if ok || event == nil || event.Reason != sc.CGNameRegexInvalid {
+		t.Errorf("validateComputeGroup expected %s when the pattern not compiled, got %+v", sc.CGNameRegexInvalid, event)
+	}
+}
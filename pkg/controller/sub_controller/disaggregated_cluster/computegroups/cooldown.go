@@ -0,0 +1,114 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// deferScaleInCooldown keep the replicas of existing statefulset when the last scale operation of compute group is in cooldown, return true when deferred.
+// the other changes of statefulset still apply, the replicas in spec applied after the cooldown elapses.
+func (dcgs *DisaggregatedComputeGroupsController) deferScaleInCooldown(cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) bool {
+	if cgStatus == nil {
+		return false
+	}
+	cgStatus.ScaleDeferredUntil = nil
+	//the scale down in progress should continue, it has started before.
+	if cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+		return false
+	}
+
+	remaining := scaleCooldownRemaining(cg, cgStatus, *st.Spec.Replicas, *est.Spec.Replicas, time.Now())
+	if remaining <= 0 {
+		return false
+	}
+
+	until := metav1.NewTime(time.Now().Add(remaining))
+	cgStatus.ScaleDeferredUntil = &until
+	msg := fmt.Sprintf("compute group %s scale from %d to %d deferred by cooldown, will apply after %s.", cg.UniqueId, *est.Spec.Replicas, *st.Spec.Replicas, remaining.Round(time.Second).String())
+	dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGScaleCooldown), msg)
+	st.Spec.Replicas = est.Spec.Replicas
+	return true
+}
+
+// holdScaleDownForFEMetadata keep the replicas of existing statefulset when fe metadata unhealthy, the drop or decommission on corrupted metadata may remove the wrong backends.
+// return true when a scale down in progress, the reconcile should be skipped until the metadata recovered.
+func holdScaleDownForFEMetadata(cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) bool {
+	if cgStatus == nil || !cluster.IsFEMetadataUnhealthy() {
+		return false
+	}
+	if cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+		return true
+	}
+	if *st.Spec.Replicas < *est.Spec.Replicas {
+		st.Spec.Replicas = est.Spec.Replicas
+	}
+	return false
+}
+
+// deferScaleDownDegraded keep the replicas of existing statefulset when scaling down a compute group that not all pods available, return true when deferred.
+// the scale down in progress continues, and allowDegradedScaleDown skips the check for emergency scale-in. the pods ready trigger reconciling to scale down.
+func (dcgs *DisaggregatedComputeGroupsController) deferScaleDownDegraded(cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) bool {
+	if cgStatus == nil || cg.AllowDegradedScaleDown || *st.Spec.Replicas >= *est.Spec.Replicas {
+		return false
+	}
+	if cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+		return false
+	}
+	if cgStatus.AvailableReplicas >= *est.Spec.Replicas {
+		return false
+	}
+
+	msg := fmt.Sprintf("compute group %s scale down from %d to %d deferred, only %d pods available. set allowDegradedScaleDown for emergency scale-in.", cg.UniqueId, *est.Spec.Replicas, *st.Spec.Replicas, cgStatus.AvailableReplicas)
+	dcgs.K8srecorder.Event(cluster, string(sc.EventWarning), string(sc.CGScaleDownDeferredDegraded), msg)
+	st.Spec.Replicas = est.Spec.Replicas
+	return true
+}
+
+// scaleCooldownRemaining return the remaining time of cooldown from the last scale, return 0 when replicas not changed or cooldown not configured.
+// the scale up driven by nodePoolReplicas use the nodePoolScaleUpCooldown if configured.
+func scaleCooldownRemaining(cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, replicas, existReplicas int32, now time.Time) time.Duration {
+	if replicas == existReplicas || cgStatus.LastScaleTime == nil {
+		return 0
+	}
+
+	cooldown := cg.ScaleCooldown
+	if replicas > existReplicas && cg.NodePoolReplicas != nil && cg.NodePoolScaleUpCooldown != nil {
+		cooldown = cg.NodePoolScaleUpCooldown
+	}
+	if cooldown == nil {
+		return 0
+	}
+
+	return cgStatus.LastScaleTime.Add(cooldown.Duration).Sub(now)
+}
+
+// recordScaleTime record the time of scale operation applied on statefulset, it is the start of cooldown.
+func recordScaleTime(cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) {
+	if cgStatus == nil || *st.Spec.Replicas == *est.Spec.Replicas {
+		return
+	}
+	now := metav1.Now()
+	cgStatus.LastScaleTime = &now
+}
@@ -0,0 +1,141 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_scaleCooldownRemaining(t *testing.T) {
+	now := time.Now()
+	last := metav1.NewTime(now.Add(-2 * time.Minute))
+	cgStatus := &dv1.ComputeGroupStatus{LastScaleTime: &last}
+	cg := &dv1.ComputeGroup{
+		ScaleCooldown:           &metav1.Duration{Duration: 10 * time.Minute},
+		NodePoolScaleUpCooldown: &metav1.Duration{Duration: time.Minute},
+	}
+
+	if r := scaleCooldownRemaining(cg, cgStatus, 3, 3, now); r != 0 {
+		t.Errorf("scaleCooldownRemaining expected 0 when replicas not changed, got %s", r)
+	}
+	if r := scaleCooldownRemaining(cg, cgStatus, 4, 3, now); r != 8*time.Minute {
+		t.Errorf("scaleCooldownRemaining expected 8m for scale up, got %s", r)
+	}
+	//the scale up driven by node pool use the shorter cooldown.
+	cg.NodePoolReplicas = &dv1.NodePoolReplicas{Percentage: 50}
+	if r := scaleCooldownRemaining(cg, cgStatus, 4, 3, now); r > 0 {
+		t.Errorf("scaleCooldownRemaining expected node pool scale up cooldown elapsed, got %s", r)
+	}
+	if r := scaleCooldownRemaining(cg, cgStatus, 2, 3, now); r != 8*time.Minute {
+		t.Errorf("scaleCooldownRemaining expected 8m for node pool scale down, got %s", r)
+	}
+	if r := scaleCooldownRemaining(&dv1.ComputeGroup{}, cgStatus, 2, 3, now); r != 0 {
+		t.Errorf("scaleCooldownRemaining expected 0 when cooldown not configured, got %s", r)
+	}
+}
+
+func Test_deferScaleInCooldown(t *testing.T) {
+	last := metav1.NewTime(time.Now().Add(-time.Minute))
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling, LastScaleTime: &last}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", ScaleCooldown: &metav1.Duration{Duration: 10 * time.Minute}}
+	st := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(5)}}
+	est := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(3)}}
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+
+	if !dcgs.deferScaleInCooldown(&dv1.DorisDisaggregatedCluster{}, cg, cgStatus, st, est) {
+		t.Fatalf("deferScaleInCooldown expected deferred in cooldown")
+	}
+	if *st.Spec.Replicas != 3 || cgStatus.ScaleDeferredUntil == nil {
+		t.Errorf("deferScaleInCooldown expected keep replicas 3 and set deferred time, got replicas %d deferred %v", *st.Spec.Replicas, cgStatus.ScaleDeferredUntil)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("deferScaleInCooldown expected 1 event, got %d", len(recorder.Events))
+	}
+
+	//the scale down in progress not deferred.
+	st.Spec.Replicas = resource.GetInt32Pointer(2)
+	cgStatus.Phase = dv1.Decommissioning
+	if dcgs.deferScaleInCooldown(&dv1.DorisDisaggregatedCluster{}, cg, cgStatus, st, est) || cgStatus.ScaleDeferredUntil != nil {
+		t.Errorf("deferScaleInCooldown expected not defer decommissioning compute group")
+	}
+}
+
+func Test_deferScaleDownDegraded(t *testing.T) {
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling, AvailableReplicas: 2}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	st := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(1)}}
+	est := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(3)}}
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+
+	if !dcgs.deferScaleDownDegraded(&dv1.DorisDisaggregatedCluster{}, cg, cgStatus, st, est) || *st.Spec.Replicas != 3 {
+		t.Errorf("deferScaleDownDegraded expected keep replicas 3 when degraded, got %d", *st.Spec.Replicas)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("deferScaleDownDegraded expected 1 event, got %d", len(recorder.Events))
+	}
+
+	//emergency scale-in not deferred.
+	cg.AllowDegradedScaleDown = true
+	st.Spec.Replicas = resource.GetInt32Pointer(1)
+	if dcgs.deferScaleDownDegraded(&dv1.DorisDisaggregatedCluster{}, cg, cgStatus, st, est) || *st.Spec.Replicas != 1 {
+		t.Errorf("deferScaleDownDegraded expected not defer when allowDegradedScaleDown")
+	}
+
+	//all pods available not deferred.
+	cg.AllowDegradedScaleDown = false
+	cgStatus.AvailableReplicas = 3
+	if dcgs.deferScaleDownDegraded(&dv1.DorisDisaggregatedCluster{}, cg, cgStatus, st, est) {
+		t.Errorf("deferScaleDownDegraded expected not defer fully ready compute group")
+	}
+}
+
+func Test_holdScaleDownForFEMetadata(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{}
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling}
+	newSts := func(replicas int32) *appv1.StatefulSet {
+		return &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(replicas)}}
+	}
+
+	st := newSts(2)
+	if skip := holdScaleDownForFEMetadata(ddc, cgStatus, st, newSts(3)); skip || *st.Spec.Replicas != 2 {
+		t.Errorf("holdScaleDownForFEMetadata expected not hold when fe metadata healthy, skip %t replicas %d", skip, *st.Spec.Replicas)
+	}
+
+	ddc.Status.Conditions = []metav1.Condition{{Type: dv1.FEMetadataUnhealthy, Status: metav1.ConditionTrue, Reason: dv1.FEMetadataInconsistent}}
+	if skip := holdScaleDownForFEMetadata(ddc, cgStatus, st, newSts(3)); skip || *st.Spec.Replicas != 3 {
+		t.Errorf("holdScaleDownForFEMetadata expected keep replicas 3, skip %t replicas %d", skip, *st.Spec.Replicas)
+	}
+	st = newSts(4)
+	if skip := holdScaleDownForFEMetadata(ddc, cgStatus, st, newSts(3)); skip || *st.Spec.Replicas != 4 {
+		t.Errorf("holdScaleDownForFEMetadata expected not hold scaling up, skip %t replicas %d", skip, *st.Spec.Replicas)
+	}
+	cgStatus.Phase = dv1.Decommissioning
+	if skip := holdScaleDownForFEMetadata(ddc, cgStatus, newSts(2), newSts(3)); !skip {
+		t.Errorf("holdScaleDownForFEMetadata expected skip the decommissioning compute group")
+	}
+}
@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// reconcileCordon disable the queries of backends in fe when compute group cordoned, and restore them when uncordoned. the backends kept alive and the statefulset not changed.
+// the backends registered in cordoned(ep: scale out) are disabled in next reconcile. the Cordoned condition removed after all backends restored.
+func (dcgs *DisaggregatedComputeGroupsController) reconcileCordon(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	if cgStatus == nil || cgStatus.ComputeGroupId == "" || ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		return nil, nil
+	}
+	prev := meta.FindStatusCondition(cgStatus.Conditions, dv1.Cordoned)
+	if !cg.Cordoned && prev == nil {
+		return nil, nil
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgStatus.ComputeGroupId)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+
+	if !cg.Cordoned {
+		meta.SetStatusCondition(&cgStatus.Conditions, metav1.Condition{
+			Type:               dv1.Cordoned,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: ddc.Generation,
+			Reason:             dv1.ComputeGroupUncordoning,
+			Message:            "restoring the queries of backends in fe.",
+		})
+	}
+
+	var changed []string
+	for _, be := range backends {
+		disabled, err := be.QueryDisabled()
+		if err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController reconcileCordon namespace %s name %s parse status of backend %s failed, status: %s, err=%s", ddc.Namespace, ddc.Name, be.Host, be.Status, err.Error())
+		}
+		if err == nil && disabled == cg.Cordoned {
+			continue
+		}
+		if err := sqlClient.SetBackendQueryDisabled(be, cg.Cordoned); err != nil {
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+		}
+		changed = append(changed, strings.Split(be.Host, ".")[0])
+	}
+
+	if !cg.Cordoned {
+		meta.RemoveStatusCondition(&cgStatus.Conditions, dv1.Cordoned)
+		msg := fmt.Sprintf("compute group %s uncordoned, the backends of pods %s receive queries again.", cg.UniqueId, strings.Join(changed, ","))
+		klog.Infof("disaggregatedComputeGroupsController reconcileCordon namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGUncordoned), msg)
+		return nil, nil
+	}
+
+	meta.SetStatusCondition(&cgStatus.Conditions, metav1.Condition{
+		Type:               dv1.Cordoned,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ddc.Generation,
+		Reason:             dv1.ComputeGroupCordoned,
+		Message:            fmt.Sprintf("%d backends disabled query in fe, the new queries not routed to the compute group.", len(backends)),
+	})
+	if prev == nil || prev.Status != metav1.ConditionTrue || prev.Reason != dv1.ComputeGroupCordoned {
+		msg := fmt.Sprintf("compute group %s cordoned, the backends of pods %s not receive new queries, the backends kept alive.", cg.UniqueId, strings.Join(changed, ","))
+		klog.Infof("disaggregatedComputeGroupsController reconcileCordon namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGCordoned), msg)
+	}
+	return nil, nil
+}
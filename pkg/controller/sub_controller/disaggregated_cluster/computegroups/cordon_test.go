@@ -0,0 +1,52 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reconcileCordon_neverCordoned(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build(), K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ddc.Status.FEStatus.AvailableStatus = dv1.Available
+	ddc.Status.ComputeGroupStatuses = []dv1.ComputeGroupStatus{{UniqueId: "cg1", ComputeGroupId: "cg1id"}}
+
+	//the compute group never cordoned not connects fe.
+	event, err := dcgs.reconcileCordon(context.Background(), ddc, &dv1.ComputeGroup{UniqueId: "cg1"})
+	if event != nil || err != nil || len(recorder.Events) != 0 {
+		t.Errorf("reconcileCordon not cordoned expect nothing done, got event %v err %v", event, err)
+	}
+
+	//cordoned but fe not connectable, the failure surfaced and the condition not set.
+	event, err = dcgs.reconcileCordon(context.Background(), ddc, &dv1.ComputeGroup{UniqueId: "cg1", Cordoned: true})
+	if err == nil || event == nil || event.Reason != sc.CGSqlExecFailed {
+		t.Errorf("reconcileCordon expect CGSqlExecFailed when fe not connectable, got event %v err %v", event, err)
+	}
+	if len(ddc.Status.ComputeGroupStatuses[0].Conditions) != 0 {
+		t.Errorf("reconcileCordon expect no condition when backends not disabled, got %v", ddc.Status.ComputeGroupStatuses[0].Conditions)
+	}
+}
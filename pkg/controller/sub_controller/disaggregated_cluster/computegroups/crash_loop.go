@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the waiting reason of container that kubelet backs off restarting the failed container.
+const crashLoopBackOffReason = "CrashLoopBackOff"
+
+// checkCGCrashLoop set the Degraded condition of compute group by the crash looping pods, emit warning event when the condition becomes true.
+func (dcgs *DisaggregatedComputeGroupsController) checkCGCrashLoop(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, crashLooping []string) {
+	condition := newDegradedCondition(cgs.UniqueId, crashLooping, ddc.Generation)
+	if condition.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(cgs.Conditions, dv1.Degraded) {
+		klog.Errorf("disaggregatedComputeGroupsController checkCGCrashLoop namespace %s name %s %s", ddc.Namespace, ddc.Name, condition.Message)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGPodsCrashLooping), condition.Message)
+	}
+	meta.SetStatusCondition(&cgs.Conditions, condition)
+}
+
+func newDegradedCondition(uniqueId string, crashLooping []string, generation int64) metav1.Condition {
+	if len(crashLooping) == 0 {
+		return metav1.Condition{
+			Type:               dv1.Degraded,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             dv1.PodsNotCrashLooping,
+			Message:            "no pod of compute group crash looping.",
+		}
+	}
+	return metav1.Condition{
+		Type:               dv1.Degraded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             dv1.PodsCrashLooping,
+		Message:            fmt.Sprintf("compute group %s %d pods crash looping, %s.", uniqueId, len(crashLooping), strings.Join(crashLooping, "; ")),
+	}
+}
+
+// podCrashLoopReason return the description of the crash looping container of pod, empty when no container in CrashLoopBackOff.
+// the description have the restart count and the reason of last termination, ep: OOMKilled, Error with exit code.
+func podCrashLoopReason(pod *corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting == nil || cs.State.Waiting.Reason != crashLoopBackOffReason {
+			continue
+		}
+		reason := fmt.Sprintf("pod %s container %s restarted %d times", pod.Name, cs.Name, cs.RestartCount)
+		if t := cs.LastTerminationState.Terminated; t != nil {
+			reason = fmt.Sprintf("%s, last terminated by %s with exit code %d", reason, t.Reason, t.ExitCode)
+		}
+		return reason
+	}
+	return ""
+}
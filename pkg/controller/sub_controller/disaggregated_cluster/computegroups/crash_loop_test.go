@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_podCrashLoopReason(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-0"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "compute",
+				RestartCount:         5,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}},
+		},
+	}
+	if reason := podCrashLoopReason(pod); reason != "pod test-cg1-0 container compute restarted 5 times, last terminated by OOMKilled with exit code 137" {
+		t.Errorf("podCrashLoopReason not expected, got %s", reason)
+	}
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+	if reason := podCrashLoopReason(pod); reason != "" {
+		t.Errorf("podCrashLoopReason expected empty for creating container, got %s", reason)
+	}
+}
+
+func Test_checkCGCrashLoop(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1"}
+
+	for i := 0; i < 2; i++ {
+		dcgs.checkCGCrashLoop(ddc, cgs, []string{"pod test-cg1-0 container compute restarted 5 times"})
+	}
+	if !meta.IsStatusConditionTrue(cgs.Conditions, dv1.Degraded) {
+		t.Errorf("checkCGCrashLoop expect Degraded condition true, got %v", cgs.Conditions)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("checkCGCrashLoop expect 1 event when condition becomes true, got %d", len(recorder.Events))
+	}
+
+	dcgs.checkCGCrashLoop(ddc, cgs, nil)
+	if meta.IsStatusConditionTrue(cgs.Conditions, dv1.Degraded) {
+		t.Errorf("checkCGCrashLoop expect Degraded condition false when no pods crash looping.")
+	}
+}
@@ -0,0 +1,234 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the suffix of temporary statefulset name that serves when recreating the statefulset of compute group.
+const cutoverStatefulsetSuffix = "-cutover"
+
+// cutoverStatefulset recreate the statefulset when the immutable fields changed, a temporary statefulset with the new spec serves in the gap of recreating.
+// the temporary pods have the labels of compute group pods, the service selects them with the pods of statefulset, so the ready pods of either one serve the traffic.
+// the steps: create the temporary statefulset -> delete the old statefulset after the temporary ready and backends registered -> the statefulset recreated in next reconcile
+// -> remove the temporary statefulset and drop its backends after the recreated ready. return true when the cutover in progress, the apply of statefulset skipped.
+func (dcgs *DisaggregatedComputeGroupsController) cutoverStatefulset(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, st, est *appv1.StatefulSet) (bool, *sc.Event, error) {
+	cgStatus := findCGStatus(cluster, cg.UniqueId)
+	if cgStatus == nil {
+		return false, nil, nil
+	}
+	changed := immutableFieldsChanged(st, est)
+
+	if cgStatus.CutoverStatefulsetName == "" {
+		//the scale in progress drops backends by ordinal, start the cutover after it finished.
+		if !cg.CutoverOnRecreate || len(changed) == 0 || *st.Spec.Replicas != *est.Spec.Replicas ||
+			cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+			return false, nil, nil
+		}
+		tst := dcgs.newCutoverStatefulset(st)
+		if err := k8s.CreateClientObject(ctx, dcgs.K8sclient, tst); err != nil && !apierrors.IsAlreadyExists(err) {
+			klog.Errorf("disaggregatedComputeGroupsController cutoverStatefulset create statefulset namespace=%s name=%s failed, err=%s", tst.Namespace, tst.Name, err.Error())
+			return true, &sc.Event{Type: sc.EventWarning, Reason: sc.CGCreateResourceFailed, Message: err.Error()}, err
+		}
+		cgStatus.CutoverStatefulsetName = tst.Name
+		cgStatus.Phase = dv1.CuttingOver
+		msg := fmt.Sprintf("compute group %s immutable fields %s changed, statefulset %s will be recreated after the temporary statefulset %s serving.", cg.UniqueId, strings.Join(changed, ","), st.Name, tst.Name)
+		klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s %s", cluster.Namespace, cluster.Name, msg)
+		dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGCutoverStarted), msg)
+		return true, nil, nil
+	}
+
+	cgStatus.Phase = dv1.CuttingOver
+	//the cutover deletes statefulset and drops backends, wait the fe metadata recovered.
+	if cluster.IsFEMetadataUnhealthy() {
+		klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s wait fe metadata recovered.", cluster.Namespace, cluster.Name)
+		return true, nil, nil
+	}
+	if len(changed) != 0 {
+		tst, err := k8s.GetStatefulSet(ctx, dcgs.K8sclient, cluster.Namespace, cgStatus.CutoverStatefulsetName)
+		if apierrors.IsNotFound(err) {
+			//the temporary statefulset deleted by others, create it again.
+			tst = dcgs.newCutoverStatefulset(st)
+			return true, nil, k8s.CreateClientObject(ctx, dcgs.K8sclient, tst)
+		} else if err != nil {
+			return true, nil, err
+		}
+		if !dcgs.statefulsetServing(ctx, cluster, cgStatus, tst) {
+			klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s wait temporary statefulset %s serving.", cluster.Namespace, cluster.Name, tst.Name)
+			return true, nil, nil
+		}
+		//the pods of old statefulset drain connections before deleted, the temporary pods serve the new connections.
+		if cg.ConnectionDraining != nil && !dcgs.drainConnections(ctx, cluster, cg, cgStatus, statefulsetPods(est)) {
+			klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s wait statefulset %s pods draining connections.", cluster.Namespace, cluster.Name, est.Name)
+			return true, nil, nil
+		}
+		klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s temporary statefulset %s serving, delete statefulset %s for recreating.", cluster.Namespace, cluster.Name, tst.Name, est.Name)
+		if err := k8s.DeleteClientObject(ctx, dcgs.K8sclient, est); err != nil && !apierrors.IsNotFound(err) {
+			return true, &sc.Event{Type: sc.EventWarning, Reason: sc.CGApplyResourceFailed, Message: err.Error()}, err
+		}
+		clearDraining(cgStatus)
+		return true, nil, nil
+	}
+
+	//the statefulset recreated with the new spec, remove the temporary after the recreated serving.
+	if !dcgs.statefulsetServing(ctx, cluster, cgStatus, est) {
+		klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s wait recreated statefulset %s serving.", cluster.Namespace, cluster.Name, est.Name)
+		return true, nil, nil
+	}
+	if err := dcgs.removeCutoverStatefulset(ctx, cluster, cgStatus); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s remove temporary statefulset %s failed, err=%s", cluster.Namespace, cluster.Name, cgStatus.CutoverStatefulsetName, err.Error())
+		return true, &sc.Event{Type: sc.EventWarning, Reason: sc.CGApplyResourceFailed, Message: err.Error()}, err
+	}
+	msg := fmt.Sprintf("compute group %s statefulset %s recreated, the temporary statefulset %s removed.", cg.UniqueId, est.Name, cgStatus.CutoverStatefulsetName)
+	klog.Infof("disaggregatedComputeGroupsController cutoverStatefulset namespace=%s name=%s %s", cluster.Namespace, cluster.Name, msg)
+	dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGCutoverFinished), msg)
+	cgStatus.CutoverStatefulsetName = ""
+	cgStatus.Phase = dv1.Reconciling
+	return false, nil, nil
+}
+
+// newCutoverStatefulset build the temporary statefulset from the new statefulset, the selector adds the cutover label for not selecting the pods of statefulset.
+func (dcgs *DisaggregatedComputeGroupsController) newCutoverStatefulset(st *appv1.StatefulSet) *appv1.StatefulSet {
+	tst := st.DeepCopy()
+	tst.Name = st.Name + cutoverStatefulsetSuffix
+	tst.ResourceVersion = ""
+	tst.Labels = withCutoverLabel(st.Labels)
+	tst.Spec.Selector = &metav1.LabelSelector{MatchLabels: withCutoverLabel(st.Spec.Selector.MatchLabels)}
+	tst.Spec.Template.Labels = withCutoverLabel(st.Spec.Template.Labels)
+	dcgs.DisaggregatedSubDefaultController.AddDownwardAPI(tst)
+	dcgs.useNewDefaultValuesInStatefulset(tst)
+	return tst
+}
+
+// withCutoverLabel return a copy of labels with the cutover label.
+func withCutoverLabel(labels map[string]string) map[string]string {
+	l := map[string]string{}
+	for k, v := range labels {
+		l[k] = v
+	}
+	l[dv1.DorisDisaggregatedCutover] = "true"
+	return l
+}
+
+// immutableFieldsChanged return the immutable fields of statefulset that the new differs from the existing.
+func immutableFieldsChanged(st, est *appv1.StatefulSet) []string {
+	var changed []string
+	if st.Spec.PodManagementPolicy != est.Spec.PodManagementPolicy {
+		changed = append(changed, "podManagementPolicy")
+	}
+
+	//the existing templates have the default values filled by apiserver, only compare the fields that operator sets.
+	if len(st.Spec.VolumeClaimTemplates) != len(est.Spec.VolumeClaimTemplates) {
+		return append(changed, "volumeClaimTemplates")
+	}
+	evcts := map[string]*corev1.PersistentVolumeClaim{}
+	for i := range est.Spec.VolumeClaimTemplates {
+		evcts[est.Spec.VolumeClaimTemplates[i].Name] = &est.Spec.VolumeClaimTemplates[i]
+	}
+	for i := range st.Spec.VolumeClaimTemplates {
+		vct := &st.Spec.VolumeClaimTemplates[i]
+		evct, ok := evcts[vct.Name]
+		if !ok || !reflect.DeepEqual(vct.Spec.StorageClassName, evct.Spec.StorageClassName) || !reflect.DeepEqual(vct.Spec.AccessModes, evct.Spec.AccessModes) ||
+			!vct.Spec.Resources.Requests.Storage().Equal(*evct.Spec.Resources.Requests.Storage()) {
+			return append(changed, "volumeClaimTemplates")
+		}
+	}
+	return changed
+}
+
+// statefulsetServing return true when all pods of statefulset ready and the backends of them alive in fe.
+func (dcgs *DisaggregatedComputeGroupsController) statefulsetServing(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus, st *appv1.StatefulSet) bool {
+	if statefulsetRolling(st) || cgStatus.ComputeGroupId == "" {
+		return false
+	}
+	sqlClient, err := dcgs.getMasterSqlClient(ctx, cluster)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController statefulsetServing namespace=%s name=%s get sql client failed, err=%s", cluster.Namespace, cluster.Name, err.Error())
+		return false
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgStatus.ComputeGroupId)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController statefulsetServing namespace=%s name=%s get backends failed, err=%s", cluster.Namespace, cluster.Name, err.Error())
+		return false
+	}
+
+	var alive int32
+	for _, be := range statefulsetBackends(backends, st.Name) {
+		if be.Alive {
+			alive++
+		}
+	}
+	return st.Spec.Replicas == nil || alive >= *st.Spec.Replicas
+}
+
+// removeCutoverStatefulset delete the temporary statefulset and its pvcs, and drop the backends of its pods in fe.
+func (dcgs *DisaggregatedComputeGroupsController) removeCutoverStatefulset(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus) error {
+	tst, err := k8s.GetStatefulSet(ctx, dcgs.K8sclient, cluster.Namespace, cgStatus.CutoverStatefulsetName)
+	if err == nil {
+		if err := k8s.DeleteClientObject(ctx, dcgs.K8sclient, tst); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		for _, vct := range tst.Spec.VolumeClaimTemplates {
+			for i := int32(0); tst.Spec.Replicas != nil && i < *tst.Spec.Replicas; i++ {
+				pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: tst.Namespace, Name: fmt.Sprintf("%s-%s-%d", vct.Name, tst.Name, i)}}
+				if err := k8s.DeleteClientObject(ctx, dcgs.K8sclient, pvc); err != nil && !apierrors.IsNotFound(err) {
+					return err
+				}
+			}
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgStatus.ComputeGroupId)
+	if err != nil {
+		return err
+	}
+	return dcgs.dropBackends(cluster, sqlClient, cgStatus.UniqueId, statefulsetBackends(backends, cgStatus.CutoverStatefulsetName))
+}
+
+// statefulsetBackends return the backends that registered by the pods of statefulset.
+func statefulsetBackends(backends []*mysql.Backend, stsName string) []*mysql.Backend {
+	var res []*mysql.Backend
+	for _, be := range backends {
+		if backendStatefulsetName(be.Host) == stsName {
+			res = append(res, be)
+		}
+	}
+	return res
+}
@@ -0,0 +1,121 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_immutableFieldsChanged(t *testing.T) {
+	newSts := func(policy appv1.PodManagementPolicyType, storage string) *appv1.StatefulSet {
+		return &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{
+			PodManagementPolicy: policy,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "be-storage"},
+				Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: apiresource.MustParse(storage)},
+				}},
+			}},
+		}}
+	}
+
+	est := newSts(appv1.ParallelPodManagement, "100Gi")
+	//the default values filled by apiserver are not changes.
+	volumeMode := corev1.PersistentVolumeFilesystem
+	est.Spec.VolumeClaimTemplates[0].Spec.VolumeMode = &volumeMode
+	if changed := immutableFieldsChanged(newSts(appv1.ParallelPodManagement, "100Gi"), est); len(changed) != 0 {
+		t.Errorf("immutableFieldsChanged expected not changed, got %v", changed)
+	}
+	if changed := immutableFieldsChanged(newSts(appv1.OrderedReadyPodManagement, "200Gi"), est); len(changed) != 2 {
+		t.Errorf("immutableFieldsChanged expected podManagementPolicy and volumeClaimTemplates changed, got %v", changed)
+	}
+}
+
+func Test_cutoverStatefulset_start(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Status: dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{
+			{UniqueId: "cg1", Phase: dv1.Reconciling},
+		}},
+	}
+	k8sclient := fake.NewClientBuilder().Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	selector := map[string]string{dv1.DorisDisaggregatedComputeGroupUniqueId: "cg1"}
+	newSts := func(policy appv1.PodManagementPolicyType) *appv1.StatefulSet {
+		return &appv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"},
+			Spec: appv1.StatefulSetSpec{
+				Replicas:            resource.GetInt32Pointer(3),
+				PodManagementPolicy: policy,
+				Selector:            &metav1.LabelSelector{MatchLabels: selector},
+				Template:            corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: selector}},
+			},
+		}
+	}
+
+	if cutover, _, _ := dcgs.cutoverStatefulset(context.Background(), ddc, cg, newSts(appv1.OrderedReadyPodManagement), newSts(appv1.ParallelPodManagement)); cutover {
+		t.Errorf("cutoverStatefulset expected not cutover when cutoverOnRecreate disabled")
+	}
+
+	cg.CutoverOnRecreate = true
+	cutover, _, err := dcgs.cutoverStatefulset(context.Background(), ddc, cg, newSts(appv1.OrderedReadyPodManagement), newSts(appv1.ParallelPodManagement))
+	cgStatus := &ddc.Status.ComputeGroupStatuses[0]
+	if !cutover || err != nil || cgStatus.Phase != dv1.CuttingOver || cgStatus.CutoverStatefulsetName != "test-cg1-cutover" {
+		t.Errorf("cutoverStatefulset expected start cutover, cutover %t err %v status %+v", cutover, err, cgStatus)
+	}
+	tst, err := k8s.GetStatefulSet(context.Background(), k8sclient, "default", "test-cg1-cutover")
+	if err != nil {
+		t.Fatalf("cutoverStatefulset expected create temporary statefulset, err=%s", err.Error())
+	}
+	if tst.Spec.PodManagementPolicy != appv1.OrderedReadyPodManagement || tst.Spec.Selector.MatchLabels[dv1.DorisDisaggregatedCutover] != "true" ||
+		tst.Spec.Template.Labels[dv1.DorisDisaggregatedComputeGroupUniqueId] != "cg1" {
+		t.Errorf("cutoverStatefulset temporary statefulset not use new spec or labels, got %+v", tst.Spec)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("cutoverStatefulset expected 1 event, got %d", len(recorder.Events))
+	}
+}
+
+func Test_statefulsetBackends(t *testing.T) {
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-0.test-cg1.default.svc.cluster.local"},
+		{Host: "test-cg1-cutover-0.test-cg1.default.svc.cluster.local"},
+		{Host: "test-cg1-cutover-1.test-cg1.default.svc.cluster.local"},
+	}
+	if bes := statefulsetBackends(backends, "test-cg1"); len(bes) != 1 {
+		t.Errorf("statefulsetBackends of test-cg1 expected 1, got %d", len(bes))
+	}
+	if bes := statefulsetBackends(backends, "test-cg1-cutover"); len(bes) != 2 {
+		t.Errorf("statefulsetBackends of test-cg1-cutover expected 2, got %d", len(bes))
+	}
+}
@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// the restarts of compute container that the pod counted as crash looping for the mass failure.
+const massFailureRestartThreshold int32 = 3
+
+// checkCGMassFailure hold the statefulset updates of compute group when at least half of replicas(at least 2 pods) crash looping, ep: a bad config rolled to all pods.
+// rolling the pods again only adds restarts to the storm, the hold keeps the compute group in `Degraded` phase until the spec of cluster changed or released by annotation.
+// the hold is released when the pods recovered.
+func (dcgs *DisaggregatedComputeGroupsController) checkCGMassFailure(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, pods []corev1.Pod) {
+	var failing int32
+	for i := range pods {
+		if crashLooping(&pods[i], massFailureRestartThreshold) {
+			failing++
+		}
+	}
+	mass := failing >= 2 && failing*2 >= cgs.Replicas
+
+	if cgs.HeldGeneration != 0 {
+		if !mass {
+			dcgs.releaseDegradedHold(ddc, cgs, "the pods recovered")
+			return
+		}
+		cgs.Phase = dv1.HeldDegraded
+		return
+	}
+	//the scale down and removing keep their phases, the rolled back image is recovering the pods.
+	if !mass || cgs.RolledBackImage != "" || cgs.Phase == dv1.Decommissioning || cgs.Phase == dv1.ScaleDownFailed || cgs.Phase == dv1.ScaleDownBlocked ||
+		cgs.Phase == dv1.Removing || cgs.Phase == dv1.CuttingOver {
+		return
+	}
+
+	cgs.HeldGeneration = ddc.Generation
+	cgs.Phase = dv1.HeldDegraded
+	msg := fmt.Sprintf("compute group %s %d of %d pods crash looping, the statefulset updates are held to avoid restart storm. please check the logs of pods and fix the config or image, "+
+		"the hold is released when the spec of cluster changed or annotate cluster with '%s'.", cgs.UniqueId, failing, cgs.Replicas, strings.ToLower(fmt.Sprintf(dv1.ReleaseDegradedHold, cgs.UniqueId)))
+	klog.Errorf("disaggregatedComputeGroupsController checkCGMassFailure namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGMassCrashLooping), msg)
+}
+
+// degradedHeld return true when the statefulset updates of compute group held for mass failure.
+// the newer generation of cluster(ep: the config fixed) or the annotation `doris.disaggregated.cluster/release-degraded-hold-{uniqueId}` releases the hold, the fix is applied in the same reconcile.
+func (dcgs *DisaggregatedComputeGroupsController) degradedHeld(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) bool {
+	cgs := findCGStatus(ddc, cg.UniqueId)
+	if cgs == nil || cgs.HeldGeneration == 0 {
+		return false
+	}
+
+	releaseKey := strings.ToLower(fmt.Sprintf(dv1.ReleaseDegradedHold, cg.UniqueId))
+	_, releaseByAnno := ddc.Annotations[releaseKey]
+	if !releaseByAnno && ddc.Generation == cgs.HeldGeneration {
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s compute group %s statefulset updates held for pods crash looping.", ddc.Namespace, ddc.Name, cg.UniqueId)
+		return true
+	}
+
+	reason := "the spec of cluster changed"
+	if releaseByAnno {
+		reason = "released by annotation"
+		if err := dcgs.removeClusterAnnotation(ctx, ddc, releaseKey); err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController degradedHeld remove annotation %s namespace=%s name=%s failed, err=%s", releaseKey, ddc.Namespace, ddc.Name, err.Error())
+		}
+	}
+	dcgs.releaseDegradedHold(ddc, cgs, reason)
+	return false
+}
+
+func (dcgs *DisaggregatedComputeGroupsController) releaseDegradedHold(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, reason string) {
+	cgs.HeldGeneration = 0
+	if cgs.Phase == dv1.HeldDegraded {
+		cgs.Phase = dv1.Reconciling
+	}
+	msg := fmt.Sprintf("compute group %s statefulset updates hold released, %s.", cgs.UniqueId, reason)
+	klog.Infof("disaggregatedComputeGroupsController namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGDegradedHoldReleased), msg)
+}
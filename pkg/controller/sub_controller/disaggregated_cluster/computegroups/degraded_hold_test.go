@@ -0,0 +1,88 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newCrashLoopingPod(name string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name: resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, RestartCount: 5,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason}},
+		}}},
+	}
+}
+
+func Test_checkCGMassFailure(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Generation: 3}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Replicas: 4, Phase: dv1.Reconciling}
+	pods := []corev1.Pod{newCrashLoopingPod("test-cg1-0"), {ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-1"}}}
+
+	dcgs.checkCGMassFailure(ddc, cgs, pods)
+	if cgs.HeldGeneration != 0 || len(recorder.Events) != 0 {
+		t.Fatalf("checkCGMassFailure expected not held when one pod crash looping, got held generation %d", cgs.HeldGeneration)
+	}
+
+	pods[1] = newCrashLoopingPod("test-cg1-1")
+	dcgs.checkCGMassFailure(ddc, cgs, pods)
+	dcgs.checkCGMassFailure(ddc, cgs, pods)
+	if cgs.HeldGeneration != 3 || cgs.Phase != dv1.HeldDegraded || len(recorder.Events) != 1 {
+		t.Errorf("checkCGMassFailure expected held at generation 3 with 1 event, got held generation %d phase %s events %d", cgs.HeldGeneration, cgs.Phase, len(recorder.Events))
+	}
+
+	dcgs.checkCGMassFailure(ddc, cgs, nil)
+	if cgs.HeldGeneration != 0 || cgs.Phase != dv1.Reconciling {
+		t.Errorf("checkCGMassFailure expected released when the pods recovered, got held generation %d phase %s", cgs.HeldGeneration, cgs.Phase)
+	}
+}
+
+func Test_degradedHeld(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Generation: 3},
+		Status:     dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1", HeldGeneration: 3, Phase: dv1.HeldDegraded}}},
+	}
+	k8sclient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(10)}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+
+	if !dcgs.degradedHeld(context.Background(), ddc, cg) {
+		t.Errorf("degradedHeld expected held when the generation not changed")
+	}
+	ddc.Generation = 4
+	if dcgs.degradedHeld(context.Background(), ddc, cg) {
+		t.Errorf("degradedHeld expected released when the generation changed")
+	}
+	if cgs := ddc.Status.ComputeGroupStatuses[0]; cgs.HeldGeneration != 0 || cgs.Phase != dv1.Reconciling {
+		t.Errorf("degradedHeld expected the hold cleared, got held generation %d phase %s", cgs.HeldGeneration, cgs.Phase)
+	}
+}
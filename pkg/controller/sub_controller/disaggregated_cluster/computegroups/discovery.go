@@ -0,0 +1,132 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the key of discovery configmap data, the value is json array of computeGroupEndpoint.
+const discoveryConfigMapKey = "compute_groups.json"
+
+// computeGroupEndpoint describe how to access a compute group, client applications read it from discovery configmap.
+type computeGroupEndpoint struct {
+	UniqueId         string           `json:"uniqueId"`
+	ComputeGroupName string           `json:"computeGroupName"`
+	ServiceName      string           `json:"serviceName"`
+	ServiceAddress   string           `json:"serviceAddress"`
+	Ports            map[string]int32 `json:"ports"`
+	Phase            dv1.Phase        `json:"phase,omitempty"`
+	Ready            bool             `json:"ready"`
+}
+
+// newComputeGroupEndpoints build the endpoints of compute groups in spec from status, the result sorted by uniqueId for stable content.
+func (dcgs *DisaggregatedComputeGroupsController) newComputeGroupEndpoints(ddc *dv1.DorisDisaggregatedCluster) []computeGroupEndpoint {
+	cgssMap := map[string]*dv1.ComputeGroupStatus{}
+	for i := range ddc.Status.ComputeGroupStatuses {
+		cgssMap[ddc.Status.ComputeGroupStatuses[i].UniqueId] = &ddc.Status.ComputeGroupStatuses[i]
+	}
+
+	endpoints := []computeGroupEndpoint{}
+	for i := range ddc.Spec.ComputeGroups {
+		cg := &ddc.Spec.ComputeGroups[i]
+		cgs, ok := cgssMap[cg.UniqueId]
+		if !ok {
+			continue
+		}
+
+		cvs := dcgs.GetConfigValuesFromConfigMaps(ddc.Namespace, resource.BE_RESOLVEKEY, mountedConfigMaps(ddc, cg))
+		ports := map[string]int32{}
+		for _, sp := range newComputeServicePorts(cvs, cg.CommonSpec.Service) {
+			ports[sp.Name] = sp.Port
+		}
+
+		endpoints = append(endpoints, computeGroupEndpoint{
+			UniqueId:         cg.UniqueId,
+			ComputeGroupName: ddc.GetCGName(cg),
+			ServiceName:      cgs.ServiceName,
+			ServiceAddress:   cgs.ServiceName + "." + ddc.Namespace,
+			Ports:            ports,
+			Phase:            cgs.Phase,
+			Ready:            cgServing(cgs),
+		})
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].UniqueId < endpoints[j].UniqueId
+	})
+	return endpoints
+}
+
+// applyDiscoveryConfigMap create or update the configmap that list the compute groups, only update when the content changed.
+func (dcgs *DisaggregatedComputeGroupsController) applyDiscoveryConfigMap(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) error {
+	content, err := json.Marshal(dcgs.newComputeGroupEndpoints(ddc))
+	if err != nil {
+		return err
+	}
+
+	cm, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, ddc.Namespace, ddc.GetCGDiscoveryConfigMapName())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ddc.Namespace,
+				Name:            ddc.GetCGDiscoveryConfigMapName(),
+				Labels:          dcgs.GetCG2LayerCommonSchedulerLabels(ddc.Name),
+				OwnerReferences: []metav1.OwnerReference{resource.GetOwnerReference(ddc)},
+			},
+			Data: map[string]string{discoveryConfigMapKey: string(content)},
+		}
+		return k8s.CreateClientObject(ctx, dcgs.K8sclient, cm)
+	}
+
+	if cm.Data[discoveryConfigMapKey] == string(content) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[discoveryConfigMapKey] = string(content)
+	return k8s.UpdateClientObject(ctx, dcgs.K8sclient, cm)
+}
+
+// clearDiscoveryConfigMap delete the discovery configmap when the cluster have not any compute group.
+func (dcgs *DisaggregatedComputeGroupsController) clearDiscoveryConfigMap(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) error {
+	if len(ddc.Spec.ComputeGroups) != 0 || len(ddc.Status.ComputeGroupStatuses) != 0 {
+		return nil
+	}
+
+	cm, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, ddc.Namespace, ddc.GetCGDiscoveryConfigMapName())
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return k8s.DeleteClientObject(ctx, dcgs.K8sclient, cm)
+}
@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_applyDiscoveryConfigMap(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: dv1.DorisDisaggregatedClusterSpec{
+			ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg2"}, {UniqueId: "cg1"}},
+		},
+		Status: dv1.DorisDisaggregatedClusterStatus{
+			ComputeGroupStatuses: []dv1.ComputeGroupStatus{
+				{UniqueId: "cg1", ServiceName: "test-cg1", Phase: dv1.Ready},
+				{UniqueId: "cg2", ServiceName: "test-cg2", Phase: dv1.Reconciling},
+			},
+		},
+	}
+	k8sclient := fake.NewClientBuilder().Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+	ctx := context.Background()
+
+	if err := dcgs.applyDiscoveryConfigMap(ctx, ddc); err != nil {
+		t.Fatalf("applyDiscoveryConfigMap create failed, err=%s", err.Error())
+	}
+	cm, err := k8s.GetConfigMap(ctx, k8sclient, "default", ddc.GetCGDiscoveryConfigMapName())
+	if err != nil {
+		t.Fatalf("applyDiscoveryConfigMap expected configmap created, err=%s", err.Error())
+	}
+	var endpoints []computeGroupEndpoint
+	if err := json.Unmarshal([]byte(cm.Data[discoveryConfigMapKey]), &endpoints); err != nil {
+		t.Fatalf("discovery configmap content is not valid json, err=%s", err.Error())
+	}
+	if len(endpoints) != 2 || endpoints[0].UniqueId != "cg1" || !endpoints[0].Ready || endpoints[1].Ready {
+		t.Errorf("discovery configmap content not expected, got %+v", endpoints)
+	}
+	if endpoints[0].ServiceAddress != "test-cg1.default" || endpoints[0].Ports[resource.GetPortKey(resource.HEARTBEAT_SERVICE_PORT)] != resource.GetDefaultPort(resource.HEARTBEAT_SERVICE_PORT) {
+		t.Errorf("discovery configmap endpoint of cg1 not expected, got %+v", endpoints[0])
+	}
+
+	ddc.Status.ComputeGroupStatuses[1].Phase = dv1.Ready
+	if err := dcgs.applyDiscoveryConfigMap(ctx, ddc); err != nil {
+		t.Fatalf("applyDiscoveryConfigMap update failed, err=%s", err.Error())
+	}
+	cm, _ = k8s.GetConfigMap(ctx, k8sclient, "default", ddc.GetCGDiscoveryConfigMapName())
+	_ = json.Unmarshal([]byte(cm.Data[discoveryConfigMapKey]), &endpoints)
+	if !endpoints[1].Ready {
+		t.Errorf("discovery configmap expected cg2 ready after update, got %+v", endpoints[1])
+	}
+
+	//the configmap is kept when the cluster have compute groups.
+	if err := dcgs.clearDiscoveryConfigMap(ctx, ddc); err != nil {
+		t.Fatalf("clearDiscoveryConfigMap failed, err=%s", err.Error())
+	}
+	if _, err := k8s.GetConfigMap(ctx, k8sclient, "default", ddc.GetCGDiscoveryConfigMapName()); err != nil {
+		t.Errorf("clearDiscoveryConfigMap expected configmap kept, err=%s", err.Error())
+	}
+
+	ddc.Spec.ComputeGroups = nil
+	ddc.Status.ComputeGroupStatuses = nil
+	if err := dcgs.clearDiscoveryConfigMap(ctx, ddc); err != nil {
+		t.Fatalf("clearDiscoveryConfigMap failed, err=%s", err.Error())
+	}
+	if _, err := k8s.GetConfigMap(ctx, k8sclient, "default", ddc.GetCGDiscoveryConfigMapName()); !apierrors.IsNotFound(err) {
+		t.Errorf("clearDiscoveryConfigMap expected configmap deleted, err=%v", err)
+	}
+}
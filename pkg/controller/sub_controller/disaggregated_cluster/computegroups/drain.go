@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// drainScaledInPods take the pods removed by scaling in out of the service endpoints, return true when the connections drained and the backends can be dropped or decommissioned.
+// the scale down in progress had drained the pods, not wait again.
+func (dcgs *DisaggregatedComputeGroupsController) drainScaledInPods(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) bool {
+	if cg.ConnectionDraining == nil || cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+		return true
+	}
+	var pods []string
+	for i := *st.Spec.Replicas; i < *est.Spec.Replicas; i++ {
+		pods = append(pods, fmt.Sprintf("%s-%d", est.Name, i))
+	}
+	return dcgs.drainConnections(ctx, cluster, cg, cgStatus, pods)
+}
+
+// statefulsetPods return the names of pods that the statefulset have.
+func statefulsetPods(st *appv1.StatefulSet) []string {
+	var pods []string
+	for i := int32(0); st.Spec.Replicas != nil && i < *st.Spec.Replicas; i++ {
+		pods = append(pods, fmt.Sprintf("%s-%d", st.Name, i))
+	}
+	return pods
+}
+
+// drainConnections label the pods not serving for the service stops sending new connections to them, return true after the draining timeout elapsed.
+// the draining restarts when the pods to drain changed.
+func (dcgs *DisaggregatedComputeGroupsController) drainConnections(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, pods []string) bool {
+	if !reflect.DeepEqual(cgStatus.DrainingPods, pods) || cgStatus.DrainingStartTime == nil {
+		cgStatus.DrainingPods = pods
+		cgStatus.DrainingStartTime = &metav1.Time{Time: time.Now()}
+		msg := fmt.Sprintf("compute group %s pods %s taken out of the service, the existing connections drain in %d seconds.", cg.UniqueId, strings.Join(pods, ","), cg.ConnectionDraining.TimeoutSeconds)
+		klog.Infof("disaggregatedComputeGroupsController drainConnections namespace=%s name=%s %s", cluster.Namespace, cluster.Name, msg)
+		dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGConnectionDraining), msg)
+	}
+	//the pods must be out of the endpoints before counting the timeout, retry labeling in next reconcile.
+	if err := dcgs.labelServingPods(ctx, cluster, cg, cgStatus); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController drainConnections namespace=%s name=%s label draining pods of compute group %s failed, err=%s", cluster.Namespace, cluster.Name, cg.UniqueId, err.Error())
+		cgStatus.DrainingStartTime = &metav1.Time{Time: time.Now()}
+		return false
+	}
+	return time.Since(cgStatus.DrainingStartTime.Time) >= time.Duration(cg.ConnectionDraining.TimeoutSeconds)*time.Second
+}
+
+// clearDraining reset the draining in status, the pods still exist are labeled serving in next reconcile.
+func clearDraining(cgStatus *dv1.ComputeGroupStatus) {
+	cgStatus.DrainingPods = nil
+	cgStatus.DrainingStartTime = nil
+}
+
+// labelServingPods label the pods of compute group serving or not, the draining pods in status are `false`, others are `true`.
+// the pods created before connection draining configured have not the label, label them for keeping in the service endpoints.
+func (dcgs *DisaggregatedComputeGroupsController) labelServingPods(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus) error {
+	if cg.ConnectionDraining == nil {
+		return nil
+	}
+	pods, err := k8s.GetPods(ctx, dcgs.K8sclient, cluster.Namespace, dcgs.newCGPodsSelector(cluster.Name, cg.UniqueId))
+	if err != nil {
+		return err
+	}
+	draining := map[string]bool{}
+	if cgStatus != nil {
+		for _, name := range cgStatus.DrainingPods {
+			draining[name] = true
+		}
+	}
+	for i := range pods.Items {
+		serving := "true"
+		if draining[pods.Items[i].Name] {
+			serving = "false"
+		}
+		if err := dcgs.addMissingLabels(ctx, &pods.Items[i], map[string]string{dv1.DorisDisaggregatedServing: serving}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
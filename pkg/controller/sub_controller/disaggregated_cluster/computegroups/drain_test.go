@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_drainScaledInPods(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	labels := dcgs.newCGPodsSelector("test", "cg1")
+	var objs []*corev1.Pod
+	for _, name := range []string{"test-cg1-0", "test-cg1-1", "test-cg1-2"} {
+		objs = append(objs, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}})
+	}
+	k8sclient := fake.NewClientBuilder().WithObjects(objs[0], objs[1], objs[2]).Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs = &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", ConnectionDraining: &dv1.ConnectionDraining{TimeoutSeconds: 60}}
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling}
+	st := &appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}, Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(1)}}
+	est := &appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}, Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(3)}}
+	ctx := context.Background()
+
+	if dcgs.drainScaledInPods(ctx, ddc, cg, cgStatus, st, est) {
+		t.Errorf("drainScaledInPods expected wait the draining timeout")
+	}
+	if len(cgStatus.DrainingPods) != 2 || cgStatus.DrainingStartTime == nil || len(recorder.Events) != 1 {
+		t.Errorf("drainScaledInPods expected 2 draining pods and 1 event, got status %+v events %d", cgStatus, len(recorder.Events))
+	}
+	for name, serving := range map[string]string{"test-cg1-0": "true", "test-cg1-1": "false", "test-cg1-2": "false"} {
+		var pod corev1.Pod
+		_ = k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &pod)
+		if pod.Labels[dv1.DorisDisaggregatedServing] != serving {
+			t.Errorf("drainScaledInPods expected pod %s serving label %s, got %s", name, serving, pod.Labels[dv1.DorisDisaggregatedServing])
+		}
+	}
+
+	cgStatus.DrainingStartTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	if !dcgs.drainScaledInPods(ctx, ddc, cg, cgStatus, st, est) || len(recorder.Events) != 1 {
+		t.Errorf("drainScaledInPods expected drained after the timeout without restarting draining")
+	}
+	cgStatus.DrainingStartTime = nil
+	cgStatus.Phase = dv1.Decommissioning
+	if !dcgs.drainScaledInPods(ctx, ddc, cg, cgStatus, st, est) {
+		t.Errorf("drainScaledInPods expected not drain again when decommissioning")
+	}
+}
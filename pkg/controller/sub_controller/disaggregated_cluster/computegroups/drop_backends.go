@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// dropAnnotatedBackends drop the backends listed in annotation `doris.disaggregated.cluster/drop-backends-{uniqueId}` on cluster, the annotation removed after dropped.
+// the statefulset always keeps the pods of ordinals under replicas, so the pods of dropped backends are deleted to restart and register as new backends, the replicas not changed.
+func (dcgs *DisaggregatedComputeGroupsController) dropAnnotatedBackends(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	key := strings.ToLower(fmt.Sprintf(dv1.DropBackends, cg.UniqueId))
+	value, ok := ddc.Annotations[key]
+	if !ok {
+		return nil, nil
+	}
+	//the scale down in progress drops backends by replicas, drop the annotated after it finished.
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	if cgStatus == nil || cgStatus.ComputeGroupId == "" || ddc.Status.FEStatus.AvailableStatus != dv1.Available || ddc.IsFEMetadataUnhealthy() ||
+		cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+		klog.Infof("disaggregatedComputeGroupsController dropAnnotatedBackends namespace %s name %s compute group %s not ready for dropping backends %s, wait next reconcile.", ddc.Namespace, ddc.Name, cg.UniqueId, value)
+		return nil, nil
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgStatus.ComputeGroupId)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+
+	drops, unmatched := matchDropBackends(backends, value)
+	if len(unmatched) != 0 {
+		msg := fmt.Sprintf("compute group %s backends %s in annotation %s not found, please correct it with pod ordinals or host:heartbeatPort.", cg.UniqueId, strings.Join(unmatched, ","), key)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGDropBackendsInvalid, Message: msg}, errors.New(msg)
+	}
+	if err := dcgs.dropBackends(ddc, sqlClient, cg.UniqueId, drops); err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+
+	var pods []string
+	for _, be := range drops {
+		podName := strings.Split(be.Host, ".")[0]
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ddc.Namespace, Name: podName}}
+		if err := dcgs.K8sclient.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("disaggregatedComputeGroupsController dropAnnotatedBackends namespace %s name %s delete pod %s failed, err=%s", ddc.Namespace, ddc.Name, podName, err.Error())
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGApplyResourceFailed, Message: err.Error()}, err
+		}
+		pods = append(pods, podName)
+	}
+
+	if err := dcgs.removeClusterAnnotation(ctx, ddc, key); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController dropAnnotatedBackends remove annotation %s namespace=%s name=%s failed, err=%s", key, ddc.Namespace, ddc.Name, err.Error())
+	}
+	msg := fmt.Sprintf("compute group %s dropped backends %s by annotation, the pods %s restarted to register as new backends.", cg.UniqueId, value, strings.Join(pods, ","))
+	klog.Infof("disaggregatedComputeGroupsController dropAnnotatedBackends namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGBackendsDropped), msg)
+	return nil, nil
+}
+
+// matchDropBackends return the backends matched by the comma separated pod ordinals or host:heartbeatPort, and the entries not matched any backend.
+func matchDropBackends(backends []*mysql.Backend, value string) ([]*mysql.Backend, []string) {
+	var drops []*mysql.Backend
+	var unmatched []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		ordinal, ordinalErr := strconv.Atoi(entry)
+		var matched *mysql.Backend
+		for _, be := range backends {
+			if ordinalErr == nil {
+				if o, err := backendOrdinal(be.Host); err == nil && o == ordinal {
+					matched = be
+					break
+				}
+			} else if fmt.Sprintf("%s:%d", be.Host, be.HeartbeatPort) == entry {
+				matched = be
+				break
+			}
+		}
+		if matched == nil {
+			unmatched = append(unmatched, entry)
+			continue
+		}
+		drops = append(drops, matched)
+	}
+	return drops, unmatched
+}
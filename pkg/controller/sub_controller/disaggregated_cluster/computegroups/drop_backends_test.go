@@ -0,0 +1,42 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+)
+
+func Test_matchDropBackends(t *testing.T) {
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050},
+		{Host: "test-cg1-1.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050},
+		{Host: "test-cg1-2.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050},
+	}
+
+	drops, unmatched := matchDropBackends(backends, "1, test-cg1-2.test-cg1.default.svc.cluster.local:9050")
+	if len(unmatched) != 0 || len(drops) != 2 || drops[0] != backends[1] || drops[1] != backends[2] {
+		t.Errorf("matchDropBackends expect backends 1 and 2, got %v unmatched %v", drops, unmatched)
+	}
+
+	drops, unmatched = matchDropBackends(backends, "0,5,test-cg1-0.test-cg1.default.svc.cluster.local:9051")
+	if len(drops) != 1 || len(unmatched) != 2 || unmatched[0] != "5" {
+		t.Errorf("matchDropBackends expect ordinal 5 and wrong port unmatched, got %v", unmatched)
+	}
+}
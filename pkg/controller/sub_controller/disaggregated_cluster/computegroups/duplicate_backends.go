@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// dropDuplicateBackends drop the stale backends that have the same host:heartbeatPort with another backend in fe, the duplicates are left by recreating pods.
+// the duplicated pod ordinal confuses the ordinal-based selection of scaling down, the stale is the not alive one when the other is alive.
+func (dcgs *DisaggregatedComputeGroupsController) dropDuplicateBackends(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, backends []*mysql.Backend) {
+	//the duplicate backend ids is a signal of fe metadata unhealthy, not drop on it.
+	if ddc.IsFEMetadataUnhealthy() {
+		return
+	}
+	stale := staleDuplicateBackends(backends, cgStatefulsetNames(ddc))
+	if len(stale) == 0 {
+		return
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController dropDuplicateBackends namespace %s name %s get sql client failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+	defer sqlClient.Close()
+
+	var names []string
+	for _, be := range stale {
+		names = append(names, fmt.Sprintf("%s(%s:%d)", be.BackendID, be.Host, be.HeartbeatPort))
+	}
+	err = sqlClient.DropBEByIds(stale)
+	sc.AuditSQL(dcgs.K8srecorder, ddc, sqlClient, sc.SQLAuditRecord{Operation: sc.AuditDropBackend, Nodes: sc.BackendNodes(stale), SQL: mysql.DropBEByIdsSQL(stale)}, err)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController dropDuplicateBackends namespace %s name %s drop backends %s failed, err=%s", ddc.Namespace, ddc.Name, strings.Join(names, ","), err.Error())
+		return
+	}
+	msg := fmt.Sprintf("dropped the stale duplicate backends %s, another alive backend registered with the same host and heartbeat port.", strings.Join(names, ","))
+	klog.Infof("disaggregatedComputeGroupsController dropDuplicateBackends namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGDuplicateBackendsDropped), msg)
+}
+
+// staleDuplicateBackends return the not alive backends of compute groups that duplicate with an alive backend, the duplicates all not alive are kept for the pod maybe restarting.
+func staleDuplicateBackends(backends []*mysql.Backend, statefulsetNames map[string]bool) []*mysql.Backend {
+	groups := map[string][]*mysql.Backend{}
+	for _, be := range backends {
+		if !statefulsetNames[backendStatefulsetName(be.Host)] {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d", be.Host, be.HeartbeatPort)
+		groups[key] = append(groups[key], be)
+	}
+
+	var keys []string
+	for key, bes := range groups {
+		if len(bes) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var stale []*mysql.Backend
+	for _, key := range keys {
+		var alive bool
+		for _, be := range groups[key] {
+			alive = alive || be.Alive
+		}
+		if !alive {
+			continue
+		}
+		for _, be := range groups[key] {
+			if !be.Alive {
+				stale = append(stale, be)
+			}
+		}
+	}
+	return stale
+}
+
+// cgStatefulsetNames return the statefulset names of compute groups in status.
+func cgStatefulsetNames(ddc *dv1.DorisDisaggregatedCluster) map[string]bool {
+	names := map[string]bool{}
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		names[cgs.StatefulsetName] = true
+	}
+	return names
+}
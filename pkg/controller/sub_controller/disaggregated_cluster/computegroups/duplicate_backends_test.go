@@ -0,0 +1,40 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+)
+
+func Test_staleDuplicateBackends(t *testing.T) {
+	backends := []*mysql.Backend{
+		{BackendID: "10001", Host: "test-cg1-0.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050, Alive: false},
+		{BackendID: "10002", Host: "test-cg1-0.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050, Alive: true},
+		{BackendID: "10003", Host: "test-cg1-1.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050, Alive: false},
+		{BackendID: "10004", Host: "test-cg1-1.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050, Alive: false},
+		{BackendID: "10005", Host: "other-cg-0.other-cg.default.svc.cluster.local", HeartbeatPort: 9050, Alive: false},
+		{BackendID: "10006", Host: "other-cg-0.other-cg.default.svc.cluster.local", HeartbeatPort: 9050, Alive: true},
+	}
+
+	stale := staleDuplicateBackends(backends, map[string]bool{"test-cg1": true})
+	if len(stale) != 1 || stale[0].BackendID != "10001" {
+		t.Errorf("staleDuplicateBackends expect backend 10001, got %v", stale)
+	}
+}
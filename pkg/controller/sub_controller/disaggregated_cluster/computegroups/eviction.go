@@ -0,0 +1,157 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// EvictionWebhookPath the path of the pods eviction webhook registered in webhook server.
+const EvictionWebhookPath = "/validate-pods-eviction-disaggregated-compute-group"
+
+// evictionHandleTimeout bound the handling of an eviction below the timeoutSeconds(10s) of the webhook configuration,
+// the apiserver ignores the response arrived after the webhook timeout and the decommission started by it is not reported.
+const evictionHandleTimeout = 8 * time.Second
+
+// EvictionHandler validate the eviction of compute group pods, the backend of the evicted pod is decommissioned and dropped from fe before the eviction allowed.
+// the eviction is rejected with 429 when the backend decommissioning, the drain(ep: `kubectl drain`) retries it until allowed.
+type EvictionHandler struct {
+	dcgs *DisaggregatedComputeGroupsController
+}
+
+func NewEvictionHandler(mgr ctrl.Manager, watchNamespaces []string) *EvictionHandler {
+	return &EvictionHandler{dcgs: &DisaggregatedComputeGroupsController{
+		sc.DisaggregatedSubDefaultController{
+			K8sclient:       mgr.GetClient(),
+			K8srecorder:     mgr.GetEventRecorderFor(disaggregatedComputeGroupsController),
+			ControllerName:  disaggregatedComputeGroupsController,
+			WatchNamespaces: watchNamespaces,
+		},
+	}}
+}
+
+func (h *EvictionHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	//the dry run eviction should not have side effects.
+	if req.Operation != admissionv1.Create || req.SubResource != "eviction" || (req.DryRun != nil && *req.DryRun) {
+		return admission.Allowed("")
+	}
+	//the pods out of the watch namespaces are not managed by operator.
+	if err := h.dcgs.CheckNamespaceInScope(req.Namespace); err != nil {
+		return admission.Allowed("")
+	}
+	ctx, cancel := context.WithTimeout(ctx, evictionHandleTimeout)
+	defer cancel()
+	pod := &corev1.Pod{}
+	if err := h.dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name}, pod); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController eviction webhook get pod namespace=%s name=%s failed, allow the eviction, err=%s", req.Namespace, req.Name, err.Error())
+		return admission.Allowed("")
+	}
+
+	if allowed, msg := h.dcgs.prepareEviction(ctx, pod); !allowed {
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Code: http.StatusTooManyRequests, Reason: metav1.StatusReasonTooManyRequests, Message: msg},
+		}}
+	}
+	return admission.Allowed("")
+}
+
+// prepareEviction decommission the backend of the compute group pod, return true when the backend decommissioned and dropped or the pod not need decommission.
+// the failures of connecting fe allow the eviction, the drain should not be blocked forever. fe is connected in one attempt, the retries not fit in the webhook timeout.
+func (dcgs *DisaggregatedComputeGroupsController) prepareEviction(ctx context.Context, pod *corev1.Pod) (bool, string) {
+	ddcName := pod.Labels[dv1.DorisDisaggregatedClusterName]
+	if ddcName == "" || pod.Labels[dv1.DorisDisaggregatedPodType] != "compute" {
+		return true, ""
+	}
+	//the backend of not ready pod can not migrate tablets, decommission will not finish.
+	if !k8s.PodIsReady(&pod.Status) {
+		return true, ""
+	}
+	ddc := &dv1.DorisDisaggregatedCluster{}
+	if err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: ddcName}, ddc); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController prepareEviction get ddc namespace=%s name=%s failed, err=%s", pod.Namespace, ddcName, err.Error())
+		return true, ""
+	}
+	if !ddc.Spec.DecommissionOnEviction || ddc.IsPlanMode() {
+		return true, ""
+	}
+
+	sqlClient, err := dcgs.getEvictionSqlClient(ctx, ddc)
+	if err != nil {
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGEvictionDecommissionStarted), fmt.Sprintf("pod %s evicted without decommission, connect fe failed, err=%s", pod.Name, err.Error()))
+		return true, ""
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.ShowBackends()
+	if err != nil {
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGEvictionDecommissionStarted), fmt.Sprintf("pod %s evicted without decommission, show backends failed, err=%s", pod.Name, err.Error()))
+		return true, ""
+	}
+	backend := findPodBackend(backends, pod.Name)
+	if backend == nil {
+		return true, ""
+	}
+
+	if !backend.SystemDecommissioned {
+		if err := sqlClient.DecommissionBE([]*mysql.Backend{backend}); err != nil {
+			return false, fmt.Sprintf("decommission backend %s of pod %s failed, retry later, err=%s", backend.Host, pod.Name, err.Error())
+		}
+		msg := fmt.Sprintf("pod %s evicting, decommission backend %s.", pod.Name, backend.Host)
+		klog.Infof("disaggregatedComputeGroupsController prepareEviction namespace=%s name=%s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGEvictionDecommissionStarted), msg)
+		return false, fmt.Sprintf("backend %s of pod %s decommissioning, retry later.", backend.Host, pod.Name)
+	}
+	if backend.TabletNum > 0 {
+		return false, fmt.Sprintf("backend %s of pod %s decommissioning, %d tablets remain, retry later.", backend.Host, pod.Name, backend.TabletNum)
+	}
+
+	//drop the decommissioned backend, the pod recreated registers as a new backend.
+	if err := dcgs.dropBackends(ddc, sqlClient, pod.Labels[dv1.DorisDisaggregatedComputeGroupUniqueId], []*mysql.Backend{backend}); err != nil {
+		return false, fmt.Sprintf("drop decommissioned backend %s of pod %s failed, retry later, err=%s", backend.Host, pod.Name, err.Error())
+	}
+	msg := fmt.Sprintf("pod %s evicting, backend %s decommissioned and dropped.", pod.Name, backend.Host)
+	klog.Infof("disaggregatedComputeGroupsController prepareEviction namespace=%s name=%s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGEvictionDecommissionFinished), msg)
+	return true, ""
+}
+
+// findPodBackend return the backend registered by the pod, the host of backend is the fqdn of pod.
+func findPodBackend(backends []*mysql.Backend, podName string) *mysql.Backend {
+	for _, backend := range backends {
+		if strings.HasPrefix(backend.Host, podName+".") {
+			return backend
+		}
+	}
+	return nil
+}
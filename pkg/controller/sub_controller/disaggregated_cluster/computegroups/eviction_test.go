@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func Test_prepareEviction(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = dv1.AddToScheme(scheme)
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	k8sclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ddc).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(10)}}
+
+	readyStatus := corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}
+	fePod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-fe-0", Labels: map[string]string{
+		dv1.DorisDisaggregatedClusterName: "test", dv1.DorisDisaggregatedPodType: "fe"}}, Status: readyStatus}
+	if allowed, _ := dcgs.prepareEviction(context.Background(), fePod); !allowed {
+		t.Errorf("prepareEviction expected allow the eviction of fe pod")
+	}
+
+	//decommissionOnEviction not enabled.
+	cgPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1-0", Labels: map[string]string{
+		dv1.DorisDisaggregatedClusterName: "test", dv1.DorisDisaggregatedPodType: "compute"}}, Status: readyStatus}
+	if allowed, _ := dcgs.prepareEviction(context.Background(), cgPod); !allowed {
+		t.Errorf("prepareEviction expected allow the eviction when decommissionOnEviction not enabled")
+	}
+}
+
+func Test_EvictionHandler_timeout(t *testing.T) {
+	var deadline time.Time
+	k8sclient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			deadline, _ = ctx.Deadline()
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	h := &EvictionHandler{dcgs: &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(10)}}}
+
+	resp := h.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create, SubResource: "eviction", Namespace: "default", Name: "test-cg1-0"}})
+	if !resp.Allowed {
+		t.Errorf("Handle expected allow the eviction of pod not found")
+	}
+	if deadline.IsZero() || deadline.After(time.Now().Add(evictionHandleTimeout)) {
+		t.Errorf("Handle expected the eviction handled in %s, got deadline %v", evictionHandleTimeout, deadline)
+	}
+	if evictionHandleTimeout >= 10*time.Second {
+		t.Errorf("evictionHandleTimeout %s expected less than the timeoutSeconds of eviction webhook", evictionHandleTimeout)
+	}
+}
+
+func Test_findPodBackend(t *testing.T) {
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-1.test-cg1.default.svc.cluster.local"},
+		{Host: "test-cg1-10.test-cg1.default.svc.cluster.local"},
+	}
+	if b := findPodBackend(backends, "test-cg1-1"); b == nil || b != backends[0] {
+		t.Errorf("findPodBackend expected the backend of test-cg1-1, got %+v", b)
+	}
+	if b := findPodBackend(backends, "test-cg1-2"); b != nil {
+		t.Errorf("findPodBackend expected nil for pod not registered, got %+v", b)
+	}
+}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the default seconds of stabilization window that the highest replicas resolved from external metric in it is used.
+const defaultStabilizationWindowSeconds int32 = 300
+
+// resolveExternalMetricReplicas resolve the externalMetricReplicas of compute group to an absolute replicas, the result is set to cg.Replicas.
+// the metric read in every reconcile, the highest replicas resolved in stabilization window is used. reading metric failed keeps the last desired replicas.
+func (dcgs *DisaggregatedComputeGroupsController) resolveExternalMetricReplicas(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	emr := cg.ExternalMetricReplicas
+	if emr == nil {
+		if cgStatus != nil {
+			cgStatus.ExternalMetric = nil
+		}
+		return nil, nil
+	}
+	if cg.NodePoolReplicas != nil {
+		msg := fmt.Sprintf("compute group %s configured both nodePoolReplicas and externalMetricReplicas, please use one of them.", cg.UniqueId)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGExternalMetricFailed, Message: msg}, errors.New(msg)
+	}
+	if emr.MetricName == "" || emr.TargetAverageValue.Sign() <= 0 {
+		msg := fmt.Sprintf("compute group %s externalMetricReplicas should have metricName and positive targetAverageValue.", cg.UniqueId)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGExternalMetricFailed, Message: msg}, errors.New(msg)
+	}
+
+	//the status not initialized in the first reconcile, resolve without the stabilization.
+	ems := &dv1.ExternalMetricStatus{MetricName: emr.MetricName}
+	if cgStatus != nil {
+		if cgStatus.ExternalMetric == nil || cgStatus.ExternalMetric.MetricName != emr.MetricName {
+			cgStatus.ExternalMetric = ems
+		}
+		ems = cgStatus.ExternalMetric
+	}
+
+	value, err := dcgs.readExternalMetric(ctx, ddc.Namespace, emr)
+	if err != nil {
+		msg := fmt.Sprintf("compute group %s read external metric %s failed, keep the replicas, err=%s", cg.UniqueId, emr.MetricName, err.Error())
+		klog.Errorf("disaggregatedComputeGroupsController resolveExternalMetricReplicas namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGExternalMetricFailed), msg)
+		if ems.DesiredReplicas > 0 {
+			replicas := ems.DesiredReplicas
+			cg.Replicas = &replicas
+		}
+		return nil, nil
+	}
+
+	now := metav1.Now()
+	window := defaultStabilizationWindowSeconds
+	if emr.StabilizationWindowSeconds != nil {
+		window = *emr.StabilizationWindowSeconds
+	}
+	ems.Value = value.String()
+	ems.LastReadTime = &now
+	ems.Recommendations = appendRecommendation(ems.Recommendations, dv1.ReplicasRecommendation{Time: now, Replicas: computeExternalMetricReplicas(value, emr)}, time.Duration(window)*time.Second)
+	replicas := ems.Recommendations[0].Replicas
+	if ems.DesiredReplicas != replicas {
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s compute group %s resolved replicas %d from external metric %s value %s.", ddc.Namespace, ddc.Name, cg.UniqueId, replicas, emr.MetricName, ems.Value)
+	}
+	ems.DesiredReplicas = replicas
+	cg.Replicas = &replicas
+	return nil, nil
+}
+
+// readExternalMetric return the sum of values of the series selected.
+func (dcgs *DisaggregatedComputeGroupsController) readExternalMetric(ctx context.Context, namespace string, emr *dv1.ExternalMetricReplicas) (apiresource.Quantity, error) {
+	if dcgs.ExternalMetrics == nil {
+		return apiresource.Quantity{}, errors.New("the external metrics client not initialized")
+	}
+	values, err := dcgs.ExternalMetrics.GetExternalMetricValues(ctx, namespace, emr.MetricName, emr.MetricSelector)
+	if err != nil {
+		return apiresource.Quantity{}, err
+	}
+	if len(values) == 0 {
+		return apiresource.Quantity{}, errors.New("no series of metric selected")
+	}
+	sum := apiresource.Quantity{}
+	for _, v := range values {
+		sum.Add(v)
+	}
+	return sum, nil
+}
+
+// computeExternalMetricReplicas calculate replicas by the metric value divided by target average value, rounded up and clamped by min and max.
+func computeExternalMetricReplicas(value apiresource.Quantity, emr *dv1.ExternalMetricReplicas) int32 {
+	replicas := int32(math.Ceil(value.AsApproximateFloat64() / emr.TargetAverageValue.AsApproximateFloat64()))
+	min := int32(1)
+	if emr.MinReplicas != nil {
+		min = *emr.MinReplicas
+	}
+	if replicas < min {
+		replicas = min
+	}
+	if emr.MaxReplicas != nil && replicas > *emr.MaxReplicas {
+		replicas = *emr.MaxReplicas
+	}
+	return replicas
+}
+
+// appendRecommendation append the recommendation and drop the ones out of window or not higher than it, the first is the highest in window.
+// the dropped not higher ones never be the highest again, so the recommendations are bounded by the distinct replicas.
+func appendRecommendation(recs []dv1.ReplicasRecommendation, rec dv1.ReplicasRecommendation, window time.Duration) []dv1.ReplicasRecommendation {
+	var res []dv1.ReplicasRecommendation
+	for _, r := range recs {
+		if rec.Time.Sub(r.Time.Time) < window && r.Replicas > rec.Replicas {
+			res = append(res, r)
+		}
+	}
+	return append(res, rec)
+}
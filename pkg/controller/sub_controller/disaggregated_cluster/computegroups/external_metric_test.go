@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+type fakeExternalMetrics struct {
+	values []apiresource.Quantity
+	err    error
+}
+
+func (f *fakeExternalMetrics) GetExternalMetricValues(ctx context.Context, namespace, metricName string, selector map[string]string) ([]apiresource.Quantity, error) {
+	return f.values, f.err
+}
+
+func Test_resolveExternalMetricReplicas(t *testing.T) {
+	metrics := &fakeExternalMetrics{values: []apiresource.Quantity{apiresource.MustParse("250"), apiresource.MustParse("200")}}
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder, ExternalMetrics: metrics}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ddc.Status.ComputeGroupStatuses = []dv1.ComputeGroupStatus{{UniqueId: "cg1"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", ExternalMetricReplicas: &dv1.ExternalMetricReplicas{
+		MetricName:         "kafka_lag",
+		TargetAverageValue: apiresource.MustParse("100"),
+		MaxReplicas:        resource.GetInt32Pointer(10),
+	}}
+	ctx := context.Background()
+
+	if _, err := dcgs.resolveExternalMetricReplicas(ctx, ddc, cg); err != nil || *cg.Replicas != 5 {
+		t.Errorf("resolveExternalMetricReplicas expected 5 replicas, got %d err %v", *cg.Replicas, err)
+	}
+	ems := ddc.Status.ComputeGroupStatuses[0].ExternalMetric
+	if ems == nil || ems.Value != "450" || ems.DesiredReplicas != 5 {
+		t.Errorf("resolveExternalMetricReplicas expected metric value 450 in status, got %+v", ems)
+	}
+
+	//the lower replicas in stabilization window not applied.
+	metrics.values = []apiresource.Quantity{apiresource.MustParse("150")}
+	if _, _ = dcgs.resolveExternalMetricReplicas(ctx, ddc, cg); *cg.Replicas != 5 || len(ems.Recommendations) != 2 {
+		t.Errorf("resolveExternalMetricReplicas expected keep 5 replicas in window, got %d recommendations %+v", *cg.Replicas, ems.Recommendations)
+	}
+	//the higher replicas out of window, scale down.
+	ems.Recommendations[0].Time = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	if _, _ = dcgs.resolveExternalMetricReplicas(ctx, ddc, cg); *cg.Replicas != 2 {
+		t.Errorf("resolveExternalMetricReplicas expected 2 replicas after window, got %d", *cg.Replicas)
+	}
+
+	//reading metric failed keeps the last desired replicas.
+	metrics.err = errors.New("metric not found")
+	cg.Replicas = resource.GetInt32Pointer(7)
+	if _, err := dcgs.resolveExternalMetricReplicas(ctx, ddc, cg); err != nil || *cg.Replicas != 2 || len(recorder.Events) != 1 {
+		t.Errorf("resolveExternalMetricReplicas expected keep 2 replicas and 1 event when read failed, got %d err %v", *cg.Replicas, err)
+	}
+}
+
+func Test_computeExternalMetricReplicas(t *testing.T) {
+	emr := &dv1.ExternalMetricReplicas{TargetAverageValue: apiresource.MustParse("500m"), MinReplicas: resource.GetInt32Pointer(2), MaxReplicas: resource.GetInt32Pointer(6)}
+	for value, expected := range map[string]int32{"0": 2, "1.2": 3, "100": 6} {
+		if r := computeExternalMetricReplicas(apiresource.MustParse(value), emr); r != expected {
+			t.Errorf("computeExternalMetricReplicas value %s expected %d, got %d", value, expected, r)
+		}
+	}
+}
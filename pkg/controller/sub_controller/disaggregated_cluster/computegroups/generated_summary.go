@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// recordGeneratedSummary record the summary of generated statefulset and service in status when cluster annotated for debugging, the event emitted when the summary changed.
+func (dcgs *DisaggregatedComputeGroupsController) recordGeneratedSummary(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, st *appv1.StatefulSet, svc *corev1.Service) {
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	if cgStatus == nil {
+		return
+	}
+	if ddc.Annotations[dv1.DebugGenerated] != "true" {
+		cgStatus.GeneratedSummary = ""
+		return
+	}
+
+	summary := summarizeGenerated(st, svc)
+	if summary == cgStatus.GeneratedSummary {
+		return
+	}
+	cgStatus.GeneratedSummary = summary
+	msg := fmt.Sprintf("compute group %s generated %s", cg.UniqueId, summary)
+	klog.Infof("disaggregatedComputeGroupsController recordGeneratedSummary namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGGeneratedResources), msg)
+}
+
+// summarizeGenerated describe the fields of statefulset and service that usually cause the misbehavior, the env only lists the names for not leaking the values.
+func summarizeGenerated(st *appv1.StatefulSet, svc *corev1.Service) string {
+	var b strings.Builder
+	replicas := "nil"
+	if st.Spec.Replicas != nil {
+		replicas = strconv.Itoa(int(*st.Spec.Replicas))
+	}
+	fmt.Fprintf(&b, "statefulset %s: replicas=%s, volumes=%d, volumeClaimTemplates=%d", st.Name, replicas, len(st.Spec.Template.Spec.Volumes), len(st.Spec.VolumeClaimTemplates))
+	for _, c := range st.Spec.Template.Spec.Containers {
+		if c.Name != resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME {
+			continue
+		}
+		var env []string
+		for _, e := range c.Env {
+			env = append(env, e.Name)
+		}
+		sort.Strings(env)
+		fmt.Fprintf(&b, ", image=%s, requests=%s, limits=%s, env=[%s]", c.Image, resourceListString(c.Resources.Requests), resourceListString(c.Resources.Limits), strings.Join(env, ","))
+	}
+
+	var ports []string
+	for _, p := range svc.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%s:%d", p.Name, p.Port))
+	}
+	fmt.Fprintf(&b, "; service %s: type=%s, ports=[%s]", svc.Name, svc.Spec.Type, strings.Join(ports, ","))
+	return b.String()
+}
+
+func resourceListString(rl corev1.ResourceList) string {
+	var rs []string
+	for name, q := range rl {
+		rs = append(rs, fmt.Sprintf("%s=%s", name, q.String()))
+	}
+	sort.Strings(rs)
+	return "{" + strings.Join(rs, ",") + "}"
+}
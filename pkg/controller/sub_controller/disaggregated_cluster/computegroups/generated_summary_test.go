@@ -0,0 +1,72 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_recordGeneratedSummary(t *testing.T) {
+	replicas := int32(3)
+	st := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cg1"},
+		Spec: appv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:      resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME,
+				Image:     "apache/doris:be-3.0.3",
+				Env:       []corev1.EnvVar{{Name: "USER", Value: "root"}, {Name: "CONFIGMAP_MOUNT_PATH", Value: "/etc/doris"}},
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("4"), corev1.ResourceMemory: apiresource.MustParse("16Gi")}},
+			}}}},
+		},
+	}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1"}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Name: "heartbeat-port", Port: 9050}}}}
+	expect := "statefulset test-cg1: replicas=3, volumes=0, volumeClaimTemplates=0, image=apache/doris:be-3.0.3, requests={cpu=4,memory=16Gi}, limits={}, env=[CONFIGMAP_MOUNT_PATH,USER]; service test-cg1: type=ClusterIP, ports=[heartbeat-port:9050]"
+	if summary := summarizeGenerated(st, svc); summary != expect {
+		t.Errorf("summarizeGenerated not expected, got %s", summary)
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Annotations: map[string]string{dv1.DebugGenerated: "true"}},
+		Status:     dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1"}}},
+	}
+	for i := 0; i < 2; i++ {
+		dcgs.recordGeneratedSummary(ddc, cg, st, svc)
+	}
+	if ddc.Status.ComputeGroupStatuses[0].GeneratedSummary != expect || len(recorder.Events) != 1 {
+		t.Errorf("recordGeneratedSummary expect summary recorded with 1 event, got %q events %d", ddc.Status.ComputeGroupStatuses[0].GeneratedSummary, len(recorder.Events))
+	}
+
+	delete(ddc.Annotations, dv1.DebugGenerated)
+	dcgs.recordGeneratedSummary(ddc, cg, st, svc)
+	if ddc.Status.ComputeGroupStatuses[0].GeneratedSummary != "" {
+		t.Errorf("recordGeneratedSummary expect summary cleared without annotation.")
+	}
+}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// the default key of sql statements in the configmap of initSQL.
+const defaultInitSQLKey = "init.sql"
+
+// runInitSQL run the statements of initSQL configmap in order when compute group ready, the executed ones recorded in status and the failed one retried in next reconcile.
+// the statements run again from the first when the configmap edited.
+func (dcgs *DisaggregatedComputeGroupsController) runInitSQL(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	if cgStatus == nil {
+		return nil, nil
+	}
+	if cg.InitSQL == nil {
+		cgStatus.InitSQL = nil
+		return nil, nil
+	}
+
+	cm, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, ddc.Namespace, cg.InitSQL.ConfigMapName)
+	if err != nil {
+		msg := fmt.Sprintf("compute group %s get initSQL configmap %s failed, err=%s", cg.UniqueId, cg.InitSQL.ConfigMapName, err.Error())
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGInitSQLFailed, Message: msg}, errors.New(msg)
+	}
+	key := initSQLKey(cg.InitSQL)
+	statements := splitSQLStatements(cm.Data[key])
+	is := cgStatus.InitSQL
+	if is == nil || is.ResourceVersion != cm.ResourceVersion {
+		is = &dv1.InitSQLStatus{ResourceVersion: cm.ResourceVersion, TotalStatements: int32(len(statements))}
+		cgStatus.InitSQL = is
+	}
+	if is.ExecutedStatements >= is.TotalStatements {
+		return nil, nil
+	}
+	//run after all pods of compute group ready, the statements usually reference the compute group.
+	if cgStatus.Replicas == 0 || cgStatus.AvailableReplicas < cgStatus.Replicas || ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		return nil, nil
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	defer sqlClient.Close()
+	for int(is.ExecutedStatements) < len(statements) {
+		if _, err := sqlClient.Exec(statements[is.ExecutedStatements]); err != nil {
+			is.LastError = fmt.Sprintf("statement %d failed: %s", is.ExecutedStatements+1, err.Error())
+			msg := fmt.Sprintf("compute group %s initSQL configmap %s %s, will retry from it.", cg.UniqueId, cg.InitSQL.ConfigMapName, is.LastError)
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGInitSQLFailed, Message: msg}, errors.New(msg)
+		}
+		is.ExecutedStatements++
+	}
+	is.LastError = ""
+
+	msg := fmt.Sprintf("compute group %s executed %d statements of initSQL configmap %s.", cg.UniqueId, is.TotalStatements, cg.InitSQL.ConfigMapName)
+	klog.Infof("disaggregatedComputeGroupsController runInitSQL namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGInitSQLExecuted), msg)
+	return nil, nil
+}
+
+// initSQLConfigMapMissing return true when the compute group references the initSQL configmap not exist, the compute group not marked Ready.
+func (dcgs *DisaggregatedComputeGroupsController) initSQLConfigMapMissing(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, uniqueId string) bool {
+	cg := findCG(ddc, uniqueId)
+	if cg == nil || cg.InitSQL == nil {
+		return false
+	}
+	_, err := k8s.GetConfigMap(ctx, dcgs.K8sclient, ddc.Namespace, cg.InitSQL.ConfigMapName)
+	return apierrors.IsNotFound(err)
+}
+
+func initSQLKey(is *dv1.InitSQL) string {
+	if is.Key == "" {
+		return defaultInitSQLKey
+	}
+	return is.Key
+}
+
+// splitSQLStatements split the sql text into statements by `;` that not quoted, the empty and comment lines are dropped.
+func splitSQLStatements(text string) []string {
+	var statements []string
+	var sb strings.Builder
+	var quote rune
+	appendStatement := func() {
+		var lines []string
+		for _, line := range strings.Split(sb.String(), "\n") {
+			if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				lines = append(lines, line)
+			}
+		}
+		if stmt := strings.TrimSpace(strings.Join(lines, "\n")); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		sb.Reset()
+	}
+
+	for _, c := range text {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			appendStatement()
+			continue
+		}
+		sb.WriteRune(c)
+	}
+	appendStatement()
+	return statements
+}
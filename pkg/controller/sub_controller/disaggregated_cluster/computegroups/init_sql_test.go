@@ -0,0 +1,87 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_splitSQLStatements(t *testing.T) {
+	text := `-- grant the usage of compute group
+GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO ROLE 'analyst';
+
+SET PROPERTY FOR 'bi' 'default_compute_group' = 'cg1';
+SELECT ';' ;`
+	statements := splitSQLStatements(text)
+	if len(statements) != 3 || statements[0] != "GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO ROLE 'analyst'" || statements[2] != "SELECT ';'" {
+		t.Errorf("splitSQLStatements not expected, got %q", statements)
+	}
+}
+
+func Test_runInitSQL(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cg1-init"}, Data: map[string]string{"init.sql": "SELECT 1; SELECT 2;"}}
+	k8sclient := fake.NewClientBuilder().WithObjects(cm).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(10)}}
+	cg := dv1.ComputeGroup{UniqueId: "cg1", InitSQL: &dv1.InitSQL{ConfigMapName: "cg1-init"}}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{cg}},
+		Status:     dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1", Replicas: 2, AvailableReplicas: 1}}},
+	}
+
+	//the statements wait the compute group ready.
+	if _, err := dcgs.runInitSQL(context.Background(), ddc, &cg); err != nil {
+		t.Errorf("runInitSQL failed, err=%s", err.Error())
+	}
+	is := ddc.Status.ComputeGroupStatuses[0].InitSQL
+	if is == nil || is.ResourceVersion != cm.ResourceVersion || is.TotalStatements != 2 || is.ExecutedStatements != 0 {
+		t.Errorf("runInitSQL expect 2 statements not executed, got %+v", is)
+	}
+
+	//the edited configmap runs the statements again.
+	is.ExecutedStatements = 2
+	cm.Data["init.sql"] = "SELECT 3;"
+	if err := k8sclient.Update(context.Background(), cm); err != nil {
+		t.Fatalf("update configmap failed, err=%s", err.Error())
+	}
+	if _, err := dcgs.runInitSQL(context.Background(), ddc, &cg); err != nil {
+		t.Errorf("runInitSQL failed, err=%s", err.Error())
+	}
+	if is = ddc.Status.ComputeGroupStatuses[0].InitSQL; is.TotalStatements != 1 || is.ExecutedStatements != 0 {
+		t.Errorf("runInitSQL expect the statements reset after configmap edited, got %+v", is)
+	}
+
+	if dcgs.initSQLConfigMapMissing(context.Background(), ddc, "cg1") {
+		t.Errorf("initSQLConfigMapMissing expect false when configmap exists.")
+	}
+	ddc.Spec.ComputeGroups[0].InitSQL.ConfigMapName = "not-exist"
+	if !dcgs.initSQLConfigMapMissing(context.Background(), ddc, "cg1") {
+		t.Errorf("initSQLConfigMapMissing expect true when configmap not exist.")
+	}
+	if event, err := dcgs.runInitSQL(context.Background(), ddc, &ddc.Spec.ComputeGroups[0]); err == nil || event.Reason != sc.CGInitSQLFailed {
+		t.Errorf("runInitSQL expect CGInitSQLFailed when configmap not exist, got %v", event)
+	}
+}
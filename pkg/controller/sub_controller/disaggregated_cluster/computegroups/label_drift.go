@@ -0,0 +1,91 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileCGLabelDrift re-assert the selector of service and the labels of pods that managed by operator when they are modified externally, emit warning event when drift detected.
+// the service not selecting the pods makes the compute group receive no queries while the pods are ready, the service hash annotation not changed by the external modification, so compare the selector directly.
+func (dcgs *DisaggregatedComputeGroupsController) reconcileCGLabelDrift(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, svc *corev1.Service) error {
+	var drifted []string
+	var esvc corev1.Service
+	if err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}, &esvc); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	} else if err == nil && !selectorEqual(esvc.Spec.Selector, svc.Spec.Selector) {
+		patch := client.MergeFrom(esvc.DeepCopy())
+		esvc.Spec.Selector = svc.Spec.Selector
+		if err := dcgs.K8sclient.Patch(ctx, &esvc, patch); err != nil {
+			return err
+		}
+		drifted = append(drifted, "service "+esvc.Name+" selector")
+	}
+
+	labels := dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId)
+	var pods corev1.PodList
+	if err := dcgs.K8sclient.List(ctx, &pods, client.InNamespace(ddc.Namespace)); err != nil {
+		return err
+	}
+	stsName := ddc.GetCGStatefulsetName(cg)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !ownedByStatefulset(pod, stsName) || !labelsDrifted(pod.Labels, labels) {
+			continue
+		}
+		if err := dcgs.addMissingLabels(ctx, pod, labels); err != nil {
+			return err
+		}
+		drifted = append(drifted, "pod "+pod.Name+" labels")
+	}
+
+	if len(drifted) == 0 {
+		return nil
+	}
+	sort.Strings(drifted)
+	msg := fmt.Sprintf("compute group %s %s modified externally, re-asserted the labels managed by operator.", cg.UniqueId, strings.Join(drifted, ","))
+	klog.Errorf("disaggregatedComputeGroupsController reconcileCGLabelDrift namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGLabelDrifted), msg)
+	return nil
+}
+
+// labelsDrifted return true when the labels missing or have different value.
+func labelsDrifted(objLabels, labels map[string]string) bool {
+	for k, v := range labels {
+		if objLabels[k] != v {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorEqual return true when the selectors have the same labels.
+func selectorEqual(a, b map[string]string) bool {
+	return len(a) == len(b) && !labelsDrifted(a, b)
+}
@@ -0,0 +1,68 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reconcileCGLabelDrift(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	labels := dcgs.newCGPodsSelector("test", "cg1")
+	driftedLabels := map[string]string{dv1.DorisDisaggregatedClusterName: "test", "edited": "true"}
+	owner := []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "test-cg1", UID: "uid"}}
+	esvc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}, Spec: corev1.ServiceSpec{Selector: driftedLabels}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1-0", Labels: driftedLabels, OwnerReferences: owner}}
+	syncedPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1-1", Labels: labels, OwnerReferences: owner}}
+
+	k8sclient := fake.NewClientBuilder().WithObjects(esvc, pod, syncedPod).Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs = &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}, Spec: corev1.ServiceSpec{Selector: labels}}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := dcgs.reconcileCGLabelDrift(ctx, ddc, cg, svc); err != nil {
+			t.Fatalf("reconcileCGLabelDrift failed, err=%s", err.Error())
+		}
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("reconcileCGLabelDrift expected 1 event only when drift detected, got %d", len(recorder.Events))
+	}
+	var rsvc corev1.Service
+	_ = k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1"}, &rsvc)
+	if !selectorEqual(rsvc.Spec.Selector, labels) {
+		t.Errorf("reconcileCGLabelDrift expected service selector re-asserted, got %v", rsvc.Spec.Selector)
+	}
+	var rpod corev1.Pod
+	_ = k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1-0"}, &rpod)
+	if labelsDrifted(rpod.Labels, labels) || rpod.Labels["edited"] != "true" {
+		t.Errorf("reconcileCGLabelDrift expected pod labels re-asserted and others kept, got %v", rpod.Labels)
+	}
+}
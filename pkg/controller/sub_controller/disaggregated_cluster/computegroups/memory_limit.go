@@ -0,0 +1,83 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// the workload group that every compute group have in fe, the queries not assigned workload group use it.
+const memoryLimitWorkloadGroup = "normal"
+
+// reconcileCGMemoryLimit set the memoryLimit of compute groups to the workload group in fe after the backends registered, the applied limit recorded in status.
+// the sql client is created only when the limit changed.
+func (dcgs *DisaggregatedComputeGroupsController) reconcileCGMemoryLimit(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) {
+	if ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		return
+	}
+
+	var sqlClient *mysql.DB
+	defer func() {
+		if sqlClient != nil {
+			sqlClient.Close()
+		}
+	}()
+	for i := range ddc.Spec.ComputeGroups {
+		cg := &ddc.Spec.ComputeGroups[i]
+		cgs := findCGStatus(ddc, cg.UniqueId)
+		if !memoryLimitChanged(cg, cgs) {
+			continue
+		}
+		//the limit removed from spec keeps in fe, as the default of workload group is not known.
+		if cg.MemoryLimit == "" {
+			klog.Infof("disaggregatedComputeGroupsController reconcileCGMemoryLimit namespace %s name %s compute group %s memoryLimit removed, keep %s in fe.", ddc.Namespace, ddc.Name, cg.UniqueId, cgs.AppliedMemoryLimit)
+			cgs.AppliedMemoryLimit = ""
+			continue
+		}
+
+		if sqlClient == nil {
+			var err error
+			if sqlClient, err = dcgs.getMasterSqlClient(ctx, ddc); err != nil {
+				klog.Errorf("disaggregatedComputeGroupsController reconcileCGMemoryLimit namespace %s name %s get sql client failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+				return
+			}
+		}
+		if err := sqlClient.SetWorkloadGroupMemoryLimit(memoryLimitWorkloadGroup, ddc.GetCGName(cg), cg.MemoryLimit); err != nil {
+			msg := fmt.Sprintf("compute group %s set memory limit %s failed, err=%s", cg.UniqueId, cg.MemoryLimit, err.Error())
+			klog.Errorf("disaggregatedComputeGroupsController reconcileCGMemoryLimit namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGMemoryLimitApplyFailed), msg)
+			continue
+		}
+		klog.Infof("disaggregatedComputeGroupsController reconcileCGMemoryLimit namespace %s name %s compute group %s memory limit set to %s.", ddc.Namespace, ddc.Name, cg.UniqueId, cg.MemoryLimit)
+		cgs.AppliedMemoryLimit = cg.MemoryLimit
+	}
+}
+
+// memoryLimitChanged return true when the memoryLimit different from the applied, the limit is applied only after the backends registered in fe.
+func memoryLimitChanged(cg *dv1.ComputeGroup, cgs *dv1.ComputeGroupStatus) bool {
+	if cgs == nil || cg.MemoryLimit == cgs.AppliedMemoryLimit {
+		return false
+	}
+	return cg.MemoryLimit == "" || cgs.AliveBackends > 0
+}
@@ -0,0 +1,44 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+)
+
+func Test_memoryLimitChanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		cg      *dv1.ComputeGroup
+		cgs     *dv1.ComputeGroupStatus
+		changed bool
+	}{
+		{name: "status not initialized", cg: &dv1.ComputeGroup{MemoryLimit: "50%"}, cgs: nil, changed: false},
+		{name: "backends not registered", cg: &dv1.ComputeGroup{MemoryLimit: "50%"}, cgs: &dv1.ComputeGroupStatus{}, changed: false},
+		{name: "applied", cg: &dv1.ComputeGroup{MemoryLimit: "50%"}, cgs: &dv1.ComputeGroupStatus{AliveBackends: 1, AppliedMemoryLimit: "50%"}, changed: false},
+		{name: "changed", cg: &dv1.ComputeGroup{MemoryLimit: "60%"}, cgs: &dv1.ComputeGroupStatus{AliveBackends: 1, AppliedMemoryLimit: "50%"}, changed: true},
+		{name: "removed", cg: &dv1.ComputeGroup{}, cgs: &dv1.ComputeGroupStatus{AppliedMemoryLimit: "50%"}, changed: true},
+	}
+	for _, test := range tests {
+		if changed := memoryLimitChanged(test.cg, test.cgs); changed != test.changed {
+			t.Errorf("memoryLimitChanged %s expected %t, got %t", test.name, test.changed, changed)
+		}
+	}
+}
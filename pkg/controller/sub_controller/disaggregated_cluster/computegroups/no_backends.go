@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the time waiting the backends of ready pods registered and alive in fe, the be registers after started and the heartbeat takes seconds.
+const noBackendsGracePeriod = 5 * time.Minute
+
+// checkNoBackendsRegistered set the NoBackendsRegistered condition of compute group, emit warning event when the compute group have ready pods but no alive backends beyond the grace period.
+func (dcgs *DisaggregatedComputeGroupsController) checkNoBackendsRegistered(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, registered int32) {
+	prev := meta.FindStatusCondition(cgs.Conditions, dv1.NoBackendsRegistered)
+	condition := newNoBackendsRegisteredCondition(cgs, prev, registered, ddc.Generation, time.Now())
+	if condition.Status == metav1.ConditionTrue && (prev == nil || prev.Status != metav1.ConditionTrue) {
+		msg := fmt.Sprintf("compute group %s %s", cgs.UniqueId, condition.Message)
+		klog.Errorf("disaggregatedComputeGroupsController checkNoBackendsRegistered namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGNoBackendsRegistered), msg)
+	}
+	meta.SetStatusCondition(&cgs.Conditions, condition)
+}
+
+// newNoBackendsRegisteredCondition return Unknown when the ready pods have no alive backends in the grace period, the grace period starts from the last transition to Unknown.
+// the message of True tells the likely cause by whether the backends registered or not.
+func newNoBackendsRegisteredCondition(cgs *dv1.ComputeGroupStatus, prev *metav1.Condition, registered int32, generation int64, now time.Time) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               dv1.NoBackendsRegistered,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             dv1.BackendsRegistered,
+		Message:            fmt.Sprintf("%d pods ready, %d backends alive in fe.", cgs.AvailableReplicas, cgs.AliveBackends),
+	}
+	if cgs.Replicas == 0 || cgs.AvailableReplicas == 0 || cgs.AliveBackends > 0 {
+		return condition
+	}
+
+	if prev == nil || prev.Status == metav1.ConditionFalse || (prev.Status == metav1.ConditionUnknown && now.Sub(prev.LastTransitionTime.Time) < noBackendsGracePeriod) {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = dv1.WaitingBackendsRegistered
+		condition.Message = fmt.Sprintf("%d pods ready, waiting the backends registered and alive in fe.", cgs.AvailableReplicas)
+		return condition
+	}
+
+	condition.Status = metav1.ConditionTrue
+	if registered == 0 {
+		condition.Reason = dv1.BackendsNotRegistered
+		condition.Message = fmt.Sprintf("%d pods ready but no backends registered in fe after %s, the compute group can not serve queries. the likely cause is fe rejected the adding of backends, "+
+			"please check the authentication of fe(the user and password of operator), the cluster id and token of be, and the logs of be and fe.", cgs.AvailableReplicas, noBackendsGracePeriod)
+	} else {
+		condition.Reason = dv1.BackendsRegisteredNotAlive
+		condition.Message = fmt.Sprintf("%d pods ready, %d backends registered but none alive in fe after %s, the compute group can not serve queries. the likely cause is fe can not reach be by heartbeat, "+
+			"please check the network between fe and be(dns, network policy, heartbeat_service_port).", cgs.AvailableReplicas, registered, noBackendsGracePeriod)
+	}
+	return condition
+}
+
+// countRegisteredBackendsByStatefulset return the number of backends registered in fe grouped by the statefulset name that the backend pod belongs to.
+func countRegisteredBackendsByStatefulset(backends []*mysql.Backend) map[string]int32 {
+	m := map[string]int32{}
+	for _, backend := range backends {
+		if name := backendStatefulsetName(backend.Host); name != "" {
+			m[name]++
+		}
+	}
+	return m
+}
@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_checkNoBackendsRegistered(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Replicas: 3, AvailableReplicas: 3}
+
+	dcgs.checkNoBackendsRegistered(ddc, cgs, 0)
+	c := meta.FindStatusCondition(cgs.Conditions, dv1.NoBackendsRegistered)
+	if c == nil || c.Status != metav1.ConditionUnknown || len(recorder.Events) != 0 {
+		t.Fatalf("checkNoBackendsRegistered expected Unknown in grace period without event, got %+v", c)
+	}
+
+	c.LastTransitionTime = metav1.NewTime(time.Now().Add(-noBackendsGracePeriod))
+	dcgs.checkNoBackendsRegistered(ddc, cgs, 0)
+	dcgs.checkNoBackendsRegistered(ddc, cgs, 0)
+	c = meta.FindStatusCondition(cgs.Conditions, dv1.NoBackendsRegistered)
+	if c.Status != metav1.ConditionTrue || c.Reason != dv1.BackendsNotRegistered || len(recorder.Events) != 1 {
+		t.Errorf("checkNoBackendsRegistered expected True with reason %s and 1 event after grace period, got %+v events %d", dv1.BackendsNotRegistered, c, len(recorder.Events))
+	}
+
+	cgs.AliveBackends = 3
+	dcgs.checkNoBackendsRegistered(ddc, cgs, 3)
+	if c = meta.FindStatusCondition(cgs.Conditions, dv1.NoBackendsRegistered); c.Status != metav1.ConditionFalse {
+		t.Errorf("checkNoBackendsRegistered expected False when backends alive, got %+v", c)
+	}
+}
+
+func Test_newNoBackendsRegisteredCondition_registeredNotAlive(t *testing.T) {
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Replicas: 2, AvailableReplicas: 2}
+	prev := &metav1.Condition{Status: metav1.ConditionUnknown, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))}
+	if c := newNoBackendsRegisteredCondition(cgs, prev, 2, 1, time.Now()); c.Status != metav1.ConditionTrue || c.Reason != dv1.BackendsRegisteredNotAlive {
+		t.Errorf("newNoBackendsRegisteredCondition expected True with reason %s, got %+v", dv1.BackendsRegisteredNotAlive, c)
+	}
+}
@@ -0,0 +1,123 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// resolveNodePoolReplicas resolve the nodePoolReplicas of compute group to an absolute replicas, the result is set to cg.Replicas.
+// the nodes in pool is listed in every reconcile, so replicas follow the changes of node pool.
+func (dcgs *DisaggregatedComputeGroupsController) resolveNodePoolReplicas(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	npr := cg.NodePoolReplicas
+	if npr == nil {
+		return nil, nil
+	}
+
+	if npr.Percentage <= 0 || npr.Percentage > 100 {
+		msg := fmt.Sprintf("compute group %s nodePoolReplicas percentage %d is not in range 1 to 100.", cg.UniqueId, npr.Percentage)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGNodePoolResolveFailed, Message: msg}, errors.New(msg)
+	}
+
+	selector := npr.NodeSelector
+	if len(selector) == 0 {
+		selector = cg.NodeSelector
+	}
+	nodes, err := k8s.GetNodes(ctx, dcgs.K8sclient, selector)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController resolveNodePoolReplicas namespace %s name %s list nodes failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGNodePoolResolveFailed, Message: "list nodes failed, " + err.Error()}, err
+	}
+
+	var schedulable int32
+	for i := range nodes.Items {
+		if k8s.NodeIsSchedulable(&nodes.Items[i]) {
+			schedulable++
+		}
+	}
+
+	replicas := computeNodePoolReplicas(schedulable, npr)
+	if cg.Replicas == nil || *cg.Replicas != replicas {
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s compute group %s resolved replicas %d from %d schedulable nodes.", ddc.Namespace, ddc.Name, cg.UniqueId, replicas, schedulable)
+	}
+	cg.Replicas = &replicas
+	return nil, nil
+}
+
+// defaultReplicas return the initial replicas when replicas not set and not resolved from node pool.
+// when nodePoolReplicas or externalMetricReplicas configured, use the minReplicas of it, not the fixed default, for not scaling down after the first resolving.
+func defaultReplicas(cg *dv1.ComputeGroup) int32 {
+	if cg.NodePoolReplicas != nil && cg.NodePoolReplicas.MinReplicas != nil {
+		return *cg.NodePoolReplicas.MinReplicas
+	}
+	if cg.ExternalMetricReplicas != nil && cg.ExternalMetricReplicas.MinReplicas != nil {
+		return *cg.ExternalMetricReplicas.MinReplicas
+	}
+	return 1
+}
+
+// computeNodePoolReplicas calculate replicas by percentage of schedulable nodes, rounded up and clamped by min and max.
+func computeNodePoolReplicas(schedulable int32, npr *dv1.NodePoolReplicas) int32 {
+	replicas := (schedulable*npr.Percentage + 99) / 100
+	min := int32(1)
+	if npr.MinReplicas != nil {
+		min = *npr.MinReplicas
+	}
+	if replicas < min {
+		replicas = min
+	}
+	if npr.MaxReplicas != nil && replicas > *npr.MaxReplicas {
+		replicas = *npr.MaxReplicas
+	}
+
+	return replicas
+}
+
+// checkRackTopologyKey check the rack topology key exist on the nodes that compute group can be scheduled to, emit warning event if rack labels are missing.
+// the pods can't be spread across racks when nodes have not the rack label.
+func (dcgs *DisaggregatedComputeGroupsController) checkRackTopologyKey(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) {
+	if cg.RackAwareness == nil || cg.RackAwareness.TopologyKey == "" {
+		return
+	}
+
+	nodes, err := k8s.GetNodes(ctx, dcgs.K8sclient, cg.NodeSelector)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController checkRackTopologyKey namespace %s name %s list nodes failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+
+	var missing []string
+	for _, node := range nodes.Items {
+		if _, ok := node.Labels[cg.RackAwareness.TopologyKey]; !ok {
+			missing = append(missing, node.Name)
+		}
+	}
+	if len(missing) != 0 {
+		msg := fmt.Sprintf("compute group %s rack topology key %s is missing on nodes %s.", cg.UniqueId, cg.RackAwareness.TopologyKey, strings.Join(missing, ","))
+		klog.Errorf("disaggregatedComputeGroupsController checkRackTopologyKey namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGRackLabelMissing), msg)
+	}
+}
@@ -0,0 +1,96 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_computeNodePoolReplicas(t *testing.T) {
+	tests := []struct {
+		schedulable int32
+		npr         dv1.NodePoolReplicas
+		want        int32
+	}{
+		{schedulable: 10, npr: dv1.NodePoolReplicas{Percentage: 50}, want: 5},
+		{schedulable: 5, npr: dv1.NodePoolReplicas{Percentage: 50}, want: 3},
+		{schedulable: 0, npr: dv1.NodePoolReplicas{Percentage: 50}, want: 1},
+		{schedulable: 2, npr: dv1.NodePoolReplicas{Percentage: 50, MinReplicas: resource.GetInt32Pointer(3)}, want: 3},
+		{schedulable: 20, npr: dv1.NodePoolReplicas{Percentage: 100, MaxReplicas: resource.GetInt32Pointer(8)}, want: 8},
+	}
+
+	for _, test := range tests {
+		if got := computeNodePoolReplicas(test.schedulable, &test.npr); got != test.want {
+			t.Errorf("computeNodePoolReplicas schedulable %d npr %+v, got %d, want %d", test.schedulable, test.npr, got, test.want)
+		}
+	}
+}
+
+func Test_defaultReplicas(t *testing.T) {
+	if got := defaultReplicas(&dv1.ComputeGroup{}); got != 1 {
+		t.Errorf("defaultReplicas expected 1 without nodePoolReplicas, got %d", got)
+	}
+	cg := &dv1.ComputeGroup{NodePoolReplicas: &dv1.NodePoolReplicas{Percentage: 50, MinReplicas: resource.GetInt32Pointer(3)}}
+	if got := defaultReplicas(cg); got != 3 {
+		t.Errorf("defaultReplicas expected the minReplicas 3 of nodePoolReplicas, got %d", got)
+	}
+}
+
+func Test_resolveNodePoolReplicas(t *testing.T) {
+	var nodes []client.Object
+	for i := 0; i < 4; i++ {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node" + strconv.Itoa(i), Labels: map[string]string{"pool": "doris"}},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		}
+		// node3 is cordoned.
+		if i == 3 {
+			node.Spec.Unschedulable = true
+		}
+		nodes = append(nodes, node)
+	}
+	nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"pool": "other"}}})
+
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{
+		K8sclient: fake.NewClientBuilder().WithObjects(nodes...).Build(),
+	}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{NodeSelector: map[string]string{"pool": "doris"}},
+		NodePoolReplicas: &dv1.NodePoolReplicas{Percentage: 100}}
+	if _, err := dcgs.resolveNodePoolReplicas(context.Background(), ddc, cg); err != nil {
+		t.Fatalf("resolveNodePoolReplicas failed, err=%s", err.Error())
+	}
+	if cg.Replicas == nil || *cg.Replicas != 3 {
+		t.Errorf("resolveNodePoolReplicas expected replicas 3, got %v", cg.Replicas)
+	}
+
+	cg.NodePoolReplicas.Percentage = 0
+	if event, err := dcgs.resolveNodePoolReplicas(context.Background(), ddc, cg); err == nil || event.Reason != sc.CGNodePoolResolveFailed {
+		t.Errorf("resolveNodePoolReplicas expected failed when percentage is 0, event=%v", event)
+	}
+}
@@ -19,18 +19,25 @@ package computegroups
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
 	"github.com/apache/doris-operator/pkg/common/utils/k8s"
 	"github.com/apache/doris-operator/pkg/common/utils/mysql"
 	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
 	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 )
 
-func (dcgs *DisaggregatedComputeGroupsController) preApplyStatefulSet(ctx context.Context, st, est *appv1.StatefulSet, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) error {
+// preApplyStatefulSet return event when the pre-processing need to display a specific reason, if event is nil and err not nil, the caller use the default reason.
+func (dcgs *DisaggregatedComputeGroupsController) preApplyStatefulSet(ctx context.Context, st, est *appv1.StatefulSet, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
 	var cgStatus *dv1.ComputeGroupStatus
 	uniqueId := cg.UniqueId
 	for i := range cluster.Status.ComputeGroupStatuses {
@@ -43,120 +50,317 @@ func (dcgs *DisaggregatedComputeGroupsController) preApplyStatefulSet(ctx contex
 
 	switch optType {
 	case "scaleDown":
-		err := dcgs.scaleOut(ctx, cgStatus, cluster, cg)
-		if err != nil {
-			return err
+		//the scale down canceled by restoring replicas, not need to block.
+		if cgStatus.Phase == dv1.ScaleDownBlocked && *(st.Spec.Replicas) >= *(est.Spec.Replicas) {
+			recordScaleDownSqlResult(cgStatus, nil)
+			cgStatus.Phase = dv1.Reconciling
+			return nil, nil
+		}
+		//the blocked scale down not apply statefulset, and not retry sql until reset.
+		if dcgs.scaleDownBlocked(ctx, cluster, cgStatus) {
+			klog.Infof("disaggregatedComputeGroupsController preApplyStatefulSet namespace=%s name=%s compute group %s scale down blocked.", cluster.Namespace, cluster.Name, uniqueId)
+			return nil, nil
+		}
+		//the removed pods drain connections out of the service endpoints before dropping backends, keep the replicas in draining.
+		if !dcgs.drainScaledInPods(ctx, cluster, cg, cgStatus, st, est) {
+			klog.Infof("disaggregatedComputeGroupsController preApplyStatefulSet namespace=%s name=%s compute group %s wait pods %s draining connections.", cluster.Namespace, cluster.Name, uniqueId, strings.Join(cgStatus.DrainingPods, ","))
+			st.Spec.Replicas = est.Spec.Replicas
+			return nil, nil
+		}
+		//shrink the statefulset first, the backends of removed pods are dropped in postApplyStatefulSet.
+		if cluster.Spec.ScaleDownOrder == dv1.ShrinkFirst {
+			return nil, nil
+		}
+		event, err := dcgs.scaleOut(ctx, cgStatus, cluster, cg, *st.Spec.Replicas)
+		if blockedEvent := recordScaleDownSqlResult(cgStatus, err); blockedEvent != nil {
+			return blockedEvent, err
 		}
+		return event, err
 	default:
-		// default do nothing, not need pre ApplyStatefulSet
+		//the scale down finished or canceled, the pods kept are labeled serving again.
+		if cgStatus.DrainingStartTime != nil {
+			clearDraining(cgStatus)
+		}
+		cgStatus.DecommissionStartTime = nil
+		dcgs.restoreCacheWarmupBackends(ctx, cluster, cg, cgStatus)
 	}
 
-	return nil
+	return nil, nil
+
+}
+
+// postApplyStatefulSet drop the backends of removed pods after the statefulset shrunk, only used when the scaleDownOrder is ShrinkFirst.
+func (dcgs *DisaggregatedComputeGroupsController) postApplyStatefulSet(ctx context.Context, st, est *appv1.StatefulSet, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	if cluster.Spec.ScaleDownOrder != dv1.ShrinkFirst {
+		return nil, nil
+	}
+	var cgStatus *dv1.ComputeGroupStatus
+	for i := range cluster.Status.ComputeGroupStatuses {
+		if cluster.Status.ComputeGroupStatuses[i].UniqueId == cg.UniqueId {
+			cgStatus = &cluster.Status.ComputeGroupStatuses[i]
+			break
+		}
+	}
+	if cgStatus == nil || getOperationType(st, est, cgStatus.Phase) != "scaleDown" {
+		return nil, nil
+	}
 
+	event, err := dcgs.dropShrunkBackends(ctx, cgStatus, cluster, cg, *st.Spec.Replicas)
+	if blockedEvent := recordScaleDownSqlResult(cgStatus, err); blockedEvent != nil {
+		return blockedEvent, err
+	}
+	return event, err
 }
 
-func (dcgs *DisaggregatedComputeGroupsController) scaleOut(ctx context.Context, cgStatus *dv1.ComputeGroupStatus, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) error {
-	sqlClient, err := dcgs.getMasterSqlClient(ctx, cluster)
+// dropShrunkBackends drop the backends that the pods removed by shrinking statefulset, keepAmount is the replicas of statefulset shrunk to.
+func (dcgs *DisaggregatedComputeGroupsController) dropShrunkBackends(ctx context.Context, cgStatus *dv1.ComputeGroupStatus, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, keepAmount int32) (*sc.Event, error) {
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, cluster)
 	if err != nil {
-		klog.Errorf("ScaleOut getMasterSqlClient failed, get fe master node connection err:%s", err.Error())
-		return err
+		klog.Errorf("dropShrunkBackends getOperationSqlClient failed, get fe master node connection err:%s", err.Error())
+		cgStatus.Phase = dv1.ScaleDownFailed
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	defer sqlClient.Close()
+
+	if err := dcgs.scaledOutBENodesByDrop(ctx, cluster, sqlClient, cgStatus, cgStatus.ComputeGroupId, keepAmount); err != nil {
+		cgStatus.Phase = dv1.ScaleDownFailed
+		klog.Errorf("dropShrunkBackends scaledOutBENodesByDrop ddcName:%s, namespace:%s, computeGroupId:%s, drop nodes failed:%s ", cluster.Name, cluster.Namespace, cgStatus.ComputeGroupId, err.Error())
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	cgStatus.Phase = dv1.Scaling
+	return nil, nil
+}
+
+// scaleOut drop or decommission the backends of compute group not kept, keepAmount is the replicas of statefulset in this step of scaling in.
+func (dcgs *DisaggregatedComputeGroupsController) scaleOut(ctx context.Context, cgStatus *dv1.ComputeGroupStatus, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, keepAmount int32) (*sc.Event, error) {
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, cluster)
+	if err != nil {
+		klog.Errorf("ScaleOut getOperationSqlClient failed, get fe master node connection err:%s", err.Error())
+		return nil, err
 	}
 	defer sqlClient.Close()
 
-	cgKeepAmount := *cg.Replicas
+	cgKeepAmount := keepAmount
 	cgid := cgStatus.ComputeGroupId
 
-	if cluster.Spec.EnableDecommission {
-		if err := dcgs.scaledOutBENodesByDecommission(cluster, cgStatus, sqlClient, cgid, cgKeepAmount); err != nil {
-			return err
+	if event, err := dcgs.confirmBackendsInFE(ctx, sqlClient, cluster, cg, cgid, cgKeepAmount); err != nil {
+		cgStatus.Phase = dv1.Scaling
+		klog.Errorf("ScaleOut confirmBackendsInFE ddcName:%s, namespace:%s, uniqueId:%s, failed:%s", cluster.Name, cluster.Namespace, cg.UniqueId, err.Error())
+		return event, err
+	}
+
+	//not start a new scale down when tablets balancing, the in progress decommission(include the timed out one) should continue.
+	if cgStatus.Phase != dv1.Decommissioning && cgStatus.DecommissionStartTime == nil {
+		if event, err := dcgs.waitTabletsBalanced(sqlClient, cluster, cg); err != nil {
+			cgStatus.Phase = dv1.Scaling
+			klog.Errorf("ScaleOut waitTabletsBalanced ddcName:%s, namespace:%s, uniqueId:%s, failed:%s", cluster.Name, cluster.Namespace, cg.UniqueId, err.Error())
+			return event, err
+		}
+	}
+
+	if cgStatus.Phase != dv1.Decommissioning {
+		if event, err := dcgs.waitCacheWarmed(sqlClient, cluster, cg, cgStatus, cgKeepAmount); err != nil {
+			cgStatus.Phase = dv1.Scaling
+			klog.Infof("ScaleOut waitCacheWarmed ddcName:%s, namespace:%s, uniqueId:%s, %s", cluster.Name, cluster.Namespace, cg.UniqueId, err.Error())
+			return event, err
+		}
+	}
+
+	if decommissionEnabled(cluster, cg) {
+		if event, err := dcgs.scaledOutBENodesByDecommission(ctx, cluster, cg, cgStatus, sqlClient, cgid, cgKeepAmount); err != nil {
+			return event, err
 		}
 	} else { // not decommission , drop node
-		if err := dcgs.scaledOutBENodesByDrop(sqlClient, cgid, cgKeepAmount); err != nil {
+		if err := dcgs.scaledOutBENodesByDrop(ctx, cluster, sqlClient, cgStatus, cgid, cgKeepAmount); err != nil {
 			cgStatus.Phase = dv1.ScaleDownFailed
 			klog.Errorf("ScaleOut scaledOutBENodesByDrop ddcName:%s, namespace:%s, computeGroupName:%s, drop nodes failed:%s ", cluster.Name, cluster.Namespace, cgid, err.Error())
-			return err
+			return nil, err
 		}
 		cgStatus.Phase = dv1.Scaling
 	}
 	// return nil will apply sts
-	return nil
+	return nil, nil
+}
+
+// confirmBackendsInFE distinguish "genuinely no backends" from "fe metadata not ready". when fe returns no backend of the compute group,
+// but the pods more than keepAmount(the replicas kept in this step of scaling in, not the replicas of spec when suspending or limited by scale in step) still exist,
+// the scale down should wait for next reconcile, not treat as succeed.
+func (dcgs *DisaggregatedComputeGroupsController) confirmBackendsInFE(ctx context.Context, sqlClient *mysql.DB, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgid string, keepAmount int32) (*sc.Event, error) {
+	if cg.ScaleDownWithoutBackends {
+		return nil, nil
+	}
+
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgid)
+	if err != nil {
+		return nil, err
+	}
+	if len(backends) != 0 {
+		return nil, nil
+	}
+
+	pods, err := k8s.GetPods(ctx, dcgs.K8sclient, cluster.Namespace, dcgs.newCGPodsSelector(cluster.Name, cg.UniqueId))
+	if err != nil {
+		return nil, err
+	}
+	if int32(len(pods.Items)) <= keepAmount {
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("compute group %s have %d pods, but fe not return any backend of compute group id '%s', waiting fe metadata ready to scale down.", cg.UniqueId, len(pods.Items), cgid)
+	return &sc.Event{Type: sc.EventWarning, Reason: sc.CGBackendsNotConfirmed, Message: msg}, errors.New(msg)
 }
 
-func (dcgs *DisaggregatedComputeGroupsController) scaledOutBENodesByDecommission(cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus, sqlClient *mysql.DB, cgid string, cgKeepAmount int32) error {
+// waitTabletsBalanced defer the scale down when fe is balancing or cloning tablets in cluster, scale down in stressed state may cause cascading unavailability.
+func (dcgs *DisaggregatedComputeGroupsController) waitTabletsBalanced(sqlClient *mysql.DB, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	if cluster.Spec.SkipBalanceCheckOnScaleDown {
+		return nil, nil
+	}
+
+	num, err := sqlClient.GetBalancingTabletsNum()
+	if err != nil {
+		return nil, err
+	}
+	if num == 0 {
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("compute group %s scale down deferred, fe have %d tablets in balancing or cloning.", cg.UniqueId, num)
+	return &sc.Event{Type: sc.EventWarning, Reason: sc.CGScaleDownDeferred, Message: msg}, errors.New(msg)
+}
+
+// scaledOutBENodesByDecommission decommission the backends not kept, drop them after decommissioned. return the event when the decommission timed out.
+func (dcgs *DisaggregatedComputeGroupsController) scaledOutBENodesByDecommission(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, sqlClient *mysql.DB, cgid string, cgKeepAmount int32) (*sc.Event, error) {
 	decommissionPhase, err := dcgs.decommissionProgressCheck(sqlClient, cgid, cgKeepAmount)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	switch decommissionPhase {
 	case resource.DecommissionAcceptable:
-		err = dcgs.decommissionBENodes(sqlClient, cgid, cgKeepAmount)
+		err = dcgs.decommissionBENodes(ctx, cluster, sqlClient, cgStatus, cgid, cgKeepAmount)
 		if err != nil {
 			cgStatus.Phase = dv1.ScaleDownFailed
 			klog.Errorf("scaledOutBENodesByDecommission ddcName:%s, namespace:%s, computeGroupId:%s , Decommission failed, err:%s ", cluster.Name, cluster.Namespace, cgid, err.Error())
-			return err
+			return nil, err
 		}
+		now := metav1.Now()
+		cgStatus.DecommissionStartTime = &now
 		cgStatus.Phase = dv1.Decommissioning
-		return nil
+		return nil, nil
 	case resource.Decommissioning, resource.DecommissionPhaseUnknown:
+		if decommissionTimedOut(cg, cgStatus, time.Now()) {
+			cgStatus.Phase = dv1.ScaleDownFailed
+			msg := fmt.Sprintf("compute group %s decommission backends not finished in %s since %s, please check the tablets migration of backends in fe.", cg.UniqueId, cg.DecommissionTimeout.Duration.String(), cgStatus.DecommissionStartTime.String())
+			klog.Errorf("scaledOutBENodesByDecommission ddcName:%s, namespace:%s, %s", cluster.Name, cluster.Namespace, msg)
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGDecommissionTimeout, Message: msg}, errors.New(msg)
+		}
 		cgStatus.Phase = dv1.Decommissioning
 		klog.Infof("scaledOutBENodesByDecommission ddcName:%s, namespace:%s, computeGroupId:%s, Decommission in progress", cluster.Name, cluster.Namespace, cgid)
-		return nil
+		return nil, nil
 	case resource.Decommissioned:
-		dcgs.scaledOutBENodesByDrop(sqlClient, cgid, cgKeepAmount)
+		if err := dcgs.scaledOutBENodesByDrop(ctx, cluster, sqlClient, cgStatus, cgid, cgKeepAmount); err != nil {
+			cgStatus.Phase = dv1.ScaleDownFailed
+			klog.Errorf("scaledOutBENodesByDecommission ddcName:%s, namespace:%s, computeGroupId:%s, drop decommissioned nodes failed, err:%s ", cluster.Name, cluster.Namespace, cgid, err.Error())
+			return nil, err
+		}
 	}
+	cgStatus.DecommissionStartTime = nil
 	cgStatus.Phase = dv1.Scaling
-	return nil
+	return nil, nil
+}
+
+// decommissionEnabled return true when the backends of compute group decommissioned before dropped in scaling down, enabled by cluster or compute group.
+func decommissionEnabled(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) bool {
+	return ddc.Spec.EnableDecommission || cg.DecommissionBeforeDrop
+}
+
+// decommissionTimedOut return true when the decommission started before the decommissionTimeout of compute group.
+// the decommission started by the operator of old version not have start time, it starts from now.
+func decommissionTimedOut(cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, now time.Time) bool {
+	if cgStatus.DecommissionStartTime == nil {
+		start := metav1.NewTime(now)
+		cgStatus.DecommissionStartTime = &start
+	}
+	return cg.DecommissionTimeout != nil && now.Sub(cgStatus.DecommissionStartTime.Time) > cg.DecommissionTimeout.Duration
 }
 
 func getOperationType(st, est *appv1.StatefulSet, phase dv1.Phase) string {
 	//Should not check 'phase == dv1.Ready', because the default value of the state initialization is Reconciling in the new Reconcile
 	// *st.Spec.Replicas < *est.Spec.Replicas represents need initial scaleDown, it belongs to the start phase.
-	if *(st.Spec.Replicas) < *(est.Spec.Replicas) || phase == dv1.Decommissioning || phase == dv1.ScaleDownFailed {
+	if *(st.Spec.Replicas) < *(est.Spec.Replicas) || phase == dv1.Decommissioning || phase == dv1.ScaleDownFailed || phase == dv1.ScaleDownBlocked {
 		return "scaleDown"
 	}
 	return ""
 }
 
+// scaledOutBENodesByDrop drop the backends not kept, the dropped are counted in status for reporting when the scale down succeeded.
+// the backends that the host can not be mapped to a pod are reported by event, not fail the scale down of the others.
 func (dcgs *DisaggregatedComputeGroupsController) scaledOutBENodesByDrop(
+	ctx context.Context,
+	cluster *dv1.DorisDisaggregatedCluster,
 	masterDBClient *mysql.DB,
+	cgStatus *dv1.ComputeGroupStatus,
 	cgid string,
 	cgKeepAmount int32) error {
 
-	dropNodes, err := getScaledOutBENode(masterDBClient, cgid, cgKeepAmount)
+	dropNodes, unparsed, err := dcgs.getScaledOutBENode(ctx, cluster, masterDBClient, cgStatus, cgid, cgKeepAmount)
 	if err != nil {
 		klog.Errorf("scaledOutBENodesByDrop getScaledOutBENode cgid %s failed, err:%s ", cgid, err.Error())
 		return err
 	}
+	dcgs.reportUnparsedBackends(cluster, cgStatus, unparsed)
 
 	if len(dropNodes) == 0 {
 		return nil
 	}
-	err = masterDBClient.DropBE(dropNodes)
-	if err != nil {
-		klog.Errorf("scaledOutBENodesByDrop cgid %s DropBENodes failed, err:%s ", cgid, err.Error())
-		return err
+	if derr := dcgs.dropBackends(cluster, masterDBClient, cgStatus.UniqueId, dropNodes); derr != nil {
+		klog.Errorf("scaledOutBENodesByDrop cgid %s DropBENodes failed, err:%s ", cgid, derr.Error())
+		return derr
 	}
+	cgStatus.DroppedBackends += int32(len(dropNodes))
 	return nil
 }
 
+// reportUnparsedBackends emit warning event for the backends that the ordinal of pod not parsed from host, they are not dropped or decommissioned by scaling down.
+func (dcgs *DisaggregatedComputeGroupsController) reportUnparsedBackends(cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus, unparsed []string) {
+	if len(unparsed) == 0 {
+		return
+	}
+	msg := fmt.Sprintf("compute group %s scale down skipped the backends not mapped to pods, please check and drop them manually if they are not used: %s", cgStatus.UniqueId, strings.Join(unparsed, "; "))
+	klog.Errorf("disaggregatedComputeGroupsController reportUnparsedBackends namespace %s name %s %s", cluster.Namespace, cluster.Name, msg)
+	dcgs.K8srecorder.Event(cluster, string(sc.EventWarning), string(sc.CGBackendsUnparsed), msg)
+}
+
+// dropBackends drop the backends of compute group in fe and audit the drop.
+func (dcgs *DisaggregatedComputeGroupsController) dropBackends(ddc *dv1.DorisDisaggregatedCluster, sqlClient *mysql.DB, uniqueId string, backends []*mysql.Backend) error {
+	if len(backends) == 0 {
+		return nil
+	}
+	err := sqlClient.DropBE(backends)
+	sc.AuditSQL(dcgs.K8srecorder, ddc, sqlClient, sc.SQLAuditRecord{ComputeGroup: uniqueId, Operation: sc.AuditDropBackend, Nodes: sc.BackendNodes(backends), SQL: mysql.DropBESQL(backends)}, err)
+	return err
+}
+
 func (dcgs *DisaggregatedComputeGroupsController) decommissionBENodes(
+	ctx context.Context,
+	cluster *dv1.DorisDisaggregatedCluster,
 	masterDBClient *mysql.DB,
+	cgStatus *dv1.ComputeGroupStatus,
 	cgName string,
 	cgKeepAmount int32) error {
 
-	dropNodes, err := getScaledOutBENode(masterDBClient, cgName, cgKeepAmount)
+	dropNodes, unparsed, err := dcgs.getScaledOutBENode(ctx, cluster, masterDBClient, cgStatus, cgName, cgKeepAmount)
 	if err != nil {
 		klog.Errorf("decommissionBENodes getScaledOutBENode cgName %s failed, err:%s ", cgName, err.Error())
 		return err
 	}
+	dcgs.reportUnparsedBackends(cluster, cgStatus, unparsed)
 
 	if len(dropNodes) == 0 {
 		return nil
 	}
-	err = masterDBClient.DecommissionBE(dropNodes)
-	if err != nil {
-		klog.Errorf("decommissionBENodes cgName %s DropBENodes failed, err:%s ", cgName, err.Error())
-		return err
+	if derr := masterDBClient.DecommissionBE(dropNodes); derr != nil {
+		klog.Errorf("decommissionBENodes cgName %s DropBENodes failed, err:%s ", cgName, derr.Error())
+		return derr
 	}
 	return nil
 }
@@ -164,7 +368,64 @@ func (dcgs *DisaggregatedComputeGroupsController) decommissionBENodes(
 func (dcgs *DisaggregatedComputeGroupsController) getMasterSqlClient(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster) (*mysql.DB, error) {
 	// get user and password
 	adminUserName, password := dcgs.GetManagementAdminUserAndPWD(ctx, cluster)
+	return dcgs.newMasterSqlClient(ctx, cluster, adminUserName, password, true)
+}
+
+// getEvictionSqlClient connect fe master by the management admin user in one attempt, the eviction webhook answers in the webhook timeout.
+func (dcgs *DisaggregatedComputeGroupsController) getEvictionSqlClient(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster) (*mysql.DB, error) {
+	adminUserName, password := dcgs.GetManagementAdminUserAndPWD(ctx, cluster)
+	return dcgs.newMasterSqlClient(ctx, cluster, adminUserName, password, false)
+}
+
+// getOperationSqlClient connect fe master with the user of operationSecret for dropping or decommissioning backends, fall back to the management admin user when not configured.
+func (dcgs *DisaggregatedComputeGroupsController) getOperationSqlClient(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster) (*mysql.DB, error) {
+	userName, password := dcgs.GetOperationUserAndPWD(ctx, cluster)
+	sqlClient, err := dcgs.newMasterSqlClient(ctx, cluster, userName, password, true)
+	if err != nil {
+		return nil, err
+	}
+	dcgs.CheckOperationUserPrivilege(cluster, sqlClient)
+	return sqlClient, nil
+}
+
+// newMasterSqlClient connect to the master of fe cluster, retry tries the fe pods in rotation after the fe service failed, otherwise only the fe service connected once.
+func (dcgs *DisaggregatedComputeGroupsController) newMasterSqlClient(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, adminUserName, password string, retry bool) (*mysql.DB, error) {
+
+	// the config resolved in every connecting, the query_port changed between reconciles never uses a stale port.
+	dbConf, confMap := dcgs.newFEDBConfig(cluster, adminUserName, password)
+	queryPort := resource.GetPort(confMap, resource.QUERY_PORT)
+	// the query_port in config not exposed by service is refused, tell it precisely than the connection error.
+	if err := k8s.CheckServicePortExposed(ctx, dcgs.K8sclient, cluster.Namespace, cluster.GetFEServiceName(), queryPort); err != nil {
+		msg := fmt.Sprintf("the query_port %d of fe config not exposed by fe service, please check the query_port in fe config and the service, err=%s", queryPort, err.Error())
+		klog.Errorf("getMasterSqlClient namespace %s name %s %s", cluster.Namespace, cluster.Name, msg)
+		dcgs.K8srecorder.Event(cluster, string(sc.EventWarning), sc.FEQueryPortNotExposed, msg)
+		return nil, errors.New(msg)
+	}
+
+	tlsConfig, secret, err := dcgs.GetTLSConfigAndSecret(ctx, confMap, cluster)
+	if err != nil {
+		klog.Errorf("getMasterSqlClient namespace %s name %s %s", cluster.Namespace, cluster.Name, err.Error())
+		return nil, err
+	}
+
+	// Connect to the master and run the SQL statement of system admin, because it is not excluded that the user can shrink be and fe at the same time
+	// the fe pods are tried in rotation when the service routes to a fe not reachable the master transiently.
+	dbConf.FallbackHosts = cluster.GetFEPodAddresses()
+	attempts := 1
+	if retry {
+		attempts = len(dbConf.FallbackHosts) + 1
+	}
+	masterDBClient, err := mysql.NewDorisMasterSqlDBWithRetry(ctx, dbConf, attempts, mysql.DefaultMasterConnectBackoff, tlsConfig, secret)
+	if err != nil {
+		klog.Errorf("getMasterSqlClient NewDorisMasterSqlDB failed for ddc %s namespace %s, get fe node connection err:%s", cluster.Namespace, cluster.Name, err.Error())
+		dcgs.CheckFEMasterReachable(cluster, dbConf, err)
+		return nil, err
+	}
+	return dcgs.PlanSqlClient(masterDBClient), nil
+}
 
+// newFEDBConfig build the config connecting to fe service from the fe config read now, return the resolved fe config for tls.
+func (dcgs *DisaggregatedComputeGroupsController) newFEDBConfig(cluster *dv1.DorisDisaggregatedCluster, adminUserName, password string) (mysql.DBConfig, map[string]interface{}) {
 	// get host and port
 	// When the operator and dcr are deployed in different namespace, it will be inaccessible, so need to add the dcr svc namespace
 	host := cluster.GetFEVIPAddresss()
@@ -173,23 +434,13 @@ func (dcgs *DisaggregatedComputeGroupsController) getMasterSqlClient(ctx context
 
 	// connect to doris sql to get master node
 	// It may not be the master, or even the node that needs to be deleted, causing the deletion SQL to fail.
-	dbConf := mysql.DBConfig{
+	return mysql.DBConfig{
 		User:     adminUserName,
 		Password: password,
 		Host:     host,
 		Port:     strconv.FormatInt(int64(queryPort), 10),
 		Database: "mysql",
-	}
-	tlsConfig, secretName := dcgs.DisaggregatedSubDefaultController.FindSecretTLSConfig(confMap, cluster)
-	secret, _ := k8s.GetSecret(context.Background(), dcgs.K8sclient, cluster.Namespace, secretName)
-
-	// Connect to the master and run the SQL statement of system admin, because it is not excluded that the user can shrink be and fe at the same time
-	masterDBClient, err := mysql.NewDorisMasterSqlDB(dbConf, tlsConfig, secret)
-	if err != nil {
-		klog.Errorf("getMasterSqlClient NewDorisMasterSqlDB failed for ddc %s namespace %s, get fe node connection err:%s", cluster.Namespace, cluster.Name, err.Error())
-		return nil, err
-	}
-	return masterDBClient, nil
+	}, confMap
 }
 
 // isDecommissionProgressFinished check decommission status
@@ -203,32 +454,93 @@ func (dcgs *DisaggregatedComputeGroupsController) decommissionProgressCheck(mast
 	return dts.GetDecommissionPhase(), nil
 }
 
-func getScaledOutBENode(
+// getScaledOutBENode return the backends of compute group that the ordinals of pods not less than cgKeepAmount.
+// the backends registered by ip(fqdn mode disabled) are mapped to pods by the pod ip, the reasons of hosts not mapped to an ordinal are returned as unparsed.
+// listing pods failed returns the error, not reports all the backends registered by ip as unparsed.
+// the statefulset always removes the pods of the highest ordinals, so the backends of kept pods on cordoned or draining nodes can not be dropped instead,
+// they are moved out by draining the node, and decommissioned before evicted when decommissionOnEviction enabled.
+func (dcgs *DisaggregatedComputeGroupsController) getScaledOutBENode(
+	ctx context.Context,
+	cluster *dv1.DorisDisaggregatedCluster,
 	masterDBClient *mysql.DB,
+	cgStatus *dv1.ComputeGroupStatus,
 	cgid string,
-	cgKeepAmount int32) ([]*mysql.Backend, error) {
+	cgKeepAmount int32) ([]*mysql.Backend, []string, error) {
 
 	allBackends, err := masterDBClient.GetBackendsByComputeGroupId(cgid)
 	if err != nil {
 		klog.Errorf("scaledOutBEPreprocessing failed,  cgid %s ShowBackends err:%s", cgid, err.Error())
-		return nil, err
+		return nil, nil, err
+	}
+	if len(allBackends) == 0 {
+		return nil, nil, nil
 	}
 
+	pods, err := k8s.GetPods(ctx, dcgs.K8sclient, cluster.Namespace, dcgs.newCGPodsSelector(cluster.Name, cgStatus.UniqueId))
+	if err != nil {
+		klog.Errorf("scaledOutBEPreprocessing namespace %s name %s list pods of compute group %s failed, err=%s", cluster.Namespace, cluster.Name, cgStatus.UniqueId, err.Error())
+		return nil, nil, err
+	}
+	podNames := podNamesByIP(pods.Items)
+
 	var dropNodes []*mysql.Backend
+	var unparsed []string
 	for i := range allBackends {
 		node := allBackends[i]
-		split := strings.Split(node.Host, ".")
-		splitCGIDArr := strings.Split(split[0], "-")
-		podNum, err := strconv.Atoi(splitCGIDArr[len(splitCGIDArr)-1])
+		podNum, err := backendPodOrdinal(node.Host, cgStatus.StatefulsetName, podNames)
 		if err != nil {
-			klog.Errorf("scaledOutBEPreprocessing  cgid %s splitCGIDArr can not split host : %s,err:%s", cgid, node.Host, err.Error())
-			return nil, err
+			klog.Errorf("scaledOutBEPreprocessing cgid %s %s", cgid, err.Error())
+			unparsed = append(unparsed, err.Error())
+			continue
 		}
 		if podNum >= int(cgKeepAmount) {
 			dropNodes = append(dropNodes, node)
 		}
 	}
-	return dropNodes, nil
+	return dropNodes, unparsed, nil
+}
+
+// backendPodOrdinal return the ordinal of pod that the backend belongs to, the host is the fqdn of pod or the pod ip mapped to pod name by podNames.
+// the pod name matched with the statefulset name as prefix, the names with dashes or ending in digits not mistake the ordinal.
+func backendPodOrdinal(host, stsName string, podNames map[string]string) (int, error) {
+	prefix := stsName + "-"
+	podName := strings.Split(host, ".")[0]
+	if !strings.HasPrefix(host, prefix) {
+		name, ok := podNames[host]
+		if !ok {
+			return 0, fmt.Errorf("backend host %s is not the pod of statefulset %s and not the ip of any pod", host, stsName)
+		}
+		podName = name
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(podName, prefix))
+	if err != nil || ordinal < 0 || !strings.HasPrefix(podName, prefix) {
+		return 0, fmt.Errorf("backend host %s(pod %s) can not parse the ordinal of statefulset %s", host, podName, stsName)
+	}
+	return ordinal, nil
+}
+
+// podNamesByIP return the names of pods by the pod ip.
+func podNamesByIP(pods []corev1.Pod) map[string]string {
+	names := map[string]string{}
+	for i := range pods {
+		if ip := pods[i].Status.PodIP; ip != "" {
+			names[ip] = pods[i].Name
+		}
+	}
+	return names
+}
+
+// backendOrdinal return the ordinal of pod that the backend host(the fqdn of pod) belongs to.
+func backendOrdinal(host string) (int, error) {
+	split := strings.Split(host, ".")
+	splitCGIDArr := strings.Split(split[0], "-")
+	return strconv.Atoi(splitCGIDArr[len(splitCGIDArr)-1])
+}
+
+// backendStatefulsetName return the name of statefulset that the backend host(the fqdn of pod) belongs to.
+func backendStatefulsetName(host string) string {
+	podName := strings.Split(host, ".")[0]
+	return podName[:max(strings.LastIndex(podName, "-"), 0)]
 }
 
 // if in decommission, skip apply statefulset.
@@ -242,7 +554,7 @@ func skipApplyStatefulset(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGro
 		}
 	}
 
-	if cgStatus.Phase == dv1.Decommissioning {
+	if cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownBlocked {
 		return true
 	}
 	return false
@@ -0,0 +1,404 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"github.com/jmoiron/sqlx"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var backendColumns = []string{"BackendId", "Host", "HeartbeatPort", "BePort", "HttpPort", "BrpcPort", "ArrowFlightSqlPort", "LastStartTime",
+	"LastHeartbeat", "Alive", "SystemDecommissioned", "TabletNum", "DataUsedCapacity", "TrashUsedCapacity", "AvailCapacity", "TotalCapacity", "UsedPct", "MaxDiskUsedPct",
+	"RemoteUsedCapacity", "Tag", "ErrMsg", "Version", "Status", "HeartbeatFailureCounter", "NodeRole"}
+
+func newBackendRow(host string, cgid string) []driver.Value {
+	return []driver.Value{"10009", host, 9050, 9060, 8040, 8060, -1, "2024-08-21 10:05:37",
+		"2024-08-22 08:29:46", true, false, 0, "0.000", "0.000", "74.619 GB", "439.037 GB", "83.00 %", "83.00 %", "0.000",
+		"{\"location\" : \"default\",\"compute_group_id\":\"" + cgid + "\"}", "", "doris-3.0.3", "{}", 0, "mix"}
+}
+
+func newTestCGPods(ddcName, uniqueId string, count int) []client.Object {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	var pods []client.Object
+	for i := 0; i < count; i++ {
+		pods = append(pods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      ddcName + "-" + uniqueId + "-" + string(rune('0'+i)),
+				Labels:    dcgs.newCGPodsSelector(ddcName, uniqueId),
+			},
+		})
+	}
+	return pods
+}
+
+func Test_confirmBackendsInFE(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	tests := []struct {
+		name       string
+		cg         dv1.ComputeGroup
+		pods       int
+		keepAmount int32
+		rows       [][]driver.Value
+		wantEvent  bool
+	}{
+		{name: "backends exist", cg: dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}, pods: 2, keepAmount: 1,
+			rows: [][]driver.Value{newBackendRow("test-cg1-0.test-cg1.default.svc.cluster.local", "cgid1")}},
+		{name: "no backends and no pods", cg: dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}, pods: 0, keepAmount: 1},
+		{name: "no backends but pods exist", cg: dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}, pods: 2, keepAmount: 1, wantEvent: true},
+		//the replicas of spec equal the pods when suspending or limited by scale in step, the keepAmount of the step is compared.
+		{name: "no backends and step keeps less than spec", cg: dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(3)}}, pods: 3, keepAmount: 0, wantEvent: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mdb, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("sqlmock new failed %s", err.Error())
+			}
+			rows := sqlmock.NewRows(backendColumns)
+			for _, r := range test.rows {
+				rows.AddRow(r...)
+			}
+			mock.ExpectQuery("show backends").WillReturnRows(rows)
+			db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+			defer db.Close()
+
+			dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{
+				K8sclient: fake.NewClientBuilder().WithObjects(newTestCGPods(ddc.Name, test.cg.UniqueId, test.pods)...).Build(),
+			}}
+			event, err := dcgs.confirmBackendsInFE(context.Background(), db, ddc, &test.cg, "cgid1", test.keepAmount)
+			if test.wantEvent && (event == nil || err == nil || event.Reason != sc.CGBackendsNotConfirmed) {
+				t.Errorf("confirmBackendsInFE expected CGBackendsNotConfirmed, event=%v, err=%v", event, err)
+			}
+			if !test.wantEvent && (event != nil || err != nil) {
+				t.Errorf("confirmBackendsInFE expected confirmed, event=%v, err=%v", event, err)
+			}
+		})
+	}
+}
+
+func Test_confirmBackendsInFE_ScaleDownWithoutBackends(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := dv1.ComputeGroup{UniqueId: "cg1", ScaleDownWithoutBackends: true, CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{
+		K8sclient: fake.NewClientBuilder().WithObjects(newTestCGPods(ddc.Name, cg.UniqueId, 3)...).Build(),
+	}}
+	//the sql client should not be used when skip confirming.
+	if event, err := dcgs.confirmBackendsInFE(context.Background(), nil, ddc, &cg, "", 1); event != nil || err != nil {
+		t.Errorf("confirmBackendsInFE with ScaleDownWithoutBackends expected skipped, event=%v, err=%v", event, err)
+	}
+}
+
+func Test_scaledOutBENodesByDrop_CordonedNodes(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	rows := sqlmock.NewRows(backendColumns)
+	for i := 0; i < 5; i++ {
+		rows.AddRow(newBackendRow("test-cg1-"+strconv.Itoa(i)+".test-cg1.default.svc.cluster.local", "cgid1")...)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(rows)
+	//the statefulset removes the highest ordinals, only their backends dropped. the kept test-cg1-0 on the cordoned node not dropped.
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM DROPP BACKEND "test-cg1-2.test-cg1.default.svc.cluster.local:9050","test-cg1-3.test-cg1.default.svc.cluster.local:9050","test-cg1-4.test-cg1.default.svc.cluster.local:9050";`)).
+		WillReturnResult(sqlmock.NewResult(1, 3))
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", ComputeGroupId: "cgid1", StatefulsetName: "test-cg1"}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	objs := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "normal"}},
+	}
+	for i, node := range []string{"cordoned", "normal", "normal", "normal", "cordoned"} {
+		objs = append(objs, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1-" + strconv.Itoa(i), Labels: dcgs.newCGPodsSelector("test", "cg1")}, Spec: corev1.PodSpec{NodeName: node}})
+	}
+	dcgs.K8sclient = fake.NewClientBuilder().WithObjects(objs...).Build()
+	dcgs.K8srecorder = record.NewFakeRecorder(10)
+
+	if err := dcgs.scaledOutBENodesByDrop(context.Background(), ddc, db, cgStatus, "cgid1", 2); err != nil {
+		t.Fatalf("scaledOutBENodesByDrop failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("scaledOutBENodesByDrop expected drop the backends of ordinal 2,3,4, err=%s", err.Error())
+	}
+	if cgStatus.DroppedBackends != 3 {
+		t.Errorf("scaledOutBENodesByDrop expected 3 backends dropped, got %d", cgStatus.DroppedBackends)
+	}
+}
+
+func Test_getScaledOutBENode_OutOfOrder(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	//with Parallel podManagementPolicy the backends registered out of order, the scale down still drop by ordinal.
+	rows := sqlmock.NewRows(backendColumns)
+	for _, host := range []string{"test-cg-1-3.test-cg-1.default.svc.cluster.local", "test-cg-1-0.test-cg-1.default.svc.cluster.local",
+		"test-cg-1-10.test-cg-1.default.svc.cluster.local", "test-cg-1-2.test-cg-1.default.svc.cluster.local"} {
+		rows.AddRow(newBackendRow(host, "cgid1")...)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(rows)
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build()}}
+	dropNodes, unparsed, err := dcgs.getScaledOutBENode(context.Background(), &dv1.DorisDisaggregatedCluster{}, db, &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg-1"}, "cgid1", 3)
+	if err != nil || len(unparsed) != 0 {
+		t.Fatalf("getScaledOutBENode failed, unparsed=%v, err=%v", unparsed, err)
+	}
+	if len(dropNodes) != 2 || dropNodes[0].Host != "test-cg-1-3.test-cg-1.default.svc.cluster.local" || dropNodes[1].Host != "test-cg-1-10.test-cg-1.default.svc.cluster.local" {
+		t.Errorf("getScaledOutBENode expected drop ordinal 3 and 10, got %d nodes", len(dropNodes))
+	}
+}
+
+func Test_getScaledOutBENode_IPHosts(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	//the backends registered by ip when fqdn mode disabled, the unknown ip not block dropping the others.
+	rows := sqlmock.NewRows(backendColumns)
+	for _, host := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.9", "test-cg-1-3.test-cg-1.default.svc.cluster.local"} {
+		rows.AddRow(newBackendRow(host, "cgid1")...)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(rows)
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	var objs []client.Object
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg-1-" + strconv.Itoa(i), Labels: dcgs.newCGPodsSelector("test", "cg1")}, Status: corev1.PodStatus{PodIP: ip}}
+		objs = append(objs, pod)
+	}
+	dcgs.K8sclient = fake.NewClientBuilder().WithObjects(objs...).Build()
+
+	dropNodes, unparsed, err := dcgs.getScaledOutBENode(context.Background(), ddc, db, &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg-1"}, "cgid1", 1)
+	if err != nil || len(unparsed) != 1 || !strings.Contains(unparsed[0], "10.0.0.9") {
+		t.Errorf("getScaledOutBENode expected the unparsed host 10.0.0.9 returned without error, unparsed=%v, err=%v", unparsed, err)
+	}
+	if len(dropNodes) != 2 || dropNodes[0].Host != "10.0.0.2" || dropNodes[1].Host != "test-cg-1-3.test-cg-1.default.svc.cluster.local" {
+		t.Errorf("getScaledOutBENode expected drop 10.0.0.2 and ordinal 3, got %d nodes", len(dropNodes))
+	}
+}
+
+func Test_getScaledOutBENode_ListPodsFailed(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	rows := sqlmock.NewRows(backendColumns)
+	for _, host := range []string{"10.0.0.1", "10.0.0.2"} {
+		rows.AddRow(newBackendRow(host, "cgid1")...)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(rows)
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	//the pods not listed should fail the scale down, not report all the ip hosts as unparsed and drop nothing.
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	dcgs.K8sclient = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{List: func(ctx context.Context, client client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+		return apierrors.NewServiceUnavailable("list pods")
+	}}).Build()
+	dropNodes, unparsed, err := dcgs.getScaledOutBENode(context.Background(), ddc, db, &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg-1"}, "cgid1", 1)
+	if err == nil || len(dropNodes) != 0 || len(unparsed) != 0 {
+		t.Errorf("getScaledOutBENode expected the list error returned, dropNodes=%d, unparsed=%v, err=%v", len(dropNodes), unparsed, err)
+	}
+}
+
+func Test_scaledOutBENodesByDrop_Unparsed(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	rows := sqlmock.NewRows(backendColumns)
+	for _, host := range []string{"10.0.0.9", "test-cg-1-0.test-cg-1.default.svc.cluster.local", "test-cg-1-1.test-cg-1.default.svc.cluster.local"} {
+		rows.AddRow(newBackendRow(host, "cgid1")...)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM DROPP BACKEND "test-cg-1-1.test-cg-1.default.svc.cluster.local:9050";`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build(), K8srecorder: recorder}}
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg-1", Phase: dv1.Scaling}
+
+	//the unparsed ip not fail the drop of the parsed backends.
+	if err := dcgs.scaledOutBENodesByDrop(context.Background(), ddc, db, cgStatus, "cgid1", 1); err != nil {
+		t.Errorf("scaledOutBENodesByDrop expected succeed for the dropped backends, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("scaledOutBENodesByDrop expected test-cg-1-1 dropped, err=%s", err.Error())
+	}
+	if cgStatus.DroppedBackends != 1 || cgStatus.Phase != dv1.Scaling || cgStatus.ScaleDownSqlFailures != 0 {
+		t.Errorf("scaledOutBENodesByDrop expected 1 backend dropped and the phase not failed, got %+v", cgStatus)
+	}
+	unparsed := false
+	for len(recorder.Events) > 0 {
+		e := <-recorder.Events
+		unparsed = unparsed || (strings.Contains(e, string(sc.CGBackendsUnparsed)) && strings.Contains(e, "10.0.0.9"))
+	}
+	if !unparsed {
+		t.Errorf("scaledOutBENodesByDrop expected the unparsed host 10.0.0.9 reported by event")
+	}
+}
+
+func Test_backendPodOrdinal(t *testing.T) {
+	podNames := map[string]string{"10.0.0.1": "test-cg-1-2", "10.0.0.2": "other-0"}
+	tests := []struct {
+		host    string
+		stsName string
+		ordinal int
+		err     bool
+	}{
+		{host: "test-cg-1-2.test-cg-1.default.svc.cluster.local", stsName: "test-cg-1", ordinal: 2},
+		//the statefulset name with multiple dashes.
+		{host: "test-my-cg-a-10.test-my-cg-a.default.svc.cluster.local", stsName: "test-my-cg-a", ordinal: 10},
+		//the statefulset name ending in digits.
+		{host: "test-cg12-3.test-cg12.default.svc.cluster.local", stsName: "test-cg12", ordinal: 3},
+		{host: "test-cg-1-0-3.test-cg-1-0.default.svc.cluster.local", stsName: "test-cg-1", err: true},
+		{host: "10.0.0.1", stsName: "test-cg-1", ordinal: 2},
+		{host: "10.0.0.2", stsName: "test-cg-1", err: true},
+		{host: "10.0.0.3", stsName: "test-cg-1", err: true},
+	}
+	for _, test := range tests {
+		ordinal, err := backendPodOrdinal(test.host, test.stsName, podNames)
+		if (err != nil) != test.err || (err == nil && ordinal != test.ordinal) {
+			t.Errorf("backendPodOrdinal(%s, %s) expected ordinal %d err %t, got %d %v", test.host, test.stsName, test.ordinal, test.err, ordinal, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), test.host) {
+			t.Errorf("backendPodOrdinal expected the error naming host %s, got %s", test.host, err.Error())
+		}
+	}
+}
+
+func Test_preApplyStatefulSet_ShrinkFirst(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{ScaleDownOrder: dv1.ShrinkFirst},
+		Status: dv1.DorisDisaggregatedClusterStatus{
+			ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1", Phase: dv1.Reconciling}},
+		},
+	}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(1)}}
+	st := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(1)}}
+	est := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(3)}}
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build()}}
+
+	//the statefulset is shrunk without executing sql before applying.
+	event, err := dcgs.preApplyStatefulSet(context.Background(), st, est, ddc, cg)
+	if event != nil || err != nil {
+		t.Errorf("preApplyStatefulSet expected shrink first without sql, event %v, err %v", event, err)
+	}
+	if skipApplyStatefulset(ddc, cg) {
+		t.Errorf("preApplyStatefulSet expected statefulset applied when shrink first")
+	}
+
+	//not scale down, not drop backends after applying.
+	event, err = dcgs.postApplyStatefulSet(context.Background(), st, st.DeepCopy(), ddc, cg)
+	if event != nil || err != nil {
+		t.Errorf("postApplyStatefulSet expected nothing to do when not scale down, event %v, err %v", event, err)
+	}
+}
+
+func Test_newFEDBConfig_QueryPortChanged(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fe-cm"}, Data: map[string]string{"fe.conf": "query_port = 9030\n"}}
+	k8sclient := fake.NewClientBuilder().WithObjects(cm).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ddc.Spec.FeSpec.ConfigMaps = []dv1.ConfigMap{{Name: "fe-cm", MountPath: "/etc/doris"}}
+
+	if dbConf, _ := dcgs.newFEDBConfig(ddc, "root", ""); dbConf.Port != "9030" {
+		t.Errorf("newFEDBConfig expect port 9030, got %s", dbConf.Port)
+	}
+	//the query_port changed between reconciles, the next connecting uses the new port.
+	cm.Data["fe.conf"] = "query_port = 9031\n"
+	if err := k8sclient.Update(context.Background(), cm); err != nil {
+		t.Fatalf("update configmap failed, err=%s", err.Error())
+	}
+	if dbConf, _ := dcgs.newFEDBConfig(ddc, "root", ""); dbConf.Port != "9031" {
+		t.Errorf("newFEDBConfig expect port 9031 after query_port changed, got %s", dbConf.Port)
+	}
+}
+
+func Test_scaledOutBENodesByDecommission_Timeout(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	newRows := func() *sqlmock.Rows {
+		decommissioning := newBackendRow("test-cg-1-1.test-cg-1.default.svc.cluster.local", "cgid1")
+		decommissioning[10], decommissioning[11] = true, 10
+		return sqlmock.NewRows(backendColumns).AddRow(newBackendRow("test-cg-1-0.test-cg-1.default.svc.cluster.local", "cgid1")...).AddRow(decommissioning...)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(newRows())
+	mock.ExpectQuery("show backends").WillReturnRows(newRows())
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	dcgs := &DisaggregatedComputeGroupsController{}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", DecommissionBeforeDrop: true}
+	if !decommissionEnabled(ddc, cg) {
+		t.Errorf("decommissionEnabled expected enabled by compute group")
+	}
+	start := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Decommissioning, DecommissionStartTime: &start}
+
+	//no timeout keeps decommissioning.
+	if event, err := dcgs.scaledOutBENodesByDecommission(context.Background(), ddc, cg, cgStatus, db, "cgid1", 1); event != nil || err != nil || cgStatus.Phase != dv1.Decommissioning {
+		t.Errorf("scaledOutBENodesByDecommission without timeout expected Decommissioning, got phase %s err %v", cgStatus.Phase, err)
+	}
+
+	cg.DecommissionTimeout = &metav1.Duration{Duration: time.Minute}
+	event, err := dcgs.scaledOutBENodesByDecommission(context.Background(), ddc, cg, cgStatus, db, "cgid1", 1)
+	if err == nil || event == nil || event.Reason != sc.CGDecommissionTimeout || cgStatus.Phase != dv1.ScaleDownFailed {
+		t.Errorf("scaledOutBENodesByDecommission timed out expected ScaleDownFailed with event, got phase %s event %+v", cgStatus.Phase, event)
+	}
+	if cgStatus.DecommissionStartTime == nil {
+		t.Errorf("scaledOutBENodesByDecommission timed out expected the start time kept")
+	}
+}
@@ -0,0 +1,125 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"errors"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// selectedProfile return the name of profile selected for cluster, the annotation of cluster takes precedence over the flag of operator.
+func (dcgs *DisaggregatedComputeGroupsController) selectedProfile(ddc *dv1.DorisDisaggregatedCluster) (string, bool) {
+	if name, ok := ddc.Annotations[dv1.ProfileAnnotation]; ok && name != "" {
+		return name, true
+	}
+	return dcgs.Profile, false
+}
+
+// applyProfile override the compute groups by the selected profile before validating, the returned func restores the base values.
+// the spec of cluster is written back when changed in reconciling, the overrides should be restored after syncing to keep the base values in computeGroups.
+func (dcgs *DisaggregatedComputeGroupsController) applyProfile(ddc *dv1.DorisDisaggregatedCluster) (func(), *sc.Event, error) {
+	restore := func() {}
+	name, annotated := dcgs.selectedProfile(ddc)
+	var profile *dv1.Profile
+	for i := range ddc.Spec.Profiles {
+		if ddc.Spec.Profiles[i].Name == name {
+			profile = &ddc.Spec.Profiles[i]
+			break
+		}
+	}
+	if name == "" || (profile == nil && !annotated) {
+		ddc.Status.Profile = ""
+		return restore, nil, nil
+	}
+	if profile == nil {
+		msg := fmt.Sprintf("the profile %s selected by annotation %s not defined in profiles.", name, dv1.ProfileAnnotation)
+		return restore, &sc.Event{Type: sc.EventWarning, Reason: sc.CGProfileInvalid, Message: msg}, errors.New(msg)
+	}
+	if msg := validateProfiles(ddc); msg != "" {
+		return restore, &sc.Event{Type: sc.EventWarning, Reason: sc.CGProfileInvalid, Message: msg}, errors.New(msg)
+	}
+
+	type base struct {
+		index     int
+		replicas  *int32
+		resources corev1.ResourceRequirements
+	}
+	var bases []base
+	cgs := ddc.Spec.ComputeGroups
+	for _, o := range profile.ComputeGroups {
+		for i := range cgs {
+			if cgs[i].UniqueId != o.UniqueId {
+				continue
+			}
+			bases = append(bases, base{index: i, replicas: cgs[i].Replicas, resources: cgs[i].ResourceRequirements})
+			if o.Replicas != nil {
+				replicas := *o.Replicas
+				cgs[i].Replicas = &replicas
+			}
+			if o.Resources != nil {
+				cgs[i].ResourceRequirements = *o.Resources.DeepCopy()
+			}
+		}
+	}
+
+	if ddc.Status.Profile != name {
+		klog.Infof("disaggregatedComputeGroupsController applyProfile namespace %s name %s compute groups use profile %s.", ddc.Namespace, ddc.Name, name)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGProfileApplied), fmt.Sprintf("compute groups use profile %s.", name))
+	}
+	ddc.Status.Profile = name
+	return func() {
+		for _, b := range bases {
+			cgs[b.index].Replicas = b.replicas
+			cgs[b.index].ResourceRequirements = b.resources
+		}
+	}, nil, nil
+}
+
+// validateProfiles check the profiles have unique names, and the overrides match the compute groups with non-negative replicas.
+func validateProfiles(ddc *dv1.DorisDisaggregatedCluster) string {
+	uniqueIds := map[string]bool{}
+	for _, cg := range ddc.Spec.ComputeGroups {
+		uniqueIds[cg.UniqueId] = true
+	}
+	names := map[string]bool{}
+	for _, p := range ddc.Spec.Profiles {
+		if names[p.Name] {
+			return fmt.Sprintf("the profile %s duplicated in profiles.", p.Name)
+		}
+		names[p.Name] = true
+		overridden := map[string]bool{}
+		for _, o := range p.ComputeGroups {
+			if !uniqueIds[o.UniqueId] {
+				return fmt.Sprintf("the profile %s overrides compute group %s that not in computeGroups.", p.Name, o.UniqueId)
+			}
+			if overridden[o.UniqueId] {
+				return fmt.Sprintf("the profile %s overrides compute group %s more than once.", p.Name, o.UniqueId)
+			}
+			overridden[o.UniqueId] = true
+			if o.Replicas != nil && *o.Replicas < 0 {
+				return fmt.Sprintf("the profile %s overrides compute group %s with negative replicas %d.", p.Name, o.UniqueId, *o.Replicas)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func newProfileCluster(annotations map[string]string) *dv1.DorisDisaggregatedCluster {
+	base, dev, prod := int32(2), int32(1), int32(6)
+	return &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Annotations: annotations},
+		Spec: dv1.DorisDisaggregatedClusterSpec{
+			ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: &base}}, {UniqueId: "cg2", CommonSpec: dv1.CommonSpec{Replicas: &base}}},
+			Profiles: []dv1.Profile{
+				{Name: "dev", ComputeGroups: []dv1.ComputeGroupOverride{{UniqueId: "cg1", Replicas: &dev}}},
+				{Name: "prod", ComputeGroups: []dv1.ComputeGroupOverride{{UniqueId: "cg1", Replicas: &prod, Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+				}}}},
+			},
+		},
+	}
+}
+
+func Test_applyProfile(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder, Profile: "dev"}}
+
+	//the annotation takes precedence over the flag.
+	ddc := newProfileCluster(map[string]string{dv1.ProfileAnnotation: "prod"})
+	restore, _, err := dcgs.applyProfile(ddc)
+	if err != nil {
+		t.Fatalf("applyProfile failed, err=%s", err.Error())
+	}
+	cg1, cg2 := ddc.Spec.ComputeGroups[0], ddc.Spec.ComputeGroups[1]
+	if *cg1.Replicas != 6 || cg1.Requests.Cpu().String() != "8" || *cg2.Replicas != 2 || ddc.Status.Profile != "prod" || len(recorder.Events) != 1 {
+		t.Errorf("applyProfile expected cg1 overridden by prod and cg2 kept, got cg1 replicas %d cpu %s cg2 replicas %d profile %s", *cg1.Replicas, cg1.Requests.Cpu().String(), *cg2.Replicas, ddc.Status.Profile)
+	}
+	restore()
+	if cg1 = ddc.Spec.ComputeGroups[0]; *cg1.Replicas != 2 || cg1.Requests != nil {
+		t.Errorf("applyProfile expected restore the base values, got replicas %d requests %v", *cg1.Replicas, cg1.Requests)
+	}
+
+	ddc = newProfileCluster(nil)
+	if _, _, err = dcgs.applyProfile(ddc); err != nil || *ddc.Spec.ComputeGroups[0].Replicas != 1 {
+		t.Errorf("applyProfile expected the flag selected dev, got replicas %d err %v", *ddc.Spec.ComputeGroups[0].Replicas, err)
+	}
+
+	//the profile selected by flag not defined in cluster uses the base values.
+	dcgs.Profile = "staging"
+	if _, _, err = dcgs.applyProfile(ddc); err != nil || ddc.Status.Profile != "" {
+		t.Errorf("applyProfile expected base values when the flag profile not defined, got profile %s err %v", ddc.Status.Profile, err)
+	}
+
+	ddc = newProfileCluster(map[string]string{dv1.ProfileAnnotation: "staging"})
+	if _, event, err := dcgs.applyProfile(ddc); err == nil || event.Reason != sc.CGProfileInvalid {
+		t.Errorf("applyProfile expected failed when the annotation profile not defined")
+	}
+}
+
+func Test_validateProfiles(t *testing.T) {
+	ddc := newProfileCluster(nil)
+	if msg := validateProfiles(ddc); msg != "" {
+		t.Errorf("validateProfiles expected valid, got %s", msg)
+	}
+	ddc.Spec.Profiles[0].ComputeGroups = append(ddc.Spec.Profiles[0].ComputeGroups, dv1.ComputeGroupOverride{UniqueId: "cg3"})
+	if msg := validateProfiles(ddc); msg == "" {
+		t.Errorf("validateProfiles expected invalid when overriding compute group not exist")
+	}
+}
@@ -0,0 +1,185 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the pvc pending longer than the timeout is considered as binding failed, the dynamic provisioning normally finishes in seconds.
+const pvcBindTimeout = 5 * time.Minute
+
+// checkCGPVCBinding set the PVCBindFailed condition when the pvcs of compute group pending beyond timeout, the warning event emitted when the condition becomes true.
+// the pods are pending when pvcs not bound, the pvcs only checked when there are creating pods.
+func (dcgs *DisaggregatedComputeGroupsController) checkCGPVCBinding(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, st *appv1.StatefulSet, creatingReplicas int32) error {
+	var stuck []corev1.PersistentVolumeClaim
+	if creatingReplicas > 0 {
+		var pvcs corev1.PersistentVolumeClaimList
+		if err := dcgs.K8sclient.List(ctx, &pvcs, client.InNamespace(ddc.Namespace)); err != nil {
+			return err
+		}
+		stuck = stuckPendingPVCs(pvcs.Items, st, time.Now())
+	}
+
+	condition := newPVCBindFailedCondition(cgs.UniqueId, stuck, ddc.Generation)
+	if condition.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(cgs.Conditions, dv1.PVCBindFailed) {
+		klog.Errorf("disaggregatedComputeGroupsController checkCGPVCBinding namespace %s name %s %s", ddc.Namespace, ddc.Name, condition.Message)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.PVCBindFailed), condition.Message)
+	}
+	meta.SetStatusCondition(&cgs.Conditions, condition)
+	return nil
+}
+
+// stuckPendingPVCs return the pvcs created by the volumeClaimTemplates of statefulset that pending beyond the timeout.
+func stuckPendingPVCs(pvcs []corev1.PersistentVolumeClaim, st *appv1.StatefulSet, now time.Time) []corev1.PersistentVolumeClaim {
+	var stuck []corev1.PersistentVolumeClaim
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimPending || !claimedByStatefulset(pvc.Name, st) {
+			continue
+		}
+		if now.Sub(pvc.CreationTimestamp.Time) >= pvcBindTimeout {
+			stuck = append(stuck, pvc)
+		}
+	}
+	return stuck
+}
+
+func newPVCBindFailedCondition(uniqueId string, stuck []corev1.PersistentVolumeClaim, generation int64) metav1.Condition {
+	if len(stuck) == 0 {
+		return metav1.Condition{
+			Type:               dv1.PVCBindFailed,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             dv1.PVCsBound,
+			Message:            "no pvc of compute group pending beyond " + pvcBindTimeout.String() + ".",
+		}
+	}
+
+	var names []string
+	scs := map[string]bool{}
+	for _, pvc := range stuck {
+		names = append(names, pvc.Name)
+		scs[pvcStorageClass(&pvc)] = true
+	}
+	var storageClasses []string
+	for s := range scs {
+		storageClasses = append(storageClasses, s)
+	}
+	sort.Strings(storageClasses)
+
+	return metav1.Condition{
+		Type:               dv1.PVCBindFailed,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             dv1.PVCsPendingTimeout,
+		Message: fmt.Sprintf("compute group %s pvcs %s pending beyond %s, please check the storageClass %s and the persistent volumes.",
+			uniqueId, strings.Join(names, ","), pvcBindTimeout.String(), strings.Join(storageClasses, ",")),
+	}
+}
+
+// checkCGAwaitingStorage set the AwaitingStorage phase when pods of compute group pending for the pvcs not bound, ep: the pvcs of WaitForFirstConsumer storageClass wait the pods scheduled.
+// the phase only replaces the generic Reconciling and Scaling, the warning event emitted when the phase entered.
+func (dcgs *DisaggregatedComputeGroupsController) checkCGAwaitingStorage(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, pods []corev1.Pod) error {
+	var awaiting []string
+	if hasPendingPodWithPVC(pods) {
+		var pvcs corev1.PersistentVolumeClaimList
+		if err := dcgs.K8sclient.List(ctx, &pvcs, client.InNamespace(ddc.Namespace)); err != nil {
+			return err
+		}
+		awaiting = awaitingStoragePVCs(pods, pvcs.Items)
+	}
+
+	if len(awaiting) == 0 {
+		if cgs.Phase == dv1.AwaitingStorage {
+			cgs.Phase = dv1.Reconciling
+		}
+		return nil
+	}
+	if cgs.Phase != dv1.Reconciling && cgs.Phase != dv1.Scaling && cgs.Phase != dv1.AwaitingStorage {
+		return nil
+	}
+	if cgs.Phase != dv1.AwaitingStorage {
+		msg := fmt.Sprintf("compute group %s pods pending for pvcs not bound: %s, please check the storageClass provisioning and the scheduling of pods.", cgs.UniqueId, strings.Join(awaiting, ","))
+		klog.Warningf("disaggregatedComputeGroupsController checkCGAwaitingStorage namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGAwaitingStorage), msg)
+	}
+	cgs.Phase = dv1.AwaitingStorage
+	return nil
+}
+
+func hasPendingPodWithPVC(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awaitingStoragePVCs return the pending pvcs that used by pending pods, formatted as `{pvc}(storageClass {storageClass})`.
+func awaitingStoragePVCs(pods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim) []string {
+	pending := map[string]*corev1.PersistentVolumeClaim{}
+	for i := range pvcs {
+		if pvcs[i].Status.Phase == corev1.ClaimPending {
+			pending[pvcs[i].Name] = &pvcs[i]
+		}
+	}
+
+	var awaiting []string
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim == nil {
+				continue
+			}
+			if pvc, ok := pending[v.PersistentVolumeClaim.ClaimName]; ok {
+				awaiting = append(awaiting, fmt.Sprintf("%s(storageClass %s)", pvc.Name, pvcStorageClass(pvc)))
+			}
+		}
+	}
+	sort.Strings(awaiting)
+	return awaiting
+}
+
+// pvcStorageClass return the storageClass name of pvc, `default` when not specified.
+func pvcStorageClass(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		return *pvc.Spec.StorageClassName
+	}
+	return "default"
+}
@@ -0,0 +1,124 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_checkCGPVCBinding(t *testing.T) {
+	st := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"},
+		Spec: appv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "be-storage"}}},
+		},
+	}
+	old := metav1.NewTime(time.Now().Add(-2 * pvcBindTimeout))
+	storageClass := "fast"
+	pvcs := []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg1-0", CreationTimestamp: old}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg1-1", CreationTimestamp: old}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg1-2", CreationTimestamp: metav1.Now()}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg2-0", CreationTimestamp: old}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+	}
+
+	stuck := stuckPendingPVCs(pvcs, st, time.Now())
+	if len(stuck) != 1 || stuck[0].Name != "be-storage-test-cg1-1" {
+		t.Errorf("stuckPendingPVCs expect be-storage-test-cg1-1, got %v", stuck)
+	}
+
+	k8sclient := fake.NewClientBuilder().WithObjects(&pvcs[0], &pvcs[1], &pvcs[2], &pvcs[3]).Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1"}
+
+	for i := 0; i < 2; i++ {
+		if err := dcgs.checkCGPVCBinding(context.Background(), ddc, cgs, st, 1); err != nil {
+			t.Errorf("checkCGPVCBinding failed, err=%s", err.Error())
+		}
+	}
+	if !meta.IsStatusConditionTrue(cgs.Conditions, dv1.PVCBindFailed) {
+		t.Errorf("checkCGPVCBinding expect PVCBindFailed condition true, got %v", cgs.Conditions)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("checkCGPVCBinding expect 1 event when condition becomes true, got %d", len(recorder.Events))
+	}
+
+	if err := dcgs.checkCGPVCBinding(context.Background(), ddc, cgs, st, 0); err != nil {
+		t.Errorf("checkCGPVCBinding failed, err=%s", err.Error())
+	}
+	if meta.IsStatusConditionTrue(cgs.Conditions, dv1.PVCBindFailed) {
+		t.Errorf("checkCGPVCBinding expect PVCBindFailed condition false when no creating pods.")
+	}
+}
+
+func Test_checkCGAwaitingStorage(t *testing.T) {
+	storageClass := "local-wffc"
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg1-1"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-0"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-1"},
+			Spec:       corev1.PodSpec{Volumes: []corev1.Volume{{Name: "be-storage", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "be-storage-test-cg1-1"}}}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	}
+	if awaiting := awaitingStoragePVCs(pods, []corev1.PersistentVolumeClaim{*pvc}); len(awaiting) != 1 || awaiting[0] != "be-storage-test-cg1-1(storageClass local-wffc)" {
+		t.Errorf("awaitingStoragePVCs expect be-storage-test-cg1-1, got %v", awaiting)
+	}
+
+	k8sclient := fake.NewClientBuilder().WithObjects(pvc).Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling}
+	for i := 0; i < 2; i++ {
+		if err := dcgs.checkCGAwaitingStorage(context.Background(), ddc, cgs, pods); err != nil {
+			t.Errorf("checkCGAwaitingStorage failed, err=%s", err.Error())
+		}
+	}
+	if cgs.Phase != dv1.AwaitingStorage || len(recorder.Events) != 1 {
+		t.Errorf("checkCGAwaitingStorage expect AwaitingStorage phase with 1 event, got phase %s events %d", cgs.Phase, len(recorder.Events))
+	}
+
+	pods[1].Status.Phase = corev1.PodRunning
+	if err := dcgs.checkCGAwaitingStorage(context.Background(), ddc, cgs, pods); err != nil {
+		t.Errorf("checkCGAwaitingStorage failed, err=%s", err.Error())
+	}
+	if cgs.Phase != dv1.Reconciling {
+		t.Errorf("checkCGAwaitingStorage expect Reconciling phase after pods scheduled, got %s", cgs.Phase)
+	}
+
+	cgs.Phase = dv1.Decommissioning
+	pods[1].Status.Phase = corev1.PodPending
+	if err := dcgs.checkCGAwaitingStorage(context.Background(), ddc, cgs, pods); err != nil || cgs.Phase != dv1.Decommissioning {
+		t.Errorf("checkCGAwaitingStorage expect Decommissioning phase kept, got %s", cgs.Phase)
+	}
+}
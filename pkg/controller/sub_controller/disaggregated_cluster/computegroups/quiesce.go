@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	"k8s.io/klog/v2"
+)
+
+// quiesceForFE pause the scale operation of compute group when fe restarting, the sql of dropping or decommissioning backends may fail through the in-flux fe.
+// the new scale keeps the replicas of existing statefulset and marks the compute group QuiescingForFE. the scale down in progress keeps its phase, return true for skipping this reconcile.
+func (dcgs *DisaggregatedComputeGroupsController) quiesceForFE(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) bool {
+	if !cluster.Spec.QuiesceComputeGroupsOnFERestart || cgStatus == nil {
+		return false
+	}
+	if !dcgs.feRestarting(ctx, cluster) {
+		if cgStatus.Phase == dv1.QuiescingForFE {
+			klog.Infof("disaggregatedComputeGroupsController quiesceForFE namespace=%s name=%s compute group %s resume scaling, fe is healthy.", cluster.Namespace, cluster.Name, cg.UniqueId)
+			cgStatus.Phase = dv1.Reconciling
+		}
+		return false
+	}
+
+	if cgStatus.Phase == dv1.Decommissioning || cgStatus.Phase == dv1.ScaleDownFailed || cgStatus.Phase == dv1.ScaleDownBlocked {
+		return true
+	}
+	if *st.Spec.Replicas == *est.Spec.Replicas {
+		return false
+	}
+
+	if cgStatus.Phase != dv1.QuiescingForFE {
+		msg := fmt.Sprintf("compute group %s scale from %d to %d paused, waiting fe restarting finished.", cg.UniqueId, *est.Spec.Replicas, *st.Spec.Replicas)
+		dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGQuiescingForFE), msg)
+	}
+	cgStatus.Phase = dv1.QuiescingForFE
+	st.Spec.Replicas = est.Spec.Replicas
+	return false
+}
+
+// feRestarting return true when the fe statefulset is rolling or have not ready pods, the fe statefulset not found or failed getting is not considered restarting.
+func (dcgs *DisaggregatedComputeGroupsController) feRestarting(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster) bool {
+	fst, err := k8s.GetStatefulSet(ctx, dcgs.K8sclient, cluster.Namespace, cluster.GetFEStatefulsetName())
+	if err != nil {
+		return false
+	}
+	return statefulsetRolling(fst)
+}
+
+// statefulsetRolling return true when the statefulset spec not observed, the pods not updated to the update revision or not all ready.
+func statefulsetRolling(st *appv1.StatefulSet) bool {
+	if st.Status.ObservedGeneration < st.Generation || st.Status.UpdateRevision != st.Status.CurrentRevision {
+		return true
+	}
+	return st.Spec.Replicas != nil && st.Status.ReadyReplicas < *st.Spec.Replicas
+}
@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_quiesceForFE(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{QuiesceComputeGroupsOnFERestart: true},
+	}
+	fst := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: ddc.GetFEStatefulsetName()},
+		Spec:       appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(3)},
+		Status:     appv1.StatefulSetStatus{ReadyReplicas: 3, CurrentRevision: "rev1", UpdateRevision: "rev2"},
+	}
+	k8sclient := fake.NewClientBuilder().WithObjects(fst).Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling}
+	newSts := func(replicas int32) *appv1.StatefulSet {
+		return &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(replicas)}}
+	}
+
+	st := newSts(2)
+	if skip := dcgs.quiesceForFE(context.Background(), ddc, cg, cgStatus, st, newSts(3)); skip || *st.Spec.Replicas != 3 || cgStatus.Phase != dv1.QuiescingForFE {
+		t.Errorf("quiesceForFE expected keep replicas 3 in QuiescingForFE, skip %t replicas %d phase %s", skip, *st.Spec.Replicas, cgStatus.Phase)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("quiesceForFE expected 1 event, got %d", len(recorder.Events))
+	}
+
+	cgStatus.Phase = dv1.Decommissioning
+	if skip := dcgs.quiesceForFE(context.Background(), ddc, cg, cgStatus, newSts(2), newSts(3)); !skip || cgStatus.Phase != dv1.Decommissioning {
+		t.Errorf("quiesceForFE expected skip the decommissioning compute group, skip %t phase %s", skip, cgStatus.Phase)
+	}
+
+	fst.Status.CurrentRevision = "rev2"
+	if err := k8sclient.Status().Update(context.Background(), fst); err != nil {
+		t.Fatalf("update fe statefulset status failed, err=%s", err.Error())
+	}
+	cgStatus.Phase = dv1.QuiescingForFE
+	st = newSts(2)
+	if skip := dcgs.quiesceForFE(context.Background(), ddc, cg, cgStatus, st, newSts(3)); skip || *st.Spec.Replicas != 2 || cgStatus.Phase != dv1.Reconciling {
+		t.Errorf("quiesceForFE expected resume scaling when fe healthy, skip %t replicas %d phase %s", skip, *st.Spec.Replicas, cgStatus.Phase)
+	}
+}
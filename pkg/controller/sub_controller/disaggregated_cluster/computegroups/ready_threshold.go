@@ -0,0 +1,43 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+)
+
+// reachReadyThreshold return true when the available pods of compute group reach the readyThreshold, the compute group not configured or invalid threshold requires all pods available.
+func reachReadyThreshold(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, available int32) bool {
+	cg := findCG(ddc, cgs.UniqueId)
+	if cg == nil || cg.ReadyThreshold == nil || cgs.Replicas == 0 || available == 0 {
+		return false
+	}
+	threshold, err := intstr.GetScaledValueFromIntOrPercent(cg.ReadyThreshold, int(cgs.Replicas), true)
+	if err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController namespace %s name %s compute group %s readyThreshold %s invalid, require all pods available, err=%s", ddc.Namespace, ddc.Name, cg.UniqueId, cg.ReadyThreshold.String(), err.Error())
+		return false
+	}
+	return available >= int32(threshold)
+}
+
+// cgServing return true when the compute group serves queries by all or the threshold of pods.
+func cgServing(cgs *dv1.ComputeGroupStatus) bool {
+	return cgs.Phase == dv1.Ready || cgs.Phase == dv1.PartiallyReady
+}
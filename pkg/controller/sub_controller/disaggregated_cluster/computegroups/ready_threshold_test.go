@@ -0,0 +1,50 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func Test_reachReadyThreshold(t *testing.T) {
+	percent, number, invalid := intstr.FromString("80%"), intstr.FromInt32(3), intstr.FromString("most")
+	tests := []struct {
+		name      string
+		threshold *intstr.IntOrString
+		available int32
+		want      bool
+	}{
+		{"not configured", nil, 4, false},
+		{"percent reached", &percent, 4, true},
+		{"percent rounded up", &percent, 3, false},
+		{"number reached", &number, 3, true},
+		{"number not reached", &number, 2, false},
+		{"no available", &number, 0, false},
+		{"invalid", &invalid, 4, false},
+	}
+	for _, tt := range tests {
+		ddc := &dv1.DorisDisaggregatedCluster{Spec: dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1", ReadyThreshold: tt.threshold}}}}
+		cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Replicas: 5}
+		if got := reachReadyThreshold(ddc, cgs, tt.available); got != tt.want {
+			t.Errorf("%s reachReadyThreshold expected %t, got %t", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cgLabelSchemeVersion is the version of labels that operator set on the pods and pvcs of compute group(newCGPodsSelector).
+// increase it when the labels changed, the existing pods and pvcs are relabeled once in upgrading operator.
+const cgLabelSchemeVersion int32 = 1
+
+// relabelCGResources add the current selector labels to the pods and pvcs of compute group that created by old operator, only run once when the label scheme version in status is older.
+// the pods and pvcs are found by the statefulset not by labels, the old labels are kept for the statefulset selector is immutable.
+func (dcgs *DisaggregatedComputeGroupsController) relabelCGResources(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) error {
+	var cgStatus *dv1.ComputeGroupStatus
+	for i := range ddc.Status.ComputeGroupStatuses {
+		if ddc.Status.ComputeGroupStatuses[i].UniqueId == cg.UniqueId {
+			cgStatus = &ddc.Status.ComputeGroupStatuses[i]
+			break
+		}
+	}
+	if cgStatus == nil || cgStatus.LabelSchemeVersion >= cgLabelSchemeVersion {
+		return nil
+	}
+
+	var st appv1.StatefulSet
+	if err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: ddc.Namespace, Name: ddc.GetCGStatefulsetName(cg)}, &st); apierrors.IsNotFound(err) {
+		//the statefulset not created, the resources will be created with current labels.
+		cgStatus.LabelSchemeVersion = cgLabelSchemeVersion
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	labels := dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId)
+	var pods corev1.PodList
+	if err := dcgs.K8sclient.List(ctx, &pods, client.InNamespace(ddc.Namespace)); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if !ownedByStatefulset(&pods.Items[i], st.Name) {
+			continue
+		}
+		if err := dcgs.addMissingLabels(ctx, &pods.Items[i], labels); err != nil {
+			return err
+		}
+	}
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := dcgs.K8sclient.List(ctx, &pvcs, client.InNamespace(ddc.Namespace)); err != nil {
+		return err
+	}
+	for i := range pvcs.Items {
+		if !claimedByStatefulset(pvcs.Items[i].Name, &st) {
+			continue
+		}
+		if err := dcgs.addMissingLabels(ctx, &pvcs.Items[i], labels); err != nil {
+			return err
+		}
+	}
+
+	klog.Infof("disaggregatedComputeGroupsController relabelCGResources namespace=%s name=%s compute group %s relabeled to scheme version %d.", ddc.Namespace, ddc.Name, cg.UniqueId, cgLabelSchemeVersion)
+	cgStatus.LabelSchemeVersion = cgLabelSchemeVersion
+	return nil
+}
+
+// addMissingLabels patch the labels that missing or have different value on object.
+func (dcgs *DisaggregatedComputeGroupsController) addMissingLabels(ctx context.Context, obj client.Object, labels map[string]string) error {
+	objLabels := obj.GetLabels()
+	missing := false
+	for k, v := range labels {
+		if objLabels[k] != v {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	for k, v := range labels {
+		objLabels[k] = v
+	}
+	obj.SetLabels(objLabels)
+	return dcgs.K8sclient.Patch(ctx, obj, patch)
+}
+
+// ownedByStatefulset return true when the pod is controlled by the statefulset.
+func ownedByStatefulset(pod *corev1.Pod, stsName string) bool {
+	for _, or := range pod.OwnerReferences {
+		if or.Kind == "StatefulSet" && or.Name == stsName {
+			return true
+		}
+	}
+	return false
+}
+
+// claimedByStatefulset return true when the pvc created by the volumeClaimTemplates of statefulset, the name of pvc is `{template}-{statefulset}-{ordinal}`.
+func claimedByStatefulset(pvcName string, st *appv1.StatefulSet) bool {
+	for _, vct := range st.Spec.VolumeClaimTemplates {
+		prefix := vct.Name + "-" + st.Name + "-"
+		if !strings.HasPrefix(pvcName, prefix) {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(pvcName, prefix)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_relabelCGResources(t *testing.T) {
+	oldLabels := map[string]string{"app.doris.disaggregated.cluster": "test", "old-scheme": "cg1"}
+	st := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"},
+		Spec: appv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "be-storage"}}},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1-0", Labels: oldLabels,
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "test-cg1", UID: "uid"}}}}
+	otherPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg10-0", Labels: map[string]string{"other": "true"},
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "test-cg10", UID: "uid10"}}}}
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg1-0", Labels: oldLabels}}
+	otherPVC := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "be-storage-test-cg1-data"}}
+
+	k8sclient := fake.NewClientBuilder().WithObjects(st, pod, otherPod, pvc, otherPVC).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ddc.Status.ComputeGroupStatuses = []dv1.ComputeGroupStatus{{UniqueId: "cg1"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	ctx := context.Background()
+
+	if err := dcgs.relabelCGResources(ctx, ddc, cg); err != nil {
+		t.Fatalf("relabelCGResources failed, err=%s", err.Error())
+	}
+	if ddc.Status.ComputeGroupStatuses[0].LabelSchemeVersion != cgLabelSchemeVersion {
+		t.Errorf("relabelCGResources expected label scheme version %d, got %d", cgLabelSchemeVersion, ddc.Status.ComputeGroupStatuses[0].LabelSchemeVersion)
+	}
+
+	selector := dcgs.newCGPodsSelector("test", "cg1")
+	pods, _ := k8s.GetPods(ctx, k8sclient, "default", selector)
+	if len(pods.Items) != 1 || pods.Items[0].Name != "test-cg1-0" || pods.Items[0].Labels["old-scheme"] != "cg1" {
+		t.Errorf("relabelCGResources expected only the pod of statefulset relabeled and old labels kept, got %+v", pods.Items)
+	}
+	var rpvc corev1.PersistentVolumeClaim
+	_ = k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "be-storage-test-cg1-0"}, &rpvc)
+	if rpvc.Labels[dv1.DorisDisaggregatedComputeGroupUniqueId] != "cg1" {
+		t.Errorf("relabelCGResources expected pvc relabeled, got labels %v", rpvc.Labels)
+	}
+	var rOtherPVC corev1.PersistentVolumeClaim
+	_ = k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "be-storage-test-cg1-data"}, &rOtherPVC)
+	if len(rOtherPVC.Labels) != 0 {
+		t.Errorf("relabelCGResources expected the pvc not claimed by statefulset not relabeled, got labels %v", rOtherPVC.Labels)
+	}
+}
@@ -0,0 +1,157 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// removeComputeGroup clean the compute group that removed from spec step by step:
+// 1. clear backends in fe, decommission them first when enableDecommission, and confirm no backend left.
+// 2. delete the statefulset, service and merged configmap.
+// 3. delete all pvcs of compute group and confirm them gone, the pvcs are kept when the cluster retains the pvcs of removed compute groups.
+// return true only when all resources cleaned, the status of compute group should be kept until then.
+func (dcgs *DisaggregatedComputeGroupsController) removeComputeGroup(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus) (bool, error) {
+	cgs.Phase = dv1.Removing
+	cleared, err := dcgs.clearCGBackends(ctx, ddc, cgs)
+	if err != nil || !cleared {
+		return false, err
+	}
+
+	if err := k8s.DeleteStatefulset(ctx, dcgs.K8sclient, ddc.Namespace, cgs.StatefulsetName); err != nil {
+		klog.Errorf("DisaggregatedComputeGroupsController removeComputeGroup delete statefulset namespace=%s, name=%s failed, err=%s", ddc.Namespace, cgs.StatefulsetName, err.Error())
+		return false, err
+	}
+	if err := k8s.DeleteService(ctx, dcgs.K8sclient, ddc.Namespace, cgs.ServiceName); err != nil {
+		klog.Errorf("DisaggregatedComputeGroupsController removeComputeGroup delete service namespace=%s, name=%s failed, err=%s", ddc.Namespace, cgs.ServiceName, err.Error())
+		return false, err
+	}
+
+	if cgs.MergedConfigMapName != "" {
+		if err := dcgs.deleteMergedConfigMap(ctx, ddc.Namespace, cgs.MergedConfigMapName); err != nil {
+			klog.Errorf("DisaggregatedComputeGroupsController removeComputeGroup delete merged configmap namespace=%s, name=%s failed, err=%s", ddc.Namespace, cgs.MergedConfigMapName, err.Error())
+			return false, err
+		}
+	}
+
+	if ddc.Spec.RetainRemovedComputeGroupPVCs {
+		klog.Infof("DisaggregatedComputeGroupsController removeComputeGroup namespace=%s, ddc name=%s, compute group %s pvcs retained.", ddc.Namespace, ddc.Name, cgs.UniqueId)
+	} else {
+		remaining, err := dcgs.clearCGPVCs(ctx, ddc, cgs.UniqueId)
+		cgs.RemainingPVCs = remaining
+		if err != nil {
+			return false, err
+		}
+		if len(remaining) != 0 {
+			klog.Infof("DisaggregatedComputeGroupsController removeComputeGroup namespace=%s, ddc name=%s, compute group %s wait pvcs %v deleted.", ddc.Namespace, ddc.Name, cgs.UniqueId, remaining)
+			return false, nil
+		}
+	}
+
+	klog.Infof("DisaggregatedComputeGroupsController removeComputeGroup namespace=%s, ddc name=%s, compute group %s removed.", ddc.Namespace, ddc.Name, cgs.UniqueId)
+	return true, nil
+}
+
+// clearCGBackends remove the backends of compute group from fe, return true when fe have not any backend of compute group.
+func (dcgs *DisaggregatedComputeGroupsController) clearCGBackends(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus) (bool, error) {
+	//the compute group never registered in fe.
+	if cgs.ComputeGroupId == "" {
+		return true, nil
+	}
+	if ddc.IsFEMetadataUnhealthy() {
+		klog.Infof("DisaggregatedComputeGroupsController clearCGBackends namespace=%s, ddc name=%s, compute group %s wait fe metadata recovered.", ddc.Namespace, ddc.Name, cgs.UniqueId)
+		return false, nil
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return false, err
+	}
+	defer sqlClient.Close()
+
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgs.ComputeGroupId)
+	if err != nil {
+		return false, err
+	}
+	if len(backends) == 0 {
+		return true, nil
+	}
+
+	if ddc.Spec.EnableDecommission {
+		var undecommissioned []*mysql.Backend
+		decommissioning := false
+		for _, backend := range backends {
+			if !backend.SystemDecommissioned {
+				undecommissioned = append(undecommissioned, backend)
+			} else if backend.TabletNum != 0 {
+				decommissioning = true
+			}
+		}
+
+		if len(undecommissioned) != 0 {
+			return false, sqlClient.DecommissionBE(undecommissioned)
+		}
+		if decommissioning {
+			klog.Infof("DisaggregatedComputeGroupsController clearCGBackends namespace=%s, ddc name=%s, compute group %s is decommissioning.", ddc.Namespace, ddc.Name, cgs.UniqueId)
+			return false, nil
+		}
+	}
+
+	if err = dcgs.dropBackends(ddc, sqlClient, cgs.UniqueId, backends); err != nil {
+		return false, err
+	}
+
+	//confirm the backends dropped.
+	backends, err = sqlClient.GetBackendsByComputeGroupId(cgs.ComputeGroupId)
+	if err != nil {
+		return false, err
+	}
+	return len(backends) == 0, nil
+}
+
+// clearCGPVCs delete all pvcs of the compute group, return the pvcs still exist. the deleted pvcs exist until the pods released them(pvc-protection),
+// so the pvcs are confirmed gone only when none listed in the later reconcile.
+func (dcgs *DisaggregatedComputeGroupsController) clearCGPVCs(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, uniqueId string) ([]string, error) {
+	pvcLabels := dcgs.newCGPodsSelector(ddc.Name, uniqueId)
+	pvcs := corev1.PersistentVolumeClaimList{}
+	if err := dcgs.K8sclient.List(ctx, &pvcs, client.InNamespace(ddc.Namespace), client.MatchingLabels(pvcLabels)); err != nil {
+		return nil, err
+	}
+
+	var mergeError error
+	var remaining []string
+	for _, pvc := range pvcs.Items {
+		remaining = append(remaining, pvc.Name)
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := k8s.DeletePVC(ctx, dcgs.K8sclient, ddc.Namespace, pvc.Name, pvcLabels); err != nil {
+			klog.Errorf("DisaggregatedComputeGroupsController clearCGPVCs namespace=%s delete pvc %s failed, err=%s", ddc.Namespace, pvc.Name, err.Error())
+			mergeError = utils.MergeError(mergeError, err)
+		}
+	}
+	return remaining, mergeError
+}
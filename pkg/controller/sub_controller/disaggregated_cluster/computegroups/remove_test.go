@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_removeComputeGroup(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	labels := dcgs.newCGPodsSelector(ddc.Name, "cg1")
+	objs := []client.Object{
+		&appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-test-cg1-0", Labels: labels}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-test-cg1-1", Labels: labels}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-test-cg2-0", Labels: dcgs.newCGPodsSelector(ddc.Name, "cg2")}},
+	}
+	k8sclient := fake.NewClientBuilder().WithObjects(objs...).Build()
+	dcgs.DisaggregatedSubDefaultController = sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}
+
+	//the compute group not registered in fe, not need to clear backends.
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg1", ServiceName: "test-cg1"}
+	cleared, err := dcgs.removeComputeGroup(context.Background(), ddc, cgs)
+	if err != nil || cleared || len(cgs.RemainingPVCs) != 2 {
+		t.Fatalf("removeComputeGroup expected not cleared before the pvcs confirmed gone, cleared=%t, remaining=%v, err=%v", cleared, cgs.RemainingPVCs, err)
+	}
+	cleared, err = dcgs.removeComputeGroup(context.Background(), ddc, cgs)
+	if err != nil || !cleared || len(cgs.RemainingPVCs) != 0 {
+		t.Fatalf("removeComputeGroup expected cleared, cleared=%t, remaining=%v, err=%v", cleared, cgs.RemainingPVCs, err)
+	}
+	if cgs.Phase != dv1.Removing {
+		t.Errorf("removeComputeGroup expected phase Removing, got %s", cgs.Phase)
+	}
+
+	var stss appv1.StatefulSetList
+	var svcs corev1.ServiceList
+	var pvcs corev1.PersistentVolumeClaimList
+	_ = k8sclient.List(context.Background(), &stss)
+	_ = k8sclient.List(context.Background(), &svcs)
+	_ = k8sclient.List(context.Background(), &pvcs)
+	if len(stss.Items) != 0 || len(svcs.Items) != 0 {
+		t.Errorf("removeComputeGroup expected statefulset and service deleted, statefulsets %d, services %d", len(stss.Items), len(svcs.Items))
+	}
+	if len(pvcs.Items) != 1 || pvcs.Items[0].Name != "data-test-cg2-0" {
+		t.Errorf("removeComputeGroup expected only pvcs of cg1 deleted, left %d pvcs", len(pvcs.Items))
+	}
+}
+
+func Test_removeComputeGroup_retainPVCs(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{RetainRemovedComputeGroupPVCs: true},
+	}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-test-cg1-0", Labels: dcgs.newCGPodsSelector(ddc.Name, "cg1")}}
+	k8sclient := fake.NewClientBuilder().WithObjects(pvc).Build()
+	dcgs.DisaggregatedSubDefaultController = sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}
+
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", StatefulsetName: "test-cg1", ServiceName: "test-cg1"}
+	cleared, err := dcgs.removeComputeGroup(context.Background(), ddc, cgs)
+	if err != nil || !cleared {
+		t.Fatalf("removeComputeGroup expected cleared with pvcs retained, cleared=%t, err=%v", cleared, err)
+	}
+	var pvcs corev1.PersistentVolumeClaimList
+	_ = k8sclient.List(context.Background(), &pvcs)
+	if len(pvcs.Items) != 1 {
+		t.Errorf("removeComputeGroup expected pvcs retained, left %d pvcs", len(pvcs.Items))
+	}
+}
@@ -0,0 +1,189 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// resolveReplicaRange resolve the replicaRange of compute group to the effective replicas, the result is set to cg.Replicas.
+// the replicas reduced toward min when pods unschedulable, and scale back toward preferred by the capacity of nodes that compute group can be scheduled to.
+func (dcgs *DisaggregatedComputeGroupsController) resolveReplicaRange(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) (*sc.Event, error) {
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	rr := cg.ReplicaRange
+	if rr == nil {
+		if cgStatus != nil {
+			cgStatus.ReplicaRange = nil
+		}
+		return nil, nil
+	}
+	if cg.NodePoolReplicas != nil || cg.ExternalMetricReplicas != nil {
+		msg := fmt.Sprintf("compute group %s configured replicaRange with nodePoolReplicas or externalMetricReplicas, please use one of them.", cg.UniqueId)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGReplicaRangeInvalid, Message: msg}, errors.New(msg)
+	}
+	if rr.Preferred <= 0 || rr.Min < 0 || rr.Min > rr.Preferred {
+		msg := fmt.Sprintf("compute group %s replicaRange should have positive preferred and min not greater than preferred.", cg.UniqueId)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGReplicaRangeInvalid, Message: msg}, errors.New(msg)
+	}
+
+	//the status not initialized in the first reconcile, start with preferred.
+	current := rr.Preferred
+	if cgStatus != nil && cgStatus.ReplicaRange != nil {
+		current = cgStatus.ReplicaRange.EffectiveReplicas
+	}
+
+	pods, err := k8s.GetPods(ctx, dcgs.K8sclient, ddc.Namespace, dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId))
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGApplyResourceFailed, Message: err.Error()}, err
+	}
+	unschedulable := countUnschedulablePods(pods.Items)
+	var fits int32
+	if unschedulable == 0 && current < rr.Preferred {
+		if fits, err = dcgs.podsFitNodes(ctx, cg); err != nil {
+			//keep the replicas when the capacity unknown.
+			klog.Errorf("disaggregatedComputeGroupsController resolveReplicaRange namespace %s name %s compute group %s get the capacity of nodes failed, err=%s", ddc.Namespace, ddc.Name, cg.UniqueId, err.Error())
+		}
+	}
+
+	replicas, reason := computeRangeReplicas(rr, current, unschedulable, fits)
+	if replicas < current {
+		msg := fmt.Sprintf("compute group %s replicas reduced from %d to %d, %s", cg.UniqueId, current, replicas, reason)
+		klog.Infof("disaggregatedComputeGroupsController resolveReplicaRange namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGReplicasReduced), msg)
+	} else if replicas > current {
+		msg := fmt.Sprintf("compute group %s replicas scaled back from %d to %d of preferred %d by the capacity of nodes.", cg.UniqueId, current, replicas, rr.Preferred)
+		klog.Infof("disaggregatedComputeGroupsController resolveReplicaRange namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGReplicasRestored), msg)
+	}
+	cg.Replicas = &replicas
+	if cgStatus != nil {
+		cgStatus.ReplicaRange = &dv1.ReplicaRangeStatus{EffectiveReplicas: replicas, Reason: reason}
+	}
+	return nil, nil
+}
+
+// computeRangeReplicas return the effective replicas clamped by min and preferred, and the reason when it is less than preferred.
+// the unschedulable pods are reduced, the reduced replicas scale back by the number of pods that the nodes have capacity for.
+func computeRangeReplicas(rr *dv1.ReplicaRange, current, unschedulable, fits int32) (int32, string) {
+	replicas := current
+	if unschedulable > 0 {
+		replicas = current - unschedulable
+	} else if current < rr.Preferred {
+		replicas = current + fits
+	}
+	if replicas < rr.Min {
+		replicas = rr.Min
+	}
+	if replicas > rr.Preferred {
+		replicas = rr.Preferred
+	}
+
+	switch {
+	case replicas >= rr.Preferred:
+		return replicas, ""
+	case unschedulable > 0:
+		return replicas, fmt.Sprintf("%d pods unschedulable for insufficient capacity of nodes.", unschedulable)
+	default:
+		return replicas, fmt.Sprintf("the nodes have capacity for %d more pods, waiting the capacity to scale back to preferred %d.", fits, rr.Preferred)
+	}
+}
+
+// countUnschedulablePods return the number of pending pods that scheduler failed to find a node for.
+func countUnschedulablePods(pods []corev1.Pod) int32 {
+	var count int32
+	for i := range pods {
+		if pods[i].Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, c := range pods[i].Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// podsFitNodes return the number of compute group pods that the free cpu and memory of schedulable nodes can hold, the free is the allocatable minus the requests of running pods.
+// it is an estimation ignoring the taints and affinities, not limited when the compute group not requests cpu or memory.
+func (dcgs *DisaggregatedComputeGroupsController) podsFitNodes(ctx context.Context, cg *dv1.ComputeGroup) (int32, error) {
+	requests := map[corev1.ResourceName]int64{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if q, ok := cg.Requests[name]; ok && q.MilliValue() > 0 {
+			requests[name] = q.MilliValue()
+		}
+	}
+	if len(requests) == 0 {
+		return cg.ReplicaRange.Preferred, nil
+	}
+
+	nodes, err := k8s.GetNodes(ctx, dcgs.K8sclient, cg.NodeSelector)
+	if err != nil {
+		return 0, err
+	}
+	var pods corev1.PodList
+	if err := dcgs.K8sclient.List(ctx, &pods); err != nil {
+		return 0, err
+	}
+	used := map[string]map[corev1.ResourceName]int64{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if used[pod.Spec.NodeName] == nil {
+			used[pod.Spec.NodeName] = map[corev1.ResourceName]int64{}
+		}
+		for _, c := range pod.Spec.Containers {
+			for name := range requests {
+				if q, ok := c.Resources.Requests[name]; ok {
+					used[pod.Spec.NodeName][name] += q.MilliValue()
+				}
+			}
+		}
+	}
+
+	var fits int32
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !k8s.NodeIsSchedulable(node) {
+			continue
+		}
+		n := int64(-1)
+		for name, request := range requests {
+			allocatable := node.Status.Allocatable[name]
+			free := allocatable.MilliValue() - used[node.Name][name]
+			if m := free / request; n == -1 || m < n {
+				n = m
+			}
+		}
+		if n > 0 {
+			fits += int32(n)
+		}
+	}
+	return fits, nil
+}
@@ -0,0 +1,81 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_computeRangeReplicas(t *testing.T) {
+	rr := &dv1.ReplicaRange{Preferred: 5, Min: 2}
+	tests := []struct {
+		current, unschedulable, fits int32
+		expect                       int32
+		reduced                      bool
+	}{
+		{current: 5, expect: 5},
+		{current: 5, unschedulable: 2, expect: 3, reduced: true},
+		{current: 5, unschedulable: 4, expect: 2, reduced: true},
+		{current: 2, fits: 1, expect: 3, reduced: true},
+		{current: 2, fits: 10, expect: 5},
+		{current: 8, expect: 5},
+	}
+	for i, test := range tests {
+		replicas, reason := computeRangeReplicas(rr, test.current, test.unschedulable, test.fits)
+		if replicas != test.expect || (reason != "") != test.reduced {
+			t.Errorf("computeRangeReplicas case %d expected %d reduced %t, got %d reason %q", i, test.expect, test.reduced, replicas, reason)
+		}
+	}
+}
+
+func Test_podsFitNodes(t *testing.T) {
+	ready := []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	allocatable := corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("8"), corev1.ResourceMemory: apiresource.MustParse("32Gi")}
+	node1 := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, Status: corev1.NodeStatus{Conditions: ready, Allocatable: allocatable}}
+	node2 := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}, Status: corev1.NodeStatus{Conditions: ready, Allocatable: allocatable}}
+	cordoned := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node3"}, Spec: corev1.NodeSpec{Unschedulable: true}, Status: corev1.NodeStatus{Conditions: ready, Allocatable: allocatable}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-0"},
+		Spec: corev1.PodSpec{NodeName: "node1", Containers: []corev1.Container{{Name: "c", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("5")}}}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+
+	k8sclient := fake.NewClientBuilder().WithObjects(node1, node2, cordoned, pod).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", ReplicaRange: &dv1.ReplicaRange{Preferred: 5, Min: 1}}
+	cg.Requests = corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("2"), corev1.ResourceMemory: apiresource.MustParse("8Gi")}
+
+	//node1 fits 1 by cpu, node2 fits 4 by cpu and memory, the cordoned node not counted.
+	fits, err := dcgs.podsFitNodes(context.Background(), cg)
+	if err != nil || fits != 5 {
+		t.Errorf("podsFitNodes expected 5, got %d err=%v", fits, err)
+	}
+
+	pending := corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending, Conditions: []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}}}}
+	if n := countUnschedulablePods([]corev1.Pod{pending, *pod}); n != 1 {
+		t.Errorf("countUnschedulablePods expected 1, got %d", n)
+	}
+}
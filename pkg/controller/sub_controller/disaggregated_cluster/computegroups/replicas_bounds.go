@@ -0,0 +1,80 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// replicasBounds return the min and max replicas of the autoscaling configured in compute group, and the field name of the autoscaling.
+// the min defaults to 1 as the resolving, the max is nil when not limited. return empty name when compute group not autoscaled by bounds.
+func replicasBounds(cg *dv1.ComputeGroup) (int32, *int32, string) {
+	var min, max *int32
+	var name string
+	switch {
+	case cg.NodePoolReplicas != nil:
+		min, max, name = cg.NodePoolReplicas.MinReplicas, cg.NodePoolReplicas.MaxReplicas, "nodePoolReplicas"
+	case cg.ExternalMetricReplicas != nil:
+		min, max, name = cg.ExternalMetricReplicas.MinReplicas, cg.ExternalMetricReplicas.MaxReplicas, "externalMetricReplicas"
+	default:
+		return 0, nil, ""
+	}
+	lower := int32(1)
+	if min != nil {
+		lower = *min
+	}
+	return lower, max, name
+}
+
+// validateReplicasBounds check the min replicas of autoscaling not greater than the max, the inconsistent bounds can not resolve a replicas.
+func (dcgs *DisaggregatedComputeGroupsController) validateReplicasBounds(cgs []dv1.ComputeGroup) string {
+	for i := range cgs {
+		min, max, name := replicasBounds(&cgs[i])
+		if name != "" && max != nil && min > *max {
+			return fmt.Sprintf("compute group %s %s minReplicas %d is greater than maxReplicas %d.", cgs[i].UniqueId, name, min, *max)
+		}
+	}
+	return ""
+}
+
+// clampReplicasToBounds clamp the replicas manually set in spec into the bounds of autoscaling, emit warning event when clamped.
+// the replicas used when the autoscaling not resolved(ep: reading metric failed), the replicas out of bounds is reversed by the next resolving and makes the compute group flapping.
+func (dcgs *DisaggregatedComputeGroupsController) clampReplicasToBounds(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) {
+	min, max, name := replicasBounds(cg)
+	if name == "" || cg.Replicas == nil {
+		return
+	}
+	replicas := *cg.Replicas
+	if replicas < min {
+		replicas = min
+	}
+	if max != nil && replicas > *max {
+		replicas = *max
+	}
+	if replicas == *cg.Replicas {
+		return
+	}
+	msg := fmt.Sprintf("compute group %s replicas %d is out of the bounds of %s, clamped to %d. please change the minReplicas or maxReplicas of %s instead of replicas.", cg.UniqueId, *cg.Replicas, name, replicas, name)
+	klog.Infof("disaggregatedComputeGroupsController clampReplicasToBounds namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGReplicasOutOfBounds), msg)
+	cg.Replicas = &replicas
+}
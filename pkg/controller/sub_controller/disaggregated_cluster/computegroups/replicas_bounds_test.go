@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_validateReplicasBounds(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	tests := []struct {
+		name  string
+		cgs   []dv1.ComputeGroup
+		valid bool
+	}{
+		{name: "not autoscaled", cgs: []dv1.ComputeGroup{{UniqueId: "cg1"}}, valid: true},
+		{name: "valid", cgs: []dv1.ComputeGroup{{UniqueId: "cg1", NodePoolReplicas: &dv1.NodePoolReplicas{Percentage: 50, MinReplicas: resource.GetInt32Pointer(2), MaxReplicas: resource.GetInt32Pointer(4)}}}, valid: true},
+		{name: "default min greater than max", cgs: []dv1.ComputeGroup{{UniqueId: "cg1", ExternalMetricReplicas: &dv1.ExternalMetricReplicas{MetricName: "lag", MaxReplicas: resource.GetInt32Pointer(0)}}}, valid: false},
+		{name: "min greater than max", cgs: []dv1.ComputeGroup{{UniqueId: "cg1", ExternalMetricReplicas: &dv1.ExternalMetricReplicas{MetricName: "lag", MinReplicas: resource.GetInt32Pointer(5), MaxReplicas: resource.GetInt32Pointer(3)}}}, valid: false},
+	}
+	for _, test := range tests {
+		if msg := dcgs.validateReplicasBounds(test.cgs); (msg == "") != test.valid {
+			t.Errorf("validateReplicasBounds %s expect valid %t, got message %q", test.name, test.valid, msg)
+		}
+	}
+}
+
+func Test_clampReplicasToBounds(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	emr := &dv1.ExternalMetricReplicas{MetricName: "lag", MinReplicas: resource.GetInt32Pointer(3), MaxReplicas: resource.GetInt32Pointer(6)}
+
+	tests := []struct {
+		replicas int32
+		expect   int32
+		event    bool
+	}{
+		{replicas: 1, expect: 3, event: true},
+		{replicas: 4, expect: 4, event: false},
+		{replicas: 8, expect: 6, event: true},
+	}
+	for _, test := range tests {
+		cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(test.replicas)}, ExternalMetricReplicas: emr}
+		dcgs.clampReplicasToBounds(ddc, cg)
+		if *cg.Replicas != test.expect {
+			t.Errorf("clampReplicasToBounds replicas %d expect %d, got %d", test.replicas, test.expect, *cg.Replicas)
+		}
+		if event := len(recorder.Events) != 0; event != test.event {
+			t.Errorf("clampReplicasToBounds replicas %d expect event %t, got %t", test.replicas, test.event, event)
+		}
+		if test.event {
+			<-recorder.Events
+		}
+	}
+
+	//not autoscaled, the replicas kept.
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Replicas: resource.GetInt32Pointer(0)}}
+	dcgs.clampReplicasToBounds(ddc, cg)
+	if *cg.Replicas != 0 {
+		t.Errorf("clampReplicasToBounds not autoscaled expect replicas 0, got %d", *cg.Replicas)
+	}
+}
@@ -0,0 +1,111 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// reregisterAnnotatedBackends add the running pods of compute group that have no backend in fe as backends when annotated `doris.disaggregated.cluster/reregister-backends-{uniqueId}` on cluster.
+// the fe metadata restored from backup lost the backends added after the backup, the added backends are confirmed in next reconcile, the annotation removed after all pods registered.
+func (dcgs *DisaggregatedComputeGroupsController) reregisterAnnotatedBackends(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cvs map[string]interface{}) (*sc.Event, error) {
+	key := strings.ToLower(fmt.Sprintf(dv1.ReregisterBackends, cg.UniqueId))
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	if _, ok := ddc.Annotations[key]; !ok {
+		if cgStatus != nil {
+			cgStatus.ReregisteringBackends = nil
+		}
+		return nil, nil
+	}
+	if cgStatus == nil || ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		klog.Infof("disaggregatedComputeGroupsController reregisterAnnotatedBackends namespace %s name %s compute group %s fe not available for registering backends, wait next reconcile.", ddc.Namespace, ddc.Name, cg.UniqueId)
+		return nil, nil
+	}
+
+	pods, err := k8s.GetPods(ctx, dcgs.K8sclient, ddc.Namespace, dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId))
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGApplyResourceFailed, Message: err.Error()}, err
+	}
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.ShowBackends()
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+
+	adds, podNames := unregisteredBackends(pods.Items, backends, ddc.GetCGServiceName(cg), ddc.Namespace, int(resource.GetPort(cvs, resource.HEARTBEAT_SERVICE_PORT)))
+	if len(adds) == 0 {
+		if err := dcgs.removeClusterAnnotation(ctx, ddc, key); err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController reregisterAnnotatedBackends remove annotation %s namespace=%s name=%s failed, err=%s", key, ddc.Namespace, ddc.Name, err.Error())
+			return nil, nil
+		}
+		msg := fmt.Sprintf("compute group %s all running pods registered as backends in fe.", cg.UniqueId)
+		klog.Infof("disaggregatedComputeGroupsController reregisterAnnotatedBackends namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGBackendsReregistered), msg)
+		cgStatus.ReregisteringBackends = nil
+		return nil, nil
+	}
+
+	if err := sqlClient.AddBE(adds, ddc.GetCGName(cg)); err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	cgStatus.ReregisteringBackends = podNames
+	msg := fmt.Sprintf("compute group %s pods %s have no backend in fe, added as backends by annotation %s.", cg.UniqueId, strings.Join(podNames, ","), key)
+	klog.Infof("disaggregatedComputeGroupsController reregisterAnnotatedBackends namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGBackendsReregistering), msg)
+	return nil, nil
+}
+
+// unregisteredBackends return the backends should be added for the running pods that have no backend in fe, and the names of the pods.
+// the host of backend is the fqdn of pod, use the domain of registered backends for the cluster domain may not be the default `cluster.local`.
+func unregisteredBackends(pods []corev1.Pod, backends []*mysql.Backend, serviceName, namespace string, heartbeatPort int) ([]*mysql.Backend, []string) {
+	domain := ".svc.cluster.local"
+	for _, be := range backends {
+		if i := strings.Index(be.Host, ".svc."); i != -1 {
+			domain = be.Host[i:]
+			break
+		}
+	}
+
+	var adds []*mysql.Backend
+	var podNames []string
+	for i := range pods {
+		pod := &pods[i]
+		//the pods of temporary statefulset in cutover are registered by themselves.
+		if pod.Labels[dv1.DorisDisaggregatedCutover] != "" || pod.DeletionTimestamp != nil ||
+			pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || findPodBackend(backends, pod.Name) != nil {
+			continue
+		}
+		adds = append(adds, &mysql.Backend{Host: pod.Name + "." + serviceName + "." + namespace + domain, HeartbeatPort: heartbeatPort})
+		podNames = append(podNames, pod.Name)
+	}
+	return adds, podNames
+}
@@ -0,0 +1,48 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_unregisteredBackends(t *testing.T) {
+	running := corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-0"}, Status: running},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-1"}, Status: running},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-2"}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-cutover-0", Labels: map[string]string{dv1.DorisDisaggregatedCutover: "true"}}, Status: running},
+	}
+	backends := []*mysql.Backend{{Host: "test-cg2-0.test-cg2.default.svc.example.com", HeartbeatPort: 9050}, {Host: "test-cg1-0.test-cg1.default.svc.example.com", HeartbeatPort: 9050}}
+
+	adds, podNames := unregisteredBackends(pods, backends, "test-cg1", "default", 9050)
+	if len(adds) != 1 || adds[0].Host != "test-cg1-1.test-cg1.default.svc.example.com" || adds[0].HeartbeatPort != 9050 || podNames[0] != "test-cg1-1" {
+		t.Errorf("unregisteredBackends expected only the running pod test-cg1-1 added with the domain of registered backends, got %v %v", adds, podNames)
+	}
+
+	adds, _ = unregisteredBackends(pods, nil, "test-cg1", "default", 9050)
+	if len(adds) != 2 || adds[0].Host != "test-cg1-0.test-cg1.default.svc.cluster.local" {
+		t.Errorf("unregisteredBackends expected the default domain when no backends registered, got %v", adds)
+	}
+}
@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/hash"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// the image of placeholder container, it only sleeps.
+const defaultReservationImage = "registry.k8s.io/pause:3.9"
+
+func reservationDeploymentName(stsName string) string {
+	return stsName + "-reservation"
+}
+
+// reconcileCapacityReservation apply the deployment of placeholder pods that reserve capacity for compute group, the placeholders scheduled as the pods of compute group and request the resources of one be.
+// the deployment of compute group not configured capacityReservation is deleted in ClearResources.
+func (dcgs *DisaggregatedComputeGroupsController) reconcileCapacityReservation(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, st *appv1.StatefulSet) (*sc.Event, error) {
+	cr := cg.CapacityReservation
+	if cr == nil {
+		return nil, nil
+	}
+	if cr.PriorityClassName == "" {
+		msg := fmt.Sprintf("compute group %s capacityReservation should have priorityClassName lower than the pods of compute group, the placeholders without it are not preempted.", cg.UniqueId)
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGCapacityReservationFailed, Message: msg}, errors.New(msg)
+	}
+
+	deploy := dcgs.newReservationDeployment(ddc, cg, st)
+	var edeploy appv1.Deployment
+	err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: deploy.Namespace, Name: deploy.Name}, &edeploy)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGCapacityReservationFailed, Message: err.Error()}, err
+	}
+	if apierrors.IsNotFound(err) {
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s compute group %s create capacity reservation of %d placeholders.", ddc.Namespace, ddc.Name, cg.UniqueId, cr.Replicas)
+		err = k8s.CreateClientObject(ctx, dcgs.K8sclient, deploy)
+	} else if edeploy.Annotations[dv1.DisaggregatedSpecHashValueAnnotation] != deploy.Annotations[dv1.DisaggregatedSpecHashValueAnnotation] {
+		edeploy.Labels = deploy.Labels
+		edeploy.Annotations = deploy.Annotations
+		edeploy.Spec = deploy.Spec
+		err = k8s.UpdateClientObject(ctx, dcgs.K8sclient, &edeploy)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("compute group %s apply capacity reservation failed, err=%s", cg.UniqueId, err.Error())
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGCapacityReservationFailed, Message: msg}, err
+	}
+	return nil, nil
+}
+
+// newReservationDeployment build the deployment of placeholder pods, the placeholders use the scheduling constraints and resources of be, the labels of pods not selected as the pods of compute group.
+func (dcgs *DisaggregatedComputeGroupsController) newReservationDeployment(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, st *appv1.StatefulSet) *appv1.Deployment {
+	cr := cg.CapacityReservation
+	image := cr.Image
+	if image == "" {
+		image = defaultReservationImage
+	}
+	var resources corev1.ResourceRequirements
+	for _, c := range st.Spec.Template.Spec.Containers {
+		if c.Name == resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME {
+			resources = c.Resources
+		}
+	}
+
+	podLabels := map[string]string{dv1.DorisDisaggregatedCapacityReservation: st.Name}
+	replicas := cr.Replicas
+	var grace int64
+	tpl := &st.Spec.Template.Spec
+	deploy := &appv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       ddc.Namespace,
+			Name:            reservationDeploymentName(st.Name),
+			Labels:          dcgs.newCG2LayerSchedulerLabels(ddc.Name, cg.UniqueId),
+			OwnerReferences: []metav1.OwnerReference{resource.GetOwnerReference(ddc)},
+		},
+		Spec: appv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					PriorityClassName:             cr.PriorityClassName,
+					TerminationGracePeriodSeconds: &grace,
+					NodeSelector:                  tpl.NodeSelector,
+					Affinity:                      tpl.Affinity,
+					Tolerations:                   tpl.Tolerations,
+					ImagePullSecrets:              tpl.ImagePullSecrets,
+					Containers: []corev1.Container{{
+						Name:      "reservation",
+						Image:     image,
+						Resources: resources,
+					}},
+				},
+			},
+		},
+	}
+	deploy.Annotations = map[string]string{dv1.DisaggregatedSpecHashValueAnnotation: hash.HashObject(deploy.Spec)}
+	return deploy
+}
+
+// clearCapacityReservations delete the deployments of placeholder pods that the compute group removed or not configured capacityReservation.
+func (dcgs *DisaggregatedComputeGroupsController) clearCapacityReservations(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) error {
+	deploys, err := k8s.ListDeploymentsInNamespace(ctx, dcgs.K8sclient, ddc.Namespace, dcgs.GetCG2LayerCommonSchedulerLabels(ddc.Name))
+	if err != nil {
+		return err
+	}
+	for i := range deploys {
+		if !ownerReference2ddc(&deploys[i], ddc) {
+			continue
+		}
+		cg := findCG(ddc, getUniqueIdFromClientObject(&deploys[i]))
+		if cg != nil && cg.CapacityReservation != nil {
+			continue
+		}
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s delete capacity reservation %s.", ddc.Namespace, ddc.Name, deploys[i].Name)
+		if err := k8s.DeleteClientObject(ctx, dcgs.K8sclient, &deploys[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
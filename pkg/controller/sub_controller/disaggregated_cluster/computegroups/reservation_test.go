@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reconcileCapacityReservation(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", UID: "uid"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CapacityReservation: &dv1.CapacityReservation{Replicas: 2}}
+	ddc.Spec.ComputeGroups = []dv1.ComputeGroup{*cg}
+	resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("8")}}
+	st := &appv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"},
+		Spec: appv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"pool": "compute"},
+			Containers:   []corev1.Container{{Name: resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, Resources: resources}},
+		}}},
+	}
+	k8sclient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(10)}}
+	ctx := context.Background()
+
+	if _, err := dcgs.reconcileCapacityReservation(ctx, ddc, cg, st); err == nil {
+		t.Errorf("reconcileCapacityReservation expected error without priorityClassName")
+	}
+
+	cg.CapacityReservation.PriorityClassName = "reservation"
+	if _, err := dcgs.reconcileCapacityReservation(ctx, ddc, cg, st); err != nil {
+		t.Fatalf("reconcileCapacityReservation failed, err=%s", err.Error())
+	}
+	var deploy appv1.Deployment
+	if err := k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1-reservation"}, &deploy); err != nil {
+		t.Fatalf("reconcileCapacityReservation expected deployment created, err=%s", err.Error())
+	}
+	pod := deploy.Spec.Template
+	if *deploy.Spec.Replicas != 2 || pod.Spec.PriorityClassName != "reservation" || pod.Spec.NodeSelector["pool"] != "compute" ||
+		pod.Spec.Containers[0].Image != defaultReservationImage || !pod.Spec.Containers[0].Resources.Requests.Cpu().Equal(apiresource.MustParse("8")) {
+		t.Errorf("reconcileCapacityReservation deployment not expected, got %+v", deploy.Spec)
+	}
+	if _, ok := pod.Labels[dv1.DorisDisaggregatedComputeGroupUniqueId]; ok {
+		t.Errorf("reconcileCapacityReservation expected the placeholders not labeled as the pods of compute group, got %v", pod.Labels)
+	}
+
+	cg.CapacityReservation.Replicas = 3
+	if _, err := dcgs.reconcileCapacityReservation(ctx, ddc, cg, st); err != nil {
+		t.Fatalf("reconcileCapacityReservation update failed, err=%s", err.Error())
+	}
+	if err := k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1-reservation"}, &deploy); err != nil || *deploy.Spec.Replicas != 3 {
+		t.Errorf("reconcileCapacityReservation expected deployment updated to 3 replicas, err=%v", err)
+	}
+
+	ddc.Spec.ComputeGroups[0].CapacityReservation = nil
+	if err := dcgs.clearCapacityReservations(ctx, ddc); err != nil {
+		t.Fatalf("clearCapacityReservations failed, err=%s", err.Error())
+	}
+	if err := k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1-reservation"}, &deploy); !apierrors.IsNotFound(err) {
+		t.Errorf("clearCapacityReservations expected deployment deleted, err=%v", err)
+	}
+}
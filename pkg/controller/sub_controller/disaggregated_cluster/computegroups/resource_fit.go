@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// validateResourceRequests check the cpu and memory requests of compute groups fit in one of nodes by allocatable, the pods requesting more than the largest node keep Pending.
+// only the compute groups that new or the requests changed from the statefulset are validated, the nodes not listed in every reconcile.
+// skipped when annotated `doris.disaggregated.cluster/skip-resource-validation=true`, listing nodes failed or no nodes listed not block the reconcile.
+func (dcgs *DisaggregatedComputeGroupsController) validateResourceRequests(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) string {
+	if ddc.Annotations[dv1.SkipResourceValidation] == "true" {
+		return ""
+	}
+	var changed []*dv1.ComputeGroup
+	cgs := ddc.Spec.ComputeGroups
+	for i := range cgs {
+		if !dcgs.requestsApplied(ctx, ddc, &cgs[i]) {
+			changed = append(changed, &cgs[i])
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var nodes corev1.NodeList
+	if err := dcgs.K8sclient.List(ctx, &nodes); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController validateResourceRequests namespace %s name %s list nodes failed, skip validating, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return ""
+	}
+	for _, cg := range changed {
+		if msg := unschedulableRequests(cg, nodes.Items); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// requestsApplied return true when the statefulset of compute group exists with the same cpu and memory requests, the requests had been validated when applied.
+func (dcgs *DisaggregatedComputeGroupsController) requestsApplied(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) bool {
+	var st appv1.StatefulSet
+	if err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: ddc.Namespace, Name: ddc.GetCGStatefulsetName(cg)}, &st); err != nil {
+		return false
+	}
+	for _, c := range st.Spec.Template.Spec.Containers {
+		if c.Name == resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME {
+			return c.Resources.Requests.Cpu().Equal(*cg.Requests.Cpu()) && c.Resources.Requests.Memory().Equal(*cg.Requests.Memory())
+		}
+	}
+	return false
+}
+
+// unschedulableRequests return the message when no node have enough allocatable cpu and memory for the requests of compute group, empty means the requests fit in a node.
+func unschedulableRequests(cg *dv1.ComputeGroup, nodes []corev1.Node) string {
+	cpu, hasCPU := cg.Requests[corev1.ResourceCPU]
+	mem, hasMem := cg.Requests[corev1.ResourceMemory]
+	if (!hasCPU && !hasMem) || len(nodes) == 0 {
+		return ""
+	}
+
+	var maxCPU, maxMem apiresource.Quantity
+	for i := range nodes {
+		allocatableCPU := nodes[i].Status.Allocatable[corev1.ResourceCPU]
+		allocatableMem := nodes[i].Status.Allocatable[corev1.ResourceMemory]
+		if (!hasCPU || cpu.Cmp(allocatableCPU) <= 0) && (!hasMem || mem.Cmp(allocatableMem) <= 0) {
+			return ""
+		}
+		if allocatableCPU.Cmp(maxCPU) > 0 {
+			maxCPU = allocatableCPU
+		}
+		if allocatableMem.Cmp(maxMem) > 0 {
+			maxMem = allocatableMem
+		}
+	}
+	return fmt.Sprintf("compute group %s requests cpu=%s memory=%s not fit in any node, the largest allocatable of nodes is cpu=%s memory=%s, the pods will keep Pending. "+
+		"annotate %s=true on cluster to skip the validation when nodes provisioned by cluster autoscaler.", cg.UniqueId, cpu.String(), mem.String(), maxCPU.String(), maxMem.String(), dv1.SkipResourceValidation)
+}
@@ -0,0 +1,94 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_unschedulableRequests(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("16"), corev1.ResourceMemory: apiresource.MustParse("32Gi")}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2"}, Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("8"), corev1.ResourceMemory: apiresource.MustParse("64Gi")}}},
+	}
+	tests := []struct {
+		requests corev1.ResourceList
+		fit      bool
+	}{
+		{nil, true},
+		{corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("16"), corev1.ResourceMemory: apiresource.MustParse("32Gi")}, true},
+		{corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("8"), corev1.ResourceMemory: apiresource.MustParse("48Gi")}, true},
+		//the largest cpu and the largest memory are on different nodes.
+		{corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("12"), corev1.ResourceMemory: apiresource.MustParse("48Gi")}, false},
+		{corev1.ResourceList{corev1.ResourceMemory: apiresource.MustParse("128Gi")}, false},
+	}
+	for i, test := range tests {
+		cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+		cg.Requests = test.requests
+		if msg := unschedulableRequests(cg, nodes); (msg == "") != test.fit {
+			t.Errorf("unschedulableRequests case %d expected fit %t, got message %q", i, test.fit, msg)
+		}
+	}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	cg.Requests = corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("64")}
+	if msg := unschedulableRequests(cg, nil); msg != "" {
+		t.Errorf("unschedulableRequests expected not validate without nodes, got %q", msg)
+	}
+}
+
+func Test_validateResourceRequests(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("8"), corev1.ResourceMemory: apiresource.MustParse("32Gi")}}}
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().WithObjects(node).Build()}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := dv1.ComputeGroup{UniqueId: "cg1"}
+	cg.Requests = corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("16")}
+	ddc.Spec.ComputeGroups = []dv1.ComputeGroup{cg}
+
+	if msg := dcgs.validateResourceRequests(context.Background(), ddc); !strings.Contains(msg, "cg1") {
+		t.Errorf("validateResourceRequests expected compute group cg1 unschedulable, got %q", msg)
+	}
+	ddc.Annotations = map[string]string{dv1.SkipResourceValidation: "true"}
+	if msg := dcgs.validateResourceRequests(context.Background(), ddc); msg != "" {
+		t.Errorf("validateResourceRequests expected skipped by annotation, got %q", msg)
+	}
+
+	//the requests applied in statefulset are not validated again, the nodes not listed.
+	ddc.Annotations = nil
+	st := &appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: ddc.GetCGStatefulsetName(&cg)}}
+	st.Spec.Template.Spec.Containers = []corev1.Container{{Name: resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("16000m")}}}}
+	dcgs.K8sclient = fake.NewClientBuilder().WithObjects(st).WithInterceptorFuncs(interceptor.Funcs{List: func(ctx context.Context, client client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+		t.Errorf("validateResourceRequests expected not listing nodes when the requests applied")
+		return nil
+	}}).Build()
+	if msg := dcgs.validateResourceRequests(context.Background(), ddc); msg != "" {
+		t.Errorf("validateResourceRequests expected the applied requests not validated, got %q", msg)
+	}
+}
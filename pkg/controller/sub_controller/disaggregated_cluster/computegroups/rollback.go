@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// the default restarts of compute container that considered the upgrade failed.
+const defaultRollbackRestartThreshold int32 = 3
+
+// rollbackImage return the last known good image when the image in spec rolled back, empty means use the image in spec.
+// the rollback is released when the image in spec changed or autoRollback disabled.
+func rollbackImage(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) string {
+	cgs := findCGStatus(ddc, cg.UniqueId)
+	if cgs == nil || cgs.RolledBackImage == "" {
+		return ""
+	}
+	if cg.AutoRollback == nil || cgs.RolledBackImage != cg.Image {
+		klog.Infof("disaggregatedComputeGroupsController namespace %s name %s compute group %s release the rollback of image %s.", ddc.Namespace, ddc.Name, cg.UniqueId, cgs.RolledBackImage)
+		cgs.RolledBackImage = ""
+		return ""
+	}
+	return cgs.LastKnownGoodImage
+}
+
+// checkUpgradeRollback record the last known good image when all pods ready, and mark the image rolled back when the upgraded pods crashloop beyond threshold.
+func (dcgs *DisaggregatedComputeGroupsController) checkUpgradeRollback(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, sts *appv1.StatefulSet, pods []corev1.Pod, allReady bool) {
+	image := statefulsetImage(sts)
+	if image == "" {
+		return
+	}
+	//the status of statefulset not observed the latest spec, the pods maybe not upgraded.
+	if allReady && sts.Status.ObservedGeneration >= sts.Generation {
+		cgs.LastKnownGoodImage = image
+		return
+	}
+
+	var cg *dv1.ComputeGroup
+	for i := range ddc.Spec.ComputeGroups {
+		if ddc.Spec.ComputeGroups[i].UniqueId == cgs.UniqueId {
+			cg = &ddc.Spec.ComputeGroups[i]
+			break
+		}
+	}
+	if cg == nil || cg.AutoRollback == nil || cgs.RolledBackImage != "" || cgs.LastKnownGoodImage == "" || image == cgs.LastKnownGoodImage {
+		return
+	}
+
+	threshold := cg.AutoRollback.RestartThreshold
+	if threshold <= 0 {
+		threshold = defaultRollbackRestartThreshold
+	}
+	for i := range pods {
+		if pods[i].Labels[appv1.StatefulSetRevisionLabel] != sts.Status.UpdateRevision || !crashLooping(&pods[i], threshold) {
+			continue
+		}
+		cgs.RolledBackImage = image
+		msg := fmt.Sprintf("compute group %s pod %s crashloop after upgrading image to %s, roll back to the last known good image %s.", cg.UniqueId, pods[i].Name, image, cgs.LastKnownGoodImage)
+		klog.Errorf("disaggregatedComputeGroupsController checkUpgradeRollback namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGImageRolledBack), msg)
+		return
+	}
+}
+
+// crashLooping return true when the compute container of pod not ready and restarted not less than threshold.
+func crashLooping(pod *corev1.Pod, threshold int32) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME {
+			return !cs.Ready && cs.RestartCount >= threshold
+		}
+	}
+	return false
+}
+
+// statefulsetImage return the image of compute container in statefulset.
+func statefulsetImage(sts *appv1.StatefulSet) string {
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		if c.Name == resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME {
+			return c.Image
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_checkUpgradeRollback(t *testing.T) {
+	newSts := func(image string) *appv1.StatefulSet {
+		sts := &appv1.StatefulSet{}
+		sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, Image: image}}
+		sts.Status.UpdateRevision = "rev2"
+		return sts
+	}
+	crashPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-0", Labels: map[string]string{appv1.StatefulSetRevisionLabel: "rev2"}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, RestartCount: 3},
+		}},
+	}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{
+			{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Image: "be:3.0.4"}, AutoRollback: &dv1.AutoRollback{}},
+		}},
+		Status: dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1"}}},
+	}
+	cgs := &ddc.Status.ComputeGroupStatuses[0]
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+
+	dcgs.checkUpgradeRollback(ddc, cgs, newSts("be:3.0.3"), nil, true)
+	if cgs.LastKnownGoodImage != "be:3.0.3" {
+		t.Errorf("checkUpgradeRollback expect last known good image be:3.0.3, got %s", cgs.LastKnownGoodImage)
+	}
+
+	crashPod.Labels[appv1.StatefulSetRevisionLabel] = "rev1"
+	dcgs.checkUpgradeRollback(ddc, cgs, newSts("be:3.0.4"), []corev1.Pod{crashPod}, false)
+	if cgs.RolledBackImage != "" {
+		t.Errorf("checkUpgradeRollback expect not roll back when the crashloop pod is not upgraded, got %s", cgs.RolledBackImage)
+	}
+	crashPod.Labels[appv1.StatefulSetRevisionLabel] = "rev2"
+	dcgs.checkUpgradeRollback(ddc, cgs, newSts("be:3.0.4"), []corev1.Pod{crashPod}, false)
+	if cgs.RolledBackImage != "be:3.0.4" || len(recorder.Events) != 1 {
+		t.Errorf("checkUpgradeRollback expect rolled back image be:3.0.4 with event, got %s, events %d", cgs.RolledBackImage, len(recorder.Events))
+	}
+
+	if image := rollbackImage(ddc, &ddc.Spec.ComputeGroups[0]); image != "be:3.0.3" {
+		t.Errorf("rollbackImage expect be:3.0.3, got %s", image)
+	}
+	ddc.Spec.ComputeGroups[0].Image = "be:3.0.5"
+	if image := rollbackImage(ddc, &ddc.Spec.ComputeGroups[0]); image != "" || cgs.RolledBackImage != "" {
+		t.Errorf("rollbackImage expect released when image in spec changed, got %s, rolled back image %s", image, cgs.RolledBackImage)
+	}
+}
@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the default max time of waiting the kept backends warm the cache in scaling down with ColdestCache policy.
+const defaultCacheWarmupTimeout = 10 * time.Minute
+
+// waitCacheWarmed apply the ColdestCache policy of scaling down, return error when the scale down should wait the kept backends warming the cache.
+// the backends removed by the statefulset warmer than the coldest kept backend stop receiving queries, the queries move to the kept backends and warm their cache.
+// the scale down continues when the removed backends are the coldest, or falls back to Ordinal when cache statistics not available or the warmup timed out.
+func (dcgs *DisaggregatedComputeGroupsController) waitCacheWarmed(sqlClient *mysql.DB, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, keepAmount int32) (*sc.Event, error) {
+	if cg.ScaleDownPolicy != dv1.ColdestCache {
+		return nil, nil
+	}
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgStatus.ComputeGroupId)
+	if err != nil {
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+	}
+	ratios, err := sqlClient.GetBackendCacheHitRatios()
+	if err != nil {
+		dcgs.reportScaleDownPolicy(cluster, cgStatus, dv1.Ordinal, "the cache statistics not available in fe, err="+err.Error())
+		return nil, nil
+	}
+
+	warm := warmBackendsRemoved(backends, ratios, cgStatus.StatefulsetName, keepAmount)
+	if len(warm) == 0 {
+		dcgs.reportScaleDownPolicy(cluster, cgStatus, dv1.ColdestCache, "the removed backends are the coldest")
+		return nil, nil
+	}
+
+	now := time.Now()
+	if cgStatus.CacheWarmupStartTime == nil {
+		cgStatus.CacheWarmupStartTime = &metav1.Time{Time: now}
+	}
+	timeout := defaultCacheWarmupTimeout
+	if cg.CacheWarmupTimeout != nil {
+		timeout = cg.CacheWarmupTimeout.Duration
+	}
+	var names []string
+	for _, be := range warm {
+		names = append(names, fmt.Sprintf("%s(%.2f)", strings.Split(be.Host, ".")[0], ratios[be.BackendID]))
+	}
+	if now.Sub(cgStatus.CacheWarmupStartTime.Time) >= timeout {
+		dcgs.reportScaleDownPolicy(cluster, cgStatus, dv1.Ordinal, fmt.Sprintf("the removed backends %s still warmer than the kept ones after %s", strings.Join(names, ","), timeout.String()))
+		return nil, nil
+	}
+
+	for _, be := range warm {
+		if disabled, err := be.QueryDisabled(); err == nil && disabled {
+			continue
+		}
+		if err := sqlClient.SetBackendQueryDisabled(be, true); err != nil {
+			return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSqlExecFailed, Message: err.Error()}, err
+		}
+		if addr := backendAddress(be); !slices.Contains(cgStatus.CacheWarmupBackends, addr) {
+			cgStatus.CacheWarmupBackends = append(cgStatus.CacheWarmupBackends, addr)
+		}
+	}
+	msg := fmt.Sprintf("compute group %s scale down waits the kept backends warming the cache, the removed backends %s warmer than the kept ones stop receiving queries, scale down by Ordinal after %s.", cg.UniqueId, strings.Join(names, ","), timeout.String())
+	return &sc.Event{Type: sc.EventNormal, Reason: sc.CGScaleDownWarmingCache, Message: msg}, errors.New(msg)
+}
+
+// reportScaleDownPolicy emit the event of the policy actually applied in scaling down with ColdestCache policy, and reset the warmup.
+func (dcgs *DisaggregatedComputeGroupsController) reportScaleDownPolicy(cluster *dv1.DorisDisaggregatedCluster, cgStatus *dv1.ComputeGroupStatus, policy dv1.ScaleDownPolicy, reason string) {
+	cgStatus.CacheWarmupStartTime = nil
+	msg := fmt.Sprintf("compute group %s scale down policy ColdestCache applied as %s, %s.", cgStatus.UniqueId, policy, reason)
+	klog.Infof("disaggregatedComputeGroupsController reportScaleDownPolicy namespace %s name %s %s", cluster.Namespace, cluster.Name, msg)
+	dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGScaleDownPolicyApplied), msg)
+}
+
+// restoreCacheWarmupBackends enable the queries of backends disabled for warming the cache, when the scale down finished or canceled.
+// the dropped backends are not in fe, the backends of cordoned compute group keep disabled.
+func (dcgs *DisaggregatedComputeGroupsController) restoreCacheWarmupBackends(ctx context.Context, cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus) {
+	cgStatus.CacheWarmupStartTime = nil
+	if len(cgStatus.CacheWarmupBackends) == 0 {
+		return
+	}
+	if cg.Cordoned {
+		cgStatus.CacheWarmupBackends = nil
+		return
+	}
+
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, cluster)
+	if err != nil {
+		klog.Errorf("restoreCacheWarmupBackends namespace %s name %s get sql client failed, err=%s", cluster.Namespace, cluster.Name, err.Error())
+		return
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.GetBackendsByComputeGroupId(cgStatus.ComputeGroupId)
+	if err != nil {
+		klog.Errorf("restoreCacheWarmupBackends namespace %s name %s get backends of compute group %s failed, err=%s", cluster.Namespace, cluster.Name, cg.UniqueId, err.Error())
+		return
+	}
+	warmup := map[string]bool{}
+	for _, addr := range cgStatus.CacheWarmupBackends {
+		warmup[addr] = true
+	}
+	for _, be := range backends {
+		if !warmup[backendAddress(be)] {
+			continue
+		}
+		if err := sqlClient.SetBackendQueryDisabled(be, false); err != nil {
+			klog.Errorf("restoreCacheWarmupBackends namespace %s name %s enable queries of backend %s failed, err=%s", cluster.Namespace, cluster.Name, be.Host, err.Error())
+			return
+		}
+	}
+	klog.Infof("restoreCacheWarmupBackends namespace %s name %s compute group %s backends %s receive queries again.", cluster.Namespace, cluster.Name, cg.UniqueId, strings.Join(cgStatus.CacheWarmupBackends, ","))
+	cgStatus.CacheWarmupBackends = nil
+}
+
+// warmBackendsRemoved return the backends removed by scaling down to keepAmount that the cache hit ratio higher than the coldest kept backend.
+// the backends without cache statistics are ignored.
+func warmBackendsRemoved(backends []*mysql.Backend, ratios map[string]float64, stsName string, keepAmount int32) []*mysql.Backend {
+	coldestKept := -1.0
+	for _, be := range backends {
+		ratio, ok := ratios[be.BackendID]
+		ordinal, err := backendPodOrdinal(be.Host, stsName, nil)
+		if !ok || err != nil || ordinal >= int(keepAmount) {
+			continue
+		}
+		if coldestKept < 0 || ratio < coldestKept {
+			coldestKept = ratio
+		}
+	}
+	if coldestKept < 0 {
+		return nil
+	}
+
+	var warm []*mysql.Backend
+	for _, be := range backends {
+		ratio, ok := ratios[be.BackendID]
+		ordinal, err := backendPodOrdinal(be.Host, stsName, nil)
+		if ok && err == nil && ordinal >= int(keepAmount) && ratio > coldestKept {
+			warm = append(warm, be)
+		}
+	}
+	return warm
+}
+
+// backendAddress return the `host:heartbeatPort` identifying the backend in fe.
+func backendAddress(be *mysql.Backend) string {
+	return fmt.Sprintf("%s:%d", be.Host, be.HeartbeatPort)
+}
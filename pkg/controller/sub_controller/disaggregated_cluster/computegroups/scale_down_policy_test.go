@@ -0,0 +1,113 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"github.com/jmoiron/sqlx"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_warmBackendsRemoved(t *testing.T) {
+	backends := []*mysql.Backend{
+		{BackendID: "10000", Host: "test-cg1-0.test-cg1.default.svc.cluster.local"},
+		{BackendID: "10001", Host: "test-cg1-1.test-cg1.default.svc.cluster.local"},
+		{BackendID: "10002", Host: "test-cg1-2.test-cg1.default.svc.cluster.local"},
+		{BackendID: "10003", Host: "test-cg1-3.test-cg1.default.svc.cluster.local"},
+	}
+	ratios := map[string]float64{"10000": 0.9, "10001": 0.3, "10002": 0.8, "10003": 0.1}
+	if warm := warmBackendsRemoved(backends, ratios, "test-cg1", 2); len(warm) != 1 || warm[0].BackendID != "10002" {
+		t.Errorf("warmBackendsRemoved expect the backend 10002, got %v", warm)
+	}
+	if warm := warmBackendsRemoved(backends, map[string]float64{}, "test-cg1", 2); len(warm) != 0 {
+		t.Errorf("warmBackendsRemoved expect empty without statistics, got %v", warm)
+	}
+}
+
+// newCacheTestDB mock the backends of compute group cgid1, the backend of pod test-cg1-{index} has the cache hit ratio of ratios[index].
+func newCacheTestDB(t *testing.T, ratios []string) (*mysql.DB, sqlmock.Sqlmock) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	rows := sqlmock.NewRows(backendColumns)
+	stats := sqlmock.NewRows([]string{"BE_ID", "METRIC_VALUE"})
+	for i, ratio := range ratios {
+		row := newBackendRow("test-cg1-"+strconv.Itoa(i)+".test-cg1.default.svc.cluster.local", "cgid1")
+		row[0] = strconv.Itoa(10000 + i)
+		rows.AddRow(row...)
+		stats.AddRow(strconv.Itoa(10000+i), ratio)
+	}
+	mock.ExpectQuery("show backends").WillReturnRows(rows)
+	mock.ExpectQuery("file_cache_statistics").WillReturnRows(stats)
+	return &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}, mock
+}
+
+func Test_waitCacheWarmed(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", ScaleDownPolicy: dv1.ColdestCache}
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", ComputeGroupId: "cgid1", StatefulsetName: "test-cg1"}
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+
+	//the removed backend test-cg1-2 warmer than the kept, disable its queries and wait.
+	db, mock := newCacheTestDB(t, []string{"0.5", "0.3", "0.8"})
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER SYSTEM MODIFY BACKEND "test-cg1-2.test-cg1.default.svc.cluster.local:9050" SET ("disable_query" = "true");`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	event, err := dcgs.waitCacheWarmed(db, ddc, cg, cgStatus, 2)
+	if err == nil || event == nil || event.Reason != sc.CGScaleDownWarmingCache {
+		t.Errorf("waitCacheWarmed expected waiting the cache warmed, event=%v, err=%v", event, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("waitCacheWarmed expected the queries of warm backend disabled, err=%s", err.Error())
+	}
+	if cgStatus.CacheWarmupStartTime == nil || len(cgStatus.CacheWarmupBackends) != 1 || cgStatus.CacheWarmupBackends[0] != "test-cg1-2.test-cg1.default.svc.cluster.local:9050" {
+		t.Errorf("waitCacheWarmed expected the warmup recorded in status, got %+v", cgStatus)
+	}
+	db.Close()
+
+	//the warmup timed out, fall back to ordinal.
+	cgStatus.CacheWarmupStartTime = &metav1.Time{Time: time.Now().Add(-defaultCacheWarmupTimeout)}
+	db, _ = newCacheTestDB(t, []string{"0.5", "0.3", "0.8"})
+	if event, err := dcgs.waitCacheWarmed(db, ddc, cg, cgStatus, 2); event != nil || err != nil {
+		t.Errorf("waitCacheWarmed expected falling back to ordinal after timeout, event=%v, err=%v", event, err)
+	}
+	if cgStatus.CacheWarmupStartTime != nil || !strings.Contains(<-recorder.Events, "applied as Ordinal") {
+		t.Errorf("waitCacheWarmed expected the fallback to ordinal reported")
+	}
+	db.Close()
+
+	//the removed backends are the coldest.
+	db, _ = newCacheTestDB(t, []string{"0.5", "0.3", "0.1"})
+	if event, err := dcgs.waitCacheWarmed(db, ddc, cg, cgStatus, 2); event != nil || err != nil {
+		t.Errorf("waitCacheWarmed expected the coldest removed, event=%v, err=%v", event, err)
+	}
+	if !strings.Contains(<-recorder.Events, "applied as ColdestCache") {
+		t.Errorf("waitCacheWarmed expected ColdestCache applied reported")
+	}
+	db.Close()
+}
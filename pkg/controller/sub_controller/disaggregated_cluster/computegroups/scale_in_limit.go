@@ -0,0 +1,56 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+)
+
+// limitScaleInStep limit the backends removed in this step of scaling in by the maxScaleInPercentage of compute group, return true when limited.
+// the replicas of statefulset is the amount of backends kept in this step, the decommissioning in progress recomputes the same step from the existing statefulset.
+func (dcgs *DisaggregatedComputeGroupsController) limitScaleInStep(cluster *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, st, est *appv1.StatefulSet) bool {
+	if cg.MaxScaleInPercentage == nil || *st.Spec.Replicas >= *est.Spec.Replicas {
+		return false
+	}
+	keep := scaleInStepReplicas(*st.Spec.Replicas, *est.Spec.Replicas, *cg.MaxScaleInPercentage)
+	if keep == *st.Spec.Replicas {
+		return false
+	}
+
+	msg := fmt.Sprintf("compute group %s scale in from %d to %d limited by maxScaleInPercentage %d%%, scale in to %d in this step.", cg.UniqueId, *est.Spec.Replicas, *st.Spec.Replicas, *cg.MaxScaleInPercentage, keep)
+	dcgs.K8srecorder.Event(cluster, string(sc.EventNormal), string(sc.CGScaleInLimited), msg)
+	//the replicas of statefulset shares the pointer with compute group spec, not modify it in place.
+	st.Spec.Replicas = &keep
+	return true
+}
+
+// scaleInStepReplicas return the replicas kept in a step of scaling in from existReplicas to replicas, at most percentage of existReplicas removed and at least one.
+func scaleInStepReplicas(replicas, existReplicas, percentage int32) int32 {
+	step := existReplicas * percentage / 100
+	if step < 1 {
+		step = 1
+	}
+	if existReplicas-step > replicas {
+		return existReplicas - step
+	}
+	return replicas
+}
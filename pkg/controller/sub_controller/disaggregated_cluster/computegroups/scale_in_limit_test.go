@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_scaleInStepReplicas(t *testing.T) {
+	tests := []struct {
+		replicas, existReplicas, percentage, expect int32
+	}{
+		{replicas: 2, existReplicas: 20, percentage: 25, expect: 15},
+		{replicas: 17, existReplicas: 20, percentage: 25, expect: 17},
+		{replicas: 0, existReplicas: 3, percentage: 10, expect: 2},
+		{replicas: 0, existReplicas: 10, percentage: 100, expect: 0},
+	}
+	for _, test := range tests {
+		if got := scaleInStepReplicas(test.replicas, test.existReplicas, test.percentage); got != test.expect {
+			t.Errorf("scaleInStepReplicas(%d, %d, %d) expected %d, got %d", test.replicas, test.existReplicas, test.percentage, test.expect, got)
+		}
+	}
+}
+
+func Test_limitScaleInStep(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	cg.Replicas = resource.GetInt32Pointer(2)
+	st := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: cg.Replicas}}
+	est := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(10)}}
+
+	if dcgs.limitScaleInStep(ddc, cg, st, est) || *st.Spec.Replicas != 2 {
+		t.Errorf("limitScaleInStep without maxScaleInPercentage expected not limited, got replicas %d", *st.Spec.Replicas)
+	}
+
+	cg.MaxScaleInPercentage = resource.GetInt32Pointer(30)
+	if !dcgs.limitScaleInStep(ddc, cg, st, est) || *st.Spec.Replicas != 7 {
+		t.Errorf("limitScaleInStep expected limited to 7, got replicas %d", *st.Spec.Replicas)
+	}
+	if *cg.Replicas != 2 {
+		t.Errorf("limitScaleInStep expected the replicas of compute group not modified, got %d", *cg.Replicas)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("limitScaleInStep expected one event, got %d", len(recorder.Events))
+	}
+
+	//the last step not limited.
+	st.Spec.Replicas = cg.Replicas
+	est.Spec.Replicas = resource.GetInt32Pointer(3)
+	if dcgs.limitScaleInStep(ddc, cg, st, est) || *st.Spec.Replicas != 2 {
+		t.Errorf("limitScaleInStep last step expected not limited, got replicas %d", *st.Spec.Replicas)
+	}
+}
@@ -0,0 +1,200 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// the max failed deliveries of a scale notification, the notification is dropped after it.
+const scaleNotifyMaxAttempts = 10
+
+// the webhooks are called in reconcile, the short timeout bounds the delay of reconcile when webhooks unreachable.
+var scaleNotifyClient = &http.Client{Timeout: 5 * time.Second}
+
+// scaleNotificationPayload is the body posted to scale webhooks.
+type scaleNotificationPayload struct {
+	Namespace    string           `json:"namespace"`
+	Cluster      string           `json:"cluster"`
+	ComputeGroup string           `json:"computeGroup"`
+	Stage        dv1.ScaleStage   `json:"stage"`
+	OldReplicas  int32            `json:"oldReplicas"`
+	NewReplicas  int32            `json:"newReplicas"`
+	Outcome      dv1.ScaleOutcome `json:"outcome,omitempty"`
+	Time         metav1.Time      `json:"time"`
+}
+
+// notifyScaleStart post the Before notifications when the replicas of statefulset will be changed, the last scale operation not finished is notified superseded.
+func (dcgs *DisaggregatedComputeGroupsController) notifyScaleStart(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) {
+	if len(cg.ScaleWebhooks) == 0 || cgStatus == nil || dcgs.Plan != nil || *st.Spec.Replicas == *est.Spec.Replicas {
+		return
+	}
+	op := cgStatus.ScaleOperation
+	if op != nil && op.NewReplicas == *st.Spec.Replicas {
+		return
+	}
+	if op != nil && op.Outcome == "" {
+		op.Outcome = dv1.ScaleSuperseded
+		dcgs.notifyScale(ddc, cgStatus, cg.ScaleWebhooks, dv1.AfterScale)
+	}
+
+	cgStatus.ScaleOperation = &dv1.ScaleOperation{OldReplicas: *est.Spec.Replicas, NewReplicas: *st.Spec.Replicas, StartTime: metav1.Now()}
+	dcgs.notifyScale(ddc, cgStatus, cg.ScaleWebhooks, dv1.BeforeScale)
+}
+
+// notifyScaleFinish post the After notifications when the scale operation finished. the blocked scale down is failed, and notified succeeded again when it finished after reset.
+func (dcgs *DisaggregatedComputeGroupsController) notifyScaleFinish(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus) {
+	op := cgs.ScaleOperation
+	urls := scaleWebhooksOf(ddc, cgs.UniqueId)
+	if op == nil || len(urls) == 0 || dcgs.Plan != nil {
+		return
+	}
+
+	switch {
+	case cgs.Phase == dv1.Ready && cgs.Replicas == op.NewReplicas && (op.Outcome == "" || op.Outcome == dv1.ScaleFailed):
+		op.Outcome = dv1.ScaleSucceeded
+	case cgs.Phase == dv1.Ready && cgs.Replicas != op.NewReplicas && op.Outcome == "":
+		//the replicas changed back before applied, the scale operation never finished.
+		op.Outcome = dv1.ScaleSuperseded
+	case cgs.Phase == dv1.ScaleDownBlocked && op.Outcome == "":
+		op.Outcome = dv1.ScaleFailed
+	default:
+		return
+	}
+	dcgs.notifyScale(ddc, cgs, urls, dv1.AfterScale)
+}
+
+// notifyScale post the notification of current scale operation to every webhook, the failed ones are kept in status for retrying.
+func (dcgs *DisaggregatedComputeGroupsController) notifyScale(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, urls []string, stage dv1.ScaleStage) {
+	op := cgs.ScaleOperation
+	now := metav1.Now()
+	for _, url := range urls {
+		n := dv1.ScaleNotification{URL: url, Stage: stage, OldReplicas: op.OldReplicas, NewReplicas: op.NewReplicas, Time: now}
+		if stage == dv1.AfterScale {
+			n.Outcome = op.Outcome
+		}
+		if err := postScaleNotification(ddc, cgs.UniqueId, &n); err != nil {
+			klog.Errorf("disaggregatedComputeGroupsController notifyScale namespace %s name %s compute group %s post %s notification to %s failed, will retry, err=%s", ddc.Namespace, ddc.Name, cgs.UniqueId, stage, url, err.Error())
+			n.Attempts = 1
+			n.LastError = err.Error()
+			cgs.PendingScaleNotifications = append(cgs.PendingScaleNotifications, n)
+		}
+	}
+}
+
+// retryScaleNotifications post the pending notifications again, the ones of webhooks removed from spec or failed too many times are dropped.
+func (dcgs *DisaggregatedComputeGroupsController) retryScaleNotifications(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus) {
+	if cgStatus == nil || len(cgStatus.PendingScaleNotifications) == 0 || dcgs.Plan != nil {
+		return
+	}
+
+	urls := map[string]bool{}
+	for _, url := range cg.ScaleWebhooks {
+		urls[url] = true
+	}
+	var pending []dv1.ScaleNotification
+	for _, n := range cgStatus.PendingScaleNotifications {
+		if !urls[n.URL] {
+			continue
+		}
+		err := postScaleNotification(ddc, cg.UniqueId, &n)
+		if err == nil {
+			continue
+		}
+		n.Attempts++
+		n.LastError = err.Error()
+		if n.Attempts >= scaleNotifyMaxAttempts {
+			msg := fmt.Sprintf("compute group %s dropped the %s scale notification(%d -> %d) to %s after %d attempts, err=%s", cg.UniqueId, n.Stage, n.OldReplicas, n.NewReplicas, n.URL, n.Attempts, err.Error())
+			klog.Errorf("disaggregatedComputeGroupsController retryScaleNotifications namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGScaleNotifyFailed), msg)
+			continue
+		}
+		pending = append(pending, n)
+	}
+	cgStatus.PendingScaleNotifications = pending
+}
+
+// postScaleNotification post the notification as json, the webhook should response 2xx.
+func postScaleNotification(ddc *dv1.DorisDisaggregatedCluster, uniqueId string, n *dv1.ScaleNotification) error {
+	body, err := json.Marshal(&scaleNotificationPayload{
+		Namespace:    ddc.Namespace,
+		Cluster:      ddc.Name,
+		ComputeGroup: uniqueId,
+		Stage:        n.Stage,
+		OldReplicas:  n.OldReplicas,
+		NewReplicas:  n.NewReplicas,
+		Outcome:      n.Outcome,
+		Time:         n.Time,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := scaleNotifyClient.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded status %s", resp.Status)
+	}
+	return nil
+}
+
+// scaleWebhooksOf return the scaleWebhooks of compute group in spec.
+func scaleWebhooksOf(ddc *dv1.DorisDisaggregatedCluster, uniqueId string) []string {
+	if cg := findCG(ddc, uniqueId); cg != nil {
+		return cg.ScaleWebhooks
+	}
+	return nil
+}
+
+// recordScaleEvent emit the event when the replicas of statefulset changed, scaling to 0 is suspending and scaling from 0 is resuming.
+func (dcgs *DisaggregatedComputeGroupsController) recordScaleEvent(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, st, est *appv1.StatefulSet) {
+	if dcgs.Plan != nil || *st.Spec.Replicas == *est.Spec.Replicas {
+		return
+	}
+	reason := sc.CGScaled
+	switch {
+	case *st.Spec.Replicas == 0:
+		reason = sc.CGSuspended
+	case *est.Spec.Replicas == 0:
+		reason = sc.CGResumed
+	}
+	msg := fmt.Sprintf("compute group %s scaled from %d to %d replicas.", cg.UniqueId, *est.Spec.Replicas, *st.Spec.Replicas)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(reason), msg)
+}
+
+// reportScaleDownSucceeded emit event when the scale down dropped backends and the compute group ready with the desired replicas, the dropped count reset.
+func (dcgs *DisaggregatedComputeGroupsController) reportScaleDownSucceeded(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus) {
+	if cgs.DroppedBackends == 0 || cgs.Phase != dv1.Ready {
+		return
+	}
+	msg := fmt.Sprintf("compute group %s scaled down to %d replicas, %d backends dropped.", cgs.UniqueId, cgs.Replicas, cgs.DroppedBackends)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGScaleDownSucceeded), msg)
+	cgs.DroppedBackends = 0
+}
@@ -0,0 +1,154 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_notifyScale(t *testing.T) {
+	var received []scaleNotificationPayload
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var p scaleNotificationPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode scale notification failed, err=%s", err.Error())
+		}
+		received = append(received, p)
+	}))
+	defer server.Close()
+
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	cg := dv1.ComputeGroup{UniqueId: "cg1", ScaleWebhooks: []string{server.URL}}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{cg}},
+	}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling, Replicas: 5}
+	newStatefulset := func(replicas int32) *appv1.StatefulSet {
+		return &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: &replicas}}
+	}
+
+	//the failed notification kept for retrying, the scale started only once.
+	dcgs.notifyScaleStart(ddc, &cg, cgs, newStatefulset(5), newStatefulset(3))
+	dcgs.notifyScaleStart(ddc, &cg, cgs, newStatefulset(5), newStatefulset(3))
+	if cgs.ScaleOperation == nil || cgs.ScaleOperation.OldReplicas != 3 || cgs.ScaleOperation.NewReplicas != 5 {
+		t.Errorf("notifyScaleStart expect scale operation 3 -> 5, got %+v", cgs.ScaleOperation)
+	}
+	if len(cgs.PendingScaleNotifications) != 1 || cgs.PendingScaleNotifications[0].Stage != dv1.BeforeScale {
+		t.Errorf("notifyScaleStart expect 1 pending Before notification, got %+v", cgs.PendingScaleNotifications)
+	}
+
+	failing = false
+	dcgs.retryScaleNotifications(ddc, &cg, cgs)
+	cgs.Phase = dv1.Ready
+	dcgs.notifyScaleFinish(ddc, cgs)
+	dcgs.notifyScaleFinish(ddc, cgs)
+	if len(cgs.PendingScaleNotifications) != 0 {
+		t.Errorf("retryScaleNotifications expect no pending notification, got %+v", cgs.PendingScaleNotifications)
+	}
+	if len(received) != 2 || received[0].Stage != dv1.BeforeScale || received[1].Stage != dv1.AfterScale || received[1].Outcome != dv1.ScaleSucceeded ||
+		received[1].ComputeGroup != "cg1" || received[1].OldReplicas != 3 || received[1].NewReplicas != 5 {
+		t.Errorf("scale webhook expect Before and succeeded After notifications, got %+v", received)
+	}
+
+	//the notification failed too many times is dropped with warning event.
+	cgs.PendingScaleNotifications = []dv1.ScaleNotification{{URL: "http://127.0.0.1:1", Stage: dv1.BeforeScale, Attempts: scaleNotifyMaxAttempts - 1}}
+	cg.ScaleWebhooks = append(cg.ScaleWebhooks, "http://127.0.0.1:1")
+	dcgs.retryScaleNotifications(ddc, &cg, cgs)
+	if len(cgs.PendingScaleNotifications) != 0 || len(recorder.Events) != 1 {
+		t.Errorf("retryScaleNotifications expect dropped with 1 event, got pending %+v events %d", cgs.PendingScaleNotifications, len(recorder.Events))
+	}
+}
+
+func Test_recordScaleEvent(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	newSts := func(replicas int32) *appv1.StatefulSet {
+		return &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: &replicas}}
+	}
+	tests := []struct {
+		old, new int32
+		reason   string
+	}{
+		{3, 3, ""},
+		{2, 3, string(sc.CGScaled)},
+		{3, 0, string(sc.CGSuspended)},
+		{0, 3, string(sc.CGResumed)},
+	}
+	for _, tt := range tests {
+		recorder := record.NewFakeRecorder(10)
+		dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+		dcgs.recordScaleEvent(ddc, cg, newSts(tt.new), newSts(tt.old))
+		if tt.reason == "" {
+			if len(recorder.Events) != 0 {
+				t.Errorf("recordScaleEvent from %d to %d expected no event, got %s", tt.old, tt.new, <-recorder.Events)
+			}
+			continue
+		}
+		if len(recorder.Events) != 1 {
+			t.Fatalf("recordScaleEvent from %d to %d expected one event, got %d", tt.old, tt.new, len(recorder.Events))
+		}
+		if e := <-recorder.Events; !strings.Contains(e, tt.reason) {
+			t.Errorf("recordScaleEvent from %d to %d expected reason %s, got %s", tt.old, tt.new, tt.reason, e)
+		}
+	}
+}
+
+func Test_reportScaleDownSucceeded(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Scaling, Replicas: 2, DroppedBackends: 3}
+
+	//the scale down in progress not reported.
+	dcgs.reportScaleDownSucceeded(ddc, cgs)
+	if len(recorder.Events) != 0 || cgs.DroppedBackends != 3 {
+		t.Errorf("reportScaleDownSucceeded in scaling expected no event, got %d events dropped %d", len(recorder.Events), cgs.DroppedBackends)
+	}
+
+	cgs.Phase = dv1.Ready
+	dcgs.reportScaleDownSucceeded(ddc, cgs)
+	if len(recorder.Events) != 1 || cgs.DroppedBackends != 0 {
+		t.Fatalf("reportScaleDownSucceeded ready expected one event and dropped reset, got %d events dropped %d", len(recorder.Events), cgs.DroppedBackends)
+	}
+	if e := <-recorder.Events; !strings.Contains(e, string(sc.CGScaleDownSucceeded)) || !strings.Contains(e, "cg1") || !strings.Contains(e, "3 backends dropped") {
+		t.Errorf("reportScaleDownSucceeded event not expected, got %s", e)
+	}
+
+	//reported once.
+	dcgs.reportScaleDownSucceeded(ddc, cgs)
+	if len(recorder.Events) != 0 {
+		t.Errorf("reportScaleDownSucceeded expected reported once, got %d events", len(recorder.Events))
+	}
+}
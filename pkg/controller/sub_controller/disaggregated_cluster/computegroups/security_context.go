@@ -0,0 +1,182 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// the label on namespace that the pod security admission enforces.
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	podSecurityBaseline     = "baseline"
+	podSecurityRestricted   = "restricted"
+)
+
+// the capabilities that baseline level allows to add, reference: https://kubernetes.io/docs/concepts/security/pod-security-standards/
+var baselineAllowedCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true, "KILL": true, "MKNOD": true,
+	"NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// newCGPodSecurityContext return the pod security context of compute group with defaults, the spec is not modified.
+// when the pod runs as a non-root user, the fsGroup defaults to the group of user for the pvcs writable, and the ownership is only changed when mismatched because the cache volumes are large.
+func newCGPodSecurityContext(psc *corev1.PodSecurityContext) *corev1.PodSecurityContext {
+	if psc == nil || psc.RunAsUser == nil || *psc.RunAsUser == 0 {
+		return psc
+	}
+
+	npsc := psc.DeepCopy()
+	if npsc.FSGroup == nil {
+		gid := *psc.RunAsUser
+		if psc.RunAsGroup != nil {
+			gid = *psc.RunAsGroup
+		}
+		npsc.FSGroup = &gid
+	}
+	if npsc.FSGroupChangePolicy == nil {
+		policy := corev1.FSGroupChangeOnRootMismatch
+		npsc.FSGroupChangePolicy = &policy
+	}
+	return npsc
+}
+
+// checkPodSecurityStandard warn when the pod of compute group would be rejected by the pod security admission enforced on namespace.
+func (dcgs *DisaggregatedComputeGroupsController) checkPodSecurityStandard(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, spec *corev1.PodSpec) {
+	var ns corev1.Namespace
+	if err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Name: ddc.Namespace}, &ns); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController checkPodSecurityStandard get namespace %s failed, err=%s", ddc.Namespace, err.Error())
+		return
+	}
+
+	level := ns.Labels[podSecurityEnforceLabel]
+	violations := podSecurityViolations(level, spec)
+	if len(violations) == 0 {
+		return
+	}
+	msg := fmt.Sprintf("compute group %s pods would be rejected by the %s pod security standard of namespace %s: %s.", cg.UniqueId, level, ddc.Namespace, strings.Join(violations, "; "))
+	klog.Errorf("disaggregatedComputeGroupsController checkPodSecurityStandard namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGPodSecurityViolation), msg)
+}
+
+// podSecurityViolations return the violations of pod spec against the pod security standard level, the privileged or unknown level has none.
+func podSecurityViolations(level string, spec *corev1.PodSpec) []string {
+	if level != podSecurityBaseline && level != podSecurityRestricted {
+		return nil
+	}
+
+	var violations []string
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		violations = append(violations, "host namespaces are not allowed")
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			violations = append(violations, fmt.Sprintf("volume %s uses hostPath", v.Name))
+		}
+	}
+
+	var containers []corev1.Container
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	psc := spec.SecurityContext
+	if psc == nil {
+		psc = &corev1.PodSecurityContext{}
+	}
+	for _, c := range containers {
+		csc := c.SecurityContext
+		if csc == nil {
+			csc = &corev1.SecurityContext{}
+		}
+		if csc.Privileged != nil && *csc.Privileged {
+			violations = append(violations, fmt.Sprintf("container %s is privileged", c.Name))
+		}
+		for _, p := range c.Ports {
+			if p.HostPort != 0 {
+				violations = append(violations, fmt.Sprintf("container %s uses hostPort %d", c.Name, p.HostPort))
+			}
+		}
+		if csc.Capabilities != nil {
+			for _, ca := range csc.Capabilities.Add {
+				//the restricted level only allows adding NET_BIND_SERVICE.
+				allowed := baselineAllowedCapabilities[ca]
+				if level == podSecurityRestricted {
+					allowed = ca == "NET_BIND_SERVICE"
+				}
+				if !allowed {
+					violations = append(violations, fmt.Sprintf("container %s adds capability %s", c.Name, ca))
+				}
+			}
+		}
+		if level == podSecurityRestricted {
+			violations = append(violations, restrictedContainerViolations(c.Name, psc, csc)...)
+		}
+	}
+	return violations
+}
+
+// restrictedContainerViolations return the violations of container against the restricted level, the container fields override the pod fields.
+func restrictedContainerViolations(name string, psc *corev1.PodSecurityContext, csc *corev1.SecurityContext) []string {
+	var violations []string
+	if csc.AllowPrivilegeEscalation == nil || *csc.AllowPrivilegeEscalation {
+		violations = append(violations, fmt.Sprintf("container %s must set allowPrivilegeEscalation=false", name))
+	}
+
+	dropAll := false
+	if csc.Capabilities != nil {
+		for _, ca := range csc.Capabilities.Drop {
+			if ca == "ALL" {
+				dropAll = true
+			}
+		}
+	}
+	if !dropAll {
+		violations = append(violations, fmt.Sprintf("container %s must drop ALL capabilities", name))
+	}
+
+	runAsNonRoot := csc.RunAsNonRoot
+	if runAsNonRoot == nil {
+		runAsNonRoot = psc.RunAsNonRoot
+	}
+	if runAsNonRoot == nil || !*runAsNonRoot {
+		violations = append(violations, fmt.Sprintf("container %s must set runAsNonRoot=true", name))
+	}
+	runAsUser := csc.RunAsUser
+	if runAsUser == nil {
+		runAsUser = psc.RunAsUser
+	}
+	if runAsUser != nil && *runAsUser == 0 {
+		violations = append(violations, fmt.Sprintf("container %s must not run as user 0", name))
+	}
+
+	seccomp := csc.SeccompProfile
+	if seccomp == nil {
+		seccomp = psc.SeccompProfile
+	}
+	if seccomp == nil || (seccomp.Type != corev1.SeccompProfileTypeRuntimeDefault && seccomp.Type != corev1.SeccompProfileTypeLocalhost) {
+		violations = append(violations, fmt.Sprintf("container %s must set seccompProfile to RuntimeDefault or Localhost", name))
+	}
+	return violations
+}
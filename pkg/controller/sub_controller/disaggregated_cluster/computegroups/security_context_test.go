@@ -0,0 +1,94 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func Test_newCGPodSecurityContext(t *testing.T) {
+	if psc := newCGPodSecurityContext(nil); psc != nil {
+		t.Errorf("newCGPodSecurityContext nil expect nil, got %v", psc)
+	}
+	root := &corev1.PodSecurityContext{RunAsUser: pointer.Int64(0)}
+	if psc := newCGPodSecurityContext(root); psc.FSGroup != nil {
+		t.Errorf("newCGPodSecurityContext root expect no fsGroup, got %d", *psc.FSGroup)
+	}
+
+	spec := &corev1.PodSecurityContext{RunAsUser: pointer.Int64(1000), RunAsGroup: pointer.Int64(2000)}
+	psc := newCGPodSecurityContext(spec)
+	if psc.FSGroup == nil || *psc.FSGroup != 2000 {
+		t.Errorf("newCGPodSecurityContext expect fsGroup 2000, got %v", psc.FSGroup)
+	}
+	if psc.FSGroupChangePolicy == nil || *psc.FSGroupChangePolicy != corev1.FSGroupChangeOnRootMismatch {
+		t.Errorf("newCGPodSecurityContext expect fsGroupChangePolicy OnRootMismatch, got %v", psc.FSGroupChangePolicy)
+	}
+	if spec.FSGroup != nil {
+		t.Errorf("newCGPodSecurityContext should not modify spec.")
+	}
+
+	spec = &corev1.PodSecurityContext{RunAsUser: pointer.Int64(1000), FSGroup: pointer.Int64(3000)}
+	if psc = newCGPodSecurityContext(spec); *psc.FSGroup != 3000 {
+		t.Errorf("newCGPodSecurityContext expect configured fsGroup 3000, got %d", *psc.FSGroup)
+	}
+}
+
+func Test_podSecurityViolations(t *testing.T) {
+	privileged := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "default-init", SecurityContext: &corev1.SecurityContext{Privileged: pointer.Bool(true)}}},
+		Containers:     []corev1.Container{{Name: "compute"}},
+	}
+	restricted := &corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   pointer.Bool(true),
+			RunAsUser:      pointer.Int64(1000),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Containers: []corev1.Container{{Name: "compute", SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		}}},
+	}
+	baselineCaps := &corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "compute", SecurityContext: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"CHOWN"}},
+		}}},
+	}
+
+	tests := []struct {
+		name  string
+		level string
+		spec  *corev1.PodSpec
+		count int
+	}{
+		{name: "not enforced", level: "", spec: privileged, count: 0},
+		{name: "privileged level", level: "privileged", spec: privileged, count: 0},
+		{name: "baseline privileged init", level: podSecurityBaseline, spec: privileged, count: 1},
+		{name: "baseline allowed capability", level: podSecurityBaseline, spec: baselineCaps, count: 0},
+		{name: "restricted capability", level: podSecurityRestricted, spec: baselineCaps, count: 5},
+		{name: "restricted compliant", level: podSecurityRestricted, spec: restricted, count: 0},
+	}
+	for _, test := range tests {
+		if vs := podSecurityViolations(test.level, test.spec); len(vs) != test.count {
+			t.Errorf("podSecurityViolations %s expect %d violations, got %v", test.name, test.count, vs)
+		}
+	}
+}
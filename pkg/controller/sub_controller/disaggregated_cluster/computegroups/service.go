@@ -18,8 +18,14 @@
 package computegroups
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
 	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -36,6 +42,9 @@ func (dcgs *DisaggregatedComputeGroupsController) newService(ddc *dv1.DorisDisag
 
 	spec := &svc.Spec
 	spec.Selector = dcgs.newCGPodsSelector(ddc.Name, uniqueId)
+	if cg.ConnectionDraining != nil {
+		spec.Selector[dv1.DorisDisaggregatedServing] = "true"
+	}
 	spec.Ports = sps
 
 	if svcConf != nil && svcConf.Type != "" {
@@ -100,3 +109,16 @@ func newComputeServicePorts(cvs map[string]interface{}, svcConf *dv1.ExportServi
 
 	return sps
 }
+
+// checkServiceReady check the service of statefulset exists and have a valid selector, the pods resolve each other by the service dns.
+// creating statefulset before the service ready makes pods crashloop for waiting dns.
+func (dcgs *DisaggregatedComputeGroupsController) checkServiceReady(ctx context.Context, st *appv1.StatefulSet) error {
+	svc, err := k8s.GetService(ctx, dcgs.K8sclient, st.Namespace, st.Spec.ServiceName)
+	if err != nil {
+		return fmt.Errorf("the service %s of statefulset %s not ready, err=%s", st.Spec.ServiceName, st.Name, err.Error())
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return errors.New("the service " + svc.Name + " of statefulset " + st.Name + " have not selector")
+	}
+	return nil
+}
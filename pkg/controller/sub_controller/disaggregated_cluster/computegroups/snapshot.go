@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// snapshotPVCBeforeDelete create the VolumeSnapshot of the pvc of scaled in pod, return true when the snapshot is ready to use and the pvc can be deleted.
+func (dcgs *DisaggregatedComputeGroupsController) snapshotPVCBeforeDelete(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	err := dcgs.K8sclient.Get(ctx, types.NamespacedName{Namespace: pvc.Namespace, Name: scaleInSnapshotName(pvc)}, vs)
+	if apierrors.IsNotFound(err) {
+		vs = newScaleInVolumeSnapshot(pvc, cg.ScaleInSnapshotClassName, dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId))
+		if err = dcgs.K8sclient.Create(ctx, vs); err != nil {
+			return false, err
+		}
+		dcgs.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.CGScaleInSnapshotCreated), fmt.Sprintf("compute group %s create volume snapshot %s of pvc %s before deleting.", cg.UniqueId, vs.GetName(), pvc.Name))
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	ready, msg := volumeSnapshotReady(vs)
+	if msg != "" {
+		//the failed snapshot is kept for user checking, the pvc is kept until the snapshot deleted and recreated ready.
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGScaleInSnapshotFailed), fmt.Sprintf("compute group %s volume snapshot %s of pvc %s failed, the pvc is kept, err=%s", cg.UniqueId, vs.GetName(), pvc.Name, msg))
+		klog.Errorf("disaggregatedComputeGroupsController snapshotPVCBeforeDelete namespace %s volume snapshot %s failed, err=%s", pvc.Namespace, vs.GetName(), msg)
+	}
+	return ready, nil
+}
+
+// scaleInSnapshotName return the name of VolumeSnapshot for the pvc, the uid of pvc is used for distinguishing the pvc recreated by scaling out again.
+func scaleInSnapshotName(pvc *corev1.PersistentVolumeClaim) string {
+	uid := string(pvc.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	if uid == "" {
+		return pvc.Name + "-scalein"
+	}
+	return pvc.Name + "-scalein-" + uid
+}
+
+// newScaleInVolumeSnapshot build the VolumeSnapshot of pvc, the snapshot not have owner reference for keeping the recovery point when cluster deleted.
+func newScaleInVolumeSnapshot(pvc *corev1.PersistentVolumeClaim, className string, labels map[string]string) *unstructured.Unstructured {
+	vs := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"volumeSnapshotClassName": className,
+			"source": map[string]interface{}{
+				"persistentVolumeClaimName": pvc.Name,
+			},
+		},
+	}}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	vs.SetNamespace(pvc.Namespace)
+	vs.SetName(scaleInSnapshotName(pvc))
+	vs.SetLabels(labels)
+	return vs
+}
+
+// volumeSnapshotReady return the snapshot is ready to use or not, and the error message when snapshotting failed.
+func volumeSnapshotReady(vs *unstructured.Unstructured) (bool, string) {
+	ready, _, _ := unstructured.NestedBool(vs.Object, "status", "readyToUse")
+	msg, _, _ := unstructured.NestedString(vs.Object, "status", "error", "message")
+	return ready, msg
+}
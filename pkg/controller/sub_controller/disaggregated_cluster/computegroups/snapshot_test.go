@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ClearStatefulsetUnusedPVCs_Snapshot(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: dv1.DorisDisaggregatedClusterSpec{
+			ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1", ScaleInSnapshotClassName: "csi-snapclass"}},
+		},
+	}
+	dcgs := &DisaggregatedComputeGroupsController{}
+	labels := dcgs.newCGPodsSelector(ddc.Name, "cg1")
+	objs := []client.Object{
+		&appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}, Spec: appv1.StatefulSetSpec{Replicas: pointer.Int32(1)}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-test-cg1-0", Labels: labels}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-test-cg1-1", Labels: labels, UID: "2b7c9e1f-0000-0000-0000-000000000000"}},
+	}
+	k8sclient := fake.NewClientBuilder().WithObjects(objs...).Build()
+	dcgs.DisaggregatedSubDefaultController = sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(10)}
+	ctx := context.Background()
+
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1"}
+	if err := dcgs.ClearStatefulsetUnusedPVCs(ctx, ddc, cgs); err != nil {
+		t.Fatalf("ClearStatefulsetUnusedPVCs failed, err=%s", err.Error())
+	}
+	if cgs.PendingScaleInSnapshots != 1 {
+		t.Errorf("ClearStatefulsetUnusedPVCs expected 1 pending snapshot, got %d", cgs.PendingScaleInSnapshots)
+	}
+	if _, err := k8s.GetPVC(ctx, k8sclient, "data-test-cg1-1", "default"); err != nil {
+		t.Errorf("ClearStatefulsetUnusedPVCs expected pvc kept before snapshot ready, err=%s", err.Error())
+	}
+
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(volumeSnapshotGVK)
+	if err := k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data-test-cg1-1-scalein-2b7c9e1f"}, vs); err != nil {
+		t.Fatalf("ClearStatefulsetUnusedPVCs expected volume snapshot created, err=%s", err.Error())
+	}
+	if className, _, _ := unstructured.NestedString(vs.Object, "spec", "volumeSnapshotClassName"); className != "csi-snapclass" {
+		t.Errorf("volume snapshot class expected csi-snapclass, got %s", className)
+	}
+	_ = unstructured.SetNestedField(vs.Object, true, "status", "readyToUse")
+	if err := k8sclient.Update(ctx, vs); err != nil {
+		t.Fatalf("update volume snapshot status failed, err=%s", err.Error())
+	}
+
+	if err := dcgs.ClearStatefulsetUnusedPVCs(ctx, ddc, cgs); err != nil {
+		t.Fatalf("ClearStatefulsetUnusedPVCs failed, err=%s", err.Error())
+	}
+	if cgs.PendingScaleInSnapshots != 0 {
+		t.Errorf("ClearStatefulsetUnusedPVCs expected no pending snapshot, got %d", cgs.PendingScaleInSnapshots)
+	}
+	if _, err := k8s.GetPVC(ctx, k8sclient, "data-test-cg1-1", "default"); err == nil {
+		t.Errorf("ClearStatefulsetUnusedPVCs expected pvc deleted after snapshot ready")
+	}
+	if _, err := k8s.GetPVC(ctx, k8sclient, "data-test-cg1-0", "default"); err != nil {
+		t.Errorf("ClearStatefulsetUnusedPVCs expected pvc of running pod kept, err=%s", err.Error())
+	}
+}
@@ -24,9 +24,13 @@ import (
 	sub "github.com/apache/doris-operator/pkg/controller/sub_controller"
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
+	"regexp"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -34,6 +38,11 @@ const (
 	auth_volume_name = "basic-auth"
 )
 
+var (
+	versionRegexp           = regexp.MustCompile(`[0-9]+(\.[0-9]+)+[0-9A-Za-z._-]*`)
+	invalidLabelValueRegexp = regexp.MustCompile(`[^0-9A-Za-z._-]`)
+)
+
 // generate statefulset or service labels
 func (dcgs *DisaggregatedComputeGroupsController) newCG2LayerSchedulerLabels(ddcName /*DisaggregatedClusterName*/, uniqueId string) map[string]string {
 	labels := dcgs.GetCG2LayerCommonSchedulerLabels(ddcName)
@@ -75,6 +84,9 @@ func (dcgs *DisaggregatedComputeGroupsController) NewStatefulset(ddc *dv1.DorisD
 		st.Spec.Replicas = cg.Replicas
 		st.Spec.VolumeClaimTemplates = vcts
 		st.Spec.ServiceName = ddc.GetCGServiceName(cg)
+		if cg.PodManagementPolicy != "" {
+			st.Spec.PodManagementPolicy = cg.PodManagementPolicy
+		}
 		pts := dcgs.NewPodTemplateSpec(ddc, matchLabels, cvs, cg)
 		st.Spec.Template = pts
 	}()
@@ -84,18 +96,36 @@ func (dcgs *DisaggregatedComputeGroupsController) NewStatefulset(ddc *dv1.DorisD
 
 func (dcgs *DisaggregatedComputeGroupsController) NewPodTemplateSpec(ddc *dv1.DorisDisaggregatedCluster, selector map[string]string, cvs map[string]interface{}, cg *dv1.ComputeGroup) corev1.PodTemplateSpec {
 	pts := resource.NewPodTemplateSpecWithCommonSpec(cg.SkipDefaultSystemInit, &cg.CommonSpec, dv1.DisaggregatedBE)
+	pts.Spec.SecurityContext = newCGPodSecurityContext(cg.SecurityContext)
 	//pod template metadata.
 	func() {
 		l := (resource.Labels)(selector)
 		l.AddLabel(pts.Labels)
 		pts.Labels = l
 	}()
+	//the labels for monitoring use a new map, the version changes in upgrading should not change the selector of statefulset.
+	if version := imageVersion(cg.Image); version != "" {
+		l := resource.Labels{}
+		l.AddLabel(pts.Labels)
+		l.Add(dv1.DorisDisaggregatedVersion, version)
+		pts.Labels = l
+	}
+	//the service selects the serving pods when connection draining configured, the draining pods are labeled `false` for taking out of the endpoints.
+	if cg.ConnectionDraining != nil {
+		l := resource.Labels{}
+		l.AddLabel(pts.Labels)
+		l.Add(dv1.DorisDisaggregatedServing, "true")
+		pts.Labels = l
+	}
 
 	c := dcgs.NewCGContainer(ddc, cvs, cg)
 	pts.Spec.Containers = append(pts.Spec.Containers, c)
+	if lc := newLogRetentionContainer(cg, &c); lc != nil {
+		pts.Spec.Containers = append(pts.Spec.Containers, *lc)
+	}
 
 	vs, _, _ := dcgs.BuildVolumesVolumeMountsAndPVCs(cvs, dv1.DisaggregatedBE, &cg.CommonSpec)
-	configVolumes, _ := dcgs.BuildDefaultConfigMapVolumesVolumeMounts(cg.ConfigMaps)
+	configVolumes, _ := dcgs.BuildDefaultConfigMapVolumesVolumeMounts(mountedConfigMaps(ddc, cg))
 	pts.Spec.Volumes = append(pts.Spec.Volumes, configVolumes...)
 	pts.Spec.Volumes = append(pts.Spec.Volumes, vs...)
 
@@ -119,10 +149,32 @@ func (dcgs *DisaggregatedComputeGroupsController) NewPodTemplateSpec(ddc *dv1.Do
 	dcgs.DisaggregatedSubDefaultController.AddClusterSpecForPodTemplate(dv1.DisaggregatedBE, cvs, &ddc.Spec, &pts)
 	cgUniqueId := selector[dv1.DorisDisaggregatedComputeGroupUniqueId]
 	pts.Spec.Affinity = dcgs.ConstructDefaultAffinity(dv1.DorisDisaggregatedComputeGroupUniqueId, cgUniqueId, pts.Spec.Affinity)
+	if cg.RackAwareness != nil && cg.RackAwareness.TopologyKey != "" {
+		pts.Spec.TopologySpreadConstraints = append(pts.Spec.TopologySpreadConstraints, newRackSpreadConstraint(cg.RackAwareness, selector))
+	}
 
 	return pts
 }
 
+// newRackSpreadConstraint spread the pods of compute group across racks, the selector matches all pods of compute group.
+func newRackSpreadConstraint(ra *dv1.RackAwareness, selector map[string]string) corev1.TopologySpreadConstraint {
+	maxSkew := ra.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = 1
+	}
+	whenUnsatisfiable := ra.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+
+	return corev1.TopologySpreadConstraint{
+		MaxSkew:           maxSkew,
+		TopologyKey:       ra.TopologyKey,
+		WhenUnsatisfiable: whenUnsatisfiable,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: selector},
+	}
+}
+
 func (dcgs *DisaggregatedComputeGroupsController) NewCGContainer(ddc *dv1.DorisDisaggregatedCluster, cvs map[string]interface{}, cg *dv1.ComputeGroup) corev1.Container {
 
 	if cg.EnableWorkloadGroup {
@@ -144,6 +196,11 @@ func (dcgs *DisaggregatedComputeGroupsController) NewCGContainer(ddc *dv1.DorisD
 	c.Env = append(c.Env, resource.GetPodDefaultEnv()...)
 	c.Env = append(c.Env, dcgs.newSpecificEnvs(ddc, cg)...)
 
+	//the script uses webserver_port when the port not specified, not add env for keeping the statefulset unchanged.
+	if cg.GracefulStopPort != 0 {
+		c.Env = append(c.Env, corev1.EnvVar{Name: resource.GRACEFUL_STOP_PORT, Value: strconv.FormatInt(int64(cg.GracefulStopPort), 10)})
+	}
+
 	if cg.SkipDefaultSystemInit {
 		// Only works when the doris version is higher than 2.1.8 or 3.0.4
 		// When the environment variable SKIP_CHECK_ULIMIT=true is passed in, the start_be.sh will not check system parameters like ulimit and vm.max_map_count etc.
@@ -152,7 +209,7 @@ func (dcgs *DisaggregatedComputeGroupsController) NewCGContainer(ddc *dv1.DorisD
 
 	resource.BuildDisaggregatedProbe(&c, &cg.CommonSpec, dv1.DisaggregatedBE)
 	_, vms, _ := dcgs.BuildVolumesVolumeMountsAndPVCs(cvs, dv1.DisaggregatedBE, &cg.CommonSpec)
-	_, cmvms := dcgs.BuildDefaultConfigMapVolumesVolumeMounts(cg.ConfigMaps)
+	_, cmvms := dcgs.BuildDefaultConfigMapVolumesVolumeMounts(mountedConfigMaps(ddc, cg))
 	c.VolumeMounts = vms
 	if c.VolumeMounts == nil {
 		c.VolumeMounts = cmvms
@@ -176,6 +233,48 @@ func (dcgs *DisaggregatedComputeGroupsController) NewCGContainer(ddc *dv1.DorisD
 	return c
 }
 
+// gracefulStopPort return the port that preStop and decommission waiting reach to stop backend gracefully, default is the webserver_port in be config.
+func gracefulStopPort(cg *dv1.ComputeGroup, cvs map[string]interface{}) int32 {
+	if cg.GracefulStopPort != 0 {
+		return cg.GracefulStopPort
+	}
+	return resource.GetPort(cvs, resource.WEBSERVER_PORT)
+}
+
+// checkGracefulStopPort emit warning event when the graceful stop port is not exposed on the compute container, or the decommission requested but the port not configured.
+func (dcgs *DisaggregatedComputeGroupsController) checkGracefulStopPort(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cvs map[string]interface{}, spec *corev1.PodSpec) {
+	port := gracefulStopPort(cg, cvs)
+	var msg string
+	if port <= 0 {
+		if !decommissionEnabled(ddc, cg) {
+			return
+		}
+		msg = fmt.Sprintf("compute group %s enable decommission but the graceful stop port is not configured, please config gracefulStopPort or webserver_port in be config.", cg.UniqueId)
+	} else if !containerPortExposed(spec, resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, port) {
+		msg = fmt.Sprintf("compute group %s graceful stop port %d is not exposed on container %s, the backend can not be stopped gracefully.", cg.UniqueId, port, resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME)
+	} else {
+		return
+	}
+
+	klog.Errorf("disaggregatedComputeGroupsController checkGracefulStopPort namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sub.EventWarning), string(sub.CGGracefulStopPortInvalid), msg)
+}
+
+// containerPortExposed return true when the port is in the ports of the named container.
+func containerPortExposed(spec *corev1.PodSpec, containerName string, port int32) bool {
+	for _, c := range spec.Containers {
+		if c.Name != containerName {
+			continue
+		}
+		for _, p := range c.Ports {
+			if p.ContainerPort == port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // add specific envs for be, the env will used by be_disaggregated_entrypoint script.
 func (dcgs *DisaggregatedComputeGroupsController) newSpecificEnvs(ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup) []corev1.EnvVar {
 	var cgEnvs []corev1.EnvVar
@@ -207,9 +306,127 @@ func (dcgs *DisaggregatedComputeGroupsController) newSpecificEnvs(ddc *dv1.Doris
 		)
 	}
 
+	if cg.EnableMemoryAutoTuning {
+		cgEnvs = append(cgEnvs, newMemoryTuningEnvs(cg)...)
+	}
+
+	cgEnvs = append(cgEnvs, newLogRotationEnvs(cg.LogRotation)...)
+
 	return cgEnvs
 }
 
+// newMemoryTuningEnvs derive be memory config from the container memory limit, if limit not set use request.
+// mem_limit is 90% of container memory for reserving space to jvm and system, storage_page_cache is 20% and chunk_reserved_bytes is 10%.
+func newMemoryTuningEnvs(cg *dv1.ComputeGroup) []corev1.EnvVar {
+	mem, ok := cg.Limits[corev1.ResourceMemory]
+	if !ok {
+		mem, ok = cg.Requests[corev1.ResourceMemory]
+	}
+	if !ok || mem.Value() <= 0 {
+		klog.Infof("disaggregatedComputeGroupsController compute group %s enable memory auto tuning, but not config memory resource.", cg.UniqueId)
+		return nil
+	}
+
+	bytes := mem.Value()
+	return []corev1.EnvVar{
+		{Name: resource.BE_MEM_LIMIT, Value: strconv.FormatInt(bytes/10*9, 10)},
+		{Name: resource.BE_STORAGE_PAGE_CACHE_LIMIT, Value: strconv.FormatInt(bytes/5, 10)},
+		{Name: resource.BE_CHUNK_RESERVED_BYTES_LIMIT, Value: strconv.FormatInt(bytes/10, 10)},
+	}
+}
+
+// newLogRotationEnvs return the envs of be log rolling config, the env is not added when the config not set for keeping the statefulset unchanged.
+func newLogRotationEnvs(lr *dv1.LogRotation) []corev1.EnvVar {
+	if lr == nil {
+		return nil
+	}
+	var envs []corev1.EnvVar
+	if lr.RollSizeMB > 0 {
+		envs = append(envs, corev1.EnvVar{Name: resource.BE_SYS_LOG_ROLL_MODE, Value: fmt.Sprintf("SIZE-MB-%d", lr.RollSizeMB)})
+	}
+	if lr.RollNum > 0 {
+		envs = append(envs, corev1.EnvVar{Name: resource.BE_SYS_LOG_ROLL_NUM, Value: strconv.FormatInt(int64(lr.RollNum), 10)})
+	}
+	return envs
+}
+
+// newLogRetentionContainer return the sidecar that deletes the files in log volume modified before retention days, nil when retention not set or the log not stored in pvc.
+func newLogRetentionContainer(cg *dv1.ComputeGroup, c *corev1.Container) *corev1.Container {
+	if cg.LogRotation == nil || cg.LogRotation.RetentionDays <= 0 {
+		return nil
+	}
+	var logMount *corev1.VolumeMount
+	for i := range c.VolumeMounts {
+		if c.VolumeMounts[i].Name == sub.BELogStoreName {
+			logMount = &c.VolumeMounts[i]
+			break
+		}
+	}
+	if logMount == nil {
+		klog.Infof("disaggregatedComputeGroupsController compute group %s config log retention days, but the log not stored in pvc.", cg.UniqueId)
+		return nil
+	}
+
+	image := resource.DEFAULT_INIT_IMAGE
+	if cg.SystemInitialization != nil && cg.SystemInitialization.InitImage != "" {
+		image = cg.SystemInitialization.InitImage
+	}
+	script := fmt.Sprintf("while true; do find %s -type f -mtime +%d -delete; sleep 3600; done", logMount.MountPath, cg.LogRotation.RetentionDays-1)
+	return &corev1.Container{
+		Name:            resource.DISAGGREGATED_BE_LOG_RETENTION_CONTAINER_NAME,
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{script},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("10m"), corev1.ResourceMemory: apiresource.MustParse("16Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("100m"), corev1.ResourceMemory: apiresource.MustParse("64Mi")},
+		},
+		VolumeMounts: []corev1.VolumeMount{{Name: logMount.Name, MountPath: logMount.MountPath}},
+	}
+}
+
 func(dcgs *DisaggregatedComputeGroupsController) useNewDefaultValuesInStatefulset(st *appv1.StatefulSet) {
 	resource.UseNewDefaultInitContainerImage(&st.Spec.Template)
-}
\ No newline at end of file
+}
+
+// imageVersion parse the doris version from the tag of image as label value, ep: `apache/doris:be-3.0.3` returns `3.0.3`.
+// return the sanitized tag when not contains version, and empty when the image have not tag.
+func imageVersion(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	tag := image[i+1:]
+	if v := versionRegexp.FindString(tag); v != "" {
+		tag = v
+	}
+
+	tag = invalidLabelValueRegexp.ReplaceAllString(tag, "-")
+	if len(tag) > 63 {
+		tag = tag[:63]
+	}
+	return strings.Trim(tag, "-_.")
+}
+
+// preservePodAnnotations copy the pod template annotations that have the prefixes from the existing statefulset, the annotations set by operator take precedence.
+func preservePodAnnotations(st, est *appv1.StatefulSet, prefixes []string) {
+	for k, v := range est.Spec.Template.Annotations {
+		if _, ok := st.Spec.Template.Annotations[k]; ok {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if prefix == "" || !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			if st.Spec.Template.Annotations == nil {
+				st.Spec.Template.Annotations = map[string]string{}
+			}
+			st.Spec.Template.Annotations[k] = v
+			break
+		}
+	}
+}
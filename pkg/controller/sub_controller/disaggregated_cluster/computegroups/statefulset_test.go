@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"strings"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_newMemoryTuningEnvs(t *testing.T) {
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	if envs := newMemoryTuningEnvs(cg); len(envs) != 0 {
+		t.Errorf("newMemoryTuningEnvs without memory resource expected empty, got %v", envs)
+	}
+
+	cg.Requests = corev1.ResourceList{corev1.ResourceMemory: apiresource.MustParse("8Gi")}
+	cg.Limits = corev1.ResourceList{corev1.ResourceMemory: apiresource.MustParse("10Gi")}
+	want := map[string]string{
+		resource.BE_MEM_LIMIT:                  "9663676416",
+		resource.BE_STORAGE_PAGE_CACHE_LIMIT:   "2147483648",
+		resource.BE_CHUNK_RESERVED_BYTES_LIMIT: "1073741824",
+	}
+	envs := newMemoryTuningEnvs(cg)
+	if len(envs) != len(want) {
+		t.Fatalf("newMemoryTuningEnvs expected %d envs, got %v", len(want), envs)
+	}
+	for _, env := range envs {
+		if want[env.Name] != env.Value {
+			t.Errorf("newMemoryTuningEnvs env %s expected %s, got %s", env.Name, want[env.Name], env.Value)
+		}
+	}
+}
+
+func Test_newLogRotationEnvs(t *testing.T) {
+	if envs := newLogRotationEnvs(nil); len(envs) != 0 {
+		t.Errorf("newLogRotationEnvs without log rotation expected empty, got %v", envs)
+	}
+	envs := newLogRotationEnvs(&dv1.LogRotation{RollSizeMB: 512, RollNum: 5})
+	want := map[string]string{
+		resource.BE_SYS_LOG_ROLL_MODE: "SIZE-MB-512",
+		resource.BE_SYS_LOG_ROLL_NUM:  "5",
+	}
+	if len(envs) != len(want) {
+		t.Fatalf("newLogRotationEnvs expected %d envs, got %v", len(want), envs)
+	}
+	for _, env := range envs {
+		if want[env.Name] != env.Value {
+			t.Errorf("newLogRotationEnvs env %s expected %s, got %s", env.Name, want[env.Name], env.Value)
+		}
+	}
+}
+
+func Test_newLogRetentionContainer(t *testing.T) {
+	c := &corev1.Container{VolumeMounts: []corev1.VolumeMount{{Name: sc.BELogStoreName, MountPath: "/opt/apache-doris/be/log"}}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", LogRotation: &dv1.LogRotation{RollNum: 5}}
+	if lc := newLogRetentionContainer(cg, c); lc != nil {
+		t.Errorf("newLogRetentionContainer without retention days expected nil, got %+v", lc)
+	}
+
+	cg.LogRotation.RetentionDays = 7
+	lc := newLogRetentionContainer(cg, c)
+	if lc == nil {
+		t.Fatalf("newLogRetentionContainer with retention days expected sidecar, got nil")
+	}
+	if lc.Image != resource.DEFAULT_INIT_IMAGE || len(lc.VolumeMounts) != 1 || lc.VolumeMounts[0].MountPath != "/opt/apache-doris/be/log" {
+		t.Errorf("newLogRetentionContainer not use default image or not mount log volume, got %+v", lc)
+	}
+	if len(lc.Args) != 1 || !strings.Contains(lc.Args[0], "find /opt/apache-doris/be/log -type f -mtime +6 -delete") {
+		t.Errorf("newLogRetentionContainer script not recycle files before retention days, got %v", lc.Args)
+	}
+
+	if lc := newLogRetentionContainer(cg, &corev1.Container{}); lc != nil {
+		t.Errorf("newLogRetentionContainer without log pvc expected nil, got %+v", lc)
+	}
+}
+
+func Test_newRackSpreadConstraint(t *testing.T) {
+	selector := map[string]string{dv1.DorisDisaggregatedComputeGroupUniqueId: "cg1"}
+	tsc := newRackSpreadConstraint(&dv1.RackAwareness{TopologyKey: "topology.kubernetes.io/rack"}, selector)
+	if tsc.MaxSkew != 1 || tsc.WhenUnsatisfiable != corev1.ScheduleAnyway || tsc.TopologyKey != "topology.kubernetes.io/rack" {
+		t.Errorf("newRackSpreadConstraint default values not expected, got %+v", tsc)
+	}
+	if tsc.LabelSelector == nil || tsc.LabelSelector.MatchLabels[dv1.DorisDisaggregatedComputeGroupUniqueId] != "cg1" {
+		t.Errorf("newRackSpreadConstraint label selector not match compute group pods, got %+v", tsc.LabelSelector)
+	}
+
+	tsc = newRackSpreadConstraint(&dv1.RackAwareness{TopologyKey: "rack", MaxSkew: 2, WhenUnsatisfiable: corev1.DoNotSchedule}, selector)
+	if tsc.MaxSkew != 2 || tsc.WhenUnsatisfiable != corev1.DoNotSchedule {
+		t.Errorf("newRackSpreadConstraint not use configured values, got %+v", tsc)
+	}
+}
+
+func Test_imageVersion(t *testing.T) {
+	images := []string{"apache/doris:be-3.0.3", "registry.local:5000/doris/be:3.0.3-rc01", "registry.local:5000/doris/be", "apache/doris:latest", "apache/doris:be-3.0.3@sha256:abc"}
+	versions := []string{"3.0.3", "3.0.3-rc01", "", "latest", "3.0.3"}
+	for i := range images {
+		if v := imageVersion(images[i]); v != versions[i] {
+			t.Errorf("imageVersion of %s expected %q, got %q", images[i], versions[i], v)
+		}
+	}
+}
+
+func Test_NewStatefulset_VersionLabel(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", CommonSpec: dv1.CommonSpec{Image: "apache/doris:be-3.0.3", Replicas: resource.GetInt32Pointer(1)}}
+	dcgs := &DisaggregatedComputeGroupsController{}
+
+	st := dcgs.NewStatefulset(ddc, cg, map[string]interface{}{})
+	if st.Spec.Template.Labels[dv1.DorisDisaggregatedVersion] != "3.0.3" || st.Spec.Template.Labels[dv1.DorisDisaggregatedComputeGroupUniqueId] != "cg1" {
+		t.Errorf("NewStatefulset pod labels not expected, got %v", st.Spec.Template.Labels)
+	}
+	if _, ok := st.Spec.Selector.MatchLabels[dv1.DorisDisaggregatedVersion]; ok {
+		t.Errorf("NewStatefulset version label should not in selector, got %v", st.Spec.Selector.MatchLabels)
+	}
+}
+
+func Test_checkGracefulStopPort(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{
+		Name:  resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME,
+		Ports: resource.GetDisaggregatedContainerPorts(nil, dv1.DisaggregatedBE),
+	}}}
+
+	cg := &dv1.ComputeGroup{UniqueId: "cg1"}
+	if port := gracefulStopPort(cg, nil); port != 8040 {
+		t.Errorf("gracefulStopPort expected default webserver_port 8040, got %d", port)
+	}
+	dcgs.checkGracefulStopPort(ddc, cg, nil, spec)
+	if len(recorder.Events) != 0 {
+		t.Errorf("checkGracefulStopPort expected no event for the exposed webserver_port, got %d", len(recorder.Events))
+	}
+
+	cg.GracefulStopPort = 8041
+	dcgs.checkGracefulStopPort(ddc, cg, nil, spec)
+	if len(recorder.Events) != 1 {
+		t.Errorf("checkGracefulStopPort expected warning for the port not exposed, got %d", len(recorder.Events))
+	}
+}
+
+func Test_preservePodAnnotations(t *testing.T) {
+	st := &appv1.StatefulSet{}
+	st.Spec.Template.Annotations = map[string]string{"sidecar.istio.io/inject": "false"}
+	est := &appv1.StatefulSet{}
+	est.Spec.Template.Annotations = map[string]string{
+		"sidecar.istio.io/inject": "true",
+		"sidecar.istio.io/status": "injected",
+		"other.io/annotation":     "v",
+	}
+
+	preservePodAnnotations(st, est, []string{"sidecar.istio.io/"})
+	annos := st.Spec.Template.Annotations
+	if len(annos) != 2 || annos["sidecar.istio.io/inject"] != "false" || annos["sidecar.istio.io/status"] != "injected" {
+		t.Errorf("preservePodAnnotations expected the prefixed annotations kept and the operator ones take precedence, got %v", annos)
+	}
+
+	st = &appv1.StatefulSet{}
+	preservePodAnnotations(st, est, nil)
+	if len(st.Spec.Template.Annotations) != 0 {
+		t.Errorf("preservePodAnnotations expected nothing kept without prefixes, got %v", st.Spec.Template.Annotations)
+	}
+}
@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// waitStorageVault return the unique ids of compute groups that should wait the storage vault configured in fe, the backends are useless without storage vault.
+// only the compute groups not created(have not status or in WaitingStorageVault phase) wait, they are marked WaitingStorageVault. the created compute groups are not affected.
+func (dcgs *DisaggregatedComputeGroupsController) waitStorageVault(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) map[string]bool {
+	if !ddc.Spec.RequireStorageVault {
+		return nil
+	}
+	var pending []*dv1.ComputeGroup
+	for i := range ddc.Spec.ComputeGroups {
+		cgStatus := findCGStatus(ddc, ddc.Spec.ComputeGroups[i].UniqueId)
+		if cgStatus == nil || cgStatus.Phase == dv1.WaitingStorageVault {
+			pending = append(pending, &ddc.Spec.ComputeGroups[i])
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := dcgs.storageVaultConfigured(ctx, ddc)
+	if err == nil {
+		return nil
+	}
+
+	waiting := map[string]bool{}
+	var uniqueIds []string
+	for _, cg := range pending {
+		waiting[cg.UniqueId] = true
+		uniqueIds = append(uniqueIds, cg.UniqueId)
+		if findCGStatus(ddc, cg.UniqueId) == nil {
+			ddc.Status.ComputeGroupStatuses = append(ddc.Status.ComputeGroupStatuses, dv1.ComputeGroupStatus{
+				Phase:           dv1.WaitingStorageVault,
+				UniqueId:        cg.UniqueId,
+				StatefulsetName: ddc.GetCGStatefulsetName(cg),
+				ServiceName:     ddc.GetCGServiceName(cg),
+			})
+		}
+	}
+	msg := fmt.Sprintf("compute groups %s waiting the storage vault configured in fe, %s", strings.Join(uniqueIds, ","), err.Error())
+	klog.Infof("disaggregatedComputeGroupsController waitStorageVault namespace=%s name=%s %s", ddc.Namespace, ddc.Name, msg)
+	dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGWaitingStorageVault), msg)
+	return waiting
+}
+
+// storageVaultConfigured check the storage vault configured by `show storage vault`, return nil when fe have storage vault.
+func (dcgs *DisaggregatedComputeGroupsController) storageVaultConfigured(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) error {
+	sqlClient, err := dcgs.getMasterSqlClient(ctx, ddc)
+	if err != nil {
+		return fmt.Errorf("connect fe failed, err=%s", err.Error())
+	}
+	defer sqlClient.Close()
+
+	vaults, err := sqlClient.ShowStorageVaults()
+	if err != nil {
+		return fmt.Errorf("show storage vault failed, err=%s", err.Error())
+	}
+	if len(vaults) != 0 && !hasDefaultStorageVault(vaults) {
+		klog.Infof("disaggregatedComputeGroupsController storageVaultConfigured namespace=%s name=%s have not default storage vault, the tables should specify the storage vault when creating.", ddc.Namespace, ddc.Name)
+	}
+	if len(vaults) == 0 {
+		return errors.New("no storage vault found, please create the storage vault(s3 or hdfs) in fe.")
+	}
+	return nil
+}
+
+func hasDefaultStorageVault(vaults []*mysql.StorageVault) bool {
+	for _, vault := range vaults {
+		if vault.IsDefault {
+			return true
+		}
+	}
+	return false
+}
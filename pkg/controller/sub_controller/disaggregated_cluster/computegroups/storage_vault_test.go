@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_waitStorageVault(t *testing.T) {
+	k8sclient := fake.NewClientBuilder().Build()
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: k8sclient, K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1"}, {UniqueId: "cg2"}}},
+		Status:     dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{{UniqueId: "cg1", Phase: dv1.Ready}}},
+	}
+
+	if waiting := dcgs.waitStorageVault(context.Background(), ddc); len(waiting) != 0 {
+		t.Errorf("waitStorageVault not required expected no waiting, got %v", waiting)
+	}
+
+	//the fe not reachable, the storage vault can not be confirmed.
+	ddc.Spec.RequireStorageVault = true
+	waiting := dcgs.waitStorageVault(context.Background(), ddc)
+	cgStatus := findCGStatus(ddc, "cg2")
+	if len(waiting) != 1 || !waiting["cg2"] || cgStatus == nil || cgStatus.Phase != dv1.WaitingStorageVault || len(recorder.Events) != 1 {
+		t.Errorf("waitStorageVault expected cg2 waiting in WaitingStorageVault phase, got %v status %+v", waiting, cgStatus)
+	}
+	if cg1Status := findCGStatus(ddc, "cg1"); cg1Status.Phase != dv1.Ready {
+		t.Errorf("waitStorageVault expected created cg1 not affected, got phase %s", cg1Status.Phase)
+	}
+
+	replicas := int32(1)
+	ddc.Spec.ComputeGroups[1].Replicas = &replicas
+	dcgs.initialCGStatus(ddc, &ddc.Spec.ComputeGroups[1])
+	if cgStatus = findCGStatus(ddc, "cg2"); cgStatus.Phase != dv1.Reconciling {
+		t.Errorf("initialCGStatus expected the waiting compute group reconciling, got phase %s", cgStatus.Phase)
+	}
+}
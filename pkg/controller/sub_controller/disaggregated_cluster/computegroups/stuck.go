@@ -0,0 +1,116 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// the default seconds waiting any pod of compute group ready, the image pulling and be starting normally finish in minutes.
+const defaultReadyDeadlineSeconds int32 = 1800
+
+// checkCGStuck set the Stuck phase when the compute group have no ready pod beyond the ready deadline, the warning event with the likely cause emitted when the phase entered.
+// the phase only replaces the generic Reconciling, Scaling and AwaitingStorage, it is restored to Reconciling when any pod ready.
+func (dcgs *DisaggregatedComputeGroupsController) checkCGStuck(ddc *dv1.DorisDisaggregatedCluster, cgs *dv1.ComputeGroupStatus, sts *appv1.StatefulSet, pods []corev1.Pod) {
+	deadline := time.Duration(defaultReadyDeadlineSeconds) * time.Second
+	if cg := findCG(ddc, cgs.UniqueId); cg != nil && cg.ReadyDeadlineSeconds != nil {
+		deadline = time.Duration(*cg.ReadyDeadlineSeconds) * time.Second
+	}
+
+	stuck := deadline > 0 && cgs.Replicas > 0 && cgs.AvailableReplicas == 0 && time.Since(notReadySince(sts, pods)) >= deadline
+	if !stuck {
+		if cgs.Phase == dv1.Stuck {
+			cgs.Phase = dv1.Reconciling
+		}
+		return
+	}
+	if cgs.Phase != dv1.Reconciling && cgs.Phase != dv1.Scaling && cgs.Phase != dv1.AwaitingStorage && cgs.Phase != dv1.Stuck {
+		return
+	}
+	if cgs.Phase != dv1.Stuck {
+		msg := fmt.Sprintf("compute group %s have no ready pod beyond %s, the likely cause: %s", cgs.UniqueId, deadline, stuckCause(pods))
+		klog.Errorf("disaggregatedComputeGroupsController checkCGStuck namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGStuck), msg)
+	}
+	cgs.Phase = dv1.Stuck
+}
+
+// notReadySince return the time that the compute group starts waiting pods ready, the later of the creation of statefulset and the earliest creation of the pods.
+// the pods recreated(ep: deleted for rolling) restart the waiting.
+func notReadySince(sts *appv1.StatefulSet, pods []corev1.Pod) time.Time {
+	since := sts.CreationTimestamp.Time
+	var earliest time.Time
+	for _, pod := range pods {
+		if earliest.IsZero() || pod.CreationTimestamp.Time.Before(earliest) {
+			earliest = pod.CreationTimestamp.Time
+		}
+	}
+	if earliest.After(since) {
+		since = earliest
+	}
+	return since
+}
+
+// stuckCause return the likely cause of pods not ready gleaned from the pod conditions and container states, the first pod ordered by name that have a cause is used.
+func stuckCause(pods []corev1.Pod) string {
+	if len(pods) == 0 {
+		return "no pod created, please check the events of statefulset, ep: the pods rejected by resource quota or admission webhook."
+	}
+	sorted := append([]corev1.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for i := range sorted {
+		if cause := podNotReadyCause(&sorted[i]); cause != "" {
+			return cause
+		}
+	}
+	return "pods not ready, please check the events and logs of pods."
+}
+
+// podNotReadyCause describe why the pod not ready in the order of scheduling, containers waiting and readiness.
+func podNotReadyCause(pod *corev1.Pod) string {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			return fmt.Sprintf("pod %s not scheduled, %s: %s", pod.Name, c.Reason, c.Message)
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" && w.Reason != "PodInitializing" && w.Reason != "ContainerCreating" {
+			return fmt.Sprintf("pod %s container %s waiting, %s: %s", pod.Name, cs.Name, w.Reason, w.Message)
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Running != nil && !cs.Ready {
+			return fmt.Sprintf("pod %s container %s running but not passed the readiness probe, please check the logs of container.", pod.Name, cs.Name)
+		}
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.ContainersReady && c.Status == corev1.ConditionFalse && c.Message != "" {
+			return fmt.Sprintf("pod %s %s: %s", pod.Name, c.Reason, c.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_checkCGStuck(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       dv1.DorisDisaggregatedClusterSpec{ComputeGroups: []dv1.ComputeGroup{{UniqueId: "cg1"}}},
+	}
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	sts := &appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1", CreationTimestamp: created}}
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-0", CreationTimestamp: created},
+		Status: corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "compute", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+		}}},
+	}}
+	cgs := &dv1.ComputeGroupStatus{UniqueId: "cg1", Replicas: 1, Phase: dv1.Reconciling}
+
+	dcgs.checkCGStuck(ddc, cgs, sts, pods)
+	dcgs.checkCGStuck(ddc, cgs, sts, pods)
+	if cgs.Phase != dv1.Stuck || len(recorder.Events) != 1 {
+		t.Fatalf("checkCGStuck expected Stuck with 1 event beyond deadline, got phase %s events %d", cgs.Phase, len(recorder.Events))
+	}
+	if e := <-recorder.Events; !strings.Contains(e, "ImagePullBackOff") {
+		t.Errorf("checkCGStuck expected the event have the cause ImagePullBackOff, got %s", e)
+	}
+
+	deadline := int32(7200)
+	ddc.Spec.ComputeGroups[0].ReadyDeadlineSeconds = &deadline
+	dcgs.checkCGStuck(ddc, cgs, sts, pods)
+	if cgs.Phase != dv1.Reconciling {
+		t.Errorf("checkCGStuck expected Reconciling in the configured deadline, got %s", cgs.Phase)
+	}
+
+	cgs.Phase = dv1.Suspended
+	deadline = 60
+	dcgs.checkCGStuck(ddc, cgs, sts, pods)
+	if cgs.Phase != dv1.Suspended {
+		t.Errorf("checkCGStuck expected not replace phase %s, got %s", dv1.Suspended, cgs.Phase)
+	}
+}
+
+func Test_stuckCause(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-1"}, Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-cg1-0"}, Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "compute", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}}}},
+	}
+	if cause := stuckCause(pods); !strings.Contains(cause, "test-cg1-0") || !strings.Contains(cause, "readiness probe") {
+		t.Errorf("stuckCause expected the readiness of the first pod, got %s", cause)
+	}
+	if cause := stuckCause(pods[:1]); !strings.Contains(cause, "Unschedulable") {
+		t.Errorf("stuckCause expected unschedulable, got %s", cause)
+	}
+	if cause := stuckCause(nil); !strings.Contains(cause, "no pod created") {
+		t.Errorf("stuckCause expected no pod created, got %s", cause)
+	}
+}
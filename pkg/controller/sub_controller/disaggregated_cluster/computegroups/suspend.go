@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// reconcileSuspend replace the replicas of statefulset when the compute group suspending or resuming, return true when replaced.
+// suspending scales the statefulset to zero by the scale down steps and records the existing replicas in status, resuming restores the recorded replicas.
+func reconcileSuspend(cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, st, est *appv1.StatefulSet) bool {
+	if cgStatus == nil {
+		return false
+	}
+	if cg.Suspend {
+		if cgStatus.SuspendReplicas == 0 && *est.Spec.Replicas > 0 {
+			cgStatus.SuspendReplicas = *est.Spec.Replicas
+		}
+		//st.Spec.Replicas shares the pointer with cg.Replicas, not modify it in place.
+		st.Spec.Replicas = resource.GetInt32Pointer(0)
+		cgStatus.Replicas = 0
+		return true
+	}
+	if cgStatus.SuspendReplicas == 0 || *est.Spec.Replicas != 0 {
+		return false
+	}
+	replicas := cgStatus.SuspendReplicas
+	st.Spec.Replicas = &replicas
+	cgStatus.Replicas = replicas
+	if cgStatus.Phase == dv1.Suspended || cgStatus.Phase == dv1.SuspendFailed || cgStatus.Phase == dv1.ResumeFailed {
+		cgStatus.Phase = dv1.Reconciling
+	}
+	return true
+}
+
+// cgSuspended return true when the suspending compute group has no pods, the statefulset scaled to zero.
+func cgSuspended(cg *dv1.ComputeGroup, sts *appv1.StatefulSet, pods []corev1.Pod) bool {
+	return cg != nil && cg.Suspend && sts.Spec.Replicas != nil && *sts.Spec.Replicas == 0 && len(pods) == 0
+}
+
+// suspendFailedEvent return the event of the failure in suspending or resuming compute group, the phase set when the failure not handled by the scale down steps.
+func suspendFailedEvent(cg *dv1.ComputeGroup, cgStatus *dv1.ComputeGroupStatus, resuming bool, err error) *sc.Event {
+	if cg.Suspend {
+		if cgStatus != nil && cgStatus.Phase != dv1.ScaleDownFailed && cgStatus.Phase != dv1.ScaleDownBlocked {
+			cgStatus.Phase = dv1.SuspendFailed
+		}
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGSuspendFailed, Message: fmt.Sprintf("compute group %s suspend failed, err=%s", cg.UniqueId, err.Error())}
+	}
+	if resuming {
+		if cgStatus != nil {
+			cgStatus.Phase = dv1.ResumeFailed
+		}
+		return &sc.Event{Type: sc.EventWarning, Reason: sc.CGResumeFailed, Message: fmt.Sprintf("compute group %s resume failed, err=%s", cg.UniqueId, err.Error())}
+	}
+	return nil
+}
+
+// addResumedBackends add the backends of resumed pods that not registered in fe, the backends are dropped when suspending.
+// the suspendReplicas in status cleared after all pods of the restored replicas ready and registered.
+func (dcgs *DisaggregatedComputeGroupsController) addResumedBackends(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, cg *dv1.ComputeGroup, cvs map[string]interface{}) (*sc.Event, error) {
+	cgStatus := findCGStatus(ddc, cg.UniqueId)
+	if cg.Suspend || cgStatus == nil || cgStatus.SuspendReplicas == 0 {
+		return nil, nil
+	}
+	if cgStatus.AvailableReplicas < cgStatus.Replicas || ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		klog.Infof("disaggregatedComputeGroupsController addResumedBackends namespace %s name %s compute group %s resuming, %d of %d pods available, wait next reconcile.", ddc.Namespace, ddc.Name, cg.UniqueId, cgStatus.AvailableReplicas, cgStatus.Replicas)
+		return nil, nil
+	}
+
+	pods, err := k8s.GetPods(ctx, dcgs.K8sclient, ddc.Namespace, dcgs.newCGPodsSelector(ddc.Name, cg.UniqueId))
+	if err != nil {
+		return suspendFailedEvent(cg, cgStatus, true, err), err
+	}
+	sqlClient, err := dcgs.getOperationSqlClient(ctx, ddc)
+	if err != nil {
+		return suspendFailedEvent(cg, cgStatus, true, err), err
+	}
+	defer sqlClient.Close()
+	backends, err := sqlClient.ShowBackends()
+	if err != nil {
+		return suspendFailedEvent(cg, cgStatus, true, err), err
+	}
+
+	adds, podNames := unregisteredBackends(pods.Items, backends, ddc.GetCGServiceName(cg), ddc.Namespace, int(resource.GetPort(cvs, resource.HEARTBEAT_SERVICE_PORT)))
+	if len(adds) == 0 {
+		klog.Infof("disaggregatedComputeGroupsController addResumedBackends namespace %s name %s compute group %s resumed to %d replicas.", ddc.Namespace, ddc.Name, cg.UniqueId, cgStatus.SuspendReplicas)
+		cgStatus.SuspendReplicas = 0
+		return nil, nil
+	}
+	if err := sqlClient.AddBE(adds, ddc.GetCGName(cg)); err != nil {
+		return suspendFailedEvent(cg, cgStatus, true, err), err
+	}
+	klog.Infof("disaggregatedComputeGroupsController addResumedBackends namespace %s name %s compute group %s resumed pods %s added as backends.", ddc.Namespace, ddc.Name, cg.UniqueId, strings.Join(podNames, ","))
+	return nil, nil
+}
@@ -0,0 +1,98 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"errors"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_reconcileSuspend(t *testing.T) {
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", Suspend: true}
+	cg.Replicas = resource.GetInt32Pointer(3)
+	cgStatus := &dv1.ComputeGroupStatus{UniqueId: "cg1", Phase: dv1.Reconciling, Replicas: 3}
+	st := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: cg.Replicas}}
+	est := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(3)}}
+
+	if !reconcileSuspend(cg, cgStatus, st, est) || *st.Spec.Replicas != 0 || cgStatus.SuspendReplicas != 3 || cgStatus.Replicas != 0 {
+		t.Errorf("reconcileSuspend expected scaling to zero and recording 3 replicas, got replicas %d suspendReplicas %d", *st.Spec.Replicas, cgStatus.SuspendReplicas)
+	}
+	if *cg.Replicas != 3 {
+		t.Errorf("reconcileSuspend expected the replicas of compute group not modified, got %d", *cg.Replicas)
+	}
+
+	//the statefulset shrunk in suspending, the recorded replicas kept.
+	*est.Spec.Replicas = 0
+	cgStatus.Phase = dv1.Suspended
+	st.Spec.Replicas = cg.Replicas
+	if !reconcileSuspend(cg, cgStatus, st, est) || cgStatus.SuspendReplicas != 3 {
+		t.Errorf("reconcileSuspend expected the recorded replicas kept after shrunk, got %d", cgStatus.SuspendReplicas)
+	}
+
+	cg.Suspend = false
+	cg.Replicas = resource.GetInt32Pointer(5)
+	st.Spec.Replicas = cg.Replicas
+	if !reconcileSuspend(cg, cgStatus, st, est) || *st.Spec.Replicas != 3 || cgStatus.Phase != dv1.Reconciling {
+		t.Errorf("reconcileSuspend expected restoring 3 replicas in Reconciling, got replicas %d phase %s", *st.Spec.Replicas, cgStatus.Phase)
+	}
+
+	//the restored statefulset follows the spec, the recorded replicas cleared after backends added.
+	*est.Spec.Replicas = 3
+	st.Spec.Replicas = cg.Replicas
+	if reconcileSuspend(cg, cgStatus, st, est) || *st.Spec.Replicas != 5 {
+		t.Errorf("reconcileSuspend expected not replacing replicas after resumed, got %d", *st.Spec.Replicas)
+	}
+}
+
+func Test_cgSuspended(t *testing.T) {
+	cg := &dv1.ComputeGroup{UniqueId: "cg1", Suspend: true}
+	sts := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{Replicas: resource.GetInt32Pointer(0)}}
+	if !cgSuspended(cg, sts, nil) {
+		t.Errorf("cgSuspended expected true when statefulset scaled to zero without pods")
+	}
+	if cgSuspended(cg, sts, []corev1.Pod{{}}) {
+		t.Errorf("cgSuspended expected false when pods not removed")
+	}
+	if cgSuspended(nil, sts, nil) || cgSuspended(&dv1.ComputeGroup{UniqueId: "cg1"}, sts, nil) {
+		t.Errorf("cgSuspended expected false when compute group not suspended")
+	}
+}
+
+func Test_suspendFailedEvent(t *testing.T) {
+	err := errors.New("apply failed")
+	cgStatus := &dv1.ComputeGroupStatus{Phase: dv1.Reconciling}
+	if event := suspendFailedEvent(&dv1.ComputeGroup{UniqueId: "cg1", Suspend: true}, cgStatus, false, err); event == nil || event.Reason != sc.CGSuspendFailed || cgStatus.Phase != dv1.SuspendFailed {
+		t.Errorf("suspendFailedEvent expected SuspendFailed, got %v phase %s", event, cgStatus.Phase)
+	}
+	cgStatus.Phase = dv1.ScaleDownFailed
+	if suspendFailedEvent(&dv1.ComputeGroup{UniqueId: "cg1", Suspend: true}, cgStatus, false, err); cgStatus.Phase != dv1.ScaleDownFailed {
+		t.Errorf("suspendFailedEvent expected ScaleDownFailed kept for retrying scale down, got %s", cgStatus.Phase)
+	}
+	if event := suspendFailedEvent(&dv1.ComputeGroup{UniqueId: "cg1"}, cgStatus, true, err); event == nil || event.Reason != sc.CGResumeFailed || cgStatus.Phase != dv1.ResumeFailed {
+		t.Errorf("suspendFailedEvent expected ResumeFailed, got %v phase %s", event, cgStatus.Phase)
+	}
+	if event := suspendFailedEvent(&dv1.ComputeGroup{UniqueId: "cg1"}, cgStatus, false, err); event != nil {
+		t.Errorf("suspendFailedEvent expected nil when not suspending or resuming, got %v", event)
+	}
+}
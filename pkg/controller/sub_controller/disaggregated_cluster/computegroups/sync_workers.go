@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// DefaultWorkerCount is the default number of compute groups synced in parallel.
+const DefaultWorkerCount = 4
+
+// syncComputeGroups sync the compute groups by WorkerCount workers in parallel, the compute groups in waiting are skipped. return the errors of the failed compute groups.
+// every compute group synced on a copy of cluster, the cluster is not written concurrently. the changes of copies merged back in the order of compute groups by mergeCGSyncResult.
+func (dcgs *DisaggregatedComputeGroupsController) syncComputeGroups(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, waiting map[string]bool) []error {
+	workers := dcgs.WorkerCount
+	if workers < 1 {
+		workers = 1
+	}
+	cgs := ddc.Spec.ComputeGroups
+	base := ddc.DeepCopy()
+	copies := make([]*dv1.DorisDisaggregatedCluster, len(cgs))
+	events := make([]*sc.Event, len(cgs))
+	errs := make([]error, len(cgs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range cgs {
+		if waiting[cgs[i].UniqueId] {
+			continue
+		}
+		copies[i] = ddc.DeepCopy()
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(idx int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			events[idx], errs[idx] = dcgs.computeGroupSync(ctx, copies[idx], &copies[idx].Spec.ComputeGroups[idx])
+		}(i)
+	}
+	wg.Wait()
+
+	var res []error
+	for i := range cgs {
+		if copies[i] == nil {
+			continue
+		}
+		mergeCGSyncResult(ddc, base, copies[i], i)
+		if errs[i] == nil {
+			continue
+		}
+		if events[i] != nil {
+			dcgs.K8srecorder.Event(ddc, string(events[i].Type), string(events[i].Reason), events[i].Message)
+		}
+		res = append(res, errs[i])
+		klog.Errorf("disaggregatedComputeGroupsController computeGroups sync failed, compute group Uniqueid %s  sync failed, err=%s", cgs[i].UniqueId, errs[i].Error())
+	}
+	return res
+}
+
+// mergeCGSyncResult merge the changes of the copy that synced the compute group of index back to cluster, base is the cluster before syncing.
+// the spec of the compute group replaced and the status merged by uniqueId, the cluster conditions merged by type and the annotations, labels merged by key,
+// so the changes of different compute groups in the same reconcile are all kept. the other cluster fields are not modified in syncing compute group.
+func mergeCGSyncResult(ddc, base, synced *dv1.DorisDisaggregatedCluster, idx int) {
+	ddc.Annotations = mergeStringMap(ddc.Annotations, base.Annotations, synced.Annotations)
+	ddc.Labels = mergeStringMap(ddc.Labels, base.Labels, synced.Labels)
+	mergeConditions(&ddc.Status.Conditions, base.Status.Conditions, synced.Status.Conditions)
+	if synced.Status.Profile != base.Status.Profile {
+		ddc.Status.Profile = synced.Status.Profile
+	}
+
+	cg := &synced.Spec.ComputeGroups[idx]
+	ddc.Spec.ComputeGroups[idx] = *cg
+	if cgs := findCGStatus(synced, cg.UniqueId); cgs != nil {
+		if exist := findCGStatus(ddc, cg.UniqueId); exist != nil {
+			*exist = *cgs
+		} else {
+			ddc.Status.ComputeGroupStatuses = append(ddc.Status.ComputeGroupStatuses, *cgs)
+		}
+	}
+}
+
+// mergeStringMap apply the keys added, changed or removed in synced compared with base to current, return the merged map.
+func mergeStringMap(current, base, synced map[string]string) map[string]string {
+	for k, v := range synced {
+		if bv, ok := base[k]; ok && bv == v {
+			continue
+		}
+		if current == nil {
+			current = map[string]string{}
+		}
+		current[k] = v
+	}
+	for k := range base {
+		if _, ok := synced[k]; !ok {
+			delete(current, k)
+		}
+	}
+	return current
+}
+
+// mergeConditions apply the conditions set or removed in synced compared with base to current by type.
+func mergeConditions(current *[]metav1.Condition, base, synced []metav1.Condition) {
+	for i := range synced {
+		if prev := meta.FindStatusCondition(base, synced[i].Type); prev != nil && reflect.DeepEqual(*prev, synced[i]) {
+			continue
+		}
+		meta.RemoveStatusCondition(current, synced[i].Type)
+		*current = append(*current, synced[i])
+	}
+	for i := range base {
+		if meta.FindStatusCondition(synced, base[i].Type) == nil {
+			meta.RemoveStatusCondition(current, base[i].Type)
+		}
+	}
+}
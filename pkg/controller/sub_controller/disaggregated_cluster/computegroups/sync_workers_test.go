@@ -0,0 +1,160 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	appv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_syncComputeGroups(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	for _, uniqueId := range []string{"cg1", "cg2", "cg3", "cg4"} {
+		ddc.Spec.ComputeGroups = append(ddc.Spec.ComputeGroups, dv1.ComputeGroup{UniqueId: uniqueId})
+	}
+	//the compute group cg2 fails in syncing, cg4 waits the storage vault.
+	ddc.Spec.ComputeGroups[1].ExternalMetricReplicas = &dv1.ExternalMetricReplicas{}
+	recorder := record.NewFakeRecorder(100)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build(), K8srecorder: recorder, WorkerCount: 2}}
+
+	errs := dcgs.syncComputeGroups(context.Background(), ddc, map[string]bool{"cg4": true})
+	failed := 0
+	for _, err := range errs {
+		if strings.Contains(err.Error(), "compute group cg2") {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("syncComputeGroups expected the error of cg2, got %v", errs)
+	}
+	for _, uniqueId := range []string{"cg1", "cg3"} {
+		if findCGStatus(ddc, uniqueId) == nil {
+			t.Errorf("syncComputeGroups expected compute group %s synced and the status merged", uniqueId)
+		}
+	}
+	if findCGStatus(ddc, "cg4") != nil {
+		t.Errorf("syncComputeGroups expected the waiting compute group cg4 not synced")
+	}
+	if len(recorder.Events) == 0 {
+		t.Errorf("syncComputeGroups expected the event of failed compute group recorded")
+	}
+	var st appv1.StatefulSet
+	if err := dcgs.K8sclient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: ddc.GetCGStatefulsetName(&ddc.Spec.ComputeGroups[2])}, &st); err != nil {
+		t.Errorf("syncComputeGroups expected the statefulset of cg3 created, err=%s", err.Error())
+	}
+}
+
+func Test_syncComputeGroups_parallel(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Annotations: map[string]string{"keep": "v"}}}
+	for i := 0; i < 8; i++ {
+		ddc.Spec.ComputeGroups = append(ddc.Spec.ComputeGroups, dv1.ComputeGroup{UniqueId: fmt.Sprintf("cg%d", i)})
+	}
+	ddc.Status.Profile = "night"
+	cgs := ddc.Spec.ComputeGroups
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().Build(), K8srecorder: record.NewFakeRecorder(100), WorkerCount: 4}}
+
+	if errs := dcgs.syncComputeGroups(context.Background(), ddc, nil); len(errs) != 0 {
+		t.Errorf("syncComputeGroups expected all compute groups synced, got %v", errs)
+	}
+	if len(ddc.Status.ComputeGroupStatuses) != 8 {
+		t.Errorf("syncComputeGroups expected the statuses of 8 compute groups merged, got %d", len(ddc.Status.ComputeGroupStatuses))
+	}
+	for i := range cgs {
+		if findCGStatus(ddc, cgs[i].UniqueId) == nil {
+			t.Errorf("syncComputeGroups expected the status of %s merged", cgs[i].UniqueId)
+		}
+	}
+	if ddc.Status.Profile != "night" || ddc.Annotations["keep"] != "v" {
+		t.Errorf("syncComputeGroups expected the cluster level fields kept, got profile %s annotations %v", ddc.Status.Profile, ddc.Annotations)
+	}
+	if &ddc.Spec.ComputeGroups[0] != &cgs[0] {
+		t.Errorf("syncComputeGroups expected the compute groups merged into the same backing array for restoring profile")
+	}
+}
+
+func Test_mergeCGSyncResult(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"keep": "v", "removed": "v"}}}
+	ddc.Spec.ComputeGroups = []dv1.ComputeGroup{{UniqueId: "cg1"}, {UniqueId: "cg2"}}
+	ddc.Status.ComputeGroupStatuses = []dv1.ComputeGroupStatus{{UniqueId: "cg1", Phase: dv1.Ready}}
+	base := ddc.DeepCopy()
+
+	synced := base.DeepCopy()
+	synced.Status.ComputeGroupStatuses[0].Phase = dv1.Reconciling
+	synced.Annotations["added"] = "true"
+	delete(synced.Annotations, "removed")
+	synced.Status.Conditions = []metav1.Condition{{Type: "VersionSkew", Status: metav1.ConditionTrue}}
+	mergeCGSyncResult(ddc, base, synced, 0)
+
+	//the copy of cg2 not see the changes of cg1, the changes of cg1 not reverted by merging it.
+	synced = base.DeepCopy()
+	synced.Status.ComputeGroupStatuses = append(synced.Status.ComputeGroupStatuses, dv1.ComputeGroupStatus{UniqueId: "cg2", Phase: dv1.Reconciling})
+	synced.Spec.ComputeGroups[1].Replicas = &[]int32{3}[0]
+	synced.Status.Profile = "night"
+	mergeCGSyncResult(ddc, base, synced, 1)
+
+	if len(ddc.Status.ComputeGroupStatuses) != 2 || ddc.Status.ComputeGroupStatuses[0].Phase != dv1.Reconciling || ddc.Status.ComputeGroupStatuses[1].UniqueId != "cg2" {
+		t.Errorf("mergeCGSyncResult expected the statuses of cg1 updated and cg2 appended, got %+v", ddc.Status.ComputeGroupStatuses)
+	}
+	if ddc.Annotations["added"] != "true" || ddc.Annotations["keep"] != "v" {
+		t.Errorf("mergeCGSyncResult expected the annotation added and the others kept, got %v", ddc.Annotations)
+	}
+	if _, ok := ddc.Annotations["removed"]; ok {
+		t.Errorf("mergeCGSyncResult expected the annotation removed, got %v", ddc.Annotations)
+	}
+	if len(ddc.Status.Conditions) != 1 || ddc.Status.Profile != "night" {
+		t.Errorf("mergeCGSyncResult expected the cluster status of both compute groups merged, got conditions %v profile %s", ddc.Status.Conditions, ddc.Status.Profile)
+	}
+	if ddc.Spec.ComputeGroups[1].Replicas == nil || *ddc.Spec.ComputeGroups[1].Replicas != 3 {
+		t.Errorf("mergeCGSyncResult expected the spec of cg2 merged, got %+v", ddc.Spec.ComputeGroups[1])
+	}
+}
+
+func Test_mergeCGSyncResult_conditions(t *testing.T) {
+	ddc := &dv1.DorisDisaggregatedCluster{}
+	ddc.Spec.ComputeGroups = []dv1.ComputeGroup{{UniqueId: "cg1"}, {UniqueId: "cg2"}}
+	ddc.Status.Conditions = []metav1.Condition{{Type: "Stale", Status: metav1.ConditionTrue}, {Type: "Kept", Status: metav1.ConditionTrue}}
+	base := ddc.DeepCopy()
+
+	//cg1 and cg2 set different cluster conditions on their own copies, cg2 removes the stale one.
+	synced1 := base.DeepCopy()
+	synced1.Status.Conditions = append(synced1.Status.Conditions, metav1.Condition{Type: "VersionSkew", Status: metav1.ConditionTrue})
+	synced2 := base.DeepCopy()
+	synced2.Status.Conditions = []metav1.Condition{{Type: "Kept", Status: metav1.ConditionTrue}, {Type: "BackendsMissing", Status: metav1.ConditionTrue}}
+	mergeCGSyncResult(ddc, base, synced1, 0)
+	mergeCGSyncResult(ddc, base, synced2, 1)
+
+	for _, ct := range []string{"VersionSkew", "BackendsMissing", "Kept"} {
+		if c := meta.FindStatusCondition(ddc.Status.Conditions, ct); c == nil || c.Status != metav1.ConditionTrue {
+			t.Errorf("mergeCGSyncResult expected the condition %s kept, got %v", ct, ddc.Status.Conditions)
+		}
+	}
+	if meta.FindStatusCondition(ddc.Status.Conditions, "Stale") != nil || len(ddc.Status.Conditions) != 3 {
+		t.Errorf("mergeCGSyncResult expected the stale condition removed, got %v", ddc.Status.Conditions)
+	}
+}
@@ -0,0 +1,193 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/klog/v2"
+)
+
+// validateTenants check the users and roles of compute groups are valid names, and a user is not bound to multiple compute groups, return the error message when invalid.
+func (dcgs *DisaggregatedComputeGroupsController) validateTenants(cgs []dv1.ComputeGroup) string {
+	userCG := map[string]string{}
+	for _, cg := range cgs {
+		if cg.Tenants == nil {
+			continue
+		}
+		for _, name := range append(append([]string{}, cg.Tenants.Users...), cg.Tenants.Roles...) {
+			if name == "" || strings.ContainsAny(name, "'\\`\"") {
+				return fmt.Sprintf("compute group %s tenant name %q is invalid.", cg.UniqueId, name)
+			}
+		}
+		for _, user := range cg.Tenants.Users {
+			if bound, ok := userCG[user]; ok && bound != cg.UniqueId {
+				return fmt.Sprintf("user %s is bound to compute groups %s and %s, a user can only be bound to one compute group.", user, bound, cg.UniqueId)
+			}
+			userCG[user] = cg.UniqueId
+		}
+	}
+	return ""
+}
+
+// reconcileCGTenants grant the usage of ready compute groups to the users and roles in tenants, and revoke the removed ones, the bound tenants recorded in status.
+// the sql client is created only when the bindings changed.
+func (dcgs *DisaggregatedComputeGroupsController) reconcileCGTenants(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster) {
+	if ddc.Status.FEStatus.AvailableStatus != dv1.Available {
+		return
+	}
+
+	//the user moved to another compute group keeps the default compute group that set by the new one.
+	allUsers := map[string]bool{}
+	for _, cg := range ddc.Spec.ComputeGroups {
+		if cg.Tenants != nil {
+			for _, user := range cg.Tenants.Users {
+				allUsers[user] = true
+			}
+		}
+	}
+
+	var sqlClient *mysql.DB
+	defer func() {
+		if sqlClient != nil {
+			sqlClient.Close()
+		}
+	}()
+	for i := range ddc.Spec.ComputeGroups {
+		cg := &ddc.Spec.ComputeGroups[i]
+		cgs := findCGStatus(ddc, cg.UniqueId)
+		if cgs == nil || !cgServing(cgs) {
+			continue
+		}
+
+		var users, roles []string
+		if cg.Tenants != nil {
+			users, roles = cg.Tenants.Users, cg.Tenants.Roles
+		}
+		addUsers, removeUsers := diffTenants(users, cgs.BoundUsers)
+		addRoles, removeRoles := diffTenants(roles, cgs.BoundRoles)
+		if len(addUsers)+len(removeUsers)+len(addRoles)+len(removeRoles) == 0 {
+			continue
+		}
+
+		if sqlClient == nil {
+			var err error
+			if sqlClient, err = dcgs.getMasterSqlClient(ctx, ddc); err != nil {
+				klog.Errorf("disaggregatedComputeGroupsController reconcileCGTenants namespace %s name %s get sql client failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+				return
+			}
+		}
+		var unsetUsers []string
+		for _, user := range removeUsers {
+			if !allUsers[user] {
+				unsetUsers = append(unsetUsers, user)
+			}
+		}
+		cgName := ddc.GetCGName(cg)
+		boundUsers, userErr := bindCGTenants(sqlClient, cgName, cgs.BoundUsers, addUsers, removeUsers, unsetUsers, false)
+		boundRoles, roleErr := bindCGTenants(sqlClient, cgName, cgs.BoundRoles, addRoles, removeRoles, nil, true)
+		cgs.BoundUsers = boundUsers
+		cgs.BoundRoles = boundRoles
+		if err := errors.Join(userErr, roleErr); err != nil {
+			msg := fmt.Sprintf("compute group %s bind tenants failed, err=%s", cg.UniqueId, err.Error())
+			klog.Errorf("disaggregatedComputeGroupsController reconcileCGTenants namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+			dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGTenantsBindFailed), msg)
+			continue
+		}
+		klog.Infof("disaggregatedComputeGroupsController reconcileCGTenants namespace %s name %s compute group %s bound users %v roles %v, unbound users %v roles %v.", ddc.Namespace, ddc.Name, cg.UniqueId, addUsers, addRoles, removeUsers, removeRoles)
+	}
+}
+
+// bindCGTenants unbind the removed users or roles before binding the added, one statement per name so a failed name not blocks the others.
+// the default compute group of unsetUsers is unset before revoking, and set after granting to the added users. return the names bound after binding and the errors of the failed names.
+func bindCGTenants(sqlClient *mysql.DB, cgName string, bound, adds, removes, unsetUsers []string, isRole bool) ([]string, error) {
+	kind := "user"
+	if isRole {
+		kind = "role"
+	}
+	unset := map[string]bool{}
+	for _, name := range unsetUsers {
+		unset[name] = true
+	}
+
+	var errs []error
+	unbound := map[string]bool{}
+	for _, name := range removes {
+		if unset[name] {
+			if err := sqlClient.SetDefaultComputeGroup(name, ""); err != nil {
+				errs = append(errs, fmt.Errorf("unset default compute group of user %s: %w", name, err))
+				continue
+			}
+		}
+		if err := sqlClient.RevokeComputeGroupUsage(cgName, name, isRole); err != nil {
+			errs = append(errs, fmt.Errorf("revoke %s %s: %w", kind, name, err))
+			continue
+		}
+		unbound[name] = true
+	}
+
+	var result []string
+	for _, name := range bound {
+		if !unbound[name] {
+			result = append(result, name)
+		}
+	}
+	for _, name := range adds {
+		if err := sqlClient.GrantComputeGroupUsage(cgName, name, isRole); err != nil {
+			errs = append(errs, fmt.Errorf("grant %s %s: %w", kind, name, err))
+			continue
+		}
+		//granting again is harmless, so the user not set default compute group is left unbound to retry.
+		if !isRole {
+			if err := sqlClient.SetDefaultComputeGroup(name, cgName); err != nil {
+				errs = append(errs, fmt.Errorf("set default compute group of user %s: %w", name, err))
+				continue
+			}
+		}
+		result = append(result, name)
+	}
+	return result, errors.Join(errs...)
+}
+
+// diffTenants return the names in desired but not bound, and the names bound but not in desired.
+func diffTenants(desired, bound []string) ([]string, []string) {
+	boundSet := map[string]bool{}
+	for _, b := range bound {
+		boundSet[b] = true
+	}
+	desiredSet := map[string]bool{}
+	var adds, removes []string
+	for _, d := range desired {
+		desiredSet[d] = true
+		if !boundSet[d] {
+			adds = append(adds, d)
+		}
+	}
+	for _, b := range bound {
+		if !desiredSet[b] {
+			removes = append(removes, b)
+		}
+	}
+	return adds, removes
+}
@@ -0,0 +1,119 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+func Test_validateTenants(t *testing.T) {
+	dcgs := &DisaggregatedComputeGroupsController{}
+	tests := []struct {
+		name  string
+		cgs   []dv1.ComputeGroup
+		valid bool
+	}{
+		{name: "no tenants", cgs: []dv1.ComputeGroup{{UniqueId: "cg1"}}, valid: true},
+		{name: "valid", cgs: []dv1.ComputeGroup{
+			{UniqueId: "cg1", Tenants: &dv1.ComputeGroupTenants{Users: []string{"u1"}, Roles: []string{"r1"}}},
+			{UniqueId: "cg2", Tenants: &dv1.ComputeGroupTenants{Users: []string{"u2"}, Roles: []string{"r1"}}},
+		}, valid: true},
+		{name: "user in two compute groups", cgs: []dv1.ComputeGroup{
+			{UniqueId: "cg1", Tenants: &dv1.ComputeGroupTenants{Users: []string{"u1"}}},
+			{UniqueId: "cg2", Tenants: &dv1.ComputeGroupTenants{Users: []string{"u1"}}},
+		}, valid: false},
+		{name: "quote in name", cgs: []dv1.ComputeGroup{{UniqueId: "cg1", Tenants: &dv1.ComputeGroupTenants{Roles: []string{"r1'"}}}}, valid: false},
+	}
+	for _, test := range tests {
+		if msg := dcgs.validateTenants(test.cgs); (msg == "") != test.valid {
+			t.Errorf("validateTenants %s expect valid %t, got message %q", test.name, test.valid, msg)
+		}
+	}
+}
+
+func Test_diffTenants(t *testing.T) {
+	adds, removes := diffTenants([]string{"u1", "u2"}, []string{"u2", "u3"})
+	if len(adds) != 1 || adds[0] != "u1" || len(removes) != 1 || removes[0] != "u3" {
+		t.Errorf("diffTenants expect adds [u1] removes [u3], got adds %v removes %v", adds, removes)
+	}
+}
+
+func Test_bindCGTenants(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`REVOKE USAGE_PRIV ON COMPUTE GROUP 'cg1' FROM 'u2';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`SET PROPERTY FOR 'u3' 'default_compute_group' = '';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`REVOKE USAGE_PRIV ON COMPUTE GROUP 'cg1' FROM 'u3';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO 'u1';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`SET PROPERTY FOR 'u1' 'default_compute_group' = 'cg1';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO ROLE 'r1';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	//u2 moved to another compute group, the default compute group is not unset.
+	users, err := bindCGTenants(db, "cg1", []string{"u0", "u2", "u3"}, []string{"u1"}, []string{"u2", "u3"}, []string{"u3"}, false)
+	if err != nil {
+		t.Errorf("bindCGTenants users failed, err=%s", err.Error())
+	}
+	if !reflect.DeepEqual(users, []string{"u0", "u1"}) {
+		t.Errorf("bindCGTenants expect bound users [u0 u1], got %v", users)
+	}
+	roles, err := bindCGTenants(db, "cg1", nil, []string{"r1"}, nil, nil, true)
+	if err != nil || !reflect.DeepEqual(roles, []string{"r1"}) {
+		t.Errorf("bindCGTenants expect bound roles [r1], got %v err=%v", roles, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("bindCGTenants sql not expected, err=%s", err.Error())
+	}
+}
+
+func Test_bindCGTenants_PartialFailure(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed %s", err.Error())
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`REVOKE USAGE_PRIV ON COMPUTE GROUP 'cg1' FROM 'u2';`)).WillReturnError(errors.New("revoke denied"))
+	mock.ExpectExec(regexp.QuoteMeta(`GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO 'u1';`)).WillReturnError(errors.New("user not exist"))
+	mock.ExpectExec(regexp.QuoteMeta(`GRANT USAGE_PRIV ON COMPUTE GROUP 'cg1' TO 'u3';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`SET PROPERTY FOR 'u3' 'default_compute_group' = 'cg1';`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	db := &mysql.DB{DB: sqlx.NewDb(mdb, "mysql")}
+	defer db.Close()
+
+	//the failed u1 and u2 not block u3, u2 keeps bound and u1 is not bound to retry.
+	users, err := bindCGTenants(db, "cg1", []string{"u2"}, []string{"u1", "u3"}, []string{"u2"}, nil, false)
+	if err == nil || !strings.Contains(err.Error(), "revoke user u2") || !strings.Contains(err.Error(), "grant user u1") {
+		t.Errorf("bindCGTenants expect errors of u1 and u2, got %v", err)
+	}
+	if !reflect.DeepEqual(users, []string{"u2", "u3"}) {
+		t.Errorf("bindCGTenants expect bound users [u2 u3], got %v", users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("bindCGTenants sql not expected, err=%s", err.Error())
+	}
+}
@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the max number of usage samples kept in compute group status.
+	maxUsageSamples = 10
+	// the min interval between two usage samples, avoid sampling in every reconcile.
+	usageSampleInterval = 5 * time.Minute
+)
+
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// recordCGUsageSamples append the aggregated usage sample to compute groups status, the cache usage from backends in fe, cpu and memory usage from metrics api.
+func (dcgs *DisaggregatedComputeGroupsController) recordCGUsageSamples(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, backends []*mysql.Backend) {
+	now := metav1.Now()
+	cachePercents := averageCacheUsedPercentByStatefulset(backends)
+	for i := range ddc.Status.ComputeGroupStatuses {
+		cgs := &ddc.Status.ComputeGroupStatuses[i]
+		if !needUsageSample(cgs.UsageSamples, now.Time) {
+			continue
+		}
+
+		sample := dv1.UsageSample{Time: now, CacheUsedPercent: cachePercents[cgs.StatefulsetName]}
+		cpu, memory, err := dcgs.getCGPodsUsage(ctx, ddc, cgs.UniqueId)
+		if err != nil {
+			//the metrics api is not installed in kubernetes usually, only record cache usage.
+			klog.V(4).Infof("disaggregatedComputeGroupsController recordCGUsageSamples namespace %s uniqueId %s get pods metrics failed, err=%s", ddc.Namespace, cgs.UniqueId, err.Error())
+		} else {
+			sample.CPU = cpu.String()
+			sample.Memory = memory.String()
+		}
+		cgs.UsageSamples = appendUsageSample(cgs.UsageSamples, sample)
+	}
+}
+
+// getCGPodsUsage sum the cpu and memory usage of compute group pods by the metrics api.
+func (dcgs *DisaggregatedComputeGroupsController) getCGPodsUsage(ctx context.Context, ddc *dv1.DorisDisaggregatedCluster, uniqueId string) (apiresource.Quantity, apiresource.Quantity, error) {
+	pml := &unstructured.UnstructuredList{}
+	pml.SetGroupVersionKind(podMetricsListGVK)
+	if err := dcgs.K8sclient.List(ctx, pml, client.InNamespace(ddc.Namespace), client.MatchingLabels(dcgs.newCGPodsSelector(ddc.Name, uniqueId))); err != nil {
+		return apiresource.Quantity{}, apiresource.Quantity{}, err
+	}
+
+	cpu, memory := sumPodMetricsUsage(pml.Items)
+	return cpu, memory, nil
+}
+
+// sumPodMetricsUsage sum the usage of all containers in PodMetrics.
+func sumPodMetricsUsage(pms []unstructured.Unstructured) (apiresource.Quantity, apiresource.Quantity) {
+	cpu := apiresource.Quantity{}
+	memory := apiresource.Quantity{}
+	for _, pm := range pms {
+		containers, _, _ := unstructured.NestedSlice(pm.Object, "containers")
+		for _, c := range containers {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usage, _, _ := unstructured.NestedStringMap(cm, "usage")
+			if q, err := apiresource.ParseQuantity(usage["cpu"]); err == nil {
+				cpu.Add(q)
+			}
+			if q, err := apiresource.ParseQuantity(usage["memory"]); err == nil {
+				memory.Add(q)
+			}
+		}
+	}
+	return cpu, memory
+}
+
+// averageCacheUsedPercentByStatefulset return the average UsedPct of alive backends grouped by the statefulset name that the backend pod belongs to.
+func averageCacheUsedPercentByStatefulset(backends []*mysql.Backend) map[string]int32 {
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	re := regexp.MustCompile("(.*)-[0-9]+$")
+	for _, backend := range backends {
+		if !backend.Alive {
+			continue
+		}
+		podName := strings.Split(backend.Host, ".")[0]
+		matchs := re.FindStringSubmatch(podName)
+		if len(matchs) < 2 {
+			continue
+		}
+		//the UsedPct format as "83.00 %".
+		pct, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(backend.UsedPct), "%")), 64)
+		if err != nil {
+			continue
+		}
+		sums[matchs[1]] += pct
+		counts[matchs[1]]++
+	}
+
+	m := map[string]int32{}
+	for stsName, sum := range sums {
+		m[stsName] = int32(sum / float64(counts[stsName]))
+	}
+	return m
+}
+
+// needUsageSample return true when the last sample is older than usageSampleInterval.
+func needUsageSample(samples []dv1.UsageSample, now time.Time) bool {
+	if len(samples) == 0 {
+		return true
+	}
+	return now.Sub(samples[len(samples)-1].Time.Time) >= usageSampleInterval
+}
+
+// appendUsageSample append the sample and drop the oldest samples when exceed maxUsageSamples.
+func appendUsageSample(samples []dv1.UsageSample, sample dv1.UsageSample) []dv1.UsageSample {
+	samples = append(samples, sample)
+	if len(samples) > maxUsageSamples {
+		samples = samples[len(samples)-maxUsageSamples:]
+	}
+	return samples
+}
@@ -0,0 +1,82 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_sumPodMetricsUsage(t *testing.T) {
+	pms := []unstructured.Unstructured{
+		{Object: map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "compute", "usage": map[string]interface{}{"cpu": "500m", "memory": "1Gi"}},
+			map[string]interface{}{"name": "sidecar", "usage": map[string]interface{}{"cpu": "100m", "memory": "512Mi"}},
+		}}},
+		{Object: map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "compute", "usage": map[string]interface{}{"cpu": "1400m", "memory": "1536Mi"}},
+		}}},
+	}
+
+	cpu, memory := sumPodMetricsUsage(pms)
+	if cpu.MilliValue() != 2000 || memory.Value() != 3*1024*1024*1024 {
+		t.Errorf("sumPodMetricsUsage got cpu %s memory %s, expected cpu 2 memory 3Gi", cpu.String(), memory.String())
+	}
+}
+
+func Test_averageCacheUsedPercentByStatefulset(t *testing.T) {
+	backends := []*mysql.Backend{
+		{Host: "test-cg1-0.test-cg1.default.svc.cluster.local", Alive: true, UsedPct: "80.00 %"},
+		{Host: "test-cg1-1.test-cg1.default.svc.cluster.local", Alive: true, UsedPct: "60.00 %"},
+		{Host: "test-cg1-2.test-cg1.default.svc.cluster.local", Alive: false, UsedPct: "0.00 %"},
+		{Host: "test-cg2-0.test-cg2.default.svc.cluster.local", Alive: true, UsedPct: "33.50 %"},
+	}
+
+	m := averageCacheUsedPercentByStatefulset(backends)
+	if m["test-cg1"] != 70 || m["test-cg2"] != 33 {
+		t.Errorf("averageCacheUsedPercentByStatefulset got %v, expected test-cg1=70, test-cg2=33", m)
+	}
+}
+
+func Test_appendUsageSample(t *testing.T) {
+	now := time.Now()
+	var samples []dv1.UsageSample
+	if !needUsageSample(samples, now) {
+		t.Errorf("needUsageSample expected true when no sample")
+	}
+
+	for i := 0; i < maxUsageSamples+3; i++ {
+		samples = appendUsageSample(samples, dv1.UsageSample{Time: metav1.NewTime(now.Add(time.Duration(i) * usageSampleInterval)), CacheUsedPercent: int32(i)})
+	}
+	if len(samples) != maxUsageSamples || samples[0].CacheUsedPercent != 3 || samples[maxUsageSamples-1].CacheUsedPercent != maxUsageSamples+2 {
+		t.Errorf("appendUsageSample expected keep the newest %d samples, got %+v", maxUsageSamples, samples)
+	}
+
+	last := samples[len(samples)-1].Time.Time
+	if needUsageSample(samples, last.Add(time.Minute)) {
+		t.Errorf("needUsageSample expected false in sample interval")
+	}
+	if !needUsageSample(samples, last.Add(usageSampleInterval)) {
+		t.Errorf("needUsageSample expected true after sample interval")
+	}
+}
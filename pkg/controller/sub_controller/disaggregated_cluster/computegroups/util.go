@@ -18,6 +18,11 @@
 package computegroups
 
 import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
 	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -28,6 +33,25 @@ var (
 	compute_group_id_regex   = "[a-zA-Z](_?[0-9a-zA-Z])*"
 )
 
+// the compiled regular expressions of compute group name keyed by the expression, the user expression compiled once.
+var computeGroupNameRegexps sync.Map
+
+// computeGroupNameRegexp return the compiled expression of compute group name, the built-in compute_group_name_regex when the expression is empty.
+func computeGroupNameRegexp(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		expr = compute_group_name_regex
+	}
+	if re, ok := computeGroupNameRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	computeGroupNameRegexps.Store(expr, re)
+	return re, nil
+}
+
 func ownerReference2ddc(obj client.Object, cluster *dv1.DorisDisaggregatedCluster) bool {
 	if obj == nil {
 		return false
@@ -50,3 +74,47 @@ func getUniqueIdFromClientObject(obj client.Object) string {
 	labels := obj.GetLabels()
 	return labels[dv1.DorisDisaggregatedComputeGroupUniqueId]
 }
+
+// setCGStatusPhase set the phase of the compute group status that have the uniqueId.
+func setCGStatusPhase(ddc *dv1.DorisDisaggregatedCluster, uniqueId string, phase dv1.Phase) {
+	for i := range ddc.Status.ComputeGroupStatuses {
+		if ddc.Status.ComputeGroupStatuses[i].UniqueId == uniqueId {
+			ddc.Status.ComputeGroupStatuses[i].Phase = phase
+			return
+		}
+	}
+}
+
+// findCGStatus return the status of compute group that have the uniqueId, nil when not exist.
+func findCGStatus(ddc *dv1.DorisDisaggregatedCluster, uniqueId string) *dv1.ComputeGroupStatus {
+	for i := range ddc.Status.ComputeGroupStatuses {
+		if ddc.Status.ComputeGroupStatuses[i].UniqueId == uniqueId {
+			return &ddc.Status.ComputeGroupStatuses[i]
+		}
+	}
+	return nil
+}
+
+// findCG return the compute group in spec that have the uniqueId, nil when not exist.
+func findCG(ddc *dv1.DorisDisaggregatedCluster, uniqueId string) *dv1.ComputeGroup {
+	for i := range ddc.Spec.ComputeGroups {
+		if ddc.Spec.ComputeGroups[i].UniqueId == uniqueId {
+			return &ddc.Spec.ComputeGroups[i]
+		}
+	}
+	return nil
+}
+
+// statefulsetPVCOrdinal return the pod ordinal of the pvc created by the volumeClaimTemplates of statefulset, the name format is `{template name}-{statefulset name}-{ordinal}`.
+// the compute group have multiple templates when cache paths use different storage tiers, the template name may contain the statefulset name, so parse from the suffix.
+func statefulsetPVCOrdinal(pvcName, stsName string) (int64, bool) {
+	i := strings.LastIndex(pvcName, "-")
+	if i <= 0 || !strings.HasSuffix(pvcName[:i], "-"+stsName) {
+		return 0, false
+	}
+	ordinal, err := strconv.ParseInt(pvcName[i+1:], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return ordinal, true
+}
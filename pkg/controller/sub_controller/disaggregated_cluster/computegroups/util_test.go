@@ -32,3 +32,22 @@ func Test_Regex(t *testing.T) {
 		}
 	}
 }
+
+func Test_statefulsetPVCOrdinal(t *testing.T) {
+	tests := []struct {
+		pvcName string
+		ordinal int64
+		ok      bool
+	}{
+		{pvcName: "storage0-test-cg1-2", ordinal: 2, ok: true},
+		{pvcName: "test-cg1-ssd-test-cg1-10", ordinal: 10, ok: true},
+		{pvcName: "storage0-test-cg2-1", ok: false},
+		{pvcName: "storage0-test-cg1-x", ok: false},
+	}
+	for _, test := range tests {
+		ordinal, ok := statefulsetPVCOrdinal(test.pvcName, "test-cg1")
+		if ok != test.ok || ordinal != test.ordinal {
+			t.Errorf("statefulsetPVCOrdinal %s expect %d %t, got %d %t", test.pvcName, test.ordinal, test.ok, ordinal, ok)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultMaxMinorVersions int32 = 1
+	defaultMaxMixedSeconds  int32 = 86400
+)
+
+var majorMinorRegexp = regexp.MustCompile(`^([0-9]+)\.([0-9]+)`)
+
+// podsRunningVersions return the sorted distinct doris versions parsed from the images of compute container in pods, the images without version are skipped.
+func podsRunningVersions(pods []corev1.Pod) []string {
+	m := map[string]bool{}
+	for i := range pods {
+		for _, c := range pods[i].Spec.Containers {
+			if c.Name != resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME {
+				continue
+			}
+			if v := imageVersion(c.Image); majorMinorRegexp.MatchString(v) {
+				m[v] = true
+			}
+		}
+	}
+	var versions []string
+	for v := range m {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// checkVersionSkew set the VersionSkewExceeded condition of cluster by the versions running in compute groups, emit warning event when the skew exceeds the window.
+func (dcgs *DisaggregatedComputeGroupsController) checkVersionSkew(ddc *dv1.DorisDisaggregatedCluster) {
+	prev := meta.FindStatusCondition(ddc.Status.Conditions, dv1.VersionSkewExceeded)
+	condition := newVersionSkewCondition(ddc, prev, time.Now())
+	if condition.Status == metav1.ConditionTrue && (prev == nil || prev.Status != metav1.ConditionTrue || prev.Reason != condition.Reason) {
+		klog.Errorf("disaggregatedComputeGroupsController checkVersionSkew namespace %s name %s %s", ddc.Namespace, ddc.Name, condition.Message)
+		dcgs.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.CGVersionSkewExceeded), condition.Message)
+	}
+	meta.SetStatusCondition(&ddc.Status.Conditions, condition)
+}
+
+// newVersionSkewCondition return Unknown when compute groups running different versions in the window, the mixed duration counted from the last transition to Unknown.
+func newVersionSkewCondition(ddc *dv1.DorisDisaggregatedCluster, prev *metav1.Condition, now time.Time) metav1.Condition {
+	maxMinor, maxMixed := defaultMaxMinorVersions, defaultMaxMixedSeconds
+	if vs := ddc.Spec.VersionSkew; vs != nil {
+		if vs.MaxMinorVersions != nil {
+			maxMinor = *vs.MaxMinorVersions
+		}
+		if vs.MaxMixedSeconds != nil {
+			maxMixed = *vs.MaxMixedSeconds
+		}
+	}
+
+	//the versions of compute groups, the removing compute groups not counted.
+	cgVersions := map[string][]string{}
+	all := map[string]bool{}
+	for _, cgs := range ddc.Status.ComputeGroupStatuses {
+		if cgs.Phase == dv1.Removing || len(cgs.RunningVersions) == 0 {
+			continue
+		}
+		cgVersions[cgs.UniqueId] = cgs.RunningVersions
+		for _, v := range cgs.RunningVersions {
+			all[v] = true
+		}
+	}
+	var versions []string
+	for v := range all {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+
+	condition := metav1.Condition{
+		Type:               dv1.VersionSkewExceeded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: ddc.Generation,
+		Reason:             dv1.VersionsConsistent,
+		Message:            fmt.Sprintf("compute groups running version %s.", strings.Join(versions, ",")),
+	}
+	if len(versions) <= 1 {
+		return condition
+	}
+
+	desc := describeCGVersions(cgVersions)
+	if skew, exceeded := minorVersionSkew(versions, maxMinor); exceeded {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = dv1.MinorVersionSkewExceeded
+		condition.Message = fmt.Sprintf("compute groups running versions %s beyond the supported skew of %d minor versions, %s. please finish upgrading the compute groups to the same version.", skew, maxMinor, desc)
+		return condition
+	}
+
+	if maxMixed == 0 || prev == nil || prev.Status == metav1.ConditionFalse || prev.Reason == dv1.MinorVersionSkewExceeded ||
+		(prev.Status == metav1.ConditionUnknown && now.Sub(prev.LastTransitionTime.Time) < time.Duration(maxMixed)*time.Second) {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = dv1.VersionsMixed
+		condition.Message = fmt.Sprintf("compute groups running different versions, %s.", desc)
+		return condition
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = dv1.MixedVersionsTooLong
+	condition.Message = fmt.Sprintf("compute groups running different versions longer than %s, %s. please finish upgrading the compute groups to the same version.", time.Duration(maxMixed)*time.Second, desc)
+	return condition
+}
+
+// minorVersionSkew return the lowest and highest versions and whether the difference of them exceeds the max minor versions, the different major versions always exceed.
+func minorVersionSkew(versions []string, maxMinor int32) (string, bool) {
+	var lowest, highest string
+	var lmajor, lminor, hmajor, hminor int
+	for _, v := range versions {
+		m := majorMinorRegexp.FindStringSubmatch(v)
+		if len(m) != 3 {
+			continue
+		}
+		major, _ := strconv.Atoi(m[1])
+		minor, _ := strconv.Atoi(m[2])
+		if lowest == "" || major < lmajor || (major == lmajor && minor < lminor) {
+			lowest, lmajor, lminor = v, major, minor
+		}
+		if highest == "" || major > hmajor || (major == hmajor && minor > hminor) {
+			highest, hmajor, hminor = v, major, minor
+		}
+	}
+	skew := lowest + " to " + highest
+	return skew, lmajor != hmajor || int32(hminor-lminor) > maxMinor
+}
+
+// describeCGVersions describe the versions of compute groups sorted by uniqueId, ep: `cg1: 3.0.3; cg2: 3.0.3,3.0.4`.
+func describeCGVersions(cgVersions map[string][]string) string {
+	var ids []string
+	for id := range cgVersions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var descs []string
+	for _, id := range ids {
+		descs = append(descs, id+": "+strings.Join(cgVersions[id], ","))
+	}
+	return strings.Join(descs, "; ")
+}
@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package computegroups
+
+import (
+	"testing"
+	"time"
+
+	dv1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_podsRunningVersions(t *testing.T) {
+	newPod := func(image string) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: resource.DISAGGREGATED_BE_MAIN_CONTAINER_NAME, Image: image}}}}
+	}
+	pods := []corev1.Pod{newPod("apache/doris:be-3.0.4"), newPod("apache/doris:be-3.0.3"), newPod("apache/doris:be-3.0.4"), newPod("apache/doris:latest")}
+	versions := podsRunningVersions(pods)
+	if len(versions) != 2 || versions[0] != "3.0.3" || versions[1] != "3.0.4" {
+		t.Errorf("podsRunningVersions expected [3.0.3 3.0.4], got %v", versions)
+	}
+}
+
+func Test_newVersionSkewCondition(t *testing.T) {
+	now := time.Now()
+	newDDC := func(versions ...[]string) *dv1.DorisDisaggregatedCluster {
+		ddc := &dv1.DorisDisaggregatedCluster{}
+		for i, v := range versions {
+			ddc.Status.ComputeGroupStatuses = append(ddc.Status.ComputeGroupStatuses, dv1.ComputeGroupStatus{UniqueId: "cg" + string(rune('1'+i)), RunningVersions: v})
+		}
+		return ddc
+	}
+	mixedSince := func(d time.Duration) *metav1.Condition {
+		return &metav1.Condition{Status: metav1.ConditionUnknown, Reason: dv1.VersionsMixed, LastTransitionTime: metav1.NewTime(now.Add(-d))}
+	}
+	tests := []struct {
+		name   string
+		ddc    *dv1.DorisDisaggregatedCluster
+		prev   *metav1.Condition
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{"consistent", newDDC([]string{"3.0.3"}, []string{"3.0.3"}), nil, metav1.ConditionFalse, dv1.VersionsConsistent},
+		{"rolling in window", newDDC([]string{"3.0.3", "3.0.4"}, []string{"3.0.3"}), nil, metav1.ConditionUnknown, dv1.VersionsMixed},
+		{"minor skew in window", newDDC([]string{"3.0.3"}, []string{"3.1.0"}), mixedSince(time.Hour), metav1.ConditionUnknown, dv1.VersionsMixed},
+		{"minor skew exceeded", newDDC([]string{"3.0.3"}, []string{"3.2.0"}), nil, metav1.ConditionTrue, dv1.MinorVersionSkewExceeded},
+		{"major skew exceeded", newDDC([]string{"2.1.8"}, []string{"3.0.3"}), nil, metav1.ConditionTrue, dv1.MinorVersionSkewExceeded},
+		{"mixed too long", newDDC([]string{"3.0.3"}, []string{"3.0.4"}), mixedSince(25 * time.Hour), metav1.ConditionTrue, dv1.MixedVersionsTooLong},
+	}
+	for _, tt := range tests {
+		c := newVersionSkewCondition(tt.ddc, tt.prev, now)
+		if c.Status != tt.status || c.Reason != tt.reason {
+			t.Errorf("%s newVersionSkewCondition expected %s %s, got %s %s: %s", tt.name, tt.status, tt.reason, c.Status, c.Reason, c.Message)
+		}
+	}
+
+	disabled := int32(0)
+	ddc := newDDC([]string{"3.0.3"}, []string{"3.0.4"})
+	ddc.Spec.VersionSkew = &dv1.VersionSkew{MaxMixedSeconds: &disabled}
+	if c := newVersionSkewCondition(ddc, mixedSince(25*time.Hour), now); c.Status != metav1.ConditionUnknown {
+		t.Errorf("newVersionSkewCondition expected the mixed duration not checked when maxMixedSeconds 0, got %s %s", c.Status, c.Reason)
+	}
+}
+
+func Test_checkVersionSkew(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	dcgs := &DisaggregatedComputeGroupsController{sc.DisaggregatedSubDefaultController{K8srecorder: recorder}}
+	ddc := &dv1.DorisDisaggregatedCluster{Status: dv1.DorisDisaggregatedClusterStatus{ComputeGroupStatuses: []dv1.ComputeGroupStatus{
+		{UniqueId: "cg1", RunningVersions: []string{"2.1.8"}}, {UniqueId: "cg2", RunningVersions: []string{"3.0.3"}},
+	}}}
+
+	dcgs.checkVersionSkew(ddc)
+	dcgs.checkVersionSkew(ddc)
+	if !meta.IsStatusConditionTrue(ddc.Status.Conditions, dv1.VersionSkewExceeded) {
+		t.Errorf("checkVersionSkew expected condition VersionSkewExceeded true")
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("checkVersionSkew expected one warning event when exceeded, got %d", len(recorder.Events))
+	}
+}
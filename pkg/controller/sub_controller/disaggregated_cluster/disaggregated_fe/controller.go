@@ -59,6 +59,11 @@ func New(mgr ctrl.Manager) *DisaggregatedFEController {
 
 func (dfc *DisaggregatedFEController) Sync(ctx context.Context, obj client.Object) error {
 	ddc := obj.(*v1.DorisDisaggregatedCluster)
+	//the cluster is terminating, not create or apply resources that racing with cleaning.
+	if !ddc.DeletionTimestamp.IsZero() {
+		klog.Infof("disaggregatedFEController sync namespace=%s name=%s is deleting, skip syncing.", ddc.Namespace, ddc.Name)
+		return nil
+	}
 	//deploying fe when ms is available.
 	if !dfc.msAvailable(ddc) {
 		dfc.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.WaitMetaServiceAvailable), "meta service have not ready.")
@@ -67,6 +72,7 @@ func (dfc *DisaggregatedFEController) Sync(ctx context.Context, obj client.Objec
 
 	dfc.CheckSecretMountPath(ddc, ddc.Spec.FeSpec.Secrets)
 	dfc.CheckSecretExist(ctx, ddc, ddc.Spec.FeSpec.Secrets)
+	dfc.CheckServiceAccountExist(ctx, ddc, ddc.Spec.FeSpec.ServiceAccount)
 
 	if ddc.Spec.FeSpec.Replicas == nil {
 		klog.Errorf("disaggregatedFEController sync disaggregatedDorisCluster namespace=%s,name=%s ,The number of disaggregated fe replicas is nil and has been corrected to the default value %d", ddc.Namespace, ddc.Name, v1.DefaultFeReplicaNumber)
@@ -189,7 +195,7 @@ func (dfc *DisaggregatedFEController) podIsFollower(podName, stfName string, ele
 	return num < electionNumber
 }
 
-func (dfc *DisaggregatedFEController) UpdateComponentStatus(obj client.Object) error {
+func (dfc *DisaggregatedFEController) UpdateComponentStatus(ctx context.Context, obj client.Object) error {
 	var masterAliveReplicas int32
 	var availableReplicas int32
 	var creatingReplicas int32
@@ -198,7 +204,7 @@ func (dfc *DisaggregatedFEController) UpdateComponentStatus(obj client.Object) e
 	ddc := obj.(*v1.DorisDisaggregatedCluster)
 
 	stfName := ddc.GetFEStatefulsetName()
-	sts, err := k8s.GetStatefulSet(context.Background(), dfc.K8sclient, ddc.Namespace, stfName)
+	sts, err := k8s.GetStatefulSet(ctx, dfc.K8sclient, ddc.Namespace, stfName)
 	if err != nil {
 		klog.Errorf("DisaggregatedFEController UpdateComponentStatus get statefulset %s failed, err=%s", stfName, err.Error())
 		return err
@@ -252,9 +258,34 @@ func (dfc *DisaggregatedFEController) UpdateComponentStatus(obj client.Object) e
 		ddc.Status.FEStatus.Phase = v1.Ready
 	}
 
+	dfc.refreshFEMaster(context.Background(), ddc)
+	dfc.checkFEMetadata(context.Background(), ddc)
 	return nil
 }
 
+// refreshFEMaster record the fe master node that the admin sql executed on in status, the master is cleared when fe not available or not discovered.
+func (dfc *DisaggregatedFEController) refreshFEMaster(ctx context.Context, ddc *v1.DorisDisaggregatedCluster) {
+	ddc.Status.FEStatus.Master = nil
+	if ddc.Status.FEStatus.AvailableStatus != v1.Available {
+		return
+	}
+
+	masterDBClient, _, err := dfc.newMasterSqlClient(ctx, ddc)
+	if err != nil {
+		klog.Errorf("DisaggregatedFEController refreshFEMaster namespace %s name %s connect to fe master failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+	defer masterDBClient.Close()
+	master, _, err := masterDBClient.GetFollowers()
+	if err != nil {
+		klog.Errorf("DisaggregatedFEController refreshFEMaster namespace %s name %s discover fe master failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+	if master != nil {
+		ddc.Status.FEStatus.Master = &v1.FEMasterNode{Host: master.Host, Role: master.Role}
+	}
+}
+
 // initial fe status before sync resources. status changing with sync steps, and generate the last status by classify pods.
 func (dfc *DisaggregatedFEController) initialFEStatus(ddc *v1.DorisDisaggregatedCluster) {
 	if ddc.Status.FEStatus.Phase == v1.Reconciling || ddc.Status.FEStatus.Phase == v1.ScaleDownFailed || ddc.Status.FEStatus.Phase == v1.Scaling {
@@ -296,8 +327,15 @@ func (dfc *DisaggregatedFEController) reconcileStatefulset(ctx context.Context,
 	// fe scale check and set FEStatus phase
 	willRemovedAmount := replicas - *(est.Spec.Replicas)
 
+	//the corrupted fe metadata may drop the wrong nodes, keep the replicas until the metadata recovered.
+	metadataUnhealthy := cluster.IsFEMetadataUnhealthy()
+	if (willRemovedAmount < 0 || cluster.Status.FEStatus.Phase == v1.ScaleDownFailed) && metadataUnhealthy {
+		klog.Infof("disaggregatedFEController reconcileStatefulset namespace=%s name=%s fe metadata unhealthy, defer scaling down fe.", cluster.Namespace, cluster.Name)
+		st.Spec.Replicas = est.Spec.Replicas
+	}
+
 	//  if fe scale, drop fe node by http
-	if willRemovedAmount < 0 || cluster.Status.FEStatus.Phase == v1.ScaleDownFailed {
+	if (willRemovedAmount < 0 || cluster.Status.FEStatus.Phase == v1.ScaleDownFailed) && !metadataUnhealthy {
 		if err := dfc.dropFEBySQLClient(ctx, dfc.K8sclient, cluster); err != nil {
 			cluster.Status.FEStatus.Phase = v1.ScaleDownFailed
 			klog.Errorf("ScaleDownFE failed, err:%s ", err.Error())
@@ -343,32 +381,12 @@ func (dfc *DisaggregatedFEController) recycleResources(ctx context.Context, ddc
 
 // dropFEBySQLClient only delete the fe nodes whose pod number is greater than the expected number (cluster.Spec.FeSpec.Replicas) by calling the drop_node interface
 func (dfc *DisaggregatedFEController) dropFEBySQLClient(ctx context.Context, k8sclient client.Client, cluster *v1.DorisDisaggregatedCluster) error {
-	// get adminuserName and pwd
-	adminUserName, password := dfc.GetManagementAdminUserAndPWD(ctx, cluster)
-
-	// get host and port
-	// When the operator and dcr are deployed in different namespace, it will be inaccessible, so need to add the dcr svc namespace
-	host := cluster.GetFEVIPAddresss()
-	confMap := dfc.GetConfigValuesFromConfigMaps(cluster.Namespace, resource.FE_RESOLVEKEY, cluster.Spec.FeSpec.ConfigMaps)
-	queryPort := resource.GetPort(confMap, resource.QUERY_PORT)
-	tlsConfig, secretName := dfc.DisaggregatedSubDefaultController.FindSecretTLSConfig(confMap, cluster)
-	secret, _ := k8s.GetSecret(context.Background(), dfc.K8sclient, cluster.Namespace, secretName)
-
-	// connect to doris sql to get master node
-	// It may not be the master, or even the node that needs to be deleted, causing the deletion SQL to fail.
-	dbConf := mysql.DBConfig{
-		User:     adminUserName,
-		Password: password,
-		Host:     host,
-		Port:     strconv.FormatInt(int64(queryPort), 10),
-		Database: "mysql",
-	}
-	masterDBClient, err := mysql.NewDorisMasterSqlDB(dbConf, tlsConfig, secret)
+	masterDBClient, confMap, err := dfc.newMasterSqlClient(ctx, cluster)
 	if err != nil {
-		klog.Errorf("NewDorisMasterSqlDB failed, get fe node connection err:%s", err.Error())
 		return err
 	}
 	defer masterDBClient.Close()
+	dfc.CheckOperationUserPrivilege(cluster, masterDBClient)
 
 	allObserves, err := masterDBClient.GetObservers()
 	if err != nil {
@@ -414,5 +432,46 @@ func (dfc *DisaggregatedFEController) dropFEBySQLClient(ctx context.Context, k8s
 	}
 	observes := mysql.FindNeedDeletedObservers(frontendMap, needRemovedAmount)
 	// drop node and return
-	return masterDBClient.DropObserver(observes)
+	if len(observes) == 0 {
+		return nil
+	}
+	err = masterDBClient.DropObserver(observes)
+	sc.AuditSQL(dfc.K8srecorder, cluster, masterDBClient, sc.SQLAuditRecord{Operation: sc.AuditDropObserver, Nodes: sc.FrontendNodes(observes), SQL: mysql.DropObserverSQL(observes)}, err)
+	return err
+}
+
+// newMasterSqlClient connect to the master of fe cluster by the operation user, return the client and the fe config.
+func (dfc *DisaggregatedFEController) newMasterSqlClient(ctx context.Context, cluster *v1.DorisDisaggregatedCluster) (*mysql.DB, map[string]interface{}, error) {
+	// get the user for admin sql, fall back to the management admin user when operationSecret not configured.
+	adminUserName, password := dfc.GetOperationUserAndPWD(ctx, cluster)
+
+	// get host and port
+	// When the operator and dcr are deployed in different namespace, it will be inaccessible, so need to add the dcr svc namespace
+	host := cluster.GetFEVIPAddresss()
+	confMap := dfc.GetConfigValuesFromConfigMaps(cluster.Namespace, resource.FE_RESOLVEKEY, cluster.Spec.FeSpec.ConfigMaps)
+	queryPort := resource.GetPort(confMap, resource.QUERY_PORT)
+	tlsConfig, secret, err := dfc.GetTLSConfigAndSecret(ctx, confMap, cluster)
+	if err != nil {
+		klog.Errorf("newMasterSqlClient namespace %s name %s %s", cluster.Namespace, cluster.Name, err.Error())
+		return nil, nil, err
+	}
+
+	// connect to doris sql to get master node
+	// It may not be the master, or even the node that needs to be deleted, causing the deletion SQL to fail.
+	dbConf := mysql.DBConfig{
+		User:     adminUserName,
+		Password: password,
+		Host:     host,
+		Port:     strconv.FormatInt(int64(queryPort), 10),
+		Database: "mysql",
+	}
+	// the fe pods are tried in rotation when the service routes to a fe not reachable the master transiently.
+	dbConf.FallbackHosts = cluster.GetFEPodAddresses()
+	masterDBClient, err := mysql.NewDorisMasterSqlDBWithRetry(ctx, dbConf, len(dbConf.FallbackHosts)+1, mysql.DefaultMasterConnectBackoff, tlsConfig, secret)
+	if err != nil {
+		klog.Errorf("NewDorisMasterSqlDB failed, get fe node connection err:%s", err.Error())
+		dfc.CheckFEMasterReachable(cluster, dbConf, err)
+		return nil, nil, err
+	}
+	return dfc.PlanSqlClient(masterDBClient), confMap, nil
 }
@@ -0,0 +1,81 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package disaggregated_fe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/doris-operator/api/disaggregated/v1"
+	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// checkFEMetadata detect the known signals of fe metadata unhealthy on fe master, and set the cluster condition FEMetadataUnhealthy.
+// the condition is kept when fe not available or the check failed, as the metadata state is unknown.
+func (dfc *DisaggregatedFEController) checkFEMetadata(ctx context.Context, ddc *v1.DorisDisaggregatedCluster) {
+	if ddc.Status.FEStatus.AvailableStatus != v1.Available {
+		return
+	}
+
+	masterDBClient, _, err := dfc.newMasterSqlClient(ctx, ddc)
+	if err != nil {
+		klog.Errorf("DisaggregatedFEController checkFEMetadata namespace %s name %s connect to fe master failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+	defer masterDBClient.Close()
+	signals, err := masterDBClient.GetFEMetadataUnhealthySignals()
+	if err != nil {
+		klog.Errorf("DisaggregatedFEController checkFEMetadata namespace %s name %s check fe metadata failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+
+	wasUnhealthy := ddc.IsFEMetadataUnhealthy()
+	meta.SetStatusCondition(&ddc.Status.Conditions, newFEMetadataCondition(signals, ddc.Generation))
+	if len(signals) != 0 && !wasUnhealthy {
+		msg := fmt.Sprintf("fe metadata unhealthy: %s. the dropping and decommissioning of fe and be nodes are stopped, please check the logs and metadata of fe master, the operation resumes after the signals gone.", strings.Join(signals, "; "))
+		klog.Errorf("DisaggregatedFEController checkFEMetadata namespace %s name %s %s", ddc.Namespace, ddc.Name, msg)
+		dfc.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.FEMetadataUnhealthy), msg)
+	} else if len(signals) == 0 && wasUnhealthy {
+		klog.Infof("DisaggregatedFEController checkFEMetadata namespace %s name %s fe metadata recovered.", ddc.Namespace, ddc.Name)
+		dfc.K8srecorder.Event(ddc, string(sc.EventNormal), string(sc.FEMetadataRecovered), "fe metadata recovered, the dropping and decommissioning of nodes resumed.")
+	}
+}
+
+// newFEMetadataCondition return the condition FEMetadataUnhealthy, true when have any signal.
+func newFEMetadataCondition(signals []string, generation int64) metav1.Condition {
+	if len(signals) == 0 {
+		return metav1.Condition{
+			Type:               v1.FEMetadataUnhealthy,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             v1.FEMetadataConsistent,
+			Message:            "no unhealthy signal of fe metadata detected.",
+		}
+	}
+	return metav1.Condition{
+		Type:               v1.FEMetadataUnhealthy,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             v1.FEMetadataInconsistent,
+		Message:            strings.Join(signals, "; "),
+	}
+}
@@ -69,7 +69,7 @@ func (dms *DisaggregatedMSController) GetControllerName() string {
 	return dms.ControllerName
 }
 
-func (dms *DisaggregatedMSController) UpdateComponentStatus(obj client.Object) error {
+func (dms *DisaggregatedMSController) UpdateComponentStatus(ctx context.Context, obj client.Object) error {
 	var availableReplicas int32
 	var creatingReplicas int32
 	var failedReplicas int32
@@ -88,7 +88,7 @@ func (dms *DisaggregatedMSController) UpdateComponentStatus(obj client.Object) e
 	ddc.Status.MetaServiceStatus.MsToken = token
 
 	stsName := ddc.GetMSStatefulsetName()
-	sts, err := k8s.GetStatefulSet(context.Background(), dms.K8sclient, ddc.Namespace, stsName)
+	sts, err := k8s.GetStatefulSet(ctx, dms.K8sclient, ddc.Namespace, stsName)
 	if err != nil {
 		klog.Errorf("DisaggregatedMSController UpdateComponentStatus get statefulset %s failed, err=%s", stsName, err.Error())
 		return err
@@ -156,6 +156,11 @@ func New(mgr ctrl.Manager) *DisaggregatedMSController {
 
 func (dms *DisaggregatedMSController) Sync(ctx context.Context, obj client.Object) error {
 	ddc := obj.(*v1.DorisDisaggregatedCluster)
+	//the cluster is terminating, not create or apply resources that racing with cleaning.
+	if !ddc.DeletionTimestamp.IsZero() {
+		klog.Infof("disaggregatedMSController sync namespace=%s name=%s is deleting, skip syncing.", ddc.Namespace, ddc.Name)
+		return nil
+	}
 	msSpec := ddc.Spec.MetaService
 	confMap := dms.GetConfigValuesFromConfigMaps(ddc.Namespace, resource.MS_RESOLVEKEY, msSpec.ConfigMaps)
 	svc := dms.newService(ddc, confMap)
@@ -165,6 +170,7 @@ func (dms *DisaggregatedMSController) Sync(ctx context.Context, obj client.Objec
 
 	dms.CheckSecretMountPath(ddc, ddc.Spec.MetaService.Secrets)
 	dms.CheckSecretExist(ctx, ddc, ddc.Spec.MetaService.Secrets)
+	dms.CheckServiceAccountExist(ctx, ddc, ddc.Spec.MetaService.ServiceAccount)
 
 	event, err := dms.DefaultReconcileService(ctx, svc)
 	if err != nil {
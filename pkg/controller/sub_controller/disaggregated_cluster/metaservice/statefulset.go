@@ -145,6 +145,11 @@ func (dms *DisaggregatedMSController) newSpecificEnvs(ddc *v1.DorisDisaggregated
 
 	var fdbEndpoint string
 	if msSpec.FDB.ConfigMapNamespaceName.Namespace != "" && msSpec.FDB.ConfigMapNamespaceName.Name != "" {
+		//the configmap of fdb is in the namespace of fdbcluster, it can not be read when the namespace not watched.
+		if err := dms.CheckNamespaceInScope(msSpec.FDB.ConfigMapNamespaceName.Namespace); err != nil {
+			dms.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.FDBAddressNotConfiged), "fdb configmap "+msSpec.FDB.ConfigMapNamespaceName.Name+" can not be read, "+err.Error())
+			return nil
+		}
 		cm, err := k8s.GetConfigMap(context.Background(), dms.K8sclient, msSpec.FDB.ConfigMapNamespaceName.Namespace, msSpec.FDB.ConfigMapNamespaceName.Name)
 		if err != nil {
 			dms.K8srecorder.Event(ddc, string(sc.EventWarning), string(sc.FDBAddressNotConfiged), "configmap "+"namespace"+msSpec.FDB.ConfigMapNamespaceName.Namespace+" name "+msSpec.FDB.ConfigMapNamespaceName.Name+" find failed "+err.Error())
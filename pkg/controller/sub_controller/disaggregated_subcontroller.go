@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -37,6 +38,7 @@ import (
 	"github.com/spf13/viper"
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -61,6 +63,8 @@ const (
 	FileCachePathKey                     = "file_cache_path"
 	FileCacheSubConfigPathKey            = "path"
 	FileCacheSubConfigTotalSizeKey       = "total_size"
+	StorageRootPathKey                   = "storage_root_path"
+	StorageRootPathCapacityKey           = "capacity"
 )
 
 type DisaggregatedSubController interface {
@@ -73,13 +77,41 @@ type DisaggregatedSubController interface {
 	GetControllerName() string
 
 	//UpdateStatus update the component status on src.
-	UpdateComponentStatus(obj client.Object) error
+	UpdateComponentStatus(ctx context.Context, obj client.Object) error
 }
 
 type DisaggregatedSubDefaultController struct {
 	K8sclient      client.Client
 	K8srecorder    record.EventRecorder
 	ControllerName string
+	//ServerSideApply reconcile the statefulset and service by server-side apply, the operator only owns the fields it sets.
+	ServerSideApply bool
+	//SkipEquivalentApply skip updating the statefulset when the hash changed but the compared fields already hold in the existing statefulset.
+	SkipEquivalentApply bool
+	//Profile is the profile that overrides the compute groups when the cluster not annotated with profile.
+	Profile string
+	//WatchNamespaces are the namespaces that operator scoped to, the resources out of them can not be read. empty means all namespaces.
+	WatchNamespaces []string
+	//Plan records the writes and sql instead of executing them when not nil, the sub controller runs in plan mode.
+	Plan *Plan
+	//ExternalMetrics read the metrics of kubernetes external metrics api.
+	ExternalMetrics k8s.ExternalMetricsClient
+	//WorkerCount is the number of compute groups synced in parallel, less than 1 means syncing one by one.
+	WorkerCount int
+}
+
+// CheckNamespaceInScope return error when the namespace out of the watch namespaces of operator, the cache of operator not have the resources out of them.
+// the error tells the resource is out of scope clearly than the error of cache.
+func (d *DisaggregatedSubDefaultController) CheckNamespaceInScope(namespace string) error {
+	if len(d.WatchNamespaces) == 0 {
+		return nil
+	}
+	for _, ns := range d.WatchNamespaces {
+		if ns == namespace {
+			return nil
+		}
+	}
+	return fmt.Errorf("the namespace %s is out of the watch namespaces %s of operator, please add it to the watch-namespaces of operator or use the resource in scope", namespace, strings.Join(d.WatchNamespaces, ","))
 }
 
 func (d *DisaggregatedSubDefaultController) GetConfigValuesFromConfigMaps(namespace string, resolveKey string, cms []v1.ConfigMap) map[string]interface{} {
@@ -232,9 +264,14 @@ func (d *DisaggregatedSubDefaultController) newDefaultAffinity(matchKey, value s
 
 // the common logic to apply service, will used by fe,be,ms.
 func (d *DisaggregatedSubDefaultController) DefaultReconcileService(ctx context.Context, svc *corev1.Service) (*Event, error) {
-	if err := k8s.ApplyService(ctx, d.K8sclient, svc, func(nsvc, osvc *corev1.Service) bool {
+	equal := func(nsvc, osvc *corev1.Service) bool {
 		return resource.ServiceDeepEqualWithAnnoKey(nsvc, osvc, v1.DisaggregatedSpecHashValueAnnotation)
-	}); err != nil {
+	}
+	apply := k8s.ApplyService
+	if d.ServerSideApply {
+		apply = k8s.ServerSideApplyService
+	}
+	if err := apply(ctx, d.K8sclient, svc, equal); err != nil {
 		klog.Errorf("disaggregatedSubDefaultController reconcileService apply service namespace=%s name=%s failed, err=%s", svc.Namespace, svc.Name, err.Error())
 		return &Event{Type: EventWarning, Reason: ServiceApplyedFailed, Message: err.Error()}, err
 	}
@@ -270,6 +307,24 @@ func (d *DisaggregatedSubDefaultController) CheckSecretExist(ctx context.Context
 	}
 }
 
+// CheckServiceAccountExist warn when the serviceAccount of pods not exist in the namespace of cluster, the pods can not be created and the token of workload identity can not be mounted.
+func (d *DisaggregatedSubDefaultController) CheckServiceAccountExist(ctx context.Context, ddc *v1.DorisDisaggregatedCluster, serviceAccount string) {
+	if serviceAccount == "" {
+		return
+	}
+	var sa corev1.ServiceAccount
+	err := d.K8sclient.Get(ctx, types.NamespacedName{Namespace: ddc.Namespace, Name: serviceAccount}, &sa)
+	if err == nil {
+		return
+	}
+	msg := fmt.Sprintf("get the serviceAccount %s in namespace %s failed, the pods using it can not be created, err=%s", serviceAccount, ddc.Namespace, err.Error())
+	if apierrors.IsNotFound(err) {
+		msg = fmt.Sprintf("the serviceAccount %s not exist in namespace %s, the pods using it can not be created, please create it.", serviceAccount, ddc.Namespace)
+	}
+	klog.Errorf("disaggregatedSubDefaultController CheckServiceAccountExist namespace=%s name=%s %s", ddc.Namespace, ddc.Name, msg)
+	d.K8srecorder.Event(ddc, string(EventWarning), string(ServiceAccountNotExist), msg)
+}
+
 // RestrictConditionsEqual adds two StatefulSet,
 // It is used to control the conditions for comparing.
 // nst StatefulSet - a new StatefulSet
@@ -296,6 +351,55 @@ func (d *DisaggregatedSubDefaultController) GetManagementAdminUserAndPWD(ctx con
 
 }
 
+// GetOperationUserAndPWD return the user for dropping or decommissioning nodes, use the operationSecret when configured, otherwise the management admin user.
+func (d *DisaggregatedSubDefaultController) GetOperationUserAndPWD(ctx context.Context, ddc *v1.DorisDisaggregatedCluster) (string, string) {
+	ops := ddc.Spec.OperationSecret
+	if ops == nil || ops.SecretName == "" {
+		return d.GetManagementAdminUserAndPWD(ctx, ddc)
+	}
+
+	secret, err := k8s.GetSecret(ctx, d.K8sclient, ddc.Namespace, ops.SecretName)
+	if err != nil {
+		klog.Errorf("disaggregatedSubDefaultController GetOperationUserAndPWD get operation secret namespace=%s name=%s failed, use the management admin user, err=%s", ddc.Namespace, ops.SecretName, err.Error())
+		return d.GetManagementAdminUserAndPWD(ctx, ddc)
+	}
+	userKey, pwdKey := "username", "password"
+	if ops.UsernameKey != "" {
+		userKey = ops.UsernameKey
+	}
+	if ops.PasswordKey != "" {
+		pwdKey = ops.PasswordKey
+	}
+	return string(secret.Data[userKey]), string(secret.Data[pwdKey])
+}
+
+// CheckOperationUserPrivilege warn when the user of operationSecret have not privilege for node operations, the check not block the operations.
+func (d *DisaggregatedSubDefaultController) CheckOperationUserPrivilege(ddc *v1.DorisDisaggregatedCluster, db *mysql.DB) {
+	if ddc.Spec.OperationSecret == nil || ddc.Spec.OperationSecret.SecretName == "" {
+		return
+	}
+
+	ok, err := db.HasNodePrivilege()
+	if err != nil {
+		klog.Errorf("disaggregatedSubDefaultController CheckOperationUserPrivilege namespace=%s name=%s check privilege failed, err=%s", ddc.Namespace, ddc.Name, err.Error())
+		return
+	}
+	if !ok {
+		d.K8srecorder.Event(ddc, string(EventWarning), string(OperationUserNoNodePriv), fmt.Sprintf("the user of operation secret %s have not NODE_PRIV, dropping or decommissioning nodes may fail.", ddc.Spec.OperationSecret.SecretName))
+	}
+}
+
+// CheckFEMasterReachable emit warning event when the fe is reachable by the configured host and port but the master is not, the admin sql for dropping or decommissioning nodes can not be executed on master.
+func (d *DisaggregatedSubDefaultController) CheckFEMasterReachable(ddc *v1.DorisDisaggregatedCluster, dbConf mysql.DBConfig, err error) {
+	if !errors.Is(err, mysql.ErrFEMasterUnreachable) {
+		return
+	}
+	msg := fmt.Sprintf("fe %s:%s is reachable as user %s but the fe master is not, dropping or decommissioning nodes can not be executed. please check the query port of fe service routes to the fe pods and the user can connect every fe, err=%s",
+		dbConf.Host, dbConf.Port, dbConf.User, err.Error())
+	klog.Errorf("disaggregatedSubDefaultController CheckFEMasterReachable namespace=%s name=%s %s", ddc.Namespace, ddc.Name, msg)
+	d.K8srecorder.Event(ddc, string(EventWarning), string(FEMasterUnreachable), msg)
+}
+
 // add cluster specification on container spec. this is useful to add common spec on different type pods, example: kerberos volume for fe and be.
 func(d *DisaggregatedSubDefaultController) AddClusterSpecForPodTemplate(componentType v1.DisaggregatedComponentType, configMap map[string]interface{}, spec *v1.DorisDisaggregatedClusterSpec, pts *corev1.PodTemplateSpec){
 	var c *corev1.Container
@@ -656,41 +760,110 @@ func (d *DisaggregatedSubDefaultController) getBEEmptyDirVolumesVolumeMounts(con
 }
 
 func (d *DisaggregatedSubDefaultController) getCacheMaxSizeAndPaths(cvs map[string]interface{}) ([]string, int64) {
-	v := cvs[FileCachePathKey]
-	if v == nil {
+	dirs, ok := resolveCacheDirs(cvs)
+	if !ok {
 		return []string{DefaultCacheRootPath}, DefaultCacheSize
 	}
 
-	var paths []string
+	paths := []string{}
 	var maxCacheSize int64
-	vbys := v.(string)
+	for _, dir := range dirs {
+		paths = append(paths, dir.path)
+		if dir.sized && maxCacheSize < dir.size {
+			maxCacheSize = dir.size
+		}
+	}
+	return paths, maxCacheSize
+}
+
+// cacheDir is a cache path resolved from be config, sized is false when the size of it not configured.
+type cacheDir struct {
+	path  string
+	size  int64
+	sized bool
+}
+
+// resolveCacheDirs resolve the cache paths from be config, the versions of doris name the config of cache differently:
+// file_cache_path is a json array of path and total_size in bytes, used by the versions supported file cache.
+// storage_root_path is `path1[,medium:ssd][,capacity:50];path2` or `path1.SSD,50`, the capacity in GB, used by the old versions as the cache of compute node.
+// file_cache_path takes precedence when both configured. return false when none of them configured, the default cache path is used.
+func resolveCacheDirs(cvs map[string]interface{}) ([]cacheDir, bool) {
+	if v, ok := cvs[FileCachePathKey]; ok && v != nil {
+		return resolveFileCachePath(v), true
+	}
+	if v, ok := cvs[StorageRootPathKey]; ok && v != nil {
+		return resolveStorageRootPathAsCache(v), true
+	}
+	return nil, false
+}
+
+func resolveFileCachePath(v interface{}) []cacheDir {
+	vbys, ok := v.(string)
+	if !ok {
+		klog.Errorf("disaggregatedComputeGroupsController resolveFileCachePath file_cache_path is not string.")
+		return nil
+	}
 	var pa []map[string]interface{}
-	err := json.Unmarshal([]byte(vbys), &pa)
-	if err != nil {
-		klog.Errorf("disaggregatedComputeGroupsController getStorageMaxSizeAndPaths json unmarshal file_cache_path failed, err=%s", err.Error())
-		return []string{}, 0
+	if err := json.Unmarshal([]byte(vbys), &pa); err != nil {
+		klog.Errorf("disaggregatedComputeGroupsController resolveFileCachePath json unmarshal file_cache_path failed, err=%s", err.Error())
+		return nil
 	}
 
+	var dirs []cacheDir
 	for i, mp := range pa {
-		pv := mp[FileCacheSubConfigPathKey]
-		pv_str, ok := pv.(string)
+		pv_str, ok := mp[FileCacheSubConfigPathKey].(string)
 		if !ok {
-			klog.Errorf("disaggregatedComputeGroupsController getStorageMaxSizeAndPaths index %d have not path config.", i)
+			klog.Errorf("disaggregatedComputeGroupsController resolveFileCachePath index %d have not path config.", i)
 			continue
 		}
-		paths = append(paths, pv_str)
-		cache_v := mp[FileCacheSubConfigTotalSizeKey]
-		fc_size, ok := cache_v.(float64)
-		cache_size := int64(fc_size)
-		if !ok {
-			klog.Errorf("disaggregatedComputeGroupsController getStorageMaxSizeAndPaths index %d total_size is not number.", i)
+		dir := cacheDir{path: pv_str}
+		if fc_size, ok := mp[FileCacheSubConfigTotalSizeKey].(float64); ok {
+			dir.size, dir.sized = int64(fc_size), true
+		} else {
+			klog.Errorf("disaggregatedComputeGroupsController resolveFileCachePath index %d total_size is not number.", i)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func resolveStorageRootPathAsCache(v interface{}) []cacheDir {
+	vs, ok := v.(string)
+	if !ok {
+		klog.Errorf("disaggregatedComputeGroupsController resolveStorageRootPathAsCache storage_root_path is not string.")
+		return nil
+	}
+
+	var dirs []cacheDir
+	for _, pc := range strings.Split(vs, ";") {
+		items := strings.Split(pc, ",")
+		//the medium maybe the suffix of path, ep: /path1.SSD
+		path := strings.TrimSpace(items[0])
+		if i := strings.LastIndex(path, "."); i > strings.LastIndex(path, "/") {
+			path = path[:i]
+		}
+		path = strings.TrimSuffix(path, "/")
+		if path == "" {
 			continue
 		}
-		if maxCacheSize < cache_size {
-			maxCacheSize = cache_size
+
+		dir := cacheDir{path: path}
+		for _, item := range items[1:] {
+			item = strings.TrimSpace(item)
+			kv := strings.SplitN(item, ":", 2)
+			if len(kv) == 2 {
+				if strings.ToLower(strings.TrimSpace(kv[0])) != StorageRootPathCapacityKey {
+					continue
+				}
+				item = strings.TrimSpace(kv[1])
+			}
+			if gb, err := strconv.ParseFloat(item, 64); err == nil {
+				dir.size, dir.sized = int64(gb*(1<<30)), true
+			}
 		}
+		dirs = append(dirs, dir)
 	}
-	return paths, maxCacheSize
+	return dirs
 }
 
 // use emptyDir mode generate metaservice use volume and volumeMount.
@@ -765,6 +938,79 @@ func (d *DisaggregatedSubDefaultController) getLogPath(confMap map[string]interf
 	}
 }
 
+// GetCachePathsOverlapLogPath return the cache paths that same as the log path or nested with it, the log volume and cache volumes will collide when mounted.
+func (d *DisaggregatedSubDefaultController) GetCachePathsOverlapLogPath(confMap map[string]interface{}) []string {
+	logPath := filepath.Clean(d.getLogPath(confMap, v1.DisaggregatedBE))
+	cachePaths, _ := d.getCacheMaxSizeAndPaths(confMap)
+	var overlaps []string
+	for _, cachePath := range cachePaths {
+		cp := filepath.Clean(cachePath)
+		if cp == logPath || strings.HasPrefix(cp, logPath+"/") || strings.HasPrefix(logPath, cp+"/") {
+			overlaps = append(overlaps, cachePath)
+		}
+	}
+	return overlaps
+}
+
+// GetStorageTierConflicts return the misconfigurations of storage tiers that a path not mounted by a distinct volume, only check the persistentVolumes of be.
+// a path configured in multiple persistentVolumes have ambiguous storage class, a path nested in another path mounts the volume over the volume of the parent.
+func (d *DisaggregatedSubDefaultController) GetStorageTierConflicts(confMap map[string]interface{}, commonSpec *v1.CommonSpec) []string {
+	if len(commonSpec.PersistentVolumes) == 0 {
+		return nil
+	}
+
+	var conflicts []string
+	paths, _ := d.getCacheMaxSizeAndPaths(confMap)
+	owners := map[string]int{}
+	for i, pv := range commonSpec.PersistentVolumes {
+		for _, mp := range pv.MountPaths {
+			p := filepath.Clean(mp)
+			if o, ok := owners[p]; ok && o != i {
+				conflicts = append(conflicts, fmt.Sprintf("path %s configured in persistentVolumes[%d] and persistentVolumes[%d]", mp, o, i))
+				continue
+			}
+			owners[p] = i
+			paths = append(paths, mp)
+		}
+	}
+
+	ss := set.NewSetString()
+	var cleaned []string
+	for _, tp := range paths {
+		p := filepath.Clean(tp)
+		if ss.Find(p) {
+			continue
+		}
+		ss.Add(p)
+		cleaned = append(cleaned, p)
+	}
+	for _, p := range cleaned {
+		for _, parent := range cleaned {
+			if strings.HasPrefix(p, parent+"/") {
+				conflicts = append(conflicts, fmt.Sprintf("path %s nested in path %s", p, parent))
+			}
+		}
+	}
+	return conflicts
+}
+
+// GetCachePathsWithoutSize return the cache paths in file_cache_path that total_size not configured or not positive, the volumes of them are useless for be.
+func (d *DisaggregatedSubDefaultController) GetCachePathsWithoutSize(confMap map[string]interface{}) []string {
+	//be uses the capacity of disk when the capacity of storage_root_path not configured, only the file_cache_path requires the size.
+	if v, ok := confMap[FileCachePathKey]; !ok || v == nil {
+		return nil
+	}
+	dirs, _ := resolveCacheDirs(confMap)
+
+	var paths []string
+	for _, dir := range dirs {
+		if !dir.sized || dir.size <= 0 {
+			paths = append(paths, dir.path)
+		}
+	}
+	return paths
+}
+
 func (d *DisaggregatedSubDefaultController) getFEMetaPath(confMap map[string]interface{}) string {
 	v := confMap[FEMetaPathKey]
 	if v == nil {
@@ -773,7 +1019,42 @@ func (d *DisaggregatedSubDefaultController) getFEMetaPath(confMap map[string]int
 	return v.(string)
 }
 
+// GetTLSConfigAndSecret return the tls config and the secret of certificates for connecting fe, nil when fe not enabled tls.
+// the tlsSecret of spec not got returns error, the connection not fall back to plain text when the tls is required.
+func (d *DisaggregatedSubDefaultController) GetTLSConfigAndSecret(ctx context.Context, feConfMap map[string]interface{}, ddc *v1.DorisDisaggregatedCluster) (*mysql.TLSConfig, *corev1.Secret, error) {
+	tlsConfig, secretName := d.FindSecretTLSConfig(feConfMap, ddc)
+	if tlsConfig == nil {
+		return nil, nil, nil
+	}
+	secret, err := k8s.GetSecret(ctx, d.K8sclient, ddc.Namespace, secretName)
+	if err != nil && ddc.Spec.TLSSecret != nil && ddc.Spec.TLSSecret.SecretName != "" {
+		return nil, nil, fmt.Errorf("get tls secret %s failed, err=%s", secretName, err.Error())
+	}
+	return tlsConfig, secret, nil
+}
+
+// FindSecretTLSConfig return the tls config and the secret name of certificates for connecting fe, the tlsSecret of spec takes precedence over the tls config of fe.
 func (d *DisaggregatedSubDefaultController) FindSecretTLSConfig(feConfMap map[string]interface{}, ddc *v1.DorisDisaggregatedCluster) (*mysql.TLSConfig, string /*secret name*/) {
+	if ts := ddc.Spec.TLSSecret; ts != nil && ts.SecretName != "" {
+		tlsConfig := &mysql.TLSConfig{
+			CAFileName:         "ca.crt",
+			ClientCertFileName: "tls.crt",
+			ClientKeyFileName:  "tls.key",
+			InsecureSkipVerify: ts.InsecureSkipVerify,
+			ClientCertOptional: true,
+		}
+		if ts.CAKey != "" {
+			tlsConfig.CAFileName = ts.CAKey
+		}
+		if ts.CertKey != "" {
+			tlsConfig.ClientCertFileName = ts.CertKey
+		}
+		if ts.KeyKey != "" {
+			tlsConfig.ClientKeyFileName = ts.KeyKey
+		}
+		return tlsConfig, ts.SecretName
+	}
+
 	enableTLS := resource.GetString(feConfMap, resource.ENABLE_TLS_KEY)
 	if enableTLS == "" {
 		return nil, ""
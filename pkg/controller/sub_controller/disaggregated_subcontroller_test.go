@@ -17,9 +17,17 @@
 package sub_controller
 
 import (
+    "context"
+    "errors"
+    "fmt"
     v1 "github.com/apache/doris-operator/api/disaggregated/v1"
+    "github.com/apache/doris-operator/pkg/common/utils/mysql"
     corev1 "k8s.io/api/core/v1"
     "k8s.io/apimachinery/pkg/api/resource"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+    "k8s.io/client-go/tools/record"
+    "sigs.k8s.io/controller-runtime/pkg/client/fake"
+    "strings"
     "testing"
 )
 
@@ -116,3 +124,196 @@ func TestDisaggregatedSubDefaultController_PersistentVolumeArrayBuildVolumesVolu
         t.Errorf("build ms default volumes volumemounts and pvcs failed, the number is not right.")
     }
 }
+
+func TestDisaggregatedSubDefaultController_GetCachePathsOverlapLogPath(t *testing.T) {
+    d := &DisaggregatedSubDefaultController{}
+    if overlaps := d.GetCachePathsOverlapLogPath(map[string]interface{}{}); len(overlaps) != 0 {
+        t.Errorf("default log path and cache path should not overlap, got %v", overlaps)
+    }
+
+    confMap := map[string]interface{}{
+        "sys_log_dir": "/opt/apache-doris/be/log/",
+        "file_cache_path": "[{\"path\":\"/opt/apache-doris/be/log\",\"total_size\":21474836480},{\"path\":\"/opt/apache-doris/be/log/cache\",\"total_size\":21474836480},{\"path\":\"/opt/apache-doris/be/logcache\",\"total_size\":21474836480}]",
+    }
+    overlaps := d.GetCachePathsOverlapLogPath(confMap)
+    if len(overlaps) != 2 || overlaps[0] != "/opt/apache-doris/be/log" || overlaps[1] != "/opt/apache-doris/be/log/cache" {
+        t.Errorf("GetCachePathsOverlapLogPath expected the same and nested cache paths, got %v", overlaps)
+    }
+}
+
+func TestDisaggregatedSubDefaultController_GetStorageTierConflicts(t *testing.T) {
+    d := &DisaggregatedSubDefaultController{}
+    confMap := map[string]interface{}{
+        "file_cache_path": "[{\"path\":\"/opt/apache-doris/be/ssd\",\"total_size\":21474836480},{\"path\":\"/opt/apache-doris/be/hdd\",\"total_size\":107374182400}]",
+    }
+    ssd, hdd := "ssd", "hdd"
+    spec := &v1.CommonSpec{PersistentVolumes: []v1.PersistentVolume{
+        {MountPaths: []string{"/opt/apache-doris/be/ssd"}, PersistentVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{StorageClassName: &ssd}},
+        {MountPaths: []string{"/opt/apache-doris/be/hdd"}, PersistentVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{StorageClassName: &hdd}},
+    }}
+    if conflicts := d.GetStorageTierConflicts(confMap, spec); len(conflicts) != 0 {
+        t.Errorf("GetStorageTierConflicts distinct tiers expected no conflict, got %v", conflicts)
+    }
+
+    spec.PersistentVolumes[1].MountPaths = []string{"/opt/apache-doris/be/ssd/", "/opt/apache-doris/be/hdd/overflow"}
+    conflicts := d.GetStorageTierConflicts(confMap, spec)
+    if len(conflicts) != 2 {
+        t.Errorf("GetStorageTierConflicts expected the duplicated and nested paths, got %v", conflicts)
+    }
+}
+
+func TestDisaggregatedSubDefaultController_GetCachePathsWithoutSize(t *testing.T) {
+    d := &DisaggregatedSubDefaultController{}
+    if paths := d.GetCachePathsWithoutSize(map[string]interface{}{}); len(paths) != 0 {
+        t.Errorf("default cache path should have size, got %v", paths)
+    }
+
+    confMap := map[string]interface{}{
+        "file_cache_path": "[{\"path\":\"/opt/apache-doris/be/cache1\",\"total_size\":21474836480},{\"path\":\"/opt/apache-doris/be/cache2\",\"total_size\":0},{\"path\":\"/opt/apache-doris/be/cache3\"}]",
+    }
+    paths := d.GetCachePathsWithoutSize(confMap)
+    if len(paths) != 2 || paths[0] != "/opt/apache-doris/be/cache2" || paths[1] != "/opt/apache-doris/be/cache3" {
+        t.Errorf("GetCachePathsWithoutSize expected the zero and missing size cache paths, got %v", paths)
+    }
+}
+
+func TestDisaggregatedSubDefaultController_getCacheMaxSizeAndPaths(t *testing.T) {
+    d := &DisaggregatedSubDefaultController{}
+    tests := []struct {
+        name    string
+        confMap map[string]interface{}
+        paths   []string
+        maxSize int64
+    }{
+        {"default", map[string]interface{}{}, []string{DefaultCacheRootPath}, DefaultCacheSize},
+        {"file_cache_path", map[string]interface{}{
+            "file_cache_path": "[{\"path\":\"/opt/apache-doris/be/cache1\",\"total_size\":10737418240},{\"path\":\"/opt/apache-doris/be/cache2\",\"total_size\":21474836480}]",
+        }, []string{"/opt/apache-doris/be/cache1", "/opt/apache-doris/be/cache2"}, 21474836480},
+        {"storage_root_path medium and capacity", map[string]interface{}{
+            "storage_root_path": "/opt/apache-doris/be/storage1,medium:ssd,capacity:10;/opt/apache-doris/be/storage2/,medium:hdd",
+        }, []string{"/opt/apache-doris/be/storage1", "/opt/apache-doris/be/storage2"}, 10737418240},
+        {"storage_root_path medium suffix", map[string]interface{}{
+            "storage_root_path": "/opt/apache-doris/be/storage1.SSD,20;/opt/apache-doris/be/storage2.HDD;",
+        }, []string{"/opt/apache-doris/be/storage1", "/opt/apache-doris/be/storage2"}, 21474836480},
+        {"file_cache_path precedence", map[string]interface{}{
+            "file_cache_path":   "[{\"path\":\"/opt/apache-doris/be/cache1\",\"total_size\":10737418240}]",
+            "storage_root_path": "/opt/apache-doris/be/storage1",
+        }, []string{"/opt/apache-doris/be/cache1"}, 10737418240},
+        {"invalid file_cache_path", map[string]interface{}{"file_cache_path": "[{"}, []string{}, 0},
+    }
+
+    for _, test := range tests {
+        paths, maxSize := d.getCacheMaxSizeAndPaths(test.confMap)
+        if maxSize != test.maxSize || len(paths) != len(test.paths) {
+            t.Errorf("getCacheMaxSizeAndPaths %s expected paths %v max size %d, got %v %d", test.name, test.paths, test.maxSize, paths, maxSize)
+            continue
+        }
+        for i := range paths {
+            if paths[i] != test.paths[i] {
+                t.Errorf("getCacheMaxSizeAndPaths %s expected paths %v, got %v", test.name, test.paths, paths)
+            }
+        }
+    }
+
+    if paths := d.GetCachePathsWithoutSize(map[string]interface{}{"storage_root_path": "/opt/apache-doris/be/storage1"}); len(paths) != 0 {
+        t.Errorf("GetCachePathsWithoutSize storage_root_path not requires capacity, got %v", paths)
+    }
+}
+
+func TestDisaggregatedSubDefaultController_GetOperationUserAndPWD(t *testing.T) {
+    secret := &corev1.Secret{
+        ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "operator-user"},
+        Data:       map[string][]byte{"user": []byte("operator"), "pwd": []byte("123456")},
+    }
+    d := &DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().WithObjects(secret).Build()}
+    ddc := &v1.DorisDisaggregatedCluster{
+        ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+        Spec:       v1.DorisDisaggregatedClusterSpec{AdminUser: &v1.AdminUser{Name: "admin", Password: "admin"}},
+    }
+
+    if user, _ := d.GetOperationUserAndPWD(context.Background(), ddc); user != "admin" {
+        t.Errorf("GetOperationUserAndPWD expected fall back to admin user when operationSecret not set, got %s", user)
+    }
+    ddc.Spec.OperationSecret = &v1.OperationSecret{SecretName: "operator-user", UsernameKey: "user", PasswordKey: "pwd"}
+    if user, pwd := d.GetOperationUserAndPWD(context.Background(), ddc); user != "operator" || pwd != "123456" {
+        t.Errorf("GetOperationUserAndPWD expected the user of operationSecret, got %s", user)
+    }
+}
+
+func TestDisaggregatedSubDefaultController_CheckNamespaceInScope(t *testing.T) {
+	d := &DisaggregatedSubDefaultController{}
+	if err := d.CheckNamespaceInScope("fdb"); err != nil {
+		t.Errorf("CheckNamespaceInScope expected all namespaces in scope when not configured, err=%s", err.Error())
+	}
+	d.WatchNamespaces = []string{"default", "doris"}
+	if err := d.CheckNamespaceInScope("doris"); err != nil {
+		t.Errorf("CheckNamespaceInScope expected doris in scope, err=%s", err.Error())
+	}
+	if err := d.CheckNamespaceInScope("fdb"); err == nil || !strings.Contains(err.Error(), "out of the watch namespaces") {
+		t.Errorf("CheckNamespaceInScope expected fdb out of scope, err=%v", err)
+	}
+}
+
+func TestDisaggregatedSubDefaultController_CheckFEMasterReachable(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	d := &DisaggregatedSubDefaultController{K8srecorder: recorder}
+	ddc := &v1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	dbConf := mysql.DBConfig{User: "root", Host: "test-fe.default", Port: "9030"}
+
+	d.CheckFEMasterReachable(ddc, dbConf, errors.New("connection refused"))
+	if len(recorder.Events) != 0 {
+		t.Errorf("CheckFEMasterReachable expected no event when fe not reachable, got %s", <-recorder.Events)
+	}
+	d.CheckFEMasterReachable(ddc, dbConf, fmt.Errorf("%w, the connected fe fe-1 is not the master", mysql.ErrFEMasterUnreachable))
+	if len(recorder.Events) != 1 {
+		t.Fatalf("CheckFEMasterReachable expected one event when master unreachable, got %d", len(recorder.Events))
+	}
+	if e := <-recorder.Events; !strings.Contains(e, string(FEMasterUnreachable)) || !strings.Contains(e, "test-fe.default:9030") {
+		t.Errorf("CheckFEMasterReachable expected the event tells the host and port, got %s", e)
+	}
+}
+
+func TestDisaggregatedSubDefaultController_CheckServiceAccountExist(t *testing.T) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doris-s3"}}
+	recorder := record.NewFakeRecorder(10)
+	d := &DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().WithObjects(sa).Build(), K8srecorder: recorder}
+	ddc := &v1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+
+	d.CheckServiceAccountExist(context.Background(), ddc, "")
+	d.CheckServiceAccountExist(context.Background(), ddc, "doris-s3")
+	if len(recorder.Events) != 0 {
+		t.Errorf("CheckServiceAccountExist expected no event when not configured or exist, got %s", <-recorder.Events)
+	}
+	d.CheckServiceAccountExist(context.Background(), ddc, "doris-gcs")
+	if len(recorder.Events) != 1 {
+		t.Fatalf("CheckServiceAccountExist expected one event when not exist, got %d", len(recorder.Events))
+	}
+	if e := <-recorder.Events; !strings.Contains(e, string(ServiceAccountNotExist)) || !strings.Contains(e, "doris-gcs") {
+		t.Errorf("CheckServiceAccountExist expected the event tells the serviceAccount, got %s", e)
+	}
+}
+
+func TestDisaggregatedSubDefaultController_GetTLSConfigAndSecret(t *testing.T) {
+    secret := &corev1.Secret{
+        ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fe-tls"},
+        Data:       map[string][]byte{"ca.pem": []byte("ca")},
+    }
+    d := &DisaggregatedSubDefaultController{K8sclient: fake.NewClientBuilder().WithObjects(secret).Build()}
+    ddc := &v1.DorisDisaggregatedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+
+    if tc, s, err := d.GetTLSConfigAndSecret(context.Background(), map[string]interface{}{}, ddc); tc != nil || s != nil || err != nil {
+        t.Errorf("GetTLSConfigAndSecret expected nil when tls not enabled, got %v %v %v", tc, s, err)
+    }
+    ddc.Spec.TLSSecret = &v1.TLSSecret{SecretName: "fe-tls", CAKey: "ca.pem", InsecureSkipVerify: true}
+    tc, s, err := d.GetTLSConfigAndSecret(context.Background(), map[string]interface{}{}, ddc)
+    if err != nil || s == nil || s.Name != "fe-tls" {
+        t.Fatalf("GetTLSConfigAndSecret expected the secret of tlsSecret, got %v err=%v", s, err)
+    }
+    if tc.CAFileName != "ca.pem" || tc.ClientCertFileName != "tls.crt" || tc.ClientKeyFileName != "tls.key" || !tc.InsecureSkipVerify || !tc.ClientCertOptional {
+        t.Errorf("GetTLSConfigAndSecret expected the keys of tlsSecret with defaults, got %+v", tc)
+    }
+    ddc.Spec.TLSSecret.SecretName = "not-exist"
+    if _, _, err := d.GetTLSConfigAndSecret(context.Background(), map[string]interface{}{}, ddc); err == nil {
+        t.Errorf("GetTLSConfigAndSecret expected error when the tlsSecret not exist")
+    }
+}
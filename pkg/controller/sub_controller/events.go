@@ -39,6 +39,11 @@ const (
 	PVCCreate               = "PVCCreate"
 	PVCCreateFailed         = "PVCCreateFailed"
 	FollowerScaleDownFailed = "FollowerScaleDownFailed"
+	ObserverAdded           = "ObserverAdded"
+	ObserverRegisterFailed  = "ObserverRegisterFailed"
+	FEQueryPortNotExposed   = "FEQueryPortNotExposed"
+	ObserverPromoted        = "ObserverPromoted"
+	ObserverPromoteFailed   = "ObserverPromoteFailed"
 )
 
 type EventReason string
@@ -64,18 +69,103 @@ var (
 	CGApplyResourceFailed           EventReason = "CGApplyResourceFailed"
 	CGStatefulsetDeleteFailed       EventReason = "CGStatefulsetDeleteFailed"
 	CGServiceDeleteFailed           EventReason = "CGServiceDeleteFailed"
+	CGBackendsNotConfirmed          EventReason = "CGBackendsNotConfirmed"
+	CGNodePoolResolveFailed         EventReason = "CGNodePoolResolveFailed"
+	CGRemoveFailed                  EventReason = "CGRemoveFailed"
+	CGScaleDownDeferred             EventReason = "CGScaleDownDeferred"
+	CGRackLabelMissing              EventReason = "CGRackLabelMissing"
+	CGLogCachePathConflict          EventReason = "CGLogCachePathConflict"
+	CGSwapToInvalid                 EventReason = "CGSwapToInvalid"
+	CGTrafficSwapped                EventReason = "CGTrafficSwapped"
+	CGScaleDownBlocked              EventReason = "CGScaleDownBlocked"
+	CGScaleInSnapshotCreated        EventReason = "CGScaleInSnapshotCreated"
+	CGScaleInSnapshotFailed         EventReason = "CGScaleInSnapshotFailed"
+	CGWaitServiceReady              EventReason = "CGWaitServiceReady"
+	CGNameCollision                 EventReason = "CGNameCollision"
+	CGScaleCooldown                 EventReason = "CGScaleCooldown"
+	CGConfigMergeFailed             EventReason = "CGConfigMergeFailed"
+	CGPodSecurityViolation          EventReason = "CGPodSecurityViolation"
+	PVCBindFailed                   EventReason = "PVCBindFailed"
+	CGTenantsInvalid                EventReason = "CGTenantsInvalid"
+	CGTenantsBindFailed             EventReason = "CGTenantsBindFailed"
+	CGImageRolledBack               EventReason = "CGImageRolledBack"
+	CGCachePathSizeInvalid          EventReason = "CGCachePathSizeInvalid"
+	CGQuiescingForFE                EventReason = "CGQuiescingForFE"
+	CGGracefulStopPortInvalid       EventReason = "CGGracefulStopPortInvalid"
+	CGBackendsDropped               EventReason = "CGBackendsDropped"
+	CGDropBackendsInvalid           EventReason = "CGDropBackendsInvalid"
+	CGDuplicateBackendsDropped      EventReason = "CGDuplicateBackendsDropped"
+	CGScaleDownDeferredDegraded     EventReason = "CGScaleDownDeferredDegraded"
+	CGCutoverStarted                EventReason = "CGCutoverStarted"
+	CGCutoverFinished               EventReason = "CGCutoverFinished"
+	CGConnectionDraining            EventReason = "CGConnectionDraining"
+	CGExternalMetricFailed          EventReason = "CGExternalMetricFailed"
+	CGNoBackendsRegistered          EventReason = "CGNoBackendsRegistered"
+	CGWaitingStorageVault           EventReason = "CGWaitingStorageVault"
+	CGEvictionDecommissionStarted   EventReason = "CGEvictionDecommissionStarted"
+	CGEvictionDecommissionFinished  EventReason = "CGEvictionDecommissionFinished"
+	CGMemoryLimitApplyFailed        EventReason = "CGMemoryLimitApplyFailed"
+	CGPodsCrashLooping              EventReason = "CGPodsCrashLooping"
+	CGLabelDrifted                  EventReason = "CGLabelDrifted"
+	CGBackendsReregistering         EventReason = "CGBackendsReregistering"
+	CGBackendsReregistered          EventReason = "CGBackendsReregistered"
+	CGReplicaRangeInvalid           EventReason = "CGReplicaRangeInvalid"
+	CGReplicasReduced               EventReason = "CGReplicasReduced"
+	CGReplicasRestored              EventReason = "CGReplicasRestored"
+	CGScaleNotifyFailed             EventReason = "CGScaleNotifyFailed"
+	CGAwaitingStorage               EventReason = "CGAwaitingStorage"
+	CGBackendTagsInvalid            EventReason = "CGBackendTagsInvalid"
+	CGBackendTagsCorrected          EventReason = "CGBackendTagsCorrected"
+	CGScaleDownWarmingCache         EventReason = "CGScaleDownWarmingCache"
+	CGScaleDownPolicyApplied        EventReason = "CGScaleDownPolicyApplied"
+	CGBackendsUnparsed              EventReason = "CGBackendsUnparsed"
+	CGInitSQLFailed                 EventReason = "CGInitSQLFailed"
+	CGInitSQLExecuted               EventReason = "CGInitSQLExecuted"
+	CGGeneratedResources            EventReason = "CGGeneratedResources"
+	CGReplicasBoundsInvalid         EventReason = "CGReplicasBoundsInvalid"
+	CGReplicasOutOfBounds           EventReason = "CGReplicasOutOfBounds"
+	CGStorageTiersInvalid           EventReason = "CGStorageTiersInvalid"
+	CGCordoned                      EventReason = "CGCordoned"
+	CGUncordoned                    EventReason = "CGUncordoned"
+	CGBackendsUnreachable           EventReason = "CGBackendsUnreachable"
+	CGProfileInvalid                EventReason = "CGProfileInvalid"
+	CGProfileApplied                EventReason = "CGProfileApplied"
+	CGStuck                         EventReason = "CGStuck"
+	CGMassCrashLooping              EventReason = "CGMassCrashLooping"
+	CGDegradedHoldReleased          EventReason = "CGDegradedHoldReleased"
+	CGCapacityReservationFailed     EventReason = "CGCapacityReservationFailed"
+	CGVersionSkewExceeded           EventReason = "CGVersionSkewExceeded"
+	CGScaleInLimited                EventReason = "CGScaleInLimited"
+	CGDecommissionTimeout           EventReason = "CGDecommissionTimeout"
+	CGScaleDownSucceeded            EventReason = "CGScaleDownSucceeded"
+	CGSuspendFailed                 EventReason = "CGSuspendFailed"
+	CGResumeFailed                  EventReason = "CGResumeFailed"
+	CGResourceUnschedulable         EventReason = "CGResourceUnschedulable"
+	CGNameRegexInvalid              EventReason = "CGNameRegexInvalid"
+	CGScaled                        EventReason = "CGScaled"
+	CGSuspended                     EventReason = "CGSuspended"
+	CGResumed                       EventReason = "CGResumed"
 	ConfigMapPathRepeated           EventReason = "ConfigMapPathRepeated"
 	SecretPathRepeated              EventReason = "SecretPathRepeated"
 	SecretNotExist                  EventReason = "SecretNotExist"
+	ServiceAccountNotExist          EventReason = "ServiceAccountNotExist"
 	CheckSharePVC                   EventReason = "CheckSharePVC"
 	WaitMetaServiceAvailable        EventReason = "WaitMetaServiceAvailable"
 	WaitFEAvailable                 EventReason = "WaitFEAvailable"
+	FEServiceNotFound               EventReason = "FEServiceNotFound"
+	WaitFEMasterElected             EventReason = "WaitFEMasterElected"
+	FEMetadataUnhealthy             EventReason = "FEMetadataUnhealthy"
+	FEMetadataRecovered             EventReason = "FEMetadataRecovered"
+	OperationUserNoNodePriv         EventReason = "OperationUserNoNodePriv"
+	FEMasterUnreachable             EventReason = "FEMasterUnreachable"
+	FEEndpointsNotReady             EventReason = "FEEndpointsNotReady"
 	ServiceApplyedFailed            EventReason = "ServiceApplyedFailed"
 	MSServiceDeletedFailed          EventReason = "MSServiceDeletedFailed"
 	MSStatefulsetDeleteFailed       EventReason = "MSStatefulsetDeleteFailed"
 	FDBAddressNotConfiged           EventReason = "FDBAddressNotConfiged"
 	RestartTimeInvalid              EventReason = "RestartTimeInvalid"
 	ConfigMapGetFailed              EventReason = "ConfigMapGetFailed"
+	SQLAudit                        EventReason = "SQLAudit"
 )
 
 type Event struct {
@@ -21,6 +21,7 @@ import (
 	"context"
 	v1 "github.com/apache/doris-operator/api/doris/v1"
 	"github.com/apache/doris-operator/pkg/common/utils/k8s"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
 	"github.com/apache/doris-operator/pkg/common/utils/resource"
 	"github.com/apache/doris-operator/pkg/controller/sub_controller"
 	appv1 "k8s.io/api/apps/v1"
@@ -60,7 +61,40 @@ func (fc *Controller) UpdateComponentStatus(cluster *v1.DorisCluster) error {
 	newCmHash := fc.BuildCoreConfigmapStatusHash(context.Background(), cluster, v1.Component_FE)
 	cluster.Status.FEStatus.CoreConfigMapHashValue = newCmHash
 
-	return fc.ClassifyPodsByStatus(cluster.Namespace, cluster.Status.FEStatus, v1.GenerateStatefulSetSelector(cluster, v1.Component_FE), *cluster.Spec.FeSpec.Replicas, v1.Component_FE)
+	if err := fc.ClassifyPodsByStatus(cluster.Namespace, cluster.Status.FEStatus, v1.GenerateStatefulSetSelector(cluster, v1.Component_FE), *cluster.Spec.FeSpec.Replicas, v1.Component_FE); err != nil {
+		return err
+	}
+
+	fc.refreshFEMaster(context.Background(), cluster)
+	return nil
+}
+
+// refreshFEMaster record the fe master node that the admin sql executed on in status, the master is cleared when fe not available or not discovered.
+func (fc *Controller) refreshFEMaster(ctx context.Context, cluster *v1.DorisCluster) {
+	cluster.Status.FEMaster = nil
+	if cluster.Status.FEStatus.ComponentCondition.Phase != v1.Available {
+		return
+	}
+
+	masterDBClient, _, err := newMasterSqlClient(ctx, fc.K8sclient, cluster)
+	if err != nil {
+		fc.recordQueryPortNotExposed(cluster, err)
+		klog.Errorf("fe refreshFEMaster namespace %s name %s connect to fe master failed, err=%s", cluster.Namespace, cluster.Name, err.Error())
+		return
+	}
+	defer masterDBClient.Close()
+	if cluster.Status.FEMaster, err = feMasterNode(masterDBClient); err != nil {
+		klog.Errorf("fe refreshFEMaster namespace %s name %s discover fe master failed, err=%s", cluster.Namespace, cluster.Name, err.Error())
+	}
+}
+
+// feMasterNode return the master in `show frontends`, nil when the master not elected.
+func feMasterNode(db *mysql.DB) (*v1.FEMasterNode, error) {
+	master, _, err := db.GetFollowers()
+	if err != nil || master == nil {
+		return nil, err
+	}
+	return &v1.FEMasterNode{Host: master.Host, Role: master.Role}, nil
 }
 
 // New construct a FeController.
@@ -88,6 +122,8 @@ func (fc *Controller) Sync(ctx context.Context, cluster *v1.DorisCluster) error
 		oldStatus = *(cluster.Status.FEStatus.DeepCopy())
 	}
 	fc.InitStatus(cluster, v1.Component_FE)
+	// the promotion of follower continues in the next reconcile when this reconcile not reached promoting.
+	cluster.Status.FEStatus.FollowerPromotion = oldStatus.FollowerPromotion
 
 	if cluster.Spec.EnableRestartWhenConfigChange {
 		fc.CompareConfigmapAndTriggerRestart(cluster, oldStatus, v1.Component_FE)
@@ -125,11 +161,15 @@ func (fc *Controller) Sync(ctx context.Context, cluster *v1.DorisCluster) error
 		return nil
 	}
 
-	if err = fc.prepareStatefulsetApply(ctx, cluster, oldStatus); err != nil {
+	replicas, err := fc.prepareStatefulsetApply(ctx, cluster, oldStatus)
+	if err != nil {
 		return err
 	}
 
 	st := fc.buildFEStatefulSet(cluster, config)
+	if replicas != nil {
+		st.Spec.Replicas = replicas
+	}
 	if err = k8s.ApplyStatefulSet(ctx, fc.K8sclient, &st, func(new *appv1.StatefulSet, old *appv1.StatefulSet) bool {
 		fc.RestrictConditionsEqual(new, old)
 		return resource.StatefulSetDeepEqual(new, old, false)
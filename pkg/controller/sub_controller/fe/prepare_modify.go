@@ -19,26 +19,37 @@ package fe
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	v1 "github.com/apache/doris-operator/api/doris/v1"
 	"github.com/apache/doris-operator/pkg/common/utils/k8s"
 	"github.com/apache/doris-operator/pkg/common/utils/mysql"
 	"github.com/apache/doris-operator/pkg/common/utils/resource"
 	sc "github.com/apache/doris-operator/pkg/controller/sub_controller"
 	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// the time that the promoted pod waited to register as alive follower, the promotion failed when timeout.
+const followerPromotionTimeout = 10 * time.Minute
+
 // prepareStatefulsetApply means Pre-operation and status control on the client side
-func (fc *Controller) prepareStatefulsetApply(ctx context.Context, cluster *v1.DorisCluster, oldStatus v1.ComponentStatus) error {
+// the returned replicas not nil overrides the replicas of statefulset, used by scaling in observers in batches.
+func (fc *Controller) prepareStatefulsetApply(ctx context.Context, cluster *v1.DorisCluster, oldStatus v1.ComponentStatus) (*int32, error) {
 	var oldSt appv1.StatefulSet
 	err := fc.K8sclient.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: v1.GenerateComponentStatefulSetName(cluster, v1.Component_FE)}, &oldSt)
 	if err != nil {
 		klog.Infof("fe controller controlClusterPhaseAndPreOperation get fe StatefulSet failed, err: %s", err.Error())
-		return nil
+		return nil, nil
 	}
 	if cluster.Spec.FeSpec.Replicas == nil {
 		cluster.Spec.FeSpec.Replicas = resource.GetInt32Pointer(0)
@@ -50,11 +61,40 @@ func (fc *Controller) prepareStatefulsetApply(ctx context.Context, cluster *v1.D
 	wroa := *(cluster.Spec.FeSpec.Replicas) - *(oldSt.Spec.Replicas)
 	// fe scale
 	if wroa < 0 {
-		if err := fc.dropObserverBySqlClient(ctx, fc.K8sclient, cluster); err != nil {
+		replicas := observerScaleInReplicas(cluster, *oldSt.Spec.Replicas)
+		held, err := fc.dropObserverBySqlClient(ctx, fc.K8sclient, cluster, replicas)
+		if err != nil {
 			klog.Errorf("ScaleDownObserver failed, err:%s ", err.Error())
-			return err
+			return nil, err
 		}
-		return nil
+		if held {
+			return oldSt.Spec.Replicas, nil
+		}
+		if replicas != *cluster.Spec.FeSpec.Replicas {
+			return &replicas, nil
+		}
+		return nil, nil
+	}
+
+	// fe scale up observers, confirm the observers registered in fe cluster until all registered.
+	if wroa > 0 || len(oldStatus.UnregisteredObservers) != 0 {
+		unregistered, err := fc.registerObserversBySqlClient(ctx, fc.K8sclient, cluster)
+		if err != nil {
+			klog.Errorf("fe controller registerObserversBySqlClient namespace=%s name=%s failed, err:%s", cluster.Namespace, cluster.Name, err.Error())
+			unregistered = observerPodNames(cluster)
+		}
+		cluster.Status.FEStatus.UnregisteredObservers = unregistered
+	}
+
+	// the electionNumber increased, promote the observers to followers, confirm the followers registered and alive until all promoted.
+	if statefulsetElectionNumber(&oldSt) < cluster.GetElectionNumber() || len(oldStatus.UnpromotedFollowers) != 0 || oldStatus.FollowerPromotion != nil {
+		unpromoted, promotion, err := fc.promoteFollowersBySqlClient(ctx, fc.K8sclient, cluster, &oldSt, oldStatus.FollowerPromotion)
+		if err != nil {
+			klog.Errorf("fe controller promoteFollowersBySqlClient namespace=%s name=%s failed, err:%s", cluster.Namespace, cluster.Name, err.Error())
+			return nil, err
+		}
+		cluster.Status.FEStatus.UnpromotedFollowers = unpromoted
+		cluster.Status.FEStatus.FollowerPromotion = promotion
 	}
 
 	// fe rolling restart
@@ -67,7 +107,32 @@ func (fc *Controller) prepareStatefulsetApply(ctx context.Context, cluster *v1.D
 
 	//TODO check upgrade
 
-	return nil
+	return nil, nil
+}
+
+// observerScaleInReplicas return the replicas of statefulset in this reconcile when scale in observers, at most observerScaleInBatchSize observers removed from old replicas.
+func observerScaleInReplicas(cluster *v1.DorisCluster, oldReplicas int32) int32 {
+	replicas := *cluster.Spec.FeSpec.Replicas
+	batch := cluster.Spec.FeSpec.ObserverScaleInBatchSize
+	if batch > 0 && oldReplicas-batch > replicas {
+		replicas = oldReplicas - batch
+	}
+	return replicas
+}
+
+// unhealthyFrontends return the host of frontends not alive, the frontends will be dropped are excluded, they should not block themselves dropped.
+func unhealthyFrontends(frontends []*mysql.Frontend, dropping []*mysql.Frontend) []string {
+	drops := map[string]bool{}
+	for _, fe := range dropping {
+		drops[fe.Host] = true
+	}
+	var hosts []string
+	for _, fe := range frontends {
+		if !fe.Alive && !drops[fe.Host] {
+			hosts = append(hosts, fe.Host)
+		}
+	}
+	return hosts
 }
 
 func (fc *Controller) safeScaleDown(cluster *v1.DorisCluster, ost *appv1.StatefulSet) {
@@ -98,31 +163,13 @@ func (fc *Controller) safeScaleDown(cluster *v1.DorisCluster, ost *appv1.Statefu
 }
 
 // dropObserverBySqlClient handles doris'SQL(drop frontend) through the MySQL client when dealing with scale in observer
-// targetDCR is new dcr
-func (fc *Controller) dropObserverBySqlClient(ctx context.Context, k8sclient client.Client, targetDCR *v1.DorisCluster) error {
-	// get adminuserName and pwd
-	secret, _ := k8s.GetSecret(ctx, k8sclient, targetDCR.Namespace, targetDCR.Spec.AuthSecret)
-	adminUserName, password := v1.GetClusterSecret(targetDCR, secret)
-	// get host and port
-	serviceName := v1.GenerateExternalServiceName(targetDCR, v1.Component_FE)
-	// When the operator and dcr are deployed in different namespace, it will be inaccessible, so need to add the dcr svc namespace
-	host := serviceName + "." + targetDCR.Namespace
-	maps, _ := k8s.GetConfig(ctx, k8sclient, &targetDCR.Spec.FeSpec.ConfigMapInfo, targetDCR.Namespace, v1.Component_FE)
-	queryPort := resource.GetPort(maps, resource.QUERY_PORT)
-
-	// connect to doris sql to get master node
-	// It may not be the master, or even the node that needs to be deleted, causing the deletion SQL to fail.
-	dbConf := mysql.DBConfig{
-		User:     adminUserName,
-		Password: password,
-		Host:     host,
-		Port:     strconv.FormatInt(int64(queryPort), 10),
-		Database: "mysql",
-	}
-	masterDBClient, err := mysql.NewDorisMasterSqlDB(dbConf, nil, nil)
+// targetDCR is new dcr, replicas is the fe replicas after dropped in this reconcile.
+// return held true when scaling in batches and some frontends not alive, the observers not dropped and the statefulset should keep the replicas.
+func (fc *Controller) dropObserverBySqlClient(ctx context.Context, k8sclient client.Client, targetDCR *v1.DorisCluster, replicas int32) (bool, error) {
+	masterDBClient, maps, err := newMasterSqlClient(ctx, k8sclient, targetDCR)
 	if err != nil {
-		klog.Errorf("NewDorisMasterSqlDB failed, get fe node connection err:%s", err.Error())
-		return err
+		fc.recordQueryPortNotExposed(targetDCR, err)
+		return false, err
 	}
 	defer masterDBClient.Close()
 
@@ -130,17 +177,17 @@ func (fc *Controller) dropObserverBySqlClient(ctx context.Context, k8sclient cli
 	allObserves, err := masterDBClient.GetObservers()
 	if err != nil {
 		klog.Errorf("DropObserverFromSqlClient failed, GetObservers err:%s", err.Error())
-		return err
+		return false, err
 	}
 
 	// make sure needRemovedAmount, this may involve retrying tasks and scaling down followers.
 	electionNumber := targetDCR.GetElectionNumber()
 
 	// means: needRemovedAmount = allobservers - (replicas - election)
-	needRemovedAmount := int32(len(allObserves)) - *(targetDCR.Spec.FeSpec.Replicas) + electionNumber
+	needRemovedAmount := int32(len(allObserves)) - replicas + electionNumber
 	if needRemovedAmount <= 0 {
-		klog.Errorf("DropObserverFromSqlClient failed, Observers number(%d) is not larger than scale number(%d) ", len(allObserves), *(targetDCR.Spec.FeSpec.Replicas)-electionNumber)
-		return nil
+		klog.Errorf("DropObserverFromSqlClient failed, Observers number(%d) is not larger than scale number(%d) ", len(allObserves), replicas-electionNumber)
+		return false, nil
 	}
 
 	// get scale Observes
@@ -151,14 +198,14 @@ func (fc *Controller) dropObserverBySqlClient(ctx context.Context, k8sclient cli
 		frontendMap, err = mysql.BuildSeqNumberToFrontendMap(allObserves, nil, podTemplateName)
 		if err != nil {
 			klog.Errorf("DropObserverFromSqlClient failed, buildSeqNumberToFrontend err:%s", err.Error())
-			return nil
+			return false, nil
 		}
 	} else { // use ip
 		podMap := make(map[string]string) // key is pod ip, value is pod name
 		pods, err := k8s.GetPods(ctx, k8sclient, targetDCR.Namespace, v1.GetPodLabels(targetDCR, v1.Component_FE))
 		if err != nil {
 			klog.Errorf("DropObserverFromSqlClient failed, GetPods err:%s", err)
-			return nil
+			return false, nil
 		}
 		for _, item := range pods.Items {
 			if strings.HasPrefix(item.GetName(), podTemplateName) {
@@ -168,11 +215,514 @@ func (fc *Controller) dropObserverBySqlClient(ctx context.Context, k8sclient cli
 		frontendMap, err = mysql.BuildSeqNumberToFrontendMap(allObserves, podMap, podTemplateName)
 		if err != nil {
 			klog.Errorf("DropObserverFromSqlClient failed, buildSeqNumberToFrontend err:%s", err.Error())
-			return nil
+			return false, nil
 		}
 	}
 	observes := mysql.FindNeedDeletedObservers(frontendMap, needRemovedAmount)
+
+	// scale in batches, drop the next batch after the cluster healthy.
+	if targetDCR.Spec.FeSpec.ObserverScaleInBatchSize > 0 {
+		frontends, err := masterDBClient.ShowFrontends()
+		if err != nil {
+			klog.Errorf("DropObserverFromSqlClient namespace %s name %s show frontends failed, err:%s", targetDCR.Namespace, targetDCR.Name, err.Error())
+			return true, nil
+		}
+		if hosts := unhealthyFrontends(frontends, observes); len(hosts) != 0 {
+			klog.Infof("DropObserverFromSqlClient namespace %s name %s frontends %s not alive, wait for the next batch of scaling in observers.", targetDCR.Namespace, targetDCR.Name, strings.Join(hosts, ","))
+			return true, nil
+		}
+	}
 	// drop node and return
-	return masterDBClient.DropObserver(observes)
+	if len(observes) == 0 {
+		return false, nil
+	}
+	err = masterDBClient.DropObserver(observes)
+	sc.AuditSQL(fc.K8srecorder, targetDCR, masterDBClient, sc.SQLAuditRecord{Operation: sc.AuditDropObserver, Nodes: sc.FrontendNodes(observes), SQL: mysql.DropObserverSQL(observes)}, err)
+	return false, err
 
 }
+
+// newMasterSqlClient connect to the master of fe cluster by the admin user, return the client and the fe config.
+func newMasterSqlClient(ctx context.Context, k8sclient client.Client, targetDCR *v1.DorisCluster) (*mysql.DB, map[string]interface{}, error) {
+	// get adminuserName and pwd
+	secret, _ := k8s.GetSecret(ctx, k8sclient, targetDCR.Namespace, targetDCR.Spec.AuthSecret)
+	adminUserName, password := v1.GetClusterSecret(targetDCR, secret)
+	// get host and port
+	serviceName := v1.GenerateExternalServiceName(targetDCR, v1.Component_FE)
+	// When the operator and dcr are deployed in different namespace, it will be inaccessible, so need to add the dcr svc namespace
+	host := serviceName + "." + targetDCR.Namespace
+	maps, _ := k8s.GetConfig(ctx, k8sclient, &targetDCR.Spec.FeSpec.ConfigMapInfo, targetDCR.Namespace, v1.Component_FE)
+	queryPort := resource.GetPort(maps, resource.QUERY_PORT)
+	// the query_port in config not exposed by service is refused, tell it precisely than the connection error.
+	if err := k8s.CheckServicePortExposed(ctx, k8sclient, targetDCR.Namespace, serviceName, queryPort); err != nil {
+		return nil, nil, fmt.Errorf("the query_port %d of fe config not exposed by fe service, please check the query_port in fe config and the service, err=%w", queryPort, err)
+	}
+
+	// connect to doris sql to get master node
+	// It may not be the master, or even the node that needs to be deleted, causing the deletion SQL to fail.
+	// the fe pods are tried in rotation when the service routes to a fe not reachable the master transiently.
+	dbConf := mysql.DBConfig{
+		User:          adminUserName,
+		Password:      password,
+		Host:          host,
+		Port:          strconv.FormatInt(int64(queryPort), 10),
+		Database:      "mysql",
+		FallbackHosts: fePodAddresses(targetDCR),
+	}
+	masterDBClient, err := mysql.NewDorisMasterSqlDBWithRetry(ctx, dbConf, len(dbConf.FallbackHosts)+1, mysql.DefaultMasterConnectBackoff, nil, nil)
+	if err != nil {
+		klog.Errorf("NewDorisMasterSqlDB failed, get fe node connection err:%s", err.Error())
+		return nil, nil, err
+	}
+	return masterDBClient, maps, nil
+}
+
+// fePodAddresses return the addresses of fe pods resolved by the internal service.
+func fePodAddresses(targetDCR *v1.DorisCluster) []string {
+	var addrs []string
+	podTemplateName := resource.GeneratePodTemplateName(targetDCR, v1.Component_FE)
+	internalService := v1.GenerateInternalCommunicateServiceName(targetDCR, v1.Component_FE)
+	for i := int32(0); targetDCR.Spec.FeSpec.Replicas != nil && i < *(targetDCR.Spec.FeSpec.Replicas); i++ {
+		addrs = append(addrs, podTemplateName+"-"+strconv.Itoa(int(i))+"."+internalService+"."+targetDCR.Namespace)
+	}
+	return addrs
+}
+
+// recordQueryPortNotExposed emit warning event when connecting fe failed by the query port not exposed by service.
+func (fc *Controller) recordQueryPortNotExposed(cluster *v1.DorisCluster, err error) {
+	if errors.Is(err, k8s.ErrServicePortNotExposed) {
+		fc.K8srecorder.Event(cluster, string(sc.EventWarning), sc.FEQueryPortNotExposed, err.Error())
+	}
+}
+
+// registerObserversBySqlClient make sure the observer pods(index not less than electionNumber) registered as observer by `show frontends`,
+// the running pods not registered are added by `ALTER SYSTEM ADD OBSERVER`, return the pods that not confirmed registered.
+func (fc *Controller) registerObserversBySqlClient(ctx context.Context, k8sclient client.Client, targetDCR *v1.DorisCluster) ([]string, error) {
+	if *(targetDCR.Spec.FeSpec.Replicas) <= targetDCR.GetElectionNumber() {
+		return nil, nil
+	}
+
+	masterDBClient, maps, err := newMasterSqlClient(ctx, k8sclient, targetDCR)
+	if err != nil {
+		fc.recordQueryPortNotExposed(targetDCR, err)
+		return nil, err
+	}
+	defer masterDBClient.Close()
+
+	frontends, err := masterDBClient.ShowFrontends()
+	if err != nil {
+		klog.Errorf("registerObserversBySqlClient failed, ShowFrontends err:%s", err.Error())
+		return nil, err
+	}
+	pods, err := k8s.GetPods(ctx, k8sclient, targetDCR.Namespace, v1.GetPodLabels(targetDCR, v1.Component_FE))
+	if err != nil {
+		klog.Errorf("registerObserversBySqlClient failed, GetPods err:%s", err.Error())
+		return nil, err
+	}
+
+	useFqdn := resource.GetStartMode(maps) == resource.START_MODEL_FQDN
+	editLogPort := int(resource.GetPort(maps, resource.EDIT_LOG_PORT))
+	addObservers, unregistered, mismatched := classifyObservers(targetDCR, frontends, pods.Items, useFqdn, editLogPort)
+	for _, name := range mismatched {
+		fc.K8srecorder.Event(targetDCR, string(sc.EventWarning), sc.ObserverRegisterFailed, fmt.Sprintf("fe pod %s registered as not observer role, please check the fe cluster.", name))
+	}
+	if len(addObservers) == 0 {
+		return unregistered, nil
+	}
+
+	if err := masterDBClient.AddObserver(addObservers); err != nil {
+		klog.Errorf("registerObserversBySqlClient failed, AddObserver err:%s", err.Error())
+		fc.K8srecorder.Event(targetDCR, string(sc.EventWarning), sc.ObserverRegisterFailed, "add observer failed, err="+err.Error())
+		return unregistered, nil
+	}
+	var hosts []string
+	for _, ob := range addObservers {
+		hosts = append(hosts, ob.Host)
+	}
+	fc.K8srecorder.Event(targetDCR, string(sc.EventNormal), sc.ObserverAdded, "add observers "+strings.Join(hosts, ","))
+	// the added observers are confirmed in next reconcile.
+	return unregistered, nil
+}
+
+// classifyObservers find the observer pods that not registered in frontends, return the frontends should be added as observer,
+// the pods not confirmed registered, and the pods registered as other role.
+func classifyObservers(targetDCR *v1.DorisCluster, frontends []*mysql.Frontend, pods []corev1.Pod, useFqdn bool, editLogPort int) ([]*mysql.Frontend, []string, []string) {
+	podTemplateName := resource.GeneratePodTemplateName(targetDCR, v1.Component_FE)
+	podMap := map[string]*corev1.Pod{}
+	for i := range pods {
+		podMap[pods[i].Name] = &pods[i]
+	}
+	// the fqdn of pod like: doriscluster-sample-fe-3.doriscluster-sample-fe-internal.default.svc.cluster.local, use the domain of registered frontends.
+	domainSuffix := "." + v1.GenerateInternalCommunicateServiceName(targetDCR, v1.Component_FE) + "." + targetDCR.Namespace + ".svc.cluster.local"
+	for _, fe := range frontends {
+		if strings.HasPrefix(fe.Host, podTemplateName) && strings.Contains(fe.Host, ".svc.") {
+			domainSuffix = fe.Host[strings.Index(fe.Host, "."):]
+			break
+		}
+	}
+
+	var addObservers []*mysql.Frontend
+	var unregistered, mismatched []string
+	for i := targetDCR.GetElectionNumber(); i < *(targetDCR.Spec.FeSpec.Replicas); i++ {
+		podName := podTemplateName + "-" + strconv.Itoa(int(i))
+		pod := podMap[podName]
+		var fe *mysql.Frontend
+		for _, f := range frontends {
+			if (useFqdn && strings.HasPrefix(f.Host, podName+".")) || (!useFqdn && pod != nil && pod.Status.PodIP != "" && f.Host == pod.Status.PodIP) {
+				fe = f
+				break
+			}
+		}
+
+		switch {
+		case fe != nil && fe.Role == mysql.FE_OBSERVE_ROLE:
+			continue
+		case fe != nil:
+			mismatched = append(mismatched, podName)
+		case pod != nil && pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "":
+			host := pod.Status.PodIP
+			if useFqdn {
+				host = podName + domainSuffix
+			}
+			addObservers = append(addObservers, &mysql.Frontend{Host: host, EditLogPort: editLogPort})
+		}
+		unregistered = append(unregistered, podName)
+	}
+	return addObservers, unregistered, mismatched
+}
+
+// observerPodNames return the names of observer pods, the index of observer not less than electionNumber.
+func observerPodNames(targetDCR *v1.DorisCluster) []string {
+	var names []string
+	podTemplateName := resource.GeneratePodTemplateName(targetDCR, v1.Component_FE)
+	for i := targetDCR.GetElectionNumber(); i < *(targetDCR.Spec.FeSpec.Replicas); i++ {
+		names = append(names, podTemplateName+"-"+strconv.Itoa(int(i)))
+	}
+	return names
+}
+
+// statefulsetElectionNumber return the electionNumber that the fe statefulset applied by the env of fe container, return DefaultFeElectionNumber when not found.
+func statefulsetElectionNumber(st *appv1.StatefulSet) int32 {
+	for _, c := range st.Spec.Template.Spec.Containers {
+		for _, env := range c.Env {
+			if env.Name != resource.ENV_FE_ELECT_NUMBER {
+				continue
+			}
+			if n, err := strconv.ParseInt(env.Value, 10, 32); err == nil {
+				return int32(n)
+			}
+		}
+	}
+	return v1.DefaultFeElectionNumber
+}
+
+// promoteFollowersBySqlClient make sure the pods(index less than electionNumber) registered as follower by `show frontends`, return the pods that not confirmed registered as alive follower and the promotion in progress.
+// the role of a running fe can not be changed by sql, the observer is promoted by dropping it, restarting the pod and clearing the meta of it, the start script registers the pod with empty meta as follower by `--helper`.
+func (fc *Controller) promoteFollowersBySqlClient(ctx context.Context, k8sclient client.Client, targetDCR *v1.DorisCluster, oldSt *appv1.StatefulSet, promotion *v1.FollowerPromotion) ([]string, *v1.FollowerPromotion, error) {
+	masterDBClient, maps, err := newMasterSqlClient(ctx, k8sclient, targetDCR)
+	if err != nil {
+		fc.recordQueryPortNotExposed(targetDCR, err)
+		return nil, promotion, err
+	}
+	defer masterDBClient.Close()
+
+	frontends, err := masterDBClient.ShowFrontends()
+	if err != nil {
+		klog.Errorf("promoteFollowersBySqlClient failed, ShowFrontends err:%s", err.Error())
+		return nil, promotion, err
+	}
+	pods, err := k8s.GetPods(ctx, k8sclient, targetDCR.Namespace, v1.GetPodLabels(targetDCR, v1.Component_FE))
+	if err != nil {
+		klog.Errorf("promoteFollowersBySqlClient failed, GetPods err:%s", err.Error())
+		return nil, promotion, err
+	}
+
+	useFqdn := resource.GetStartMode(maps) == resource.START_MODEL_FQDN
+	return fc.promoteFollower(ctx, targetDCR, masterDBClient, oldSt, feMetaDir(maps), frontends, pods.Items, useFqdn, promotion)
+}
+
+// promoteFollower promote one observer at a time, the next one is promoted after the last promoted registered as alive follower.
+// the promotion is recorded in status before any change, every reconcile executes the step of the recorded phase and moves it to the next phase.
+func (fc *Controller) promoteFollower(ctx context.Context, targetDCR *v1.DorisCluster, db *mysql.DB, oldSt *appv1.StatefulSet, metaDir string, frontends []*mysql.Frontend, pods []corev1.Pod, useFqdn bool, promotion *v1.FollowerPromotion) ([]string, *v1.FollowerPromotion, error) {
+	promotes, unpromoted := classifyFollowers(targetDCR, frontends, pods, useFqdn)
+	if promotion != nil {
+		return unpromoted, fc.continuePromotion(ctx, targetDCR, db, oldSt, metaDir, frontends, pods, useFqdn, promotion), nil
+	}
+	if len(promotes) == 0 {
+		return unpromoted, nil, nil
+	}
+
+	// the restarted pod registers by the electionNumber of statefulset, wait the statefulset updated and rolled out.
+	if statefulsetElectionNumber(oldSt) < targetDCR.GetElectionNumber() || oldSt.Status.UpdatedReplicas != *oldSt.Spec.Replicas || oldSt.Status.ReadyReplicas != *oldSt.Spec.Replicas {
+		return unpromoted, nil, nil
+	}
+	// the pods registering as follower are waited, one follower added at a time.
+	if len(unpromoted) != len(promotes) {
+		return unpromoted, nil, nil
+	}
+	// the new follower votes before alive, only promote when the alive followers still a majority with it.
+	followers, alive := countFollowers(frontends)
+	if alive < (followers+1)/2+1 {
+		msg := fmt.Sprintf("%d of %d followers alive, not a majority after a follower added, the observer %s not promoted.", alive, followers, promotes[0].Host)
+		klog.Errorf("promoteFollowersBySqlClient namespace %s name %s %s", targetDCR.Namespace, targetDCR.Name, msg)
+		fc.K8srecorder.Event(targetDCR, string(sc.EventWarning), sc.ObserverPromoteFailed, msg)
+		return unpromoted, nil, nil
+	}
+
+	pod := findFrontendPod(pods, promotes[0], useFqdn)
+	if pod == nil {
+		return unpromoted, nil, nil
+	}
+	// nothing changed before the promotion recorded in status, the observer dropped in the next reconcile.
+	return unpromoted, &v1.FollowerPromotion{PodName: pod.Name, PodUID: string(pod.UID), Phase: v1.PromotionRestartingPod, LastTransitionTime: metav1.Now()}, nil
+}
+
+// continuePromotion execute the step of the phase recorded in status, return the promotion after the step, nil when the pod registered as alive follower.
+// the errors are recorded in the message of promotion and the step is retried in the next reconcile.
+func (fc *Controller) continuePromotion(ctx context.Context, targetDCR *v1.DorisCluster, db *mysql.DB, oldSt *appv1.StatefulSet, metaDir string, frontends []*mysql.Frontend, pods []corev1.Pod, useFqdn bool, promotion *v1.FollowerPromotion) *v1.FollowerPromotion {
+	p := promotion.DeepCopy()
+	var pod *corev1.Pod
+	for i := range pods {
+		if pods[i].Name == p.PodName {
+			pod = &pods[i]
+		}
+	}
+	var fe *mysql.Frontend
+	if pod != nil {
+		fe = findPodFrontend(frontends, pod, useFqdn)
+	}
+	if fe != nil && fe.Role == mysql.FE_FOLLOWER_ROLE && fe.Alive && p.Phase != v1.PromotionRestartingPod {
+		fc.K8srecorder.Event(targetDCR, string(sc.EventNormal), sc.ObserverPromoted, fmt.Sprintf("pod %s registered as alive follower.", p.PodName))
+		return nil
+	}
+	if !slices.Contains(followerPodNames(targetDCR), p.PodName) {
+		klog.Infof("promoteFollowersBySqlClient namespace %s name %s the pod %s not a follower pod, the promotion canceled.", targetDCR.Namespace, targetDCR.Name, p.PodName)
+		return nil
+	}
+
+	switch p.Phase {
+	case v1.PromotionRestartingPod:
+		fc.restartPromotingPod(ctx, targetDCR, db, oldSt, metaDir, pod, fe, p)
+	case v1.PromotionClearingMeta:
+		fc.clearPromotingMeta(ctx, targetDCR, oldSt, metaDir, pod, p)
+	case v1.PromotionRegistering:
+		fc.waitPromotingRegistered(ctx, targetDCR, db, oldSt, metaDir, pod, fe, p)
+	}
+	return p
+}
+
+// restartPromotingPod drop the observer of pod when it still registered and delete the pod, the meta pvc is cleared after the pod confirmed deleted.
+func (fc *Controller) restartPromotingPod(ctx context.Context, targetDCR *v1.DorisCluster, db *mysql.DB, oldSt *appv1.StatefulSet, metaDir string, pod *corev1.Pod, fe *mysql.Frontend, p *v1.FollowerPromotion) {
+	if pod != nil && string(pod.UID) == p.PodUID {
+		if fe != nil && fe.Role == mysql.FE_OBSERVE_ROLE {
+			observers := []*mysql.Frontend{fe}
+			err := db.DropObserver(observers)
+			sc.AuditSQL(fc.K8srecorder, targetDCR, db, sc.SQLAuditRecord{Operation: sc.AuditPromoteObserver, Nodes: sc.FrontendNodes(observers), SQL: mysql.DropObserverSQL(observers)}, err)
+			if err != nil {
+				fc.promotionBlocked(targetDCR, p, fmt.Sprintf("drop observer %s for promoting failed, err=%s", fe.Host, err.Error()))
+				return
+			}
+		}
+		if pod.DeletionTimestamp == nil {
+			uid := pod.UID
+			if err := fc.K8sclient.Delete(ctx, pod, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+				fc.promotionBlocked(targetDCR, p, fmt.Sprintf("restart pod %s for promoting failed, err=%s", pod.Name, err.Error()))
+				return
+			}
+			fc.K8srecorder.Event(targetDCR, string(sc.EventNormal), sc.ObserverPromoted, fmt.Sprintf("observer of pod %s dropped, the pod restarting to register as follower.", pod.Name))
+		}
+		p.Message = ""
+		return
+	}
+
+	// the pod of promotion started is deleted.
+	pvcName := metaPVCName(oldSt, metaDir, p.PodName)
+	if pvcName == "" {
+		setPromotionPhase(p, v1.PromotionRegistering)
+		return
+	}
+	var pvc corev1.PersistentVolumeClaim
+	if err := fc.K8sclient.Get(ctx, types.NamespacedName{Namespace: targetDCR.Namespace, Name: pvcName}, &pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			setPromotionPhase(p, v1.PromotionRegistering)
+			return
+		}
+		fc.promotionBlocked(targetDCR, p, fmt.Sprintf("get the meta pvc %s for promoting failed, err=%s", pvcName, err.Error()))
+		return
+	}
+	p.MetaPVCUID = string(pvc.UID)
+	setPromotionPhase(p, v1.PromotionClearingMeta)
+}
+
+// clearPromotingMeta delete the meta pvc recorded in promotion. the pod recreated by statefulset before the pvc deleted holds the pvc with the old meta, it is deleted too.
+func (fc *Controller) clearPromotingMeta(ctx context.Context, targetDCR *v1.DorisCluster, oldSt *appv1.StatefulSet, metaDir string, pod *corev1.Pod, p *v1.FollowerPromotion) {
+	pvcName := metaPVCName(oldSt, metaDir, p.PodName)
+	var pvc corev1.PersistentVolumeClaim
+	if err := fc.K8sclient.Get(ctx, types.NamespacedName{Namespace: targetDCR.Namespace, Name: pvcName}, &pvc); err != nil && !apierrors.IsNotFound(err) {
+		fc.promotionBlocked(targetDCR, p, fmt.Sprintf("get the meta pvc %s for promoting failed, err=%s", pvcName, err.Error()))
+		return
+	} else if apierrors.IsNotFound(err) || string(pvc.UID) != p.MetaPVCUID {
+		setPromotionPhase(p, v1.PromotionRegistering)
+		return
+	}
+
+	if pvc.DeletionTimestamp == nil {
+		uid := pvc.UID
+		if err := fc.K8sclient.Delete(ctx, &pvc, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+			fc.promotionBlocked(targetDCR, p, fmt.Sprintf("delete the meta pvc %s of pod %s for promoting failed, err=%s", pvcName, p.PodName, err.Error()))
+			return
+		}
+	}
+	if pod != nil && pod.DeletionTimestamp == nil {
+		uid := pod.UID
+		if err := fc.K8sclient.Delete(ctx, pod, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+			fc.promotionBlocked(targetDCR, p, fmt.Sprintf("delete pod %s holding the old meta for promoting failed, err=%s", pod.Name, err.Error()))
+			return
+		}
+	}
+	p.Message = ""
+}
+
+// waitPromotingRegistered wait the pod registered as alive follower. the pod created before the meta pvc deleted keeps pending without the pvc, it is deleted for statefulset recreating the pvc.
+// when the pod not registered in followerPromotionTimeout, the follower not alive is dropped to keep the quorum and the promotion failed.
+func (fc *Controller) waitPromotingRegistered(ctx context.Context, targetDCR *v1.DorisCluster, db *mysql.DB, oldSt *appv1.StatefulSet, metaDir string, pod *corev1.Pod, fe *mysql.Frontend, p *v1.FollowerPromotion) {
+	if pvcName := metaPVCName(oldSt, metaDir, p.PodName); pod != nil && pvcName != "" && pod.Status.Phase == corev1.PodPending && pod.DeletionTimestamp == nil {
+		if err := fc.K8sclient.Get(ctx, types.NamespacedName{Namespace: targetDCR.Namespace, Name: pvcName}, &corev1.PersistentVolumeClaim{}); apierrors.IsNotFound(err) {
+			uid := pod.UID
+			if err := fc.K8sclient.Delete(ctx, pod, client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+				fc.promotionBlocked(targetDCR, p, fmt.Sprintf("delete pending pod %s without meta pvc failed, err=%s", pod.Name, err.Error()))
+			}
+			return
+		}
+	}
+
+	if time.Since(p.LastTransitionTime.Time) < followerPromotionTimeout {
+		return
+	}
+	if fe != nil && fe.Role == mysql.FE_FOLLOWER_ROLE && !fe.Alive {
+		followers := []*mysql.Frontend{fe}
+		err := db.DropFollower(followers)
+		sc.AuditSQL(fc.K8srecorder, targetDCR, db, sc.SQLAuditRecord{Operation: sc.AuditPromoteObserver, Nodes: sc.FrontendNodes(followers), SQL: mysql.DropFollowerSQL(followers)}, err)
+		if err != nil {
+			fc.promotionBlocked(targetDCR, p, fmt.Sprintf("drop the follower %s not alive after promoting timeout failed, err=%s", fe.Host, err.Error()))
+			return
+		}
+	}
+	setPromotionPhase(p, v1.PromotionFailed)
+	p.Message = fmt.Sprintf("pod %s not registered as alive follower in %s, the follower not alive dropped, restart the pod to register it again.", p.PodName, followerPromotionTimeout)
+	fc.K8srecorder.Event(targetDCR, string(sc.EventWarning), sc.ObserverPromoteFailed, p.Message)
+}
+
+// promotionBlocked record the reason that the step of promotion failed, the step retried in the next reconcile.
+func (fc *Controller) promotionBlocked(targetDCR *v1.DorisCluster, p *v1.FollowerPromotion, msg string) {
+	klog.Errorf("promoteFollowersBySqlClient namespace %s name %s %s", targetDCR.Namespace, targetDCR.Name, msg)
+	fc.K8srecorder.Event(targetDCR, string(sc.EventWarning), sc.ObserverPromoteFailed, msg)
+	p.Message = msg
+}
+
+func setPromotionPhase(p *v1.FollowerPromotion, phase v1.FollowerPromotionPhase) {
+	p.Phase = phase
+	p.LastTransitionTime = metav1.Now()
+	p.Message = ""
+}
+
+// metaPVCName return the pvc of pod that mounted on the fe meta dir, empty when the meta not persisted by the volumeClaimTemplates.
+func metaPVCName(st *appv1.StatefulSet, metaDir string, podName string) string {
+	for _, c := range st.Spec.Template.Spec.Containers {
+		for _, vm := range c.VolumeMounts {
+			if strings.TrimSuffix(vm.MountPath, "/") != strings.TrimSuffix(metaDir, "/") {
+				continue
+			}
+			for _, vct := range st.Spec.VolumeClaimTemplates {
+				if vct.Name == vm.Name {
+					return vct.Name + "-" + podName
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// feMetaDir return the meta_dir of fe config, the default path when not configured.
+func feMetaDir(maps map[string]interface{}) string {
+	if dir := resource.GetString(maps, "meta_dir"); dir != "" {
+		return dir
+	}
+	return resource.DEFAULT_ROOT_PATH + "/fe/doris-meta"
+}
+
+// findPodFrontend return the frontend registered by the pod, nil when not registered.
+func findPodFrontend(frontends []*mysql.Frontend, pod *corev1.Pod, useFqdn bool) *mysql.Frontend {
+	for _, f := range frontends {
+		if (useFqdn && strings.HasPrefix(f.Host, pod.Name+".")) || (!useFqdn && pod.Status.PodIP != "" && f.Host == pod.Status.PodIP) {
+			return f
+		}
+	}
+	return nil
+}
+
+// findFrontendPod return the pod that registered the frontend.
+func findFrontendPod(pods []corev1.Pod, fe *mysql.Frontend, useFqdn bool) *corev1.Pod {
+	for i := range pods {
+		if findPodFrontend([]*mysql.Frontend{fe}, &pods[i], useFqdn) != nil {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+// classifyFollowers find the follower pods(index less than electionNumber) not registered as alive follower, return the frontends registered as observer should be promoted,
+// and the pods not confirmed as alive follower. the pods not registered are registered by the start script of fe, wait them registered.
+func classifyFollowers(targetDCR *v1.DorisCluster, frontends []*mysql.Frontend, pods []corev1.Pod, useFqdn bool) ([]*mysql.Frontend, []string) {
+	podMap := map[string]*corev1.Pod{}
+	for i := range pods {
+		podMap[pods[i].Name] = &pods[i]
+	}
+
+	var promotes []*mysql.Frontend
+	var unpromoted []string
+	for _, podName := range followerPodNames(targetDCR) {
+		pod := podMap[podName]
+		var fe *mysql.Frontend
+		for _, f := range frontends {
+			if (useFqdn && strings.HasPrefix(f.Host, podName+".")) || (!useFqdn && pod != nil && pod.Status.PodIP != "" && f.Host == pod.Status.PodIP) {
+				fe = f
+				break
+			}
+		}
+
+		switch {
+		case fe != nil && fe.Role == mysql.FE_FOLLOWER_ROLE && fe.Alive:
+			continue
+		case fe != nil && fe.Role == mysql.FE_OBSERVE_ROLE:
+			promotes = append(promotes, fe)
+		}
+		unpromoted = append(unpromoted, podName)
+	}
+	return promotes, unpromoted
+}
+
+// countFollowers return the number of followers(including master) registered and the alive ones.
+func countFollowers(frontends []*mysql.Frontend) (int32, int32) {
+	var followers, alive int32
+	for _, fe := range frontends {
+		if fe.Role != mysql.FE_FOLLOWER_ROLE {
+			continue
+		}
+		followers++
+		if fe.Alive {
+			alive++
+		}
+	}
+	return followers, alive
+}
+
+// followerPodNames return the names of follower pods, the index of follower less than electionNumber and replicas.
+func followerPodNames(targetDCR *v1.DorisCluster) []string {
+	var names []string
+	podTemplateName := resource.GeneratePodTemplateName(targetDCR, v1.Component_FE)
+	for i := int32(0); i < targetDCR.GetElectionNumber() && i < *(targetDCR.Spec.FeSpec.Replicas); i++ {
+		names = append(names, podTemplateName+"-"+strconv.Itoa(int(i)))
+	}
+	return names
+}
@@ -17,12 +17,26 @@
 package fe
 
 import (
+	"context"
+	"strconv"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	dorisv1 "github.com/apache/doris-operator/api/doris/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
 	"github.com/apache/doris-operator/pkg/common/utils/resource"
+	"github.com/apache/doris-operator/pkg/controller/sub_controller"
 	appv1 "k8s.io/api/apps/v1"
+	"github.com/jmoiron/sqlx"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
+	"time"
 )
 
 func Test_safeScaleDown(t *testing.T) {
@@ -104,3 +118,364 @@ func Test_safeScaleDown(t *testing.T) {
 		}
 	}
 }
+
+func Test_classifyObservers(t *testing.T) {
+	dcr := &dorisv1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample"},
+		Spec: dorisv1.DorisClusterSpec{
+			FeSpec: &dorisv1.FeSpec{
+				BaseSpec:       dorisv1.BaseSpec{Replicas: resource.GetInt32Pointer(6)},
+				ElectionNumber: resource.GetInt32Pointer(3),
+			},
+		},
+	}
+	frontends := []*mysql.Frontend{
+		{Host: "doriscluster-sample-fe-0.doriscluster-sample-fe-internal.default.svc.test.local", Role: mysql.FE_FOLLOWER_ROLE},
+		{Host: "doriscluster-sample-fe-3.doriscluster-sample-fe-internal.default.svc.test.local", Role: mysql.FE_OBSERVE_ROLE},
+		{Host: "doriscluster-sample-fe-4.doriscluster-sample-fe-internal.default.svc.test.local", Role: mysql.FE_FOLLOWER_ROLE},
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "doriscluster-sample-fe-3"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "doriscluster-sample-fe-4"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.4"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "doriscluster-sample-fe-5"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.5"}},
+	}
+
+	adds, unregistered, mismatched := classifyObservers(dcr, frontends, pods, true, 9010)
+	if len(adds) != 1 || adds[0].Host != "doriscluster-sample-fe-5.doriscluster-sample-fe-internal.default.svc.test.local" || adds[0].EditLogPort != 9010 {
+		t.Errorf("classifyObservers fqdn expected add doriscluster-sample-fe-5, got %+v", adds)
+	}
+	if len(unregistered) != 2 || unregistered[0] != "doriscluster-sample-fe-4" || unregistered[1] != "doriscluster-sample-fe-5" {
+		t.Errorf("classifyObservers fqdn unregistered not expected, got %v", unregistered)
+	}
+	if len(mismatched) != 1 || mismatched[0] != "doriscluster-sample-fe-4" {
+		t.Errorf("classifyObservers fqdn mismatched not expected, got %v", mismatched)
+	}
+
+	//use ip, the pod not running is not added.
+	pods[2].Status = corev1.PodStatus{Phase: corev1.PodPending}
+	adds, unregistered, _ = classifyObservers(dcr, []*mysql.Frontend{{Host: "10.0.0.3", Role: mysql.FE_OBSERVE_ROLE}}, pods, false, 9010)
+	if len(adds) != 1 || adds[0].Host != "10.0.0.4" {
+		t.Errorf("classifyObservers ip expected add 10.0.0.4, got %+v", adds)
+	}
+	if len(unregistered) != 2 {
+		t.Errorf("classifyObservers ip unregistered not expected, got %v", unregistered)
+	}
+}
+
+func Test_classifyFollowers(t *testing.T) {
+	dcr := &dorisv1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample"},
+		Spec: dorisv1.DorisClusterSpec{
+			FeSpec: &dorisv1.FeSpec{
+				BaseSpec:       dorisv1.BaseSpec{Replicas: resource.GetInt32Pointer(5)},
+				ElectionNumber: resource.GetInt32Pointer(3),
+			},
+		},
+	}
+	frontends := []*mysql.Frontend{
+		{Host: "doriscluster-sample-fe-0.doriscluster-sample-fe-internal.default.svc.cluster.local", Role: mysql.FE_FOLLOWER_ROLE, Alive: true},
+		{Host: "doriscluster-sample-fe-1.doriscluster-sample-fe-internal.default.svc.cluster.local", Role: mysql.FE_OBSERVE_ROLE, Alive: true, EditLogPort: 9010},
+		{Host: "doriscluster-sample-fe-2.doriscluster-sample-fe-internal.default.svc.cluster.local", Role: mysql.FE_FOLLOWER_ROLE},
+		{Host: "doriscluster-sample-fe-3.doriscluster-sample-fe-internal.default.svc.cluster.local", Role: mysql.FE_OBSERVE_ROLE, Alive: true},
+	}
+
+	promotes, unpromoted := classifyFollowers(dcr, frontends, nil, true)
+	if len(promotes) != 1 || promotes[0].Host != frontends[1].Host || promotes[0].EditLogPort != 9010 {
+		t.Errorf("classifyFollowers expected promote doriscluster-sample-fe-1, got %+v", promotes)
+	}
+	if len(unpromoted) != 2 || unpromoted[0] != "doriscluster-sample-fe-1" || unpromoted[1] != "doriscluster-sample-fe-2" {
+		t.Errorf("classifyFollowers unpromoted not expected, got %v", unpromoted)
+	}
+	if followers, alive := countFollowers(frontends); followers != 2 || alive != 1 {
+		t.Errorf("countFollowers expected 2 followers 1 alive, got %d %d", followers, alive)
+	}
+
+	//use ip, the pod not registered is waited.
+	pods := []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "doriscluster-sample-fe-0"}, Status: corev1.PodStatus{PodIP: "10.0.0.0"}}}
+	promotes, unpromoted = classifyFollowers(dcr, []*mysql.Frontend{{Host: "10.0.0.0", Role: mysql.FE_OBSERVE_ROLE}}, pods, false)
+	if len(promotes) != 1 || promotes[0].Host != "10.0.0.0" || len(unpromoted) != 3 {
+		t.Errorf("classifyFollowers ip not expected, promotes %+v unpromoted %v", promotes, unpromoted)
+	}
+}
+
+func Test_statefulsetElectionNumber(t *testing.T) {
+	st := &appv1.StatefulSet{}
+	if n := statefulsetElectionNumber(st); n != dorisv1.DefaultFeElectionNumber {
+		t.Errorf("statefulsetElectionNumber without env expected default, got %d", n)
+	}
+	st.Spec.Template.Spec.Containers = []corev1.Container{{Name: "fe", Env: []corev1.EnvVar{{Name: resource.ENV_FE_ELECT_NUMBER, Value: "5"}}}}
+	if n := statefulsetElectionNumber(st); n != 5 {
+		t.Errorf("statefulsetElectionNumber expected 5, got %d", n)
+	}
+}
+
+func Test_feMasterNode(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed, err=%s", err.Error())
+	}
+	columns := []string{"Name", "Host", "Role", "IsMaster", "CurrentConnected"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("fe_1", "test-fe-0.test-fe-internal.default.svc.cluster.local", mysql.FE_FOLLOWER_ROLE, false, "Yes").
+		AddRow("fe_2", "test-fe-1.test-fe-internal.default.svc.cluster.local", mysql.FE_FOLLOWER_ROLE, true, "No").
+		AddRow("fe_3", "test-fe-3.test-fe-internal.default.svc.cluster.local", mysql.FE_OBSERVE_ROLE, false, "No")
+	mock.ExpectQuery("show frontends").WillReturnRows(rows)
+	db := &mysql.DB{DB: sqlx.NewDb(sqlDB, "mysql")}
+	defer db.Close()
+
+	master, err := feMasterNode(db)
+	if err != nil {
+		t.Fatalf("feMasterNode failed, err=%s", err.Error())
+	}
+	if master == nil || master.Host != "test-fe-1.test-fe-internal.default.svc.cluster.local" || master.Role != mysql.FE_FOLLOWER_ROLE {
+		t.Errorf("feMasterNode expect test-fe-1 as master, got %v", master)
+	}
+}
+
+func Test_observerScaleInReplicas(t *testing.T) {
+	dcr := &dorisv1.DorisCluster{
+		Spec: dorisv1.DorisClusterSpec{
+			FeSpec: &dorisv1.FeSpec{BaseSpec: dorisv1.BaseSpec{Replicas: resource.GetInt32Pointer(3)}},
+		},
+	}
+	if r := observerScaleInReplicas(dcr, 10); r != 3 {
+		t.Errorf("observerScaleInReplicas without batch expect 3, got %d", r)
+	}
+	dcr.Spec.FeSpec.ObserverScaleInBatchSize = 2
+	if r := observerScaleInReplicas(dcr, 10); r != 8 {
+		t.Errorf("observerScaleInReplicas batch 2 expect 8, got %d", r)
+	}
+	if r := observerScaleInReplicas(dcr, 4); r != 3 {
+		t.Errorf("observerScaleInReplicas last batch expect 3, got %d", r)
+	}
+}
+
+func Test_unhealthyFrontends(t *testing.T) {
+	frontends := []*mysql.Frontend{
+		{Host: "test-fe-0", Role: mysql.FE_FOLLOWER_ROLE, Alive: true},
+		{Host: "test-fe-3", Role: mysql.FE_OBSERVE_ROLE, Alive: false},
+		{Host: "test-fe-5", Role: mysql.FE_OBSERVE_ROLE, Alive: false},
+	}
+	hosts := unhealthyFrontends(frontends, []*mysql.Frontend{{Host: "test-fe-5"}})
+	if len(hosts) != 1 || hosts[0] != "test-fe-3" {
+		t.Errorf("unhealthyFrontends expect test-fe-3, got %v", hosts)
+	}
+}
+
+// newPromoteFixture build the doriscluster with 3 followers expected and the statefulset that persist the fe meta.
+func newPromoteFixture() (*dorisv1.DorisCluster, *appv1.StatefulSet, func(int) string) {
+	dcr := &dorisv1.DorisCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample"},
+		Spec: dorisv1.DorisClusterSpec{
+			FeSpec: &dorisv1.FeSpec{
+				BaseSpec:       dorisv1.BaseSpec{Replicas: resource.GetInt32Pointer(3)},
+				ElectionNumber: resource.GetInt32Pointer(3),
+			},
+		},
+	}
+	st := &appv1.StatefulSet{
+		Spec: appv1.StatefulSetSpec{
+			Replicas: resource.GetInt32Pointer(3),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:         "fe",
+				Env:          []corev1.EnvVar{{Name: resource.ENV_FE_ELECT_NUMBER, Value: "3"}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "fe-meta", MountPath: "/opt/apache-doris/fe/doris-meta"}},
+			}}}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "fe-meta"}}},
+		},
+		Status: appv1.StatefulSetStatus{UpdatedReplicas: 3, ReadyReplicas: 3},
+	}
+	host := func(i int) string {
+		return "doriscluster-sample-fe-" + strconv.Itoa(i) + ".doriscluster-sample-fe-internal.default.svc.cluster.local"
+	}
+	return dcr, st, host
+}
+
+func newPromoteController(objs ...client.Object) (*Controller, client.Client) {
+	k8sclient := fake.NewClientBuilder().WithObjects(objs...).Build()
+	return &Controller{sub_controller.SubDefaultController{K8sclient: k8sclient, K8srecorder: record.NewFakeRecorder(100)}}, k8sclient
+}
+
+func newPromoteDB(t *testing.T) (*mysql.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed, err=%s", err.Error())
+	}
+	return &mysql.DB{DB: sqlx.NewDb(sqlDB, "mysql")}, mock
+}
+
+func listPromotePods(t *testing.T, k8sclient client.Client) []corev1.Pod {
+	var pods corev1.PodList
+	if err := k8sclient.List(context.Background(), &pods); err != nil {
+		t.Fatalf("list pods failed, err=%s", err.Error())
+	}
+	return pods.Items
+}
+
+func Test_promoteFollower(t *testing.T) {
+	dcr, st, host := newPromoteFixture()
+	newFrontends := func() []*mysql.Frontend {
+		return []*mysql.Frontend{
+			{Host: host(0), Role: mysql.FE_FOLLOWER_ROLE, Alive: true},
+			{Host: host(1), Role: mysql.FE_OBSERVE_ROLE, Alive: true, EditLogPort: 9010},
+			{Host: host(2), Role: mysql.FE_FOLLOWER_ROLE, Alive: true},
+		}
+	}
+	var objs []client.Object
+	for i := 0; i < 3; i++ {
+		objs = append(objs, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample-fe-" + strconv.Itoa(i), UID: types.UID("pod-" + strconv.Itoa(i))}})
+	}
+	objs = append(objs, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fe-meta-doriscluster-sample-fe-1", UID: "meta-1"}})
+	fc, k8sclient := newPromoteController(objs...)
+	metaDir := feMetaDir(map[string]interface{}{})
+	pvcKey := types.NamespacedName{Namespace: "default", Name: "fe-meta-doriscluster-sample-fe-1"}
+
+	//the promotion recorded before any change.
+	db, mock := newPromoteDB(t)
+	unpromoted, promotion, err := fc.promoteFollower(context.Background(), dcr, db, st, metaDir, newFrontends(), listPromotePods(t, k8sclient), true, nil)
+	if err != nil || len(unpromoted) != 1 || unpromoted[0] != "doriscluster-sample-fe-1" {
+		t.Fatalf("promoteFollower expected doriscluster-sample-fe-1 unpromoted, got %v err=%v", unpromoted, err)
+	}
+	if promotion == nil || promotion.PodName != "doriscluster-sample-fe-1" || promotion.PodUID != "pod-1" || promotion.Phase != dorisv1.PromotionRestartingPod {
+		t.Fatalf("promoteFollower expected the promotion of doriscluster-sample-fe-1 recorded, got %+v", promotion)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil || len(listPromotePods(t, k8sclient)) != 3 {
+		t.Errorf("promoteFollower expected nothing changed before the promotion recorded, err=%v", err)
+	}
+
+	//the observer dropped and the pod deleted, the meta pvc kept until the pod confirmed deleted.
+	db, mock = newPromoteDB(t)
+	mock.ExpectExec(`ALTER SYSTEM DROP OBSERVER`).WillReturnResult(sqlmock.NewResult(1, 1))
+	_, promotion, err = fc.promoteFollower(context.Background(), dcr, db, st, metaDir, newFrontends(), listPromotePods(t, k8sclient), true, promotion)
+	if err != nil || promotion == nil || promotion.Phase != dorisv1.PromotionRestartingPod {
+		t.Fatalf("promoteFollower expected restarting pod, got %+v err=%v", promotion, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("promoteFollower expected the observer dropped, err=%s", err.Error())
+	}
+	if pods := listPromotePods(t, k8sclient); len(pods) != 2 {
+		t.Errorf("promoteFollower expected the pod of observer deleted, got %d pods", len(pods))
+	}
+	if err := k8sclient.Get(context.Background(), pvcKey, &corev1.PersistentVolumeClaim{}); err != nil {
+		t.Errorf("promoteFollower expected the meta pvc kept before the pod confirmed deleted, err=%s", err.Error())
+	}
+
+	//the pod confirmed deleted, the meta pvc recorded for clearing.
+	frontends := newFrontends()
+	frontends = []*mysql.Frontend{frontends[0], frontends[2]}
+	db, mock = newPromoteDB(t)
+	_, promotion, err = fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, promotion)
+	if err != nil || promotion == nil || promotion.Phase != dorisv1.PromotionClearingMeta || promotion.MetaPVCUID != "meta-1" {
+		t.Fatalf("promoteFollower expected clearing meta-1, got %+v err=%v", promotion, err)
+	}
+
+	//the pod recreated with the old meta pvc, both deleted.
+	if err := k8sclient.Create(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample-fe-1", UID: "pod-1-recreated"}}); err != nil {
+		t.Fatalf("create the recreated pod failed, err=%s", err.Error())
+	}
+	_, promotion, err = fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, promotion)
+	if err != nil || promotion == nil || promotion.Phase != dorisv1.PromotionClearingMeta {
+		t.Fatalf("promoteFollower expected clearing meta, got %+v err=%v", promotion, err)
+	}
+	if err := k8sclient.Get(context.Background(), pvcKey, &corev1.PersistentVolumeClaim{}); err == nil {
+		t.Errorf("promoteFollower expected the meta pvc deleted")
+	}
+	if pods := listPromotePods(t, k8sclient); len(pods) != 2 {
+		t.Errorf("promoteFollower expected the pod holding the old meta deleted, got %d pods", len(pods))
+	}
+
+	//the meta cleared, wait the pod registered.
+	_, promotion, err = fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, promotion)
+	if err != nil || promotion == nil || promotion.Phase != dorisv1.PromotionRegistering {
+		t.Fatalf("promoteFollower expected registering, got %+v err=%v", promotion, err)
+	}
+
+	//the pod registered as alive follower, the promotion finished.
+	if err := k8sclient.Create(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample-fe-1", UID: "pod-1-promoted"}}); err != nil {
+		t.Fatalf("create the promoted pod failed, err=%s", err.Error())
+	}
+	frontends = append(frontends, &mysql.Frontend{Host: host(1), Role: mysql.FE_FOLLOWER_ROLE, Alive: true})
+	unpromoted, promotion, err = fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, promotion)
+	if err != nil || promotion != nil || len(unpromoted) != 0 {
+		t.Errorf("promoteFollower expected the promotion finished, got %+v unpromoted %v err=%v", promotion, unpromoted, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("promoteFollower expected no sql after the observer dropped, err=%s", err.Error())
+	}
+
+	//the alive followers not a majority after a follower added, not promote.
+	fc, k8sclient = newPromoteController(objs[0].DeepCopyObject().(client.Object), objs[1].DeepCopyObject().(client.Object), objs[2].DeepCopyObject().(client.Object))
+	db, mock = newPromoteDB(t)
+	frontends = newFrontends()
+	frontends[2].Alive = false
+	if _, promotion, err = fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, nil); err != nil || promotion != nil {
+		t.Fatalf("promoteFollower expected hold without promotion, got %+v err=%v", promotion, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil || len(listPromotePods(t, k8sclient)) != 3 {
+		t.Errorf("promoteFollower expected not promote when quorum lost, err=%v", err)
+	}
+}
+
+func Test_promoteFollower_restartFailed(t *testing.T) {
+	dcr, st, host := newPromoteFixture()
+	metaDir := feMetaDir(map[string]interface{}{})
+	frontends := []*mysql.Frontend{
+		{Host: host(0), Role: mysql.FE_FOLLOWER_ROLE, Alive: true},
+		{Host: host(1), Role: mysql.FE_FOLLOWER_ROLE, Alive: false, EditLogPort: 9010},
+		{Host: host(2), Role: mysql.FE_FOLLOWER_ROLE, Alive: true},
+	}
+	registering := &dorisv1.FollowerPromotion{PodName: "doriscluster-sample-fe-1", PodUID: "pod-1", MetaPVCUID: "meta-1", Phase: dorisv1.PromotionRegistering, LastTransitionTime: metav1.Now()}
+
+	//the pod created before the meta pvc deleted keeps pending, deleted for recreating the pvc.
+	pending := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample-fe-1", UID: "pod-1-pending"}, Status: corev1.PodStatus{Phase: corev1.PodPending}}
+	fc, k8sclient := newPromoteController(pending)
+	db, mock := newPromoteDB(t)
+	_, promotion, err := fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, registering)
+	if err != nil || promotion == nil || promotion.Phase != dorisv1.PromotionRegistering {
+		t.Fatalf("promoteFollower expected registering, got %+v err=%v", promotion, err)
+	}
+	if pods := listPromotePods(t, k8sclient); len(pods) != 0 {
+		t.Errorf("promoteFollower expected the pending pod without meta pvc deleted, got %d pods", len(pods))
+	}
+
+	//the pod not registered as alive follower in time, the follower not alive dropped and the promotion failed.
+	running := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "doriscluster-sample-fe-1", UID: "pod-1-running"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	fc, k8sclient = newPromoteController(running)
+	timeout := registering.DeepCopy()
+	timeout.LastTransitionTime = metav1.NewTime(time.Now().Add(-followerPromotionTimeout - time.Minute))
+	mock.ExpectExec(`ALTER SYSTEM DROP FOLLOWER "` + host(1) + `:9010";`).WillReturnResult(sqlmock.NewResult(1, 1))
+	unpromoted, promotion, err := fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, timeout)
+	if err != nil || promotion == nil || promotion.Phase != dorisv1.PromotionFailed || promotion.Message == "" {
+		t.Fatalf("promoteFollower expected the promotion failed, got %+v err=%v", promotion, err)
+	}
+	if len(unpromoted) != 1 || unpromoted[0] != "doriscluster-sample-fe-1" {
+		t.Errorf("promoteFollower expected doriscluster-sample-fe-1 unpromoted, got %v", unpromoted)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("promoteFollower expected the follower not alive dropped, err=%s", err.Error())
+	}
+
+	//the failed promotion kept without promoting another observer.
+	frontends = []*mysql.Frontend{frontends[0], frontends[2], {Host: host(3), Role: mysql.FE_OBSERVE_ROLE, Alive: true}}
+	if _, failed, err := fc.promoteFollower(context.Background(), dcr, db, st, metaDir, frontends, listPromotePods(t, k8sclient), true, promotion); err != nil || failed == nil || failed.Phase != dorisv1.PromotionFailed {
+		t.Errorf("promoteFollower expected the failed promotion kept, got %+v err=%v", failed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("promoteFollower expected no sql when the promotion failed, err=%s", err.Error())
+	}
+}
+
+func Test_metaPVCName(t *testing.T) {
+	st := &appv1.StatefulSet{Spec: appv1.StatefulSetSpec{
+		Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			VolumeMounts: []corev1.VolumeMount{{Name: "fe-log", MountPath: "/opt/apache-doris/fe/log"}, {Name: "meta", MountPath: "/data/meta/"}},
+		}}}},
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "fe-log"}}, {ObjectMeta: metav1.ObjectMeta{Name: "meta"}}},
+	}}
+	if name := metaPVCName(st, "/data/meta", "fe-0"); name != "meta-fe-0" {
+		t.Errorf("metaPVCName expected meta-fe-0, got %s", name)
+	}
+	if name := metaPVCName(st, feMetaDir(map[string]interface{}{}), "fe-0"); name != "" {
+		t.Errorf("metaPVCName expected empty when the meta not persisted, got %s", name)
+	}
+}
@@ -0,0 +1,186 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sub_controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	v1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the records waiting written into history, the records beyond it are dropped for not blocking reconciling.
+const historyQueueSize = 256
+
+// the normal events that are lifecycle transitions recorded in history, all warning events are recorded as failures.
+var historyNormalReasons = map[EventReason]bool{
+	CGScaled:                       true,
+	CGScaleDownSucceeded:           true,
+	CGSuspended:                    true,
+	CGResumed:                      true,
+	CGBackendsDropped:              true,
+	CGDuplicateBackendsDropped:     true,
+	CGEvictionDecommissionFinished: true,
+	CGCutoverStarted:               true,
+	CGCutoverFinished:              true,
+	CGTrafficSwapped:               true,
+	CGCordoned:                     true,
+	CGUncordoned:                   true,
+	CGReplicasRestored:             true,
+	CGScaleInSnapshotCreated:       true,
+	CGDegradedHoldReleased:         true,
+	FEMetadataRecovered:            true,
+	SQLAudit:                       true,
+}
+
+// the messages of compute group events start with `compute group {uniqueId}`.
+var historyComputeGroupRegex = regexp.MustCompile(`compute group ([0-9A-Za-z_]+)`)
+
+type historyEntry struct {
+	namespace string
+	cluster   string
+	owner     metav1.OwnerReference
+	max       int32
+	record    v1.HistoryRecord
+}
+
+// HistoryWriter write the significant events of disaggregated clusters into the DorisDisaggregatedClusterHistory of cluster, enabled by the history of cluster spec.
+// the records are written by a background worker, the recording is best-effort and never blocks reconciling.
+type HistoryWriter struct {
+	k8sclient client.Client
+	entries   chan historyEntry
+	once      sync.Once
+}
+
+func NewHistoryWriter(k8sclient client.Client) *HistoryWriter {
+	return &HistoryWriter{k8sclient: k8sclient, entries: make(chan historyEntry, historyQueueSize)}
+}
+
+// Recorder return the recorder that emits the events by the recorder and records the significant ones into history.
+func (w *HistoryWriter) Recorder(recorder record.EventRecorder) record.EventRecorder {
+	return &historyRecorder{EventRecorder: recorder, writer: w}
+}
+
+type historyRecorder struct {
+	record.EventRecorder
+	writer *HistoryWriter
+}
+
+func (r *historyRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.writer.record(object, eventtype, reason, message)
+}
+
+func (r *historyRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.writer.record(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *historyRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.writer.record(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// record queue the event when it is significant and the history enabled, the event dropped when the queue full.
+func (w *HistoryWriter) record(object runtime.Object, eventtype, reason, message string) {
+	ddc, ok := object.(*v1.DorisDisaggregatedCluster)
+	if !ok || ddc.Spec.History == nil || (eventtype != string(EventWarning) && !historyNormalReasons[EventReason(reason)]) {
+		return
+	}
+	max := ddc.Spec.History.MaxRecords
+	if max <= 0 {
+		max = v1.DefaultHistoryMaxRecords
+	}
+	//the type meta of ddc may be empty when it is read from the cache.
+	owner := metav1.OwnerReference{APIVersion: v1.GroupVersion.String(), Kind: "DorisDisaggregatedCluster", Name: ddc.Name, UID: ddc.UID}
+	entry := historyEntry{namespace: ddc.Namespace, cluster: ddc.Name, owner: owner, max: max, record: v1.HistoryRecord{
+		Time:         metav1.Now(),
+		ComputeGroup: historyComputeGroup(ddc, message),
+		Type:         eventtype,
+		Reason:       reason,
+		Message:      message,
+	}}
+
+	w.once.Do(func() { go w.run() })
+	select {
+	case w.entries <- entry:
+	default:
+		klog.Errorf("HistoryWriter namespace %s name %s history queue full, drop the record of event %s.", ddc.Namespace, ddc.Name, reason)
+	}
+}
+
+func (w *HistoryWriter) run() {
+	for entry := range w.entries {
+		if err := w.write(context.Background(), entry); err != nil {
+			klog.Errorf("HistoryWriter namespace %s name %s write the record of event %s failed, err=%s", entry.namespace, entry.cluster, entry.record.Reason, err.Error())
+		}
+	}
+}
+
+// write append the record into the history of cluster, the history created when not exist and owned by the cluster. the oldest records beyond max are removed.
+func (w *HistoryWriter) write(ctx context.Context, entry historyEntry) error {
+	name := entry.cluster + "-history"
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var h v1.DorisDisaggregatedClusterHistory
+		err := w.k8sclient.Get(ctx, types.NamespacedName{Namespace: entry.namespace, Name: name}, &h)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		create := apierrors.IsNotFound(err)
+		if create {
+			h = v1.DorisDisaggregatedClusterHistory{
+				ObjectMeta: metav1.ObjectMeta{Namespace: entry.namespace, Name: name, OwnerReferences: []metav1.OwnerReference{entry.owner}},
+				Cluster:    entry.cluster,
+			}
+		}
+
+		h.Records = append(h.Records, entry.record)
+		if n := len(h.Records) - int(entry.max); n > 0 {
+			h.Records = h.Records[n:]
+		}
+		h.RecordCount = int32(len(h.Records))
+		if create {
+			return w.k8sclient.Create(ctx, &h)
+		}
+		return w.k8sclient.Update(ctx, &h)
+	})
+}
+
+// historyComputeGroup return the uniqueId of compute group that the message is about, empty when not about a compute group of cluster.
+func historyComputeGroup(ddc *v1.DorisDisaggregatedCluster, message string) string {
+	m := historyComputeGroupRegex.FindStringSubmatch(message)
+	if len(m) != 2 {
+		return ""
+	}
+	for _, cg := range ddc.Spec.ComputeGroups {
+		if cg.UniqueId == m[1] {
+			return m[1]
+		}
+	}
+	return ""
+}
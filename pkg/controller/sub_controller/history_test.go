@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sub_controller
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/apache/doris-operator/api/disaggregated/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHistoryWriter_write(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatalf("add scheme failed, err=%s", err.Error())
+	}
+	k8sclient := fake.NewClientBuilder().WithScheme(s).Build()
+	w := NewHistoryWriter(k8sclient)
+	ctx := context.Background()
+
+	for _, reason := range []string{"CGScaled", "CGSuspended", "CGResumed"} {
+		entry := historyEntry{namespace: "default", cluster: "test", max: 2, record: v1.HistoryRecord{Time: metav1.Now(), ComputeGroup: "cg1", Type: string(EventNormal), Reason: reason}}
+		if err := w.write(ctx, entry); err != nil {
+			t.Fatalf("write history failed, err=%s", err.Error())
+		}
+	}
+
+	var h v1.DorisDisaggregatedClusterHistory
+	if err := k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-history"}, &h); err != nil {
+		t.Fatalf("get history failed, err=%s", err.Error())
+	}
+	if h.Cluster != "test" || h.RecordCount != 2 || len(h.Records) != 2 || h.Records[0].Reason != "CGSuspended" || h.Records[1].Reason != "CGResumed" {
+		t.Errorf("history expected the latest 2 records kept, got cluster=%s count=%d records=%+v", h.Cluster, h.RecordCount, h.Records)
+	}
+}
+
+func TestHistoryWriter_record(t *testing.T) {
+	w := &HistoryWriter{entries: make(chan historyEntry, 10)}
+	//mark the worker started, the entries stay in the queue for checking.
+	w.once.Do(func() {})
+	ddc := &v1.DorisDisaggregatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec:       v1.DorisDisaggregatedClusterSpec{ComputeGroups: []v1.ComputeGroup{{UniqueId: "cg1"}}},
+	}
+	recorder := w.Recorder(record.NewFakeRecorder(10))
+
+	recorder.Event(ddc, string(EventWarning), string(CGStuck), "compute group cg1 not ready.")
+	if len(w.entries) != 0 {
+		t.Errorf("record expected nothing queued when history not enabled")
+	}
+
+	ddc.Spec.History = &v1.History{}
+	recorder.Event(ddc, string(EventNormal), string(CGProfileApplied), "profile applied.")
+	recorder.Event(ddc, string(EventWarning), string(CGStuck), "compute group cg1 not ready.")
+	recorder.Eventf(ddc, string(EventNormal), string(CGScaled), "compute group %s scaled from %d to %d replicas.", "cg2", 1, 2)
+	if len(w.entries) != 2 {
+		t.Fatalf("record expected 2 entries queued, got %d", len(w.entries))
+	}
+	first, second := <-w.entries, <-w.entries
+	if first.record.Reason != string(CGStuck) || first.record.ComputeGroup != "cg1" || first.max != v1.DefaultHistoryMaxRecords || first.owner.Name != "test" {
+		t.Errorf("record expected warning of cg1 queued, got %+v", first)
+	}
+	if second.record.Reason != string(CGScaled) || second.record.ComputeGroup != "" || second.record.Message != "compute group cg2 scaled from 1 to 2 replicas." {
+		t.Errorf("record expected scaled queued without compute group not in spec, got %+v", second)
+	}
+}
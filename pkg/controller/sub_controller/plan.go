@@ -0,0 +1,182 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sub_controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/doris-operator/api/disaggregated/v1"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Plan records the actions that reconcile intends to do in plan mode, the writes of kubernetes resources and the sql executed in fe.
+type Plan struct {
+	mu      sync.Mutex
+	actions []v1.PlannedAction
+}
+
+// Reset clear the recorded actions before a new plan reconcile.
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.actions = nil
+}
+
+// Actions return the recorded actions in order.
+func (p *Plan) Actions() []v1.PlannedAction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]v1.PlannedAction{}, p.actions...)
+}
+
+// RecordSql record the sql statement that executed in fe.
+func (p *Plan) RecordSql(query string) {
+	p.record(v1.PlannedAction{Verb: "Exec", Kind: "SQL", Statement: query})
+}
+
+func (p *Plan) record(action v1.PlannedAction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	klog.Infof("plan reconcile intends to %s %s %s/%s %s", action.Verb, action.Kind, action.Namespace, action.Name, action.Statement)
+	p.actions = append(p.actions, action)
+}
+
+func (p *Plan) recordObject(c client.Client, verb string, obj runtime.Object) {
+	action := v1.PlannedAction{Verb: verb}
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		action.Kind = gvk.Kind
+	}
+	if o, ok := obj.(client.Object); ok {
+		action.Namespace = o.GetNamespace()
+		action.Name = o.GetName()
+	}
+	p.record(action)
+}
+
+// PlanSqlClient set the sql client records the statements into plan instead of executing in plan mode.
+func (d *DisaggregatedSubDefaultController) PlanSqlClient(db *mysql.DB) *mysql.DB {
+	if d.Plan != nil && db != nil {
+		db.PlanRecorder = d.Plan.RecordSql
+	}
+	return db
+}
+
+// NewPlanClient return the client that records the writes into plan without executing, the reads pass through.
+func NewPlanClient(c client.Client, p *Plan) client.Client {
+	return &planClient{Client: c, plan: p}
+}
+
+type planClient struct {
+	client.Client
+	plan *Plan
+}
+
+func (c *planClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.plan.recordObject(c.Client, "Create", obj)
+	return nil
+}
+
+func (c *planClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.plan.recordObject(c.Client, "Delete", obj)
+	return nil
+}
+
+func (c *planClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.plan.recordObject(c.Client, "Update", obj)
+	return nil
+}
+
+func (c *planClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.plan.recordObject(c.Client, "Patch", obj)
+	return nil
+}
+
+func (c *planClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.plan.recordObject(c.Client, "DeleteAllOf", obj)
+	return nil
+}
+
+func (c *planClient) Status() client.SubResourceWriter {
+	return &planStatusWriter{client: c.Client, plan: c.plan}
+}
+
+func (c *planClient) SubResource(subResource string) client.SubResourceClient {
+	return &planSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), planStatusWriter: planStatusWriter{client: c.Client, plan: c.plan}}
+}
+
+// planStatusWriter records the writes of sub resources, the verbs have the `Status` suffix.
+type planStatusWriter struct {
+	client client.Client
+	plan   *Plan
+}
+
+func (w *planStatusWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	w.plan.recordObject(w.client, "CreateStatus", obj)
+	return nil
+}
+
+func (w *planStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.plan.recordObject(w.client, "UpdateStatus", obj)
+	return nil
+}
+
+func (w *planStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	w.plan.recordObject(w.client, "PatchStatus", obj)
+	return nil
+}
+
+type planSubResourceClient struct {
+	client.SubResourceClient
+	planStatusWriter
+}
+
+func (c *planSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return c.planStatusWriter.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *planSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return c.planStatusWriter.Update(ctx, obj, opts...)
+}
+
+func (c *planSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return c.planStatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// NewPlanRecorder return the event recorder that only logs the events in plan mode, the events of not executed actions should not be seen on cluster.
+func NewPlanRecorder() record.EventRecorder {
+	return &planRecorder{}
+}
+
+type planRecorder struct{}
+
+func (r *planRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	klog.Infof("plan reconcile event type=%s reason=%s message=%s", eventtype, reason, message)
+}
+
+func (r *planRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	klog.Infof("plan reconcile event type=%s reason=%s message="+messageFmt, append([]interface{}{eventtype, reason}, args...)...)
+}
+
+func (r *planRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
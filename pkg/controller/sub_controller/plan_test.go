@@ -0,0 +1,85 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sub_controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/apache/doris-operator/pkg/common/utils/mysql"
+	"github.com/jmoiron/sqlx"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPlanClient(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1"}}
+	k8sclient := fake.NewClientBuilder().WithObjects(svc).Build()
+	plan := &Plan{}
+	pc := NewPlanClient(k8sclient, plan)
+	ctx := context.Background()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cg1-0"}}
+	if err := pc.Create(ctx, pod); err != nil {
+		t.Errorf("plan client create failed, err=%s", err.Error())
+	}
+	if err := pc.Delete(ctx, svc); err != nil {
+		t.Errorf("plan client delete failed, err=%s", err.Error())
+	}
+	if err := pc.Status().Update(ctx, svc); err != nil {
+		t.Errorf("plan client update status failed, err=%s", err.Error())
+	}
+
+	if err := k8sclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1-0"}, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Errorf("plan client expected pod not created, err=%v", err)
+	}
+	if err := pc.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-cg1"}, &corev1.Service{}); err != nil {
+		t.Errorf("plan client expected service not deleted and read through, err=%s", err.Error())
+	}
+	actions := plan.Actions()
+	if len(actions) != 3 || actions[0].Verb != "Create" || actions[0].Kind != "Pod" || actions[0].Name != "test-cg1-0" ||
+		actions[1].Verb != "Delete" || actions[1].Kind != "Service" || actions[2].Verb != "UpdateStatus" {
+		t.Errorf("plan client recorded actions not expected, got %+v", actions)
+	}
+}
+
+func TestPlanSqlClient(t *testing.T) {
+	mdb, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("new sqlmock failed, err=%s", err.Error())
+	}
+	defer mdb.Close()
+	plan := &Plan{}
+	d := &DisaggregatedSubDefaultController{Plan: plan}
+	db := d.PlanSqlClient(&mysql.DB{DB: sqlx.NewDb(mdb, "mysql")})
+
+	if err := db.DropBE([]*mysql.Backend{{Host: "test-cg1-1.test-cg1.default.svc.cluster.local", HeartbeatPort: 9050}}); err != nil {
+		t.Errorf("plan sql client drop be failed, err=%s", err.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("plan sql client expected not execute sql, err=%s", err.Error())
+	}
+	actions := plan.Actions()
+	if len(actions) != 1 || actions[0].Verb != "Exec" || actions[0].Kind != "SQL" || actions[0].Statement == "" {
+		t.Errorf("plan sql client recorded actions not expected, got %+v", actions)
+	}
+}